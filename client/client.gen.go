@@ -0,0 +1,425 @@
+// Code generated by clientgen from the survey API's OpenAPI specification. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/url"
+)
+
+// Problem is an RFC 7807 problem details object
+type Problem struct {
+	// URI identifying the problem, urn:survey-api:problem: followed by its code
+	Type string `json:"type"`
+	// Text of the HTTP status
+	Title string `json:"title"`
+	// HTTP status code
+	Status int `json:"status"`
+	// Human-readable explanation, which may change
+	Detail string `json:"detail,omitempty"`
+	// Path of the request
+	Instance string `json:"instance,omitempty"`
+	// Stable error code
+	Code string `json:"code"`
+	// Trace ID of the request, as in the X-Trace-ID header
+	TraceID string `json:"trace_id,omitempty"`
+}
+
+// SubmitRequest is a survey response to submit
+type SubmitRequest struct {
+	SurveyID string `json:"survey_id"`
+	// Answers keyed by question; values may be arrays and objects up to the configured depth
+	Answers map[string]interface{} `json:"answers"`
+}
+
+// SubmitResult is the outcome of a submission
+type SubmitResult struct {
+	Message string `json:"message"`
+	// ID of the stored response
+	ID string `json:"id"`
+}
+
+// BatchResult is the outcome of a batch submission
+type BatchResult struct {
+	Accepted int               `json:"accepted"`
+	Rejected int               `json:"rejected"`
+	Results  []BatchItemResult `json:"results"`
+}
+
+// BatchItemResult is the outcome of one response in a batch submission
+type BatchItemResult struct {
+	// Position of the response in the batch
+	Index int `json:"index"`
+	// ID of the stored response, set when it was accepted
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	// Why the response was rejected
+	Error string `json:"error,omitempty"`
+}
+
+// SurveyResponse is a stored survey response, as exported in JSON Lines
+type SurveyResponse struct {
+	ID       string                 `json:"id"`
+	SurveyID string                 `json:"survey_id"`
+	Answers  map[string]interface{} `json:"answers"`
+	// When the response was submitted, in Unix seconds
+	CreatedAt int64 `json:"created_at"`
+	// Caller that submitted the response, such as api_key:01J...
+	SubmittedBy string `json:"submitted_by,omitempty"`
+	// Tenant the response was submitted for, empty for the default tenant
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+// Report is the aggregated result of all responses to a survey
+type Report struct {
+	SurveyID      string                     `json:"survey_id"`
+	ResponseCount int                        `json:"response_count"`
+	Questions     map[string]QuestionSummary `json:"questions"`
+	// When the report was generated, in Unix seconds
+	GeneratedAt int64 `json:"generated_at"`
+	// Increases by one every time a new report is generated
+	Version int64 `json:"version"`
+}
+
+// QuestionSummary is the summary of the answers to a single question
+type QuestionSummary struct {
+	Answered int `json:"answered"`
+	// Number of times each answer value was given
+	Counts  map[string]int  `json:"counts,omitempty"`
+	Numeric *NumericSummary `json:"numeric,omitempty"`
+	// Estimated number of distinct answer values
+	Distinct int `json:"distinct"`
+}
+
+// NumericSummary is the statistics over the numeric answers to a question; quantiles are estimates
+type NumericSummary struct {
+	Count  int     `json:"count"`
+	Sum    float64 `json:"sum"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"std_dev"`
+	Median float64 `json:"median"`
+	P90    float64 `json:"p90"`
+	P99    float64 `json:"p99"`
+}
+
+// JobRecord is a report job and its lifecycle
+type JobRecord struct {
+	ID       string `json:"id"`
+	SurveyID string `json:"survey_id"`
+	Status   string `json:"status"`
+	// When the job was published, in Unix nanoseconds
+	RequestedAt int64 `json:"requested_at"`
+	// When the job may run at the earliest, in Unix nanoseconds
+	NotBefore int64 `json:"not_before,omitempty"`
+	// When the job last started, in Unix nanoseconds
+	StartedAt int64 `json:"started_at,omitempty"`
+	// When the job last finished, in Unix nanoseconds
+	FinishedAt int64 `json:"finished_at,omitempty"`
+	Attempts   int   `json:"attempts"`
+	// Why the last attempt failed
+	Error string `json:"error,omitempty"`
+}
+
+// JobList is a list of the report jobs of a survey
+type JobList struct {
+	SurveyID string      `json:"survey_id"`
+	Jobs     []JobRecord `json:"jobs"`
+}
+
+// Webhook is a URL notified about a survey's reports
+type Webhook struct {
+	ID       string `json:"id"`
+	SurveyID string `json:"survey_id"`
+	URL      string `json:"url"`
+	// Key payloads are signed with using HMAC-SHA256, only returned on registration
+	Secret string `json:"secret,omitempty"`
+	// When the webhook was registered, in Unix seconds
+	CreatedAt int64 `json:"created_at"`
+}
+
+// RegisterWebhookRequest is a webhook to register
+type RegisterWebhookRequest struct {
+	URL string `json:"url"`
+	// Signing secret; a random one is generated when it is empty
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookList is a list of the webhooks of a survey
+type WebhookList struct {
+	SurveyID string    `json:"survey_id"`
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// WebhookDeliveryAttempt is the outcome of one attempt to deliver a webhook payload
+type WebhookDeliveryAttempt struct {
+	DeliveryID string `json:"delivery_id"`
+	WebhookID  string `json:"webhook_id"`
+	SurveyID   string `json:"survey_id"`
+	Event      string `json:"event"`
+	URL        string `json:"url"`
+	Attempt    int    `json:"attempt"`
+	Status     string `json:"status"`
+	// The receiver's HTTP status, absent if it did not answer
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// When the attempt was made, in Unix nanoseconds
+	AttemptedAt int64 `json:"attempted_at"`
+	// How long the receiver took to answer
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// DeliveryList is a list of the webhook delivery attempts of a survey
+type DeliveryList struct {
+	SurveyID   string                   `json:"survey_id"`
+	Deliveries []WebhookDeliveryAttempt `json:"deliveries"`
+}
+
+// Schedule is a schedule generating a survey's report at fixed times; exactly one of cron and at is set
+type Schedule struct {
+	ID       string `json:"id"`
+	SurveyID string `json:"survey_id"`
+	// Tenant owning the survey, empty for the default tenant
+	TenantID string `json:"tenant_id,omitempty"`
+	// Five-field cron expression or descriptor such as @daily, optionally prefixed with CRON_TZ=<zone>
+	Cron string `json:"cron,omitempty"`
+	// One-off run time, in Unix seconds
+	At int64 `json:"at,omitempty"`
+	// When the schedule fires next, zero once a one-off run has fired, in Unix seconds
+	NextRunAt int64 `json:"next_run_at"`
+	// When the schedule last fired, in Unix seconds
+	LastRunAt int64 `json:"last_run_at,omitempty"`
+	// ID of the report job the schedule last published
+	LastJobID string `json:"last_job_id,omitempty"`
+	// When the schedule was created, in Unix seconds
+	CreatedAt int64 `json:"created_at"`
+}
+
+// CreateScheduleRequest is a schedule to create; exactly one property is set
+type CreateScheduleRequest struct {
+	// Five-field cron expression or descriptor such as @daily, optionally prefixed with CRON_TZ=<zone>
+	Cron string `json:"cron,omitempty"`
+	// One-off run time, in Unix seconds
+	At int64 `json:"at,omitempty"`
+}
+
+// ScheduleList is a list of the report schedules of a survey
+type ScheduleList struct {
+	SurveyID  string     `json:"survey_id"`
+	Schedules []Schedule `json:"schedules"`
+}
+
+// APIKey is an API key, without the key itself
+type APIKey struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Scopes []Scope `json:"scopes"`
+	// The only tenant the key may act for, absent if it may act for any
+	TenantID string `json:"tenant_id,omitempty"`
+	// Caller that created the key, absent if it was created with the apikey command
+	CreatedBy string `json:"created_by,omitempty"`
+	// When the key was created, in Unix seconds
+	CreatedAt int64 `json:"created_at"`
+}
+
+// CreatedAPIKey is a newly created API key together with the key itself
+type CreatedAPIKey struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Scopes []Scope `json:"scopes"`
+	// The only tenant the key may act for
+	TenantID string `json:"tenant_id,omitempty"`
+	// Caller that created the key
+	CreatedBy string `json:"created_by,omitempty"`
+	// When the key was created, in Unix seconds
+	CreatedAt int64 `json:"created_at"`
+	// The key itself, never returned again
+	Key string `json:"key"`
+}
+
+// CreateAPIKeyRequest is an API key to create
+type CreateAPIKeyRequest struct {
+	// Who or what uses the key
+	Name   string  `json:"name"`
+	Scopes []Scope `json:"scopes"`
+}
+
+// APIKeyList is a list of every API key
+type APIKeyList struct {
+	APIKeys []APIKey `json:"api_keys"`
+}
+
+// Scope is a scope granted to a caller; admin implies every other scope
+type Scope string
+
+// Values of Scope
+const (
+	ScopeSubmit     Scope = "submit"
+	ScopeReadReport Scope = "read-report"
+	ScopeAdmin      Scope = "admin"
+)
+
+// WithTenantID sets the X-Tenant-ID header: the tenant the request acts for; callers bound to a tenant may only name their own, and other callers need the admin scope to name one
+func WithTenantID(tenantID string) RequestOption {
+	return WithHeader("X-Tenant-ID", tenantID)
+}
+
+// WithIdempotencyKey sets the Idempotency-Key header: a client-chosen key; retries with the same key and body replay the original outcome
+func WithIdempotencyKey(idempotencyKey string) RequestOption {
+	return WithHeader("Idempotency-Key", idempotencyKey)
+}
+
+// SubmitResponse calls POST /survey/submit: submit a survey response
+func (c *Client) SubmitResponse(ctx context.Context, body *SubmitRequest, opts ...RequestOption) (*SubmitResult, error) {
+	var result SubmitResult
+	if err := c.do(ctx, "POST", "/survey/submit", body, &result, opts); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SubmitBatch calls POST /survey/submit/batch: submit many survey responses at once
+//
+// The body is a JSON array of responses, or NDJSON with one response per line.
+func (c *Client) SubmitBatch(ctx context.Context, body []SubmitRequest, opts ...RequestOption) (*BatchResult, error) {
+	var result BatchResult
+	if err := c.do(ctx, "POST", "/survey/submit/batch", body, &result, opts); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetReport calls GET /survey/{id}/report: get the latest report of a survey
+func (c *Client) GetReport(ctx context.Context, id string, opts ...RequestOption) (*Report, error) {
+	var result Report
+	if err := c.do(ctx, "GET", "/survey/"+url.PathEscape(id)+"/report", nil, &result, opts); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListJobs calls GET /survey/{id}/jobs: list the report jobs of a survey
+func (c *Client) ListJobs(ctx context.Context, id string, opts ...RequestOption) (*JobList, error) {
+	var result JobList
+	if err := c.do(ctx, "GET", "/survey/"+url.PathEscape(id)+"/jobs", nil, &result, opts); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// StreamEvents calls GET /survey/{id}/events: stream live updates of a survey
+func (c *Client) StreamEvents(ctx context.Context, id string, opts ...RequestOption) (io.ReadCloser, error) {
+	return c.open(ctx, "GET", "/survey/"+url.PathEscape(id)+"/events", nil, opts)
+}
+
+// ExportReportCSV calls GET /survey/{id}/report.csv: export the latest report as CSV
+func (c *Client) ExportReportCSV(ctx context.Context, id string, opts ...RequestOption) (io.ReadCloser, error) {
+	return c.open(ctx, "GET", "/survey/"+url.PathEscape(id)+"/report.csv", nil, opts)
+}
+
+// ExportReportXLSX calls GET /survey/{id}/report.xlsx: export the latest report and the responses as an XLSX workbook
+func (c *Client) ExportReportXLSX(ctx context.Context, id string, opts ...RequestOption) (io.ReadCloser, error) {
+	return c.open(ctx, "GET", "/survey/"+url.PathEscape(id)+"/report.xlsx", nil, opts)
+}
+
+// ExportResponsesCSV calls GET /survey/{id}/responses.csv: export the responses of a survey as CSV
+func (c *Client) ExportResponsesCSV(ctx context.Context, id string, opts ...RequestOption) (io.ReadCloser, error) {
+	return c.open(ctx, "GET", "/survey/"+url.PathEscape(id)+"/responses.csv", nil, opts)
+}
+
+// ExportResponsesJSONL calls GET /survey/{id}/responses.jsonl: export the responses of a survey as JSON Lines
+func (c *Client) ExportResponsesJSONL(ctx context.Context, id string, opts ...RequestOption) (io.ReadCloser, error) {
+	return c.open(ctx, "GET", "/survey/"+url.PathEscape(id)+"/responses.jsonl", nil, opts)
+}
+
+// DownloadReportHTML calls GET /survey/{id}/report.html: download the latest report as an HTML document
+func (c *Client) DownloadReportHTML(ctx context.Context, id string, opts ...RequestOption) (io.ReadCloser, error) {
+	return c.open(ctx, "GET", "/survey/"+url.PathEscape(id)+"/report.html", nil, opts)
+}
+
+// DownloadReportPDF calls GET /survey/{id}/report.pdf: download the latest report as a PDF document
+func (c *Client) DownloadReportPDF(ctx context.Context, id string, opts ...RequestOption) (io.ReadCloser, error) {
+	return c.open(ctx, "GET", "/survey/"+url.PathEscape(id)+"/report.pdf", nil, opts)
+}
+
+// ListWebhooks calls GET /survey/{id}/webhooks: list the webhooks of a survey
+func (c *Client) ListWebhooks(ctx context.Context, id string, opts ...RequestOption) (*WebhookList, error) {
+	var result WebhookList
+	if err := c.do(ctx, "GET", "/survey/"+url.PathEscape(id)+"/webhooks", nil, &result, opts); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RegisterWebhook calls POST /survey/{id}/webhooks: register a webhook for a survey
+func (c *Client) RegisterWebhook(ctx context.Context, id string, body *RegisterWebhookRequest, opts ...RequestOption) (*Webhook, error) {
+	var result Webhook
+	if err := c.do(ctx, "POST", "/survey/"+url.PathEscape(id)+"/webhooks", body, &result, opts); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteWebhook calls DELETE /survey/{id}/webhooks/{webhookID}: delete a webhook
+func (c *Client) DeleteWebhook(ctx context.Context, id string, webhookID string, opts ...RequestOption) error {
+	return c.do(ctx, "DELETE", "/survey/"+url.PathEscape(id)+"/webhooks/"+url.PathEscape(webhookID), nil, nil, opts)
+}
+
+// ListWebhookDeliveries calls GET /survey/{id}/webhooks/deliveries: list the webhook delivery log of a survey
+func (c *Client) ListWebhookDeliveries(ctx context.Context, id string, opts ...RequestOption) (*DeliveryList, error) {
+	var result DeliveryList
+	if err := c.do(ctx, "GET", "/survey/"+url.PathEscape(id)+"/webhooks/deliveries", nil, &result, opts); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListSchedules calls GET /survey/{id}/schedules: list the report schedules of a survey
+func (c *Client) ListSchedules(ctx context.Context, id string, opts ...RequestOption) (*ScheduleList, error) {
+	var result ScheduleList
+	if err := c.do(ctx, "GET", "/survey/"+url.PathEscape(id)+"/schedules", nil, &result, opts); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CreateSchedule calls POST /survey/{id}/schedules: schedule report generation for a survey
+func (c *Client) CreateSchedule(ctx context.Context, id string, body *CreateScheduleRequest, opts ...RequestOption) (*Schedule, error) {
+	var result Schedule
+	if err := c.do(ctx, "POST", "/survey/"+url.PathEscape(id)+"/schedules", body, &result, opts); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteSchedule calls DELETE /survey/{id}/schedules/{scheduleID}: delete a report schedule
+func (c *Client) DeleteSchedule(ctx context.Context, id string, scheduleID string, opts ...RequestOption) error {
+	return c.do(ctx, "DELETE", "/survey/"+url.PathEscape(id)+"/schedules/"+url.PathEscape(scheduleID), nil, nil, opts)
+}
+
+// ListAPIKeys calls GET /keys: list the API keys
+func (c *Client) ListAPIKeys(ctx context.Context, opts ...RequestOption) (*APIKeyList, error) {
+	var result APIKeyList
+	if err := c.do(ctx, "GET", "/keys", nil, &result, opts); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CreateAPIKey calls POST /keys: create an API key
+//
+// The key is bound to the tenant of the request. Keys that may act for any tenant are created with the apikey command.
+func (c *Client) CreateAPIKey(ctx context.Context, body *CreateAPIKeyRequest, opts ...RequestOption) (*CreatedAPIKey, error) {
+	var result CreatedAPIKey
+	if err := c.do(ctx, "POST", "/keys", body, &result, opts); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RevokeAPIKey calls DELETE /keys/{keyID}: revoke an API key
+func (c *Client) RevokeAPIKey(ctx context.Context, keyID string, opts ...RequestOption) error {
+	return c.do(ctx, "DELETE", "/keys/"+url.PathEscape(keyID), nil, nil, opts)
+}
@@ -0,0 +1,157 @@
+// Package client is a Go client of the survey API. The types and methods in
+// client.gen.go are generated from the API's OpenAPI specification; this file
+// holds the transport they share.
+//
+//	c := client.New("https://surveys.example.com/api/v1", client.WithAPIKey(key))
+//	result, err := c.SubmitResponse(ctx, &client.SubmitRequest{SurveyID: "s1", Answers: answers})
+//
+// Errors answered by the API are returned as a *Problem
+package client
+
+//go:generate go run ../cmd/clientgen -spec ../internal/delivery/http/openapi.json -out client.gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodyBytes caps how much of an error response that is not a problem
+// is kept as its detail
+const maxErrorBodyBytes = 4096
+
+// Client calls the survey API
+type Client struct {
+	// BaseURL is the URL the API is served under, such as https://surveys.example.com/api/v1
+	BaseURL string
+
+	// HTTPClient sends the requests, http.DefaultClient if nil
+	HTTPClient *http.Client
+
+	// Options are applied to every request, before the options of the call
+	Options []RequestOption
+}
+
+// New creates a client of the API served under baseURL, applying options to every request
+func New(baseURL string, options ...RequestOption) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), Options: options}
+}
+
+// RequestOption changes a request before it is sent
+type RequestOption func(*http.Request)
+
+// WithHeader sets a request header
+func WithHeader(name, value string) RequestOption {
+	return func(r *http.Request) {
+		r.Header.Set(name, value)
+	}
+}
+
+// WithAPIKey authenticates requests with an API key
+func WithAPIKey(key string) RequestOption {
+	return WithHeader("X-API-Key", key)
+}
+
+// WithBearerToken authenticates requests with a JWT or an API key sent as a bearer token
+func WithBearerToken(token string) RequestOption {
+	return WithHeader("Authorization", "Bearer "+token)
+}
+
+// Error returns the problem's code and detail
+func (p *Problem) Error() string {
+	if p.Detail == "" {
+		return fmt.Sprintf("survey API: %d %s", p.Status, p.Code)
+	}
+	return fmt.Sprintf("survey API: %d %s: %s", p.Status, p.Code, p.Detail)
+}
+
+// do sends a request with body encoded as JSON, if not nil, and decodes the
+// JSON response into result, if not nil
+func (c *Client) do(ctx context.Context, method, path string, body, result interface{}, opts []RequestOption) error {
+	response, err := c.send(ctx, method, path, body, opts)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response of %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// open sends a request like do and returns the body of the response, which
+// the caller must close
+func (c *Client) open(ctx context.Context, method, path string, body interface{}, opts []RequestOption) (io.ReadCloser, error) {
+	response, err := c.send(ctx, method, path, body, opts)
+	if err != nil {
+		return nil, err
+	}
+	return response.Body, nil
+}
+
+// send sends a request and returns its successful response, or the problem
+// the API answered with
+func (c *Client) send(ctx context.Context, method, path string, body interface{}, opts []RequestOption) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request of %s %s: %w", method, path, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	for _, opt := range c.Options {
+		opt(request)
+	}
+	for _, opt := range opts {
+		opt(request)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return response, nil
+	}
+	defer response.Body.Close()
+	return nil, readProblem(response)
+}
+
+// readProblem returns the problem an error response carries, or one made up
+// from its status when it carries none
+func readProblem(response *http.Response) *Problem {
+	data, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBodyBytes))
+	mediaType, _, _ := mime.ParseMediaType(response.Header.Get("Content-Type"))
+
+	var problem Problem
+	if mediaType != "application/problem+json" || json.Unmarshal(data, &problem) != nil {
+		problem = Problem{Title: http.StatusText(response.StatusCode), Detail: strings.TrimSpace(string(data))}
+	}
+	problem.Status = response.StatusCode
+	if problem.TraceID == "" {
+		problem.TraceID = response.Header.Get("X-Trace-ID")
+	}
+	return &problem
+}
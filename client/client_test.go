@@ -0,0 +1,105 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rfanazhari/distributed-queue-processor/client"
+)
+
+func TestClient_SendsRequestsAndDecodesResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/v1/survey/submit" {
+			t.Errorf("Expected POST /api/v1/survey/submit, got %s %s", r.Method, r.URL.Path)
+		}
+		for header, want := range map[string]string{
+			"Content-Type":    "application/json",
+			"X-API-Key":       "sk_test",
+			"X-Tenant-ID":     "acme",
+			"Idempotency-Key": "retry-1",
+		} {
+			if got := r.Header.Get(header); got != want {
+				t.Errorf("Expected %s=%s, got %s", header, want, got)
+			}
+		}
+		var request client.SubmitRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.SurveyID != "s1" || request.Answers["q1"] != "yes" {
+			t.Errorf("Expected the submitted response in the body, got %+v (%v)", request, err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"message": "Response submitted successfully", "id": "r1"}`))
+	}))
+	defer server.Close()
+
+	c := client.New(server.URL+"/api/v1/", client.WithAPIKey("sk_test"), client.WithTenantID("acme"))
+	result, err := c.SubmitResponse(context.Background(),
+		&client.SubmitRequest{SurveyID: "s1", Answers: map[string]interface{}{"q1": "yes"}},
+		client.WithIdempotencyKey("retry-1"))
+	if err != nil {
+		t.Fatalf("Failed to submit response: %v", err)
+	}
+	if result.ID != "r1" {
+		t.Errorf("Expected ID=r1, got %s", result.ID)
+	}
+}
+
+func TestClient_EscapesPathParameters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/survey/a%2Fb/report.csv" {
+			t.Errorf("Expected the survey ID to be escaped, got %s", r.URL.EscapedPath())
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Write([]byte("question,answered\n"))
+	}))
+	defer server.Close()
+
+	body, err := client.New(server.URL).ExportReportCSV(context.Background(), "a/b")
+	if err != nil {
+		t.Fatalf("Failed to export report: %v", err)
+	}
+	defer body.Close()
+	data, _ := io.ReadAll(body)
+	if string(data) != "question,answered\n" {
+		t.Errorf("Expected the CSV body, got %q", data)
+	}
+}
+
+func TestClient_ReturnsProblems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/survey/s1/report":
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"type": "urn:survey-api:problem:not_found", "title": "Not Found", "status": 404, "code": "not_found", "detail": "report of survey s1: not found", "trace_id": "abc"}`))
+		default:
+			w.Header().Set("X-Trace-ID", "def")
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+	c := client.New(server.URL)
+
+	_, err := c.GetReport(context.Background(), "s1")
+	var problem *client.Problem
+	if !errors.As(err, &problem) {
+		t.Fatalf("Expected a *Problem, got %v", err)
+	}
+	if problem.Status != 404 || problem.Code != "not_found" || problem.TraceID != "abc" {
+		t.Errorf("Expected the decoded problem, got %+v", problem)
+	}
+
+	err = c.DeleteWebhook(context.Background(), "s1", "w1")
+	if !errors.As(err, &problem) {
+		t.Fatalf("Expected a *Problem, got %v", err)
+	}
+	if problem.Status != 502 || problem.Code != "" || problem.Detail != "bad gateway" || problem.TraceID != "def" {
+		t.Errorf("Expected a problem made up from the response, got %+v", problem)
+	}
+}
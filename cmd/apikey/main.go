@@ -0,0 +1,97 @@
+// Command apikey creates, lists and revokes API keys directly in the
+// configured store, which is how the first admin key is created.
+//
+// Usage:
+//
+//	apikey [-config file] create -name ops -scopes admin
+//	apikey [-config file] create -name acme-ingest -scopes submit -tenant acme
+//	apikey [-config file] list
+//	apikey [-config file] revoke -id 01J...
+//
+// It connects to the backends selected by the same config file and
+// environment variables as the server (MODE, REDIS_ADDR, DATA_PATH, ...)
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/bootstrap"
+	"github.com/rfanazhari/distributed-queue-processor/internal/config"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+func main() {
+	configPath := flag.String("config", "", "path of the server's YAML or TOML config file (env "+config.FileEnv+")")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-config file] create|list|revoke [flags]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	command := flag.NewFlagSet(flag.Arg(0), flag.ExitOnError)
+	name := command.String("name", "", "name describing who or what uses the key (create)")
+	scopes := command.String("scopes", "", "comma-separated scopes to grant: submit, read-report or admin (create)")
+	tenant := command.String("tenant", "", "tenant to bind the key to, empty for a key that may act for any tenant (create)")
+	id := command.String("id", "", "ID of the key to revoke (revoke)")
+	command.Parse(flag.Args()[1:])
+
+	cfg, err := config.Load(*configPath, nil)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	repos, err := bootstrap.OpenRepositories(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize repositories: %v", err)
+	}
+	defer repos.Close()
+
+	idGenerator, err := bootstrap.NewIDGenerator(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize ID generator: %v", err)
+	}
+	authUseCase := usecase.NewAuthUseCase(repos.APIKey, nil, idGenerator)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	switch flag.Arg(0) {
+	case "create":
+		apiKey, key, err := authUseCase.CreateAPIKey(ctx, *name, *tenant, config.SplitList(*scopes))
+		if err != nil {
+			log.Fatalf("Failed to create API key: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Created API key %s; store it now, it cannot be shown again\n", apiKey.ID)
+		fmt.Println(key)
+	case "list":
+		keys, err := authUseCase.ListAPIKeys(ctx)
+		if err != nil {
+			log.Fatalf("Failed to list API keys: %v", err)
+		}
+		for i := range keys {
+			keys[i].Hash = ""
+		}
+		encoder.Encode(keys)
+	case "revoke":
+		if err := authUseCase.RevokeAPIKey(ctx, *id); err != nil {
+			log.Fatalf("Failed to revoke API key %q: %v", *id, err)
+		}
+		fmt.Fprintf(os.Stderr, "Revoked API key %s\n", *id)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
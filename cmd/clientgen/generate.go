@@ -0,0 +1,296 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// methods lists the HTTP methods operations are generated for, in the order
+// they are generated within a path
+var methods = []string{"get", "post", "put", "patch", "delete"}
+
+// initialisms are the words written in capitals in Go names
+var initialisms = map[string]bool{
+	"api": true, "csv": true, "html": true, "http": true, "id": true,
+	"json": true, "jsonl": true, "pdf": true, "url": true, "xlsx": true,
+}
+
+// pathParamPattern matches a parameter in a path template
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// generate returns the Go source of a client of the API the specification
+// describes, as a file of the given package
+func generate(data []byte, pkg string) ([]byte, error) {
+	var doc spec
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse specification: %w", err)
+	}
+
+	g := &generator{doc: &doc}
+	g.printf("// Code generated by clientgen from the survey API's OpenAPI specification. DO NOT EDIT.\n\n")
+	g.printf("package %s\n\n", pkg)
+	g.printf("import (\n\t\"context\"\n\t\"io\"\n\t\"net/url\"\n)\n")
+
+	for _, name := range doc.Components.Schemas.Keys {
+		if err := g.schemaType(name, doc.Components.Schemas.Values[name]); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+	}
+	for _, name := range doc.Components.Parameters.Keys {
+		g.headerOption(name, doc.Components.Parameters.Values[name])
+	}
+	for _, path := range doc.Paths.Keys {
+		item := doc.Paths.Values[path]
+		for _, method := range methods {
+			op, ok := item.Values[method]
+			if !ok {
+				continue
+			}
+			if err := g.operation(method, path, op); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+			}
+		}
+	}
+
+	source, err := format.Source([]byte(g.buf.String()))
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid Go: %w", err)
+	}
+	return source, nil
+}
+
+// generator writes the client's source
+type generator struct {
+	doc *spec
+	buf strings.Builder
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// comment writes text as a comment, starting with the given prefix
+func (g *generator) comment(indent, prefix, text string) {
+	if text == "" {
+		return
+	}
+	for i, line := range strings.Split(prefix+text, "\n") {
+		if i > 0 && line == "" {
+			g.printf("%s//\n", indent)
+			continue
+		}
+		g.printf("%s// %s\n", indent, line)
+	}
+}
+
+// schemaType writes the Go type of a component schema: a struct for an
+// object, or a string type with a constant per value for a string enum
+func (g *generator) schemaType(name string, s *schema) error {
+	g.printf("\n")
+	switch {
+	case s.Type == "object":
+		g.comment("", name+" is ", lowerFirst(s.Description))
+		g.printf("type %s struct {\n", name)
+		for _, property := range s.Properties.Keys {
+			field := s.Properties.Values[property]
+			required := slices.Contains(s.Required, property)
+			fieldType, err := g.goType(field, !required)
+			if err != nil {
+				return fmt.Errorf("property %s: %w", property, err)
+			}
+			tag := property
+			if !required {
+				tag += ",omitempty"
+			}
+			g.comment("\t", "", field.Description)
+			g.printf("\t%s %s `json:%s`\n", goName(property), fieldType, strconv.Quote(tag))
+		}
+		g.printf("}\n")
+	case s.Type == "string" && len(s.Enum) > 0:
+		g.comment("", name+" is ", lowerFirst(s.Description))
+		g.printf("type %s string\n\n// Values of %s\nconst (\n", name, name)
+		for _, value := range s.Enum {
+			g.printf("\t%s%s %s = %q\n", name, goName(value), name, value)
+		}
+		g.printf(")\n")
+	default:
+		return fmt.Errorf("only objects and string enums can be generated")
+	}
+	return nil
+}
+
+// headerOption writes a request option setting a header parameter
+func (g *generator) headerOption(name string, p *parameter) {
+	if p.In != "header" {
+		return
+	}
+	arg := lowerFirst(name)
+	g.printf("\n")
+	g.comment("", "With"+name+" sets the "+p.Name+" header: ", lowerFirst(p.Description))
+	g.printf("func With%s(%s string) RequestOption {\n\treturn WithHeader(%q, %s)\n}\n", name, arg, p.Name, arg)
+}
+
+// goType returns the Go type of a schema; optional objects are pointers so
+// they can be left out
+func (g *generator) goType(s *schema, optional bool) (string, error) {
+	if s.Ref != "" {
+		name := refName(s.Ref)
+		target, ok := g.doc.Components.Schemas.Values[name]
+		if !ok {
+			return "", fmt.Errorf("unknown schema %s", s.Ref)
+		}
+		if optional && target.Type == "object" {
+			return "*" + name, nil
+		}
+		return name, nil
+	}
+
+	switch s.Type {
+	case "":
+		return "interface{}", nil
+	case "string":
+		return "string", nil
+	case "boolean":
+		return "bool", nil
+	case "number":
+		return "float64", nil
+	case "integer":
+		if s.Format == "int64" {
+			return "int64", nil
+		}
+		return "int", nil
+	case "array":
+		items, err := g.goType(s.Items, false)
+		return "[]" + items, err
+	case "object":
+		if len(s.Properties.Keys) > 0 {
+			return "", fmt.Errorf("objects with properties must be component schemas")
+		}
+		if s.AdditionalProperties == nil {
+			return "map[string]interface{}", nil
+		}
+		values, err := g.goType(s.AdditionalProperties, false)
+		return "map[string]" + values, err
+	default:
+		return "", fmt.Errorf("unsupported type %q", s.Type)
+	}
+}
+
+// operation writes the client method calling an operation
+func (g *generator) operation(method, path string, op *operation) error {
+	if op.OperationID == "" {
+		return fmt.Errorf("operationId is required")
+	}
+	name := upperFirst(op.OperationID)
+
+	// Path parameters become arguments, in the order they appear in the path
+	args := []string{"ctx context.Context"}
+	var pathParts []string
+	rest := path
+	for _, match := range pathParamPattern.FindAllStringSubmatchIndex(path, -1) {
+		offset := len(path) - len(rest)
+		if literal := rest[:match[0]-offset]; literal != "" {
+			pathParts = append(pathParts, strconv.Quote(literal))
+		}
+		arg := path[match[2]:match[3]]
+		args = append(args, arg+" string")
+		pathParts = append(pathParts, "url.PathEscape("+arg+")")
+		rest = path[match[1]:]
+	}
+	if rest != "" {
+		pathParts = append(pathParts, strconv.Quote(rest))
+	}
+	pathExpr := strings.Join(pathParts, "+")
+
+	bodyArg := "nil"
+	if op.RequestBody != nil {
+		media, ok := op.RequestBody.Content.Values["application/json"]
+		if !ok {
+			return fmt.Errorf("request bodies must accept application/json")
+		}
+		bodyType, err := g.goType(media.Schema, false)
+		if err != nil {
+			return fmt.Errorf("request body: %w", err)
+		}
+		if !strings.HasPrefix(bodyType, "[]") {
+			bodyType = "*" + bodyType
+		}
+		args = append(args, "body "+bodyType)
+		bodyArg = "body"
+	}
+	args = append(args, "opts ...RequestOption")
+
+	// The first success response decides what the method returns
+	var success *body
+	for _, status := range op.Responses.Keys {
+		if strings.HasPrefix(status, "2") {
+			success = op.Responses.Values[status]
+			break
+		}
+	}
+	if success == nil {
+		return fmt.Errorf("no success response")
+	}
+
+	g.printf("\n")
+	g.comment("", name+" calls "+strings.ToUpper(method)+" "+path+": ", lowerFirst(op.Summary))
+	if op.Description != "" {
+		g.printf("//\n")
+		g.comment("", "", op.Description)
+	}
+	signature := fmt.Sprintf("func (c *Client) %s(%s)", name, strings.Join(args, ", "))
+	method = strings.ToUpper(method)
+
+	switch media, isJSON := success.Content.Values["application/json"]; {
+	case len(success.Content.Keys) == 0:
+		g.printf("%s error {\n\treturn c.do(ctx, %q, %s, %s, nil, opts)\n}\n", signature, method, pathExpr, bodyArg)
+	case isJSON:
+		if media.Schema.Ref == "" {
+			return fmt.Errorf("JSON responses must be component schemas")
+		}
+		result := refName(media.Schema.Ref)
+		g.printf("%s (*%s, error) {\n\tvar result %s\n", signature, result, result)
+		g.printf("\tif err := c.do(ctx, %q, %s, %s, &result, opts); err != nil {\n\t\treturn nil, err\n\t}\n", method, pathExpr, bodyArg)
+		g.printf("\treturn &result, nil\n}\n")
+	default:
+		g.printf("%s (io.ReadCloser, error) {\n\treturn c.open(ctx, %q, %s, %s, opts)\n}\n", signature, method, pathExpr, bodyArg)
+	}
+	return nil
+}
+
+// goName returns the exported Go name of a JSON property or enum value,
+// such as StdDev for std_dev and ReadReport for read-report
+func goName(s string) string {
+	var name strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if initialisms[word] {
+			name.WriteString(strings.ToUpper(word))
+		} else {
+			name.WriteString(upperFirst(word))
+		}
+	}
+	return name.String()
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// lowerFirst lowers the first letter of s unless it starts an initialism such as RFC
+func lowerFirst(s string) string {
+	if len(s) < 2 || unicode.IsUpper(rune(s[1])) {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestGenerate_ClientIsUpToDate(t *testing.T) {
+	spec, err := os.ReadFile("../../internal/delivery/http/openapi.json")
+	if err != nil {
+		t.Fatalf("Failed to read specification: %v", err)
+	}
+	want, err := generate(spec, "client")
+	if err != nil {
+		t.Fatalf("Failed to generate client: %v", err)
+	}
+
+	got, err := os.ReadFile("../../client/client.gen.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated client: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("Expected client/client.gen.go to match the specification; run go generate ./client")
+	}
+}
+
+func TestGoName(t *testing.T) {
+	for name, want := range map[string]string{
+		"survey_id":   "SurveyID",
+		"std_dev":     "StdDev",
+		"read-report": "ReadReport",
+		"api_keys":    "APIKeys",
+		"p90":         "P90",
+	} {
+		if got := goName(name); got != want {
+			t.Errorf("Expected goName(%q)=%s, got %s", name, want, got)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Command clientgen generates the Go client of the survey API from its
+// OpenAPI specification. It is run by go generate in the client package:
+//
+//	go generate ./client
+//
+// Only the parts of OpenAPI the specification uses are supported: object and
+// string enum component schemas, path and header parameters, JSON request
+// bodies, and JSON, empty or streamed responses.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+)
+
+func main() {
+	specPath := flag.String("spec", "openapi.json", "path of the OpenAPI specification")
+	outPath := flag.String("out", "client.gen.go", "path of the Go file to write")
+	pkg := flag.String("package", "client", "package of the generated code")
+	flag.Parse()
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatalf("Failed to read specification: %v", err)
+	}
+	source, err := generate(data, *pkg)
+	if err != nil {
+		log.Fatalf("Failed to generate client: %v", err)
+	}
+	if err := os.WriteFile(*outPath, source, 0o644); err != nil {
+		log.Fatalf("Failed to write client: %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// spec is the part of an OpenAPI 3.1 document the client is generated from
+type spec struct {
+	Paths      ordered[ordered[*operation]] `json:"paths"`
+	Components struct {
+		Schemas    ordered[*schema]    `json:"schemas"`
+		Parameters ordered[*parameter] `json:"parameters"`
+	} `json:"components"`
+}
+
+// operation is an endpoint of the API
+type operation struct {
+	OperationID string         `json:"operationId"`
+	Summary     string         `json:"summary"`
+	Description string         `json:"description"`
+	Parameters  []*parameter   `json:"parameters"`
+	RequestBody *body          `json:"requestBody"`
+	Responses   ordered[*body] `json:"responses"`
+}
+
+// parameter is a path or header parameter, or a reference to one
+type parameter struct {
+	Ref         string  `json:"$ref"`
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required"`
+	Description string  `json:"description"`
+	Schema      *schema `json:"schema"`
+}
+
+// body is a request or response body, or a reference to a response
+type body struct {
+	Ref         string              `json:"$ref"`
+	Description string              `json:"description"`
+	Content     ordered[*mediaType] `json:"content"`
+}
+
+// mediaType is one representation of a body
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+// schema is a JSON schema, or a reference to a component schema
+type schema struct {
+	Ref                  string           `json:"$ref"`
+	Type                 string           `json:"type"`
+	Format               string           `json:"format"`
+	Description          string           `json:"description"`
+	Properties           ordered[*schema] `json:"properties"`
+	Required             []string         `json:"required"`
+	Items                *schema          `json:"items"`
+	AdditionalProperties *schema          `json:"additionalProperties"`
+	Enum                 []string         `json:"enum"`
+}
+
+// refName returns the name of the component a reference points to
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// ordered is a JSON object decoded with its keys in document order, so the
+// generated code follows the order of the specification
+type ordered[T any] struct {
+	Keys   []string
+	Values map[string]T
+}
+
+// UnmarshalJSON decodes a JSON object, remembering the order of its keys
+func (o *ordered[T]) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return fmt.Errorf("expected a JSON object")
+	}
+
+	o.Keys, o.Values = nil, make(map[string]T)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key := token.(string)
+		var value T
+		if err := decoder.Decode(&value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		o.Keys = append(o.Keys, key)
+		o.Values[key] = value
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxReportedRejections bounds how many rejection reasons the summary lists
+	maxReportedRejections = 20
+
+	// checkpointInterval is how often the resume offset is written to the checkpoint file
+	checkpointInterval = time.Second
+)
+
+// importer submits records concurrently with rate limiting and checkpointing
+type importer struct {
+	sink        sink
+	concurrency int
+	// rate is the maximum number of submissions per second, zero for unlimited
+	rate       float64
+	retries    int
+	keyPrefix  string
+	checkpoint string
+}
+
+// result is the outcome of one submitted record
+type result struct {
+	offset  int
+	outcome outcome
+	err     error
+}
+
+// summary totals the outcomes of an import run
+type summary struct {
+	Read       int
+	Accepted   int
+	Duplicates int
+	Rejected   int
+	Failed     int
+	// ResumeOffset is the first offset that has not been imported successfully
+	ResumeOffset int
+	Rejections   []string
+	Elapsed      time.Duration
+}
+
+// run imports every record from the reader starting at the given offset
+// It stops early, leaving the remaining records pending, when the sink aborts
+func (im *importer) run(ctx context.Context, reader recordReader, start int) (summary, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	began := time.Now()
+	records := make(chan record)
+	results := make(chan result)
+
+	// Start the submission workers
+	var wg sync.WaitGroup
+	for i := 0; i < im.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rec := range records {
+				results <- im.submit(ctx, rec)
+			}
+		}()
+	}
+
+	// Feed records to the workers at the configured rate
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(records)
+		readErr <- im.feed(ctx, reader, start, records, results)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Tally outcomes and track the contiguous prefix of completed records
+	sum := summary{ResumeOffset: start}
+	var abortErr error
+	done := make(map[int]bool)
+	lastCheckpoint := time.Now()
+	for res := range results {
+		sum.Read++
+		switch res.outcome {
+		case outcomeAccepted:
+			sum.Accepted++
+		case outcomeDuplicate:
+			sum.Duplicates++
+		case outcomeRejected:
+			sum.Rejected++
+			if len(sum.Rejections) < maxReportedRejections {
+				sum.Rejections = append(sum.Rejections, fmt.Sprintf("offset %d: %v", res.offset, res.err))
+			}
+		case outcomeFailed:
+			sum.Failed++
+			fmt.Fprintf(os.Stderr, "offset %d failed: %v\n", res.offset, res.err)
+		case outcomeAborted:
+			sum.Failed++
+			if abortErr == nil {
+				abortErr = fmt.Errorf("offset %d: %w", res.offset, res.err)
+				cancel()
+			}
+		}
+
+		// A failed record stays pending so a resumed run retries it
+		if res.outcome != outcomeFailed && res.outcome != outcomeAborted {
+			done[res.offset] = true
+		}
+		for done[sum.ResumeOffset] {
+			delete(done, sum.ResumeOffset)
+			sum.ResumeOffset++
+		}
+
+		if time.Since(lastCheckpoint) >= checkpointInterval {
+			im.saveCheckpoint(sum.ResumeOffset)
+			lastCheckpoint = time.Now()
+		}
+	}
+	im.saveCheckpoint(sum.ResumeOffset)
+
+	sum.Elapsed = time.Since(began)
+	err := <-readErr
+	if abortErr != nil {
+		err = abortErr
+	}
+	return sum, err
+}
+
+// feed sends records from the reader to the workers, skipping those before start
+// Records that failed to parse are reported as rejected without being submitted
+func (im *importer) feed(ctx context.Context, reader recordReader, start int, records chan<- record, results chan<- result) error {
+	var tick <-chan time.Time
+	if im.rate > 0 {
+		// Rates above one per nanosecond would round the interval down to zero, which tickers reject
+		ticker := time.NewTicker(max(time.Duration(float64(time.Second)/im.rate), time.Nanosecond))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		rec, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if rec.Offset < start {
+			continue
+		}
+		if rec.Err != nil {
+			results <- result{offset: rec.Offset, outcome: outcomeRejected, err: rec.Err}
+			continue
+		}
+
+		if tick != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-tick:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case records <- rec:
+		}
+	}
+}
+
+// submit submits a record, retrying transient failures with exponential backoff
+func (im *importer) submit(ctx context.Context, rec record) result {
+	key := im.keyPrefix + strconv.Itoa(rec.Offset)
+	backoff := 200 * time.Millisecond
+
+	for attempt := 0; ; attempt++ {
+		outcome, err := im.sink.Submit(ctx, rec, key)
+		if outcome != outcomeFailed || attempt >= im.retries || ctx.Err() != nil {
+			return result{offset: rec.Offset, outcome: outcome, err: err}
+		}
+
+		select {
+		case <-ctx.Done():
+			return result{offset: rec.Offset, outcome: outcomeFailed, err: ctx.Err()}
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// saveCheckpoint writes the resume offset to the checkpoint file, if one is configured
+func (im *importer) saveCheckpoint(offset int) {
+	if im.checkpoint == "" {
+		return
+	}
+	if err := os.WriteFile(im.checkpoint, []byte(strconv.Itoa(offset)+"\n"), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write checkpoint: %v\n", err)
+	}
+}
+
+// loadCheckpoint reads the resume offset from a checkpoint file
+// Returns zero when the file does not exist
+func loadCheckpoint(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// print writes the summary in a human-readable form
+func (s summary) print(w io.Writer) {
+	fmt.Fprintf(w, "Import finished in %s\n", s.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "  read:       %d\n", s.Read)
+	fmt.Fprintf(w, "  accepted:   %d\n", s.Accepted)
+	fmt.Fprintf(w, "  duplicates: %d\n", s.Duplicates)
+	fmt.Fprintf(w, "  rejected:   %d\n", s.Rejected)
+	fmt.Fprintf(w, "  failed:     %d\n", s.Failed)
+	for _, rejection := range s.Rejections {
+		fmt.Fprintf(w, "  rejected %s\n", rejection)
+	}
+	if s.Failed > 0 {
+		fmt.Fprintf(w, "Resume with -offset %d to retry failed lines\n", s.ResumeOffset)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestImporter_StopsWhenCredentialsAreRefused(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	im := &importer{
+		sink:        &httpSink{client: server.Client(), baseURL: server.URL},
+		concurrency: 1,
+		keyPrefix:   "import:test:",
+	}
+	input := strings.Repeat(`{"survey_id": "s1", "answers": {"color": "red"}}`+"\n", 50)
+	reader, err := newRecordReader("jsonl", strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Failed to create reader: %v", err)
+	}
+
+	sum, err := im.run(context.Background(), reader, 0)
+	if err == nil || !strings.Contains(err.Error(), "HTTP 401") {
+		t.Errorf("Expected the run to stop with the refused credentials, got %v", err)
+	}
+	if sum.ResumeOffset != 0 || sum.Rejected != 0 {
+		t.Errorf("Expected every line to stay pending, got resume offset %d and %d rejected", sum.ResumeOffset, sum.Rejected)
+	}
+	if sum.Read >= 50 {
+		t.Errorf("Expected the run to stop early, got %d lines read", sum.Read)
+	}
+}
+
+func TestImporter_AcceptsRatesAboveOnePerNanosecond(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	im := &importer{
+		sink:        &httpSink{client: server.Client(), baseURL: server.URL},
+		concurrency: 1,
+		rate:        1e12,
+		keyPrefix:   "import:test:",
+	}
+	reader, err := newRecordReader("jsonl", strings.NewReader(`{"survey_id": "s1", "answers": {"color": "red"}}`+"\n"))
+	if err != nil {
+		t.Fatalf("Failed to create reader: %v", err)
+	}
+
+	sum, err := im.run(context.Background(), reader, 0)
+	if err != nil || sum.Accepted != 1 {
+		t.Errorf("Expected the line to be accepted, got %+v, %v", sum, err)
+	}
+}
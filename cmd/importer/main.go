@@ -0,0 +1,173 @@
+// Command importer bulk-imports survey responses from JSON Lines or CSV files,
+// either directly into the configured store or through a running instance's HTTP API.
+//
+// Usage:
+//
+//	importer -file responses.jsonl [-mode store|http] [-url http://localhost:8080]
+//	         [-concurrency 4] [-rate 0] [-offset 0] [-checkpoint file] [-config file]
+//	         [-api-key key] [-tenant id]
+//
+// In store mode the importer connects to the backends selected by the same
+// config file and environment variables as the server (MODE, REDIS_ADDR,
+// RABBITMQ_URL, DATA_PATH, ...)
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/internal/bootstrap"
+	"github.com/rfanazhari/distributed-queue-processor/internal/config"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+const (
+	// modeStore writes directly to the configured store
+	modeStore = "store"
+
+	// modeHTTP posts to a running instance
+	modeHTTP = "http"
+)
+
+func main() {
+	file := flag.String("file", "", "path of the JSONL or CSV file to import (required)")
+	format := flag.String("format", "", "file format, jsonl or csv (default: from the file extension)")
+	mode := flag.String("mode", modeStore, "store to write directly to the configured store, http to post to a running instance")
+	url := flag.String("url", "http://localhost:8080", "base URL of the instance in http mode")
+	concurrency := flag.Int("concurrency", 4, "number of concurrent submissions")
+	rate := flag.Float64("rate", 0, "maximum submissions per second, 0 for unlimited")
+	retries := flag.Int("retries", 3, "retries per line for transient failures")
+	offset := flag.Int("offset", -1, "zero-based line offset to resume from (default: from the checkpoint file, or 0)")
+	checkpoint := flag.String("checkpoint", "", "file to record the resume offset in while importing")
+	configPath := flag.String("config", "", "path of the server's YAML or TOML config file in store mode (env "+config.FileEnv+")")
+	apiKey := flag.String("api-key", os.Getenv("API_KEY"), "API key with the submit scope in http mode (env API_KEY)")
+	tenant := flag.String("tenant", "", "tenant to import the responses for (default: "+entity.DefaultTenant+" in store mode, the API key's in http mode)")
+	keyPrefix := flag.String("key-prefix", "", "Idempotency-Key prefix so re-runs do not duplicate lines (default: import:<hash of the absolute file path>:)")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *concurrency < 1 {
+		log.Fatalf("-concurrency must be at least 1")
+	}
+	if *rate < 0 {
+		log.Fatalf("-rate must not be negative")
+	}
+	if *tenant != "" && !entity.ValidTenantID(*tenant) {
+		log.Fatalf("Invalid -tenant %q", *tenant)
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+	if *keyPrefix == "" {
+		// Files of the same name in different directories must not share keys
+		path, err := filepath.Abs(*file)
+		if err != nil {
+			log.Fatalf("Failed to resolve file path: %v", err)
+		}
+		*keyPrefix = "import:" + fingerprint(path)[:16] + ":"
+	}
+
+	// Resume from the checkpoint unless an offset was given explicitly
+	start := *offset
+	if start < 0 {
+		start = 0
+		if *checkpoint != "" {
+			saved, err := loadCheckpoint(*checkpoint)
+			if err != nil {
+				log.Fatalf("Failed to read checkpoint: %v", err)
+			}
+			start = saved
+		}
+	}
+
+	// Cancel the import on SIGINT or SIGTERM; the checkpoint records how far it got
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	reader, err := newRecordReader(*format, f)
+	if err != nil {
+		log.Fatalf("Failed to read file: %v", err)
+	}
+
+	var s sink
+	switch *mode {
+	case modeStore:
+		cfg, err := config.Load(*configPath, nil)
+		if err != nil {
+			log.Fatalf("Failed to load configuration: %v", err)
+		}
+
+		repos, err := bootstrap.OpenRepositories(ctx, cfg)
+		if err != nil {
+			log.Fatalf("Failed to initialize repositories: %v", err)
+		}
+		defer repos.Close()
+
+		idGenerator, err := bootstrap.NewIDGenerator(cfg)
+		if err != nil {
+			log.Fatalf("Failed to initialize ID generator: %v", err)
+		}
+		reportSettings := bootstrap.NewReportSettings(cfg)
+		if *tenant == "" {
+			*tenant = entity.DefaultTenant
+		}
+
+		s = &storeSink{
+			reportUseCase:      usecase.NewReportUseCase(repos.Lock, repos.Queue, repos.Response, repos.Report, repos.Job, reportSettings),
+			idempotencyUseCase: usecase.NewIdempotencyUseCase(repos.Idempotency),
+			quotaUseCase:       usecase.NewQuotaUseCase(repos.Quota, bootstrap.NewQuotaSettings(cfg)),
+			idGenerator:        idGenerator,
+			tenantID:           *tenant,
+			maxAnswers:         cfg.HTTP.MaxAnswers,
+			maxAnswerDepth:     cfg.HTTP.MaxAnswerDepth,
+		}
+	case modeHTTP:
+		s = &httpSink{
+			client:   &http.Client{Timeout: 30 * time.Second},
+			baseURL:  *url,
+			apiKey:   *apiKey,
+			tenantID: *tenant,
+		}
+	default:
+		log.Fatalf("Unknown -mode %q, expected %q or %q", *mode, modeStore, modeHTTP)
+	}
+
+	im := &importer{
+		sink:        s,
+		concurrency: *concurrency,
+		rate:        *rate,
+		retries:     *retries,
+		keyPrefix:   *keyPrefix,
+		checkpoint:  *checkpoint,
+	}
+
+	log.Printf("Importing %s (%s) from offset %d in %s mode", *file, *format, start, *mode)
+	sum, err := im.run(ctx, reader, start)
+	sum.print(os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Import stopped early: %v\n", err)
+		os.Exit(1)
+	}
+	if sum.Failed > 0 {
+		os.Exit(1)
+	}
+}
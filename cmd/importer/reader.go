@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// formatJSONL reads one {"survey_id": ..., "answers": {...}} object per line
+	formatJSONL = "jsonl"
+
+	// formatCSV reads a header row with a survey_id column; every other column is an answer
+	formatCSV = "csv"
+)
+
+// record is one response read from an import file
+type record struct {
+	// Offset is the zero-based index of the record in the file, excluding any CSV header
+	Offset   int
+	SurveyID string
+	Answers  map[string]interface{}
+	// Raw is the record as it appeared in the file, used to fingerprint retries
+	Raw string
+	// Err is set when the record could not be parsed
+	Err error
+}
+
+// recordReader reads records from an import file one at a time
+type recordReader interface {
+	// Next returns the next record, or io.EOF when the file is exhausted
+	Next() (record, error)
+}
+
+// newRecordReader creates a reader for the given format
+func newRecordReader(format string, r io.Reader) (recordReader, error) {
+	switch format {
+	case formatJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		return &jsonlReader{scanner: scanner}, nil
+	case formatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		header, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV header: %w", err)
+		}
+		surveyColumn := -1
+		for i, column := range header {
+			header[i] = strings.TrimSpace(column)
+			if header[i] == "survey_id" {
+				surveyColumn = i
+			}
+		}
+		if surveyColumn < 0 {
+			return nil, fmt.Errorf("CSV header has no survey_id column")
+		}
+		return &csvReader{reader: reader, header: header, surveyColumn: surveyColumn}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q, expected %q or %q", format, formatJSONL, formatCSV)
+	}
+}
+
+// jsonlReader reads JSON Lines files, skipping blank lines
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	offset  int
+}
+
+// Next returns the next non-blank line as a record
+func (r *jsonlReader) Next() (record, error) {
+	for r.scanner.Scan() {
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		rec := record{Offset: r.offset, Raw: line}
+		r.offset++
+
+		var body struct {
+			SurveyID string                 `json:"survey_id"`
+			Answers  map[string]interface{} `json:"answers"`
+		}
+		if err := json.Unmarshal([]byte(line), &body); err != nil {
+			rec.Err = fmt.Errorf("invalid JSON: %w", err)
+			return rec, nil
+		}
+		rec.SurveyID = body.SurveyID
+		rec.Answers = body.Answers
+		return rec, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return record{}, err
+	}
+	return record{}, io.EOF
+}
+
+// csvReader reads CSV files whose header names the answer columns
+type csvReader struct {
+	reader       *csv.Reader
+	header       []string
+	surveyColumn int
+	offset       int
+}
+
+// Next returns the next CSV row as a record
+// Empty cells are treated as unanswered and numeric cells become numbers
+func (r *csvReader) Next() (record, error) {
+	row, err := r.reader.Read()
+	if err == io.EOF {
+		return record{}, io.EOF
+	}
+
+	rec := record{Offset: r.offset}
+	r.offset++
+	if err != nil {
+		if _, ok := err.(*csv.ParseError); ok {
+			rec.Err = fmt.Errorf("invalid CSV row: %w", err)
+			return rec, nil
+		}
+		return record{}, err
+	}
+
+	rec.Raw = strings.Join(row, ",")
+	if len(row) != len(r.header) {
+		rec.Err = fmt.Errorf("row has %d columns, header has %d", len(row), len(r.header))
+		return rec, nil
+	}
+
+	rec.Answers = make(map[string]interface{})
+	for i, value := range row {
+		if i == r.surveyColumn {
+			rec.SurveyID = strings.TrimSpace(value)
+			continue
+		}
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			rec.Answers[r.header[i]] = number
+			continue
+		}
+		rec.Answers[r.header[i]] = value
+	}
+
+	return rec, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// readAll drains a record reader
+func readAll(t *testing.T, reader recordReader) []record {
+	t.Helper()
+	var records []record
+	for {
+		rec, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("Unexpected read error: %v", err)
+		}
+		records = append(records, rec)
+	}
+}
+
+func TestCSVReader_MapsColumnsToAnswers(t *testing.T) {
+	input := "survey_id,color,rating\nsurvey-1,red,4\nsurvey-2,,5\nsurvey-3,blue\n"
+	reader, err := newRecordReader(formatCSV, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Failed to create reader: %v", err)
+	}
+
+	records := readAll(t, reader)
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+	if records[0].SurveyID != "survey-1" || records[0].Answers["color"] != "red" || records[0].Answers["rating"] != float64(4) {
+		t.Errorf("Unexpected first record: %+v", records[0])
+	}
+	if _, ok := records[1].Answers["color"]; ok {
+		t.Errorf("Expected empty cells to be left unanswered, got %+v", records[1].Answers)
+	}
+	if records[2].Err == nil || records[2].Offset != 2 {
+		t.Errorf("Expected a short row to be reported at offset 2, got %+v", records[2])
+	}
+}
+
+func TestJSONLReader_SkipsBlankLinesAndReportsBadJSON(t *testing.T) {
+	input := "{\"survey_id\":\"survey-1\",\"answers\":{\"q1\":\"a\"}}\n\nnot json\n"
+	reader, err := newRecordReader(formatJSONL, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Failed to create reader: %v", err)
+	}
+
+	records := readAll(t, reader)
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	if records[0].SurveyID != "survey-1" || records[0].Err != nil {
+		t.Errorf("Unexpected first record: %+v", records[0])
+	}
+	if records[1].Offset != 1 || records[1].Err == nil {
+		t.Errorf("Expected invalid JSON at offset 1, got %+v", records[1])
+	}
+}
+
+func TestCSVReader_RequiresSurveyIDColumn(t *testing.T) {
+	if _, err := newRecordReader(formatCSV, strings.NewReader("color,rating\n")); err == nil {
+		t.Errorf("Expected an error for a header without survey_id")
+	}
+}
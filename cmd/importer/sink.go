@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// outcome is the result of submitting one record
+type outcome int
+
+const (
+	// outcomeAccepted means the response was stored
+	outcomeAccepted outcome = iota
+
+	// outcomeDuplicate means the response had already been imported by an earlier run
+	outcomeDuplicate
+
+	// outcomeRejected means the response is invalid and retrying will not help
+	outcomeRejected
+
+	// outcomeFailed means the response could not be stored because of a transient error
+	outcomeFailed
+
+	// outcomeAborted means no response can be stored, such as when the
+	// credentials are refused, so the run stops
+	outcomeAborted
+)
+
+// sink submits records to the service
+type sink interface {
+	// Submit stores the record using key to make retries and re-runs idempotent
+	Submit(ctx context.Context, rec record, key string) (outcome, error)
+}
+
+// storeSink writes directly to the configured store through the use cases,
+// applying the same limits, quota and idempotency key scoping as the API
+type storeSink struct {
+	reportUseCase      usecase.ReportUseCase
+	idempotencyUseCase usecase.IdempotencyUseCase
+	quotaUseCase       usecase.QuotaUseCase
+	idGenerator        usecase.IDGenerator
+	// tenantID is the tenant the records are imported for
+	tenantID       string
+	maxAnswers     int
+	maxAnswerDepth int
+}
+
+// Submit validates and stores the record through the report use case
+func (s *storeSink) Submit(ctx context.Context, rec record, key string) (outcome, error) {
+	ctx = entity.WithTenant(ctx, s.tenantID)
+	response := entity.SurveyResponse{
+		ID:        s.idGenerator.NewID(),
+		SurveyID:  rec.SurveyID,
+		Answers:   rec.Answers,
+		CreatedAt: time.Now().Unix(),
+	}
+	if err := usecase.ValidateResponse(response); err != nil {
+		return outcomeRejected, err
+	}
+	if err := usecase.CheckAnswerLimits(rec.Answers, s.maxAnswers, s.maxAnswerDepth); err != nil {
+		return outcomeRejected, err
+	}
+
+	// Keys are scoped like those of unauthenticated API submissions, so a line
+	// imported in either mode is not imported again in the other
+	key = entity.TenantScopedID(s.tenantID, key)
+	fingerprint := fingerprint(rec.Raw)
+	existing, err := s.idempotencyUseCase.Begin(ctx, key, fingerprint)
+	switch {
+	case errors.Is(err, usecase.ErrIdempotencyKeyReused):
+		return outcomeRejected, fmt.Errorf("line changed since it was first imported: %w", err)
+	case err != nil:
+		return outcomeFailed, err
+	case existing != nil:
+		return outcomeDuplicate, nil
+	}
+
+	// Every further line would exceed the quota too, so the run stops
+	if err := s.quotaUseCase.ConsumeResponses(ctx, 1); err != nil {
+		s.idempotencyUseCase.Abort(ctx, key)
+		if errors.Is(err, usecase.ErrQuotaExceeded) {
+			return outcomeAborted, err
+		}
+		return outcomeFailed, err
+	}
+
+	if err := s.reportUseCase.SubmitResponse(ctx, response); err != nil {
+		s.idempotencyUseCase.Abort(ctx, key)
+		if errors.Is(err, usecase.ErrInvalidResponse) {
+			return outcomeRejected, err
+		}
+		return outcomeFailed, err
+	}
+
+	// Failing to record the key only risks a duplicate on a future re-run,
+	// the response itself is already stored
+	body, _ := json.Marshal(map[string]string{"id": response.ID})
+	s.idempotencyUseCase.Complete(ctx, key, fingerprint, http.StatusAccepted, body)
+
+	return outcomeAccepted, nil
+}
+
+// httpSink posts records to a running instance's submit endpoint
+type httpSink struct {
+	client  *http.Client
+	baseURL string
+	// apiKey authenticates the requests when set
+	apiKey string
+	// tenantID is the tenant the records are imported for
+	tenantID string
+}
+
+// Submit posts the record with an Idempotency-Key header
+func (s *httpSink) Submit(ctx context.Context, rec record, key string) (outcome, error) {
+	if err := usecase.ValidateResponse(entity.SurveyResponse{SurveyID: rec.SurveyID, Answers: rec.Answers}); err != nil {
+		return outcomeRejected, err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"survey_id": rec.SurveyID,
+		"answers":   rec.Answers,
+	})
+	if err != nil {
+		return outcomeRejected, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(s.baseURL, "/")+"/api/v1/survey/submit", bytes.NewReader(body))
+	if err != nil {
+		return outcomeFailed, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", key)
+	if s.apiKey != "" {
+		req.Header.Set("X-API-Key", s.apiKey)
+	}
+	if s.tenantID != "" {
+		req.Header.Set("X-Tenant-ID", s.tenantID)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return outcomeFailed, err
+	}
+	defer resp.Body.Close()
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	// Only a response the service found invalid is skipped; anything else,
+	// conflicts with a concurrent attempt included, stays pending for a retry
+	err = fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		if resp.Header.Get("Idempotent-Replayed") == "true" {
+			return outcomeDuplicate, nil
+		}
+		return outcomeAccepted, nil
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return outcomeRejected, err
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return outcomeAborted, err
+	default:
+		return outcomeFailed, err
+	}
+}
+
+// fingerprint hashes a raw record so a changed line is not mistaken for a retry
+func fingerprint(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
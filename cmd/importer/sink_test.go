@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	bboltRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/bbolt"
+	"github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/idgen"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+func TestHTTPSink_MapsStatusesToOutcomes(t *testing.T) {
+	tests := []struct {
+		status int
+		want   outcome
+	}{
+		{http.StatusAccepted, outcomeAccepted},
+		{http.StatusBadRequest, outcomeRejected},
+		{http.StatusUnprocessableEntity, outcomeRejected},
+		{http.StatusUnauthorized, outcomeAborted},
+		{http.StatusForbidden, outcomeAborted},
+		{http.StatusConflict, outcomeFailed},
+		{http.StatusTooManyRequests, outcomeFailed},
+		{http.StatusInternalServerError, outcomeFailed},
+		{http.StatusServiceUnavailable, outcomeFailed},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			s := &httpSink{client: server.Client(), baseURL: server.URL}
+			rec := record{SurveyID: "s1", Answers: map[string]interface{}{"color": "red"}}
+			if got, _ := s.Submit(context.Background(), rec, "import:test:0"); got != tt.want {
+				t.Errorf("Expected outcome %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+// newTestStoreSink returns a store sink for tenant writing to an embedded store
+func newTestStoreSink(t *testing.T, db *bolt.DB, tenantID string, quota int64) *storeSink {
+	t.Helper()
+	return &storeSink{
+		reportUseCase: usecase.NewReportUseCase(bboltRepo.NewLockRepository(db), bboltRepo.NewQueueRepository(db, time.Second),
+			bboltRepo.NewResponseRepository(db), bboltRepo.NewReportRepository(db), bboltRepo.NewJobRepository(db), usecase.DefaultReportSettings()),
+		idempotencyUseCase: usecase.NewIdempotencyUseCase(bboltRepo.NewIdempotencyRepository(db)),
+		quotaUseCase:       usecase.NewQuotaUseCase(bboltRepo.NewQuotaRepository(db), usecase.QuotaSettings{ResponsesPerDay: quota}),
+		idGenerator:        idgen.NewULIDGenerator(),
+		tenantID:           tenantID,
+		maxAnswers:         2,
+		maxAnswerDepth:     1,
+	}
+}
+
+func TestStoreSink_AppliesTheAPIRules(t *testing.T) {
+	db, err := bboltRepo.Open(filepath.Join(t.TempDir(), "import.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	rec := record{SurveyID: "s1", Answers: map[string]interface{}{"color": "red"}, Raw: `{"survey_id": "s1"}`}
+
+	// Keys are scoped to the tenant, so the same line imports once per tenant
+	acme := newTestStoreSink(t, db, "acme", 1)
+	for _, want := range []outcome{outcomeAccepted, outcomeDuplicate} {
+		if got, err := acme.Submit(ctx, rec, "import:test:0"); got != want {
+			t.Errorf("Expected outcome %d for acme, got %d: %v", want, got, err)
+		}
+	}
+	defaultTenant := newTestStoreSink(t, db, entity.DefaultTenant, 1)
+	if got, err := defaultTenant.Submit(ctx, rec, "import:test:0"); got != outcomeAccepted {
+		t.Errorf("Expected the line to be accepted for the default tenant, got %d: %v", got, err)
+	}
+
+	// acme's quota of one response is used up, which stops the run
+	if got, err := acme.Submit(ctx, rec, "import:test:1"); got != outcomeAborted || !errors.Is(err, usecase.ErrQuotaExceeded) {
+		t.Errorf("Expected the exceeded quota to abort, got %d: %v", got, err)
+	}
+
+	tooMany := record{SurveyID: "s1", Answers: map[string]interface{}{"a": 1, "b": 2, "c": 3}}
+	if got, _ := defaultTenant.Submit(ctx, tooMany, "import:test:2"); got != outcomeRejected {
+		t.Errorf("Expected a line above the answer limit to be rejected, got %d", got)
+	}
+	tooDeep := record{SurveyID: "s1", Answers: map[string]interface{}{"a": []interface{}{[]interface{}{1}}}}
+	if got, _ := defaultTenant.Submit(ctx, tooDeep, "import:test:3"); got != outcomeRejected {
+		t.Errorf("Expected a line above the nesting limit to be rejected, got %d", got)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// generator submits responses at a fixed rate, spreading them round-robin
+// over the survey IDs and the instance URLs
+type generator struct {
+	client      *http.Client
+	urls        []string
+	surveyIDs   []string
+	rate        float64
+	concurrency int
+}
+
+// submission is one response to send
+type submission struct {
+	seq      int
+	url      string
+	surveyID string
+}
+
+// result collects the outcome of a load run
+type result struct {
+	mu sync.Mutex
+
+	// Accepted holds the IDs of the accepted responses per survey ID
+	Accepted map[string][]string
+
+	// Failed is the number of submissions that were not accepted
+	Failed int
+
+	// Latencies holds the round-trip time of every submission
+	Latencies []time.Duration
+
+	// Elapsed is the wall-clock time of the run
+	Elapsed time.Duration
+}
+
+// record adds the outcome of one submission
+func (r *result) record(surveyID, responseID string, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Latencies = append(r.Latencies, latency)
+	if err != nil {
+		r.Failed++
+		return
+	}
+	r.Accepted[surveyID] = append(r.Accepted[surveyID], responseID)
+}
+
+// run submits until ctx is done and returns the collected result
+func (g *generator) run(ctx context.Context) *result {
+	res := &result{Accepted: make(map[string][]string)}
+	work := make(chan submission)
+
+	var wg sync.WaitGroup
+	for i := 0; i < g.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range work {
+				start := time.Now()
+				id, err := g.submit(s)
+				res.record(s.surveyID, id, time.Since(start), err)
+			}
+		}()
+	}
+
+	start := time.Now()
+	// Rates above one per nanosecond would round the interval down to zero, which tickers reject
+	ticker := time.NewTicker(max(time.Duration(float64(time.Second)/g.rate), time.Nanosecond))
+	defer ticker.Stop()
+
+	for seq := 0; ; seq++ {
+		select {
+		case <-ctx.Done():
+			close(work)
+			wg.Wait()
+			res.Elapsed = time.Since(start)
+			return res
+		case <-ticker.C:
+		}
+
+		// Rotate the instance once per round over the surveys so every survey
+		// receives responses through every instance
+		s := submission{
+			seq:      seq,
+			url:      g.urls[(seq/len(g.surveyIDs)+seq)%len(g.urls)],
+			surveyID: g.surveyIDs[seq%len(g.surveyIDs)],
+		}
+		select {
+		case work <- s:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// submit posts one response and returns its ID, or an error unless it was accepted
+func (g *generator) submit(s submission) (string, error) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"survey_id": s.surveyID,
+		"answers": map[string]interface{}{
+			"seq":    s.seq,
+			"rating": s.seq%5 + 1,
+		},
+	})
+
+	resp, err := g.client.Post(s.url+"/api/v1/survey/submit", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		io.Copy(io.Discard, resp.Body)
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	var accepted struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&accepted); err != nil || accepted.ID == "" {
+		return "", fmt.Errorf("accepted without a response ID: %v", err)
+	}
+	return accepted.ID, nil
+}
+
+// print writes the submission counts and latency percentiles
+func (r *result) print(w io.Writer) {
+	accepted := 0
+	for _, ids := range r.Accepted {
+		accepted += len(ids)
+	}
+	total := accepted + r.Failed
+
+	fmt.Fprintf(w, "Submitted:  %d in %s (%.1f/s)\n", total, r.Elapsed.Round(time.Millisecond), float64(total)/r.Elapsed.Seconds())
+	fmt.Fprintf(w, "Accepted:   %d\n", accepted)
+	fmt.Fprintf(w, "Failed:     %d\n", r.Failed)
+
+	sorted := append([]time.Duration(nil), r.Latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	fmt.Fprintf(w, "Latency:    p50=%s p90=%s p99=%s max=%s\n",
+		percentile(sorted, 50), percentile(sorted, 90), percentile(sorted, 99), percentile(sorted, 100))
+}
+
+// percentile returns the nearest-rank percentile p of the sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank].Round(time.Microsecond)
+}
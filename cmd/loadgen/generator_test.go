@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGenerator_AcceptsRatesAboveOnePerNanosecond(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"id": "r1"}`))
+	}))
+	defer server.Close()
+
+	g := &generator{
+		client:      server.Client(),
+		urls:        []string{server.URL},
+		surveyIDs:   []string{"s1"},
+		rate:        1e12,
+		concurrency: 1,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if res := g.run(ctx); len(res.Accepted["s1"]) == 0 {
+		t.Errorf("Expected submissions to be accepted, got %d failed", res.Failed)
+	}
+}
@@ -0,0 +1,117 @@
+// Command loadgen drives survey submissions across one or more running instances
+// and then verifies the debounce invariants from the recorded job and report state.
+//
+// Usage:
+//
+//	loadgen [-urls http://localhost:8080,http://localhost:8081] [-surveys 10]
+//	        [-rate 50] [-duration 1m] [-concurrency 8] [-debounce 30s] [-settle 2m]
+//	        [-api-key key]
+//
+// The invariants checked are:
+//   - at most one report job per survey starts per debounce window
+//   - every accepted response is stored and included in its survey's final report
+//
+// -debounce must match the LOCK_TTL of the instances under test
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/config"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+func main() {
+	urls := flag.String("urls", "http://localhost:8080", "comma-separated base URLs of the instances to submit to")
+	surveys := flag.Int("surveys", 10, "number of distinct survey IDs to spread submissions over")
+	prefix := flag.String("survey-prefix", "", "survey ID prefix (default: loadgen-<unix time>-)")
+	rate := flag.Float64("rate", 50, "total submissions per second")
+	duration := flag.Duration("duration", time.Minute, "how long to submit for")
+	concurrency := flag.Int("concurrency", 8, "number of concurrent submissions")
+	debounce := flag.Duration("debounce", usecase.LockTTL, "debounce window configured on the instances (LOCK_TTL)")
+	tolerance := flag.Duration("tolerance", 100*time.Millisecond, "clock tolerance allowed when comparing job start times")
+	settle := flag.Duration("settle", 0, "how long to wait for final reports after submitting (default: twice -debounce plus 10s)")
+	apiKey := flag.String("api-key", os.Getenv("API_KEY"), "API key with the submit and read-report scopes (env API_KEY)")
+	flag.Parse()
+
+	baseURLs := config.SplitList(*urls)
+	if len(baseURLs) == 0 {
+		log.Fatalf("-urls must list at least one instance")
+	}
+	if *surveys < 1 || *concurrency < 1 || *rate <= 0 {
+		log.Fatalf("-surveys, -concurrency and -rate must be positive")
+	}
+	if *prefix == "" {
+		*prefix = fmt.Sprintf("loadgen-%d-", time.Now().Unix())
+	}
+	if *settle == 0 {
+		*settle = 2**debounce + 10*time.Second
+	}
+
+	surveyIDs := make([]string, *surveys)
+	for i := range surveyIDs {
+		surveyIDs[i] = fmt.Sprintf("%s%d", *prefix, i)
+	}
+	for i, u := range baseURLs {
+		baseURLs[i] = strings.TrimRight(u, "/")
+	}
+
+	// Stop submitting early on SIGINT or SIGTERM but still verify what was sent
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	if *apiKey != "" {
+		client.Transport = apiKeyTransport{key: *apiKey}
+	}
+	gen := &generator{
+		client:      client,
+		urls:        baseURLs,
+		surveyIDs:   surveyIDs,
+		rate:        *rate,
+		concurrency: *concurrency,
+	}
+
+	log.Printf("Submitting to %d instance(s) over %d surveys at %.1f/s for %s", len(baseURLs), len(surveyIDs), *rate, *duration)
+	start := time.Now()
+	runCtx, stop := context.WithTimeout(ctx, *duration)
+	res := gen.run(runCtx)
+	stop()
+	res.print(os.Stdout)
+
+	v := &verifier{
+		client:    client,
+		url:       baseURLs[0],
+		debounce:  *debounce,
+		tolerance: *tolerance,
+		settle:    *settle,
+	}
+
+	log.Printf("Waiting up to %s for final reports", *settle)
+	violations := v.verify(context.Background(), res.Accepted, start)
+	printViolations(os.Stdout, violations)
+	if len(violations) > 0 {
+		os.Exit(1)
+	}
+}
+
+// apiKeyTransport authenticates every request with an API key
+type apiKeyTransport struct {
+	key string
+}
+
+// RoundTrip sends the request with the X-API-Key header set
+func (t apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-API-Key", t.key)
+	return http.DefaultTransport.RoundTrip(req)
+}
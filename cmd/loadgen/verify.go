@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// pollInterval is how often reports are re-fetched while waiting for them to settle
+const pollInterval = time.Second
+
+// violation is one broken invariant
+type violation struct {
+	SurveyID string
+	Message  string
+}
+
+// verifier checks the debounce invariants against the job and report state of an instance
+type verifier struct {
+	client    *http.Client
+	url       string
+	debounce  time.Duration
+	tolerance time.Duration
+	settle    time.Duration
+}
+
+// verify waits for every survey's report to include the responses accepted
+// during the run and checks the jobs the run created, returning every
+// invariant that does not hold
+func (v *verifier) verify(ctx context.Context, accepted map[string][]string, since time.Time) []violation {
+	surveyIDs := make([]string, 0, len(accepted))
+	for id := range accepted {
+		surveyIDs = append(surveyIDs, id)
+	}
+	sort.Strings(surveyIDs)
+
+	var violations []violation
+	deadline := time.Now().Add(v.settle)
+	for _, id := range surveyIDs {
+		if msg := v.waitForReport(ctx, id, accepted[id], deadline); msg != "" {
+			violations = append(violations, violation{SurveyID: id, Message: msg})
+		}
+
+		var jobs struct {
+			Jobs []entity.JobRecord `json:"jobs"`
+		}
+		if err := v.get(ctx, "/api/v1/survey/"+id+"/jobs", &jobs); err != nil {
+			violations = append(violations, violation{SurveyID: id, Message: "failed to list jobs: " + err.Error()})
+			continue
+		}
+		for _, msg := range checkJobWindows(jobs.Jobs, since.Add(-v.tolerance), v.debounce-v.tolerance) {
+			violations = append(violations, violation{SurveyID: id, Message: msg})
+		}
+	}
+
+	return violations
+}
+
+// waitForReport polls the survey's report until it includes every accepted
+// response or the deadline passes, and returns a description of the shortfall
+// if it never does. A report includes an accepted response when the response
+// is stored and the report counts at least every response stored after it was read
+func (v *verifier) waitForReport(ctx context.Context, surveyID string, accepted []string, deadline time.Time) string {
+	msg := ""
+	for {
+		var report entity.Report
+		err := v.get(ctx, "/api/v1/survey/"+surveyID+"/report", &report)
+		switch {
+		case errors.Is(err, errNotFound):
+			msg = fmt.Sprintf("no report counts any of %d accepted responses", len(accepted))
+		case err != nil:
+			msg = "failed to get report: " + err.Error()
+		case report.ResponseCount < len(accepted):
+			msg = fmt.Sprintf("final report counts %d of %d accepted responses", report.ResponseCount, len(accepted))
+		default:
+			// Responses are only appended, so listing them after reading the
+			// report tells whether the report reaches every one of them
+			stored, err := v.storedResponseIDs(ctx, surveyID)
+			if err != nil {
+				msg = "failed to list responses: " + err.Error()
+				break
+			}
+			if missing := missingIDs(accepted, stored); len(missing) > 0 {
+				return fmt.Sprintf("%d accepted response(s) were never stored, e.g. %s", len(missing), missing[0])
+			}
+			if report.ResponseCount >= len(stored) {
+				return ""
+			}
+			msg = fmt.Sprintf("final report counts %d of %d stored responses", report.ResponseCount, len(stored))
+		}
+
+		if time.Now().After(deadline) {
+			return msg
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err().Error()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// storedResponseIDs returns the set of IDs of the responses stored for the survey
+func (v *verifier) storedResponseIDs(ctx context.Context, surveyID string) (map[string]bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.url+"/api/v1/survey/"+surveyID+"/responses.jsonl", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+	}
+
+	ids := make(map[string]bool)
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var response entity.SurveyResponse
+		if err := dec.Decode(&response); err == io.EOF {
+			return ids, nil
+		} else if err != nil {
+			return nil, err
+		}
+		ids[response.ID] = true
+	}
+}
+
+// missingIDs returns the IDs of accepted that are not in stored
+func missingIDs(accepted []string, stored map[string]bool) []string {
+	var missing []string
+	for _, id := range accepted {
+		if !stored[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// errNotFound is returned by get when the resource does not exist yet
+var errNotFound = errors.New("not found")
+
+// get fetches path from the instance and decodes the JSON body into out
+func (v *verifier) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.url+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return json.NewDecoder(resp.Body).Decode(out)
+	case http.StatusNotFound:
+		return errNotFound
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+	}
+}
+
+// checkJobWindows reports every pair of consecutive jobs of one survey that were
+// requested less than window apart, i.e. two jobs within one debounce window.
+// Jobs requested before since belong to earlier runs and are left out
+func checkJobWindows(jobs []entity.JobRecord, since time.Time, window time.Duration) []string {
+	var sorted []entity.JobRecord
+	for _, job := range jobs {
+		if job.RequestedAt >= since.UnixNano() {
+			sorted = append(sorted, job)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].RequestedAt < sorted[j].RequestedAt })
+
+	var msgs []string
+	for i := 1; i < len(sorted); i++ {
+		gap := time.Duration(sorted[i].RequestedAt - sorted[i-1].RequestedAt)
+		if gap < window {
+			msgs = append(msgs, fmt.Sprintf("jobs %s and %s requested %s apart, within one debounce window",
+				sorted[i-1].ID, sorted[i].ID, gap.Round(time.Millisecond)))
+		}
+	}
+	return msgs
+}
+
+// printViolations writes the verification outcome
+func printViolations(w io.Writer, violations []violation) {
+	if len(violations) == 0 {
+		fmt.Fprintln(w, "Invariants: OK (one job per survey per debounce window, every accepted response reported)")
+		return
+	}
+
+	fmt.Fprintf(w, "Invariants: %d violation(s)\n", len(violations))
+	for _, v := range violations {
+		fmt.Fprintf(w, "  %s: %s\n", v.SurveyID, v.Message)
+	}
+	fmt.Fprintln(w, "Responses missing from final reports usually mean the instances run with DEBOUNCE_MODE=leading;")
+	fmt.Fprintln(w, "use DEBOUNCE_MODE=trailing to run each window's job after the window closes.")
+}
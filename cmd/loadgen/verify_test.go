@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+func TestCheckJobWindows(t *testing.T) {
+	base := time.Now().UnixNano()
+	window := 30 * time.Second
+
+	jobs := []entity.JobRecord{
+		{ID: "c", RequestedAt: base + int64(45*time.Second)},
+		{ID: "a", RequestedAt: base},
+		{ID: "b", RequestedAt: base + int64(30*time.Second)},
+	}
+
+	since := time.Unix(0, base)
+	msgs := checkJobWindows(jobs, since, window)
+	if len(msgs) != 1 {
+		t.Fatalf("Expected 1 violation, got %d: %v", len(msgs), msgs)
+	}
+
+	if msgs := checkJobWindows(jobs[1:], since, window); len(msgs) != 0 {
+		t.Errorf("Expected no violations, got %v", msgs)
+	}
+
+	// Jobs of an earlier run on the same survey ID are not held against this one
+	earlier := append([]entity.JobRecord{{ID: "old", RequestedAt: base - int64(time.Second)}}, jobs[1:]...)
+	if msgs := checkJobWindows(earlier, since, window); len(msgs) != 0 {
+		t.Errorf("Expected jobs before the run to be left out, got %v", msgs)
+	}
+}
+
+// newInstance serves a survey's report counting reported responses and its stored responses
+func newInstance(t *testing.T, reported int, stored ...string) *verifier {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/report"):
+			json.NewEncoder(w).Encode(entity.Report{SurveyID: "s1", ResponseCount: reported})
+		case strings.HasSuffix(r.URL.Path, "/responses.jsonl"):
+			enc := json.NewEncoder(w)
+			for _, id := range stored {
+				enc.Encode(entity.SurveyResponse{ID: id, SurveyID: "s1"})
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return &verifier{client: server.Client(), url: server.URL}
+}
+
+func TestWaitForReport(t *testing.T) {
+	tests := []struct {
+		name     string
+		reported int
+		stored   []string
+		wantMsg  string
+	}{
+		{"every accepted response reported", 3, []string{"other", "r1", "r2"}, ""},
+		{"report behind the stored responses", 2, []string{"other", "r1", "r2"}, "counts 2 of 3 stored"},
+		{"accepted response not stored", 2, []string{"other", "r1"}, "were never stored, e.g. r2"},
+		{"report behind the accepted responses", 1, []string{"r1", "r2"}, "counts 1 of 2 accepted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newInstance(t, tt.reported, tt.stored...)
+
+			msg := v.waitForReport(context.Background(), "s1", []string{"r1", "r2"}, time.Now())
+			if tt.wantMsg == "" && msg != "" || !strings.Contains(msg, tt.wantMsg) {
+				t.Errorf("Expected %q, got %q", tt.wantMsg, msg)
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+
+	tests := map[float64]time.Duration{
+		50:  50 * time.Millisecond,
+		90:  90 * time.Millisecond,
+		99:  99 * time.Millisecond,
+		100: 100 * time.Millisecond,
+	}
+	for p, want := range tests {
+		if got := percentile(sorted, p); got != want {
+			t.Errorf("percentile(%v) = %s, want %s", p, got, want)
+		}
+	}
+
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("Expected 0 for no samples, got %s", got)
+	}
+}
@@ -0,0 +1,40 @@
+package entity
+
+// ReportAggregate holds the mergeable partial aggregates a survey's report is
+// computed from, so each report run only folds in the responses stored since
+// the previous run instead of re-reading every response
+type ReportAggregate struct {
+	SurveyID string `json:"survey_id"`
+	// Offset is the number of stored responses folded in so far
+	Offset int64 `json:"offset"`
+	// LastResponseID is the ID of the last response folded in, used to detect
+	// stored responses that no longer line up with the aggregates
+	LastResponseID string                        `json:"last_response_id,omitempty"`
+	Questions      map[string]*QuestionAggregate `json:"questions"`
+	// IncrementalRuns counts the runs since the aggregates were last rebuilt from every response
+	IncrementalRuns int `json:"incremental_runs"`
+	// RecomputedAt is when the aggregates were last rebuilt from every response, in Unix seconds
+	RecomputedAt int64 `json:"recomputed_at"`
+	// Version is the version of the report last computed from the aggregates
+	Version int64 `json:"version"`
+}
+
+// QuestionAggregate holds the partial aggregates of the answers to a single question
+type QuestionAggregate struct {
+	Answered int               `json:"answered"`
+	Counts   map[string]int    `json:"counts,omitempty"`
+	Numeric  *NumericAggregate `json:"numeric,omitempty"`
+	// Distinct is a serialized HyperLogLog sketch of the distinct answer values
+	Distinct []byte `json:"distinct,omitempty"`
+}
+
+// NumericAggregate holds the partial aggregates of the numeric answers to a question
+type NumericAggregate struct {
+	Count      int     `json:"count"`
+	Sum        float64 `json:"sum"`
+	SumSquares float64 `json:"sum_squares"`
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
+	// Digest is a serialized t-digest sketch of the values for quantile estimates
+	Digest []byte `json:"digest,omitempty"`
+}
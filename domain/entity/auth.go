@@ -0,0 +1,56 @@
+package entity
+
+import "slices"
+
+const (
+	// ScopeSubmit allows submitting survey responses
+	ScopeSubmit = "submit"
+
+	// ScopeReadReport allows reading reports, jobs, exports and live updates
+	ScopeReadReport = "read-report"
+
+	// ScopeAdmin allows managing webhooks, schedules and API keys, and implies every other scope
+	ScopeAdmin = "admin"
+)
+
+const (
+	// AuthMethodAPIKey marks a caller authenticated with an API key
+	AuthMethodAPIKey = "api_key"
+
+	// AuthMethodJWT marks a caller authenticated with a JWT bearer token
+	AuthMethodJWT = "jwt"
+)
+
+// APIKey is a stored API key; only the SHA-256 hash of the key itself is kept
+type APIKey struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Hash   string   `json:"hash,omitempty"`
+	Scopes []string `json:"scopes"`
+	// TenantID binds the key to one tenant; keys without one may act for any tenant
+	TenantID string `json:"tenant_id,omitempty"`
+	// CreatedBy is the caller that created the key, empty when it was created from the command line
+	CreatedBy string `json:"created_by,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// Identity is the authenticated caller of a request
+type Identity struct {
+	// Method is how the caller authenticated, AuthMethodAPIKey or AuthMethodJWT
+	Method string `json:"method"`
+	// Subject is the API key ID or the JWT sub claim
+	Subject string   `json:"subject"`
+	Scopes  []string `json:"scopes"`
+	// TenantID is the only tenant the caller may act for, empty if it may act for any
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+// HasScope reports whether the caller was granted scope, directly or through ScopeAdmin
+func (i Identity) HasScope(scope string) bool {
+	return slices.Contains(i.Scopes, scope) || slices.Contains(i.Scopes, ScopeAdmin)
+}
+
+// String returns the method and subject, such as api_key:01J0..., as recorded on stored responses
+func (i Identity) String() string {
+	return i.Method + ":" + i.Subject
+}
@@ -0,0 +1,52 @@
+package entity
+
+import "errors"
+
+// ErrorKind classifies a domain error by what the caller did wrong, so every
+// delivery layer can map it to its own status codes
+type ErrorKind string
+
+const (
+	// ErrorInvalid means the request itself was malformed or broke a rule
+	ErrorInvalid ErrorKind = "invalid"
+
+	// ErrorUnauthenticated means the caller could not be identified
+	ErrorUnauthenticated ErrorKind = "unauthenticated"
+
+	// ErrorNotFound means the requested record does not exist
+	ErrorNotFound ErrorKind = "not_found"
+
+	// ErrorConflict means the request conflicts with one made earlier
+	ErrorConflict ErrorKind = "conflict"
+
+	// ErrorExhausted means the caller used up a rate limit or quota
+	ErrorExhausted ErrorKind = "exhausted"
+)
+
+// Error is a domain error of a given kind, identified by a stable code that
+// clients can rely on. Sentinel errors are declared as an *Error and wrapped
+// with fmt.Errorf("%w: detail") to add detail; ErrorOf finds them again
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+}
+
+// NewError creates a domain error of the given kind and code
+func NewError(kind ErrorKind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Error returns the message of the error
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ErrorOf returns the domain error err is or wraps, nil if it wraps none
+func ErrorOf(err error) *Error {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+	return nil
+}
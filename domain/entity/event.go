@@ -0,0 +1,21 @@
+package entity
+
+const (
+	// EventJobUpdated is published whenever a report job changes state
+	EventJobUpdated = "job.updated"
+
+	// EventReportUpdated is published whenever a new report version is saved
+	EventReportUpdated = "report.updated"
+)
+
+// SurveyEvent is a live update about a survey pushed to every instance
+type SurveyEvent struct {
+	Type     string `json:"type"`
+	SurveyID string `json:"survey_id"`
+	// TenantID is the tenant owning the survey, empty for DefaultTenant
+	TenantID string `json:"tenant_id,omitempty"`
+	// Job is the job's record after the change, set for job.updated events
+	Job *JobRecord `json:"job,omitempty"`
+	// Report is the new report, set for report.updated events
+	Report *Report `json:"report,omitempty"`
+}
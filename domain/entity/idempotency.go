@@ -0,0 +1,18 @@
+package entity
+
+const (
+	// IdempotencyStatusPending marks a request that is still being processed
+	IdempotencyStatusPending = "pending"
+
+	// IdempotencyStatusCompleted marks a request whose response has been recorded
+	IdempotencyStatusCompleted = "completed"
+)
+
+// IdempotencyRecord remembers the outcome of a request made with an Idempotency-Key
+type IdempotencyRecord struct {
+	Key         string `json:"key"`
+	Fingerprint string `json:"fingerprint"`
+	Status      string `json:"status"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
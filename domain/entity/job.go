@@ -0,0 +1,32 @@
+package entity
+
+const (
+	// JobStatusQueued marks a job that was published and is waiting for a worker
+	JobStatusQueued = "queued"
+
+	// JobStatusRunning marks a job a worker is generating a report for
+	JobStatusRunning = "running"
+
+	// JobStatusCompleted marks a job that produced a new report
+	JobStatusCompleted = "completed"
+
+	// JobStatusSkipped marks a job acknowledged because a newer report already covered it
+	JobStatusSkipped = "skipped"
+
+	// JobStatusFailed marks a job whose last attempt failed; it is retried from the queue
+	JobStatusFailed = "failed"
+)
+
+// JobRecord tracks the lifecycle of a report job
+// All times are in Unix nanoseconds
+type JobRecord struct {
+	ID          string `json:"id"`
+	SurveyID    string `json:"survey_id"`
+	Status      string `json:"status"`
+	RequestedAt int64  `json:"requested_at"`
+	NotBefore   int64  `json:"not_before,omitempty"`
+	StartedAt   int64  `json:"started_at,omitempty"`
+	FinishedAt  int64  `json:"finished_at,omitempty"`
+	Attempts    int    `json:"attempts"`
+	Error       string `json:"error,omitempty"`
+}
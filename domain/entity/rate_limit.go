@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// RateLimitResult is the state of a rate limit after a request was counted against it
+type RateLimitResult struct {
+	// Allowed reports whether the request was within the limit and counted
+	Allowed bool
+
+	// Limit is the number of requests the limit accepts at once
+	Limit int
+
+	// Remaining is the number of requests that would still be accepted right now
+	Remaining int
+
+	// RetryAfter is how long until the request would be accepted, zero when it was
+	RetryAfter time.Duration
+
+	// ResetAfter is how long until the limit has fully replenished
+	ResetAfter time.Duration
+}
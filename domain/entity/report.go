@@ -0,0 +1,50 @@
+package entity
+
+// Report represents the aggregated result of all responses to a survey
+type Report struct {
+	SurveyID      string                      `json:"survey_id"`
+	ResponseCount int                         `json:"response_count"`
+	Questions     map[string]*QuestionSummary `json:"questions"`
+	GeneratedAt   int64                       `json:"generated_at"`
+	// Version increases by one every time a new report is generated for the survey
+	Version int64 `json:"version"`
+}
+
+// QuestionSummary represents the aggregated answers to a single question
+type QuestionSummary struct {
+	Answered int             `json:"answered"`
+	Counts   map[string]int  `json:"counts,omitempty"`
+	Numeric  *NumericSummary `json:"numeric,omitempty"`
+	// Distinct is the estimated number of distinct answer values
+	Distinct int `json:"distinct"`
+}
+
+// NumericSummary represents statistics over the numeric answers to a question
+// The quantiles are estimates from a t-digest sketch
+type NumericSummary struct {
+	Count  int     `json:"count"`
+	Sum    float64 `json:"sum"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"std_dev"`
+	Median float64 `json:"median"`
+	P90    float64 `json:"p90"`
+	P99    float64 `json:"p99"`
+}
+
+// ReportWatermark records how far the latest completed report for a survey reaches
+type ReportWatermark struct {
+	SurveyID string `json:"survey_id"`
+	// Offset is the number of stored responses the report covers. Responses
+	// are only ever appended, so it is a position every instance agrees on
+	Offset        int64 `json:"offset"`
+	ResponseCount int   `json:"response_count"`
+	GeneratedAt   int64 `json:"generated_at"`
+}
+
+// Covers reports whether the report this watermark describes already
+// includes the first count stored responses of the survey
+func (w *ReportWatermark) Covers(count int64) bool {
+	return count > 0 && w.Offset >= count
+}
@@ -0,0 +1,27 @@
+package entity
+
+// Schedule triggers report generation for a survey at fixed times, independent
+// of submission traffic. Exactly one of Cron and At is set
+type Schedule struct {
+	ID       string `json:"id"`
+	SurveyID string `json:"survey_id"`
+	// TenantID is the tenant owning the survey, empty for DefaultTenant
+	TenantID string `json:"tenant_id,omitempty"`
+	// Cron is a standard five-field cron expression or a descriptor such as
+	// "@daily", optionally prefixed with "CRON_TZ=<zone> "; times are UTC otherwise
+	Cron string `json:"cron,omitempty"`
+	// At is a one-off run time in Unix seconds, such as when the survey closes
+	At int64 `json:"at,omitempty"`
+	// NextRunAt is when the schedule fires next in Unix seconds, zero once a one-off run has fired
+	NextRunAt int64 `json:"next_run_at"`
+	// LastRunAt is when the schedule last fired in Unix seconds
+	LastRunAt int64 `json:"last_run_at,omitempty"`
+	// LastJobID is the ID of the report job the schedule last published
+	LastJobID string `json:"last_job_id,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// SurveyKey returns the storage key of the schedule's survey, see TenantScopedID
+func (s Schedule) SurveyKey() string {
+	return TenantScopedID(s.TenantID, s.SurveyID)
+}
@@ -14,9 +14,24 @@ type SurveyResponse struct {
 	SurveyID  string                 `json:"survey_id"`
 	Answers   map[string]interface{} `json:"answers"`
 	CreatedAt int64                  `json:"created_at"`
+	// SubmittedBy is the authenticated caller that submitted the response, empty when authentication is disabled
+	SubmittedBy string `json:"submitted_by,omitempty"`
+	// TenantID is the tenant the response was submitted for, empty for DefaultTenant
+	TenantID string `json:"tenant_id,omitempty"`
 }
 
 // ReportJob represents a job to generate a report
 type ReportJob struct {
+	ID       string `json:"id,omitempty"`
 	SurveyID string `json:"survey_id"`
+	// TenantID is the tenant owning the survey, empty for DefaultTenant
+	TenantID string `json:"tenant_id,omitempty"`
+	// RequestedAt is when the job was published, in Unix nanoseconds.
+	// Every response stored before this time must be covered by the job's report
+	RequestedAt int64 `json:"requested_at,omitempty"`
+	// NotBefore delays processing until this time, in Unix nanoseconds
+	NotBefore int64 `json:"not_before,omitempty"`
+	// ResponseCount is the number of responses stored for the survey when
+	// the job was published, which the job's report must cover
+	ResponseCount int64 `json:"response_count,omitempty"`
 }
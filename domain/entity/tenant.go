@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// DefaultTenant owns every request that names no tenant, and all data stored
+// before tenants were introduced
+const DefaultTenant = "default"
+
+// tenantIDPattern limits tenant IDs to short slugs, so they can never contain
+// the ":" separating them from the survey ID in storage keys
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// ValidTenantID reports whether id can be used as a tenant ID
+func ValidTenantID(id string) bool {
+	return tenantIDPattern.MatchString(id)
+}
+
+// ValidSurveyID reports whether id can be used as a survey ID: it must not
+// contain the ":" separating tenants from surveys in storage keys
+func ValidSurveyID(id string) bool {
+	return id != "" && !strings.Contains(id, ":")
+}
+
+// tenantKey is the context key of the tenant a request or job belongs to
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx acting for the given tenant; an empty
+// tenant ID stands for DefaultTenant
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant ctx acts for, DefaultTenant if none
+func TenantFromContext(ctx context.Context) string {
+	if tenantID, _ := ctx.Value(tenantKey{}).(string); tenantID != "" {
+		return tenantID
+	}
+	return DefaultTenant
+}
+
+// TenantScopedID returns the storage key of a survey of the given tenant,
+// {tenant}:{survey}; an empty tenant ID stands for DefaultTenant. Every
+// tenant, DefaultTenant included, gets its own namespace, so no survey ID can
+// reach the data of another tenant
+func TenantScopedID(tenantID, surveyID string) string {
+	if tenantID == "" {
+		tenantID = DefaultTenant
+	}
+	return tenantID + ":" + surveyID
+}
+
+// LegacySurveyKey reports whether key is a storage key written before
+// DefaultTenant had a namespace, a bare survey ID without a tenant
+func LegacySurveyKey(key string) bool {
+	return !strings.Contains(key, ":")
+}
+
+// SurveyKey returns the storage key of a survey of the tenant ctx acts for
+func SurveyKey(ctx context.Context, surveyID string) string {
+	return TenantScopedID(TenantFromContext(ctx), surveyID)
+}
@@ -0,0 +1,83 @@
+package entity
+
+const (
+	// WebhookEventReportCompleted is sent when the worker saved a new report
+	WebhookEventReportCompleted = "report.completed"
+
+	// WebhookEventReportFailed is sent when the worker failed to generate a report
+	WebhookEventReportFailed = "report.failed"
+
+	// WebhookErrorReportFailed is the error code of a failed report that has no more specific domain error code
+	WebhookErrorReportFailed = "report_generation_failed"
+)
+
+const (
+	// DeliveryStatusDelivered marks an attempt the receiver acknowledged with a 2xx status
+	DeliveryStatusDelivered = "delivered"
+
+	// DeliveryStatusRetrying marks a failed attempt that will be retried after a backoff
+	DeliveryStatusRetrying = "retrying"
+
+	// DeliveryStatusFailed marks the last failed attempt of a delivery that was given up
+	DeliveryStatusFailed = "failed"
+)
+
+// Webhook is a URL registered to be notified about a survey's reports
+type Webhook struct {
+	ID       string `json:"id"`
+	SurveyID string `json:"survey_id"`
+	URL      string `json:"url"`
+	// Secret is the key payloads are signed with using HMAC-SHA256
+	Secret    string `json:"secret,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// WebhookPayload is the JSON body posted to a webhook
+type WebhookPayload struct {
+	Event    string `json:"event"`
+	SurveyID string `json:"survey_id"`
+	// ReportVersion is the version of the survey's latest report, zero if none was saved yet
+	ReportVersion int64 `json:"report_version"`
+	// Summary is the latest report, set for completed reports
+	Summary *Report `json:"summary,omitempty"`
+	// Error is the stable code of why the report failed, set for failed reports
+	// The cause itself is only logged, as it may name internal hosts or paths
+	Error string `json:"error,omitempty"`
+	// OccurredAt is when the report completed or failed, in Unix seconds
+	OccurredAt int64 `json:"occurred_at"`
+}
+
+// WebhookDelivery is a queued attempt to post a payload to one webhook
+type WebhookDelivery struct {
+	ID        string `json:"id"`
+	WebhookID string `json:"webhook_id"`
+	SurveyID  string `json:"survey_id"`
+	// TenantID is the tenant owning the survey, empty for DefaultTenant
+	TenantID string `json:"tenant_id,omitempty"`
+	Event    string `json:"event"`
+	// Payload is the signed JSON body, kept as sent so every attempt carries the same bytes
+	Payload []byte `json:"payload"`
+	// Attempt is the number of the next attempt, starting at 1
+	Attempt int `json:"attempt"`
+	// NotBefore delays the attempt until this time, in Unix nanoseconds
+	NotBefore int64 `json:"not_before,omitempty"`
+	CreatedAt int64 `json:"created_at"`
+}
+
+// WebhookDeliveryAttempt records the outcome of one attempt to deliver a payload
+type WebhookDeliveryAttempt struct {
+	DeliveryID string `json:"delivery_id"`
+	WebhookID  string `json:"webhook_id"`
+	SurveyID   string `json:"survey_id"`
+	Event      string `json:"event"`
+	URL        string `json:"url"`
+	Attempt    int    `json:"attempt"`
+	Status     string `json:"status"`
+	// StatusCode is the receiver's HTTP status, zero if no response was received
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// AttemptedAt is when the attempt was made, in Unix nanoseconds
+	AttemptedAt int64 `json:"attempted_at"`
+	// DurationMs is how long the receiver took to answer
+	DurationMs int64 `json:"duration_ms"`
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// APIKeyRepository defines the interface for API key storage
+type APIKeyRepository interface {
+	// SaveAPIKey stores an API key, replacing any previous key with the same hash
+	SaveAPIKey(ctx context.Context, key entity.APIKey) error
+
+	// GetAPIKeyByHash returns the API key whose key hashes to hash
+	// Returns ErrNotFound if there is no such key
+	GetAPIKeyByHash(ctx context.Context, hash string) (*entity.APIKey, error)
+
+	// ListAPIKeys returns every API key ordered by creation time
+	ListAPIKeys(ctx context.Context) ([]entity.APIKey, error)
+
+	// DeleteAPIKey removes the API key with the given ID
+	// Returns ErrNotFound if there is no such key
+	DeleteAPIKey(ctx context.Context, id string) error
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"io"
+)
+
+// BlobRepository defines the interface for storing files such as rendered report documents
+// Keys are slash-separated relative paths
+type BlobRepository interface {
+	// Put stores the content read from r under key, replacing any existing blob
+	// Readers of the key see either the previous or the new content, never a partial write
+	Put(ctx context.Context, key string, r io.Reader) error
+
+	// Get opens the blob stored under key; the caller must close it
+	// Returns ErrNotFound if there is no such blob
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the blob stored under key
+	// Deleting a blob that does not exist is not an error
+	Delete(ctx context.Context, key string) error
+}
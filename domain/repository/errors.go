@@ -0,0 +1,6 @@
+package repository
+
+import "github.com/rfanazhari/distributed-queue-processor/domain/entity"
+
+// ErrNotFound is returned when a requested record does not exist
+var ErrNotFound = entity.NewError(entity.ErrorNotFound, "not_found", "not found")
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// EventBusRepository defines the interface for broadcasting survey events to every instance
+// Delivery is best effort: subscribers only see events published while they are subscribed
+type EventBusRepository interface {
+	// PublishEvent broadcasts an event to every subscriber on every instance
+	PublishEvent(ctx context.Context, event entity.SurveyEvent) error
+
+	// SubscribeEvents calls callback for every event published until ctx is canceled
+	// It returns once the subscription is active
+	SubscribeEvents(ctx context.Context, callback func(entity.SurveyEvent)) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// IdempotencyRepository defines the interface for idempotency key storage
+type IdempotencyRepository interface {
+	// Reserve stores the record only if no record exists for its key
+	// Returns nil if the record was stored, or the existing record otherwise
+	Reserve(ctx context.Context, record entity.IdempotencyRecord, ttl time.Duration) (*entity.IdempotencyRecord, error)
+
+	// Save stores the record, replacing any existing record for its key
+	Save(ctx context.Context, record entity.IdempotencyRecord, ttl time.Duration) error
+
+	// Delete removes the record for the given key
+	Delete(ctx context.Context, key string) error
+}
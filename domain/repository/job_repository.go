@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// JobRepository defines the interface for report job status storage
+type JobRepository interface {
+	// SaveJob stores a job record, replacing any previous record with the same ID
+	SaveJob(ctx context.Context, job entity.JobRecord) error
+
+	// GetJob returns the job record with the given ID for the given survey
+	// Returns ErrNotFound if there is no such job
+	GetJob(ctx context.Context, surveyID, jobID string) (*entity.JobRecord, error)
+
+	// ListJobs returns the job records for the given survey ordered by request time
+	ListJobs(ctx context.Context, surveyID string) ([]entity.JobRecord, error)
+}
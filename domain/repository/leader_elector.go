@@ -0,0 +1,23 @@
+package repository
+
+import "context"
+
+// LeaderElector defines the interface for electing the one instance of the
+// cluster that runs a singleton task, such as the report scheduler
+type LeaderElector interface {
+	// Campaign blocks until this instance is elected or ctx is done
+	// Once elected, leadership is renewed in the background until Resign is
+	// called or renewing fails; Campaign may then be called again
+	Campaign(ctx context.Context) error
+
+	// Resign stops renewing leadership and hands it over right away
+	Resign(ctx context.Context) error
+
+	// IsLeader reports whether this instance currently leads
+	IsLeader() bool
+
+	// Leadership returns a channel that receives true when this instance is
+	// elected and false when it resigns or loses leadership
+	// Only the latest change is kept until it is read
+	Leadership() <-chan bool
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// LeaseRepository defines the interface for renewable leases held by a named holder
+// Unlike a LockRepository lock, a lease knows its holder, so only the holder
+// can extend it before it expires or release it
+type LeaseRepository interface {
+	// AcquireLease takes the lease for holder if it is free or expired, or extends it
+	// if holder already has it. Returns true if holder holds the lease for ttl from now
+	AcquireLease(ctx context.Context, key, holder string, ttl time.Duration) (bool, error)
+
+	// ReleaseLease gives up the lease if holder still has it
+	ReleaseLease(ctx context.Context, key, holder string) error
+}
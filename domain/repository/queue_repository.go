@@ -14,6 +14,14 @@ type QueueRepository interface {
 	// The callback function is called for each job received
 	ConsumeReportJobs(ctx context.Context, callback func(entity.ReportJob) error) error
 
+	// SetPrefetch sets how many jobs are delivered to the consumer ahead of
+	// being handled. It may be called again while consuming; jobs already
+	// delivered are unaffected
+	SetPrefetch(prefetch int) error
+
+	// Ping reports whether the connection to the message queue is usable
+	Ping(ctx context.Context) error
+
 	// Close closes the connection to the message queue
 	Close() error
 }
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// QuotaRepository defines the interface for usage counters checked against a quota
+type QuotaRepository interface {
+	// ConsumeQuota adds n to the usage counted under key unless that would take
+	// it above limit, checking and adding atomically across instances.
+	// Returns the usage after the call and whether n was added. The counter
+	// is dropped at expireAt, after which usage starts again from zero
+	ConsumeQuota(ctx context.Context, key string, n, limit int64, expireAt time.Time) (int64, bool, error)
+}
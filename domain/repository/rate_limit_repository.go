@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// RateLimitRepository defines the interface for rate limits shared by every instance
+type RateLimitRepository interface {
+	// TakeRateLimit counts cost requests against the limit under key, which
+	// accepts burst requests at once and replenishes rate requests per second.
+	// The requests are only counted when they fit within the limit
+	TakeRateLimit(ctx context.Context, key string, rate float64, burst, cost int) (entity.RateLimitResult, error)
+
+	// ReturnRateLimit gives back cost requests counted against the limit under
+	// key by TakeRateLimit with the same rate and burst, such as those of a
+	// request another limit refused
+	ReturnRateLimit(ctx context.Context, key string, rate float64, burst, cost int) error
+}
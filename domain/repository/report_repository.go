@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// ReportRepository defines the interface for generated report storage
+type ReportRepository interface {
+	// SaveReport stores a report, replacing any previous report for the same survey
+	SaveReport(ctx context.Context, report entity.Report) error
+
+	// GetReport returns the latest report for the given survey ID
+	// Returns ErrNotFound if no report has been generated yet
+	GetReport(ctx context.Context, surveyID string) (*entity.Report, error)
+
+	// SaveWatermark records how far the latest completed report for a survey reaches
+	SaveWatermark(ctx context.Context, watermark entity.ReportWatermark) error
+
+	// GetWatermark returns the watermark of the latest completed report for the given survey ID
+	// Returns ErrNotFound if no report has been completed yet
+	GetWatermark(ctx context.Context, surveyID string) (*entity.ReportWatermark, error)
+
+	// SaveAggregate stores the partial aggregates of a survey, replacing the previous ones
+	SaveAggregate(ctx context.Context, aggregate entity.ReportAggregate) error
+
+	// GetAggregate returns the partial aggregates of the given survey ID
+	// Returns ErrNotFound if no report has been generated since aggregates were introduced
+	GetAggregate(ctx context.Context, surveyID string) (*entity.ReportAggregate, error)
+}
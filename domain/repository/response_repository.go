@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// ResponseRepository defines the interface for survey response storage
+type ResponseRepository interface {
+	// SaveResponse stores a survey response
+	SaveResponse(ctx context.Context, response entity.SurveyResponse) error
+
+	// SaveResponses stores several survey responses, possibly for different surveys, in bulk
+	SaveResponses(ctx context.Context, responses []entity.SurveyResponse) error
+
+	// ListResponses returns all stored responses for the given survey ID
+	// Responses are returned in the order they were stored
+	ListResponses(ctx context.Context, surveyID string) ([]entity.SurveyResponse, error)
+
+	// ScanResponses calls fn for every stored response for the given survey ID
+	// after the first offset ones, in the order they were stored, reading them
+	// in pages rather than all at once
+	// Scanning stops at the first error returned by fn, which is returned as is
+	ScanResponses(ctx context.Context, surveyID string, offset int64, fn func(entity.SurveyResponse) error) error
+
+	// CountResponses returns the number of responses stored for the given survey ID
+	CountResponses(ctx context.Context, surveyID string) (int64, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+)
+
+// RuntimeConfigRepository defines the interface for the runtime configuration
+// overrides shared by every instance
+type RuntimeConfigRepository interface {
+	// GetRuntimeConfig returns the overrides document
+	// Returns ErrNotFound if no overrides are set
+	GetRuntimeConfig(ctx context.Context) ([]byte, error)
+
+	// SubscribeRuntimeConfig calls callback every time a change of the overrides
+	// is announced, until ctx is canceled. It returns once the subscription is active
+	SubscribeRuntimeConfig(ctx context.Context, callback func()) error
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// ScheduleRepository defines the interface for report schedule storage
+// Schedules are stored centrally so whichever instance runs the scheduler sees all of them
+type ScheduleRepository interface {
+	// SaveSchedule stores a schedule, replacing any previous schedule with the same ID
+	SaveSchedule(ctx context.Context, schedule entity.Schedule) error
+
+	// UpdateSchedule replaces a schedule only if it is still stored, so a schedule
+	// deleted while it was being run is not brought back
+	// Returns ErrNotFound if there is no such schedule
+	UpdateSchedule(ctx context.Context, schedule entity.Schedule) error
+
+	// GetSchedule returns the schedule with the given ID for the given survey
+	// Returns ErrNotFound if there is no such schedule
+	GetSchedule(ctx context.Context, surveyID, scheduleID string) (*entity.Schedule, error)
+
+	// ListSchedules returns the schedules of the given survey ordered by creation time
+	ListSchedules(ctx context.Context, surveyID string) ([]entity.Schedule, error)
+
+	// ListDueSchedules returns the schedules of every survey whose next run is at or before now,
+	// given in Unix seconds, ordered by next run time
+	ListDueSchedules(ctx context.Context, now int64) ([]entity.Schedule, error)
+
+	// DeleteSchedule removes the schedule with the given ID for the given survey
+	// Returns ErrNotFound if there is no such schedule
+	DeleteSchedule(ctx context.Context, surveyID, scheduleID string) error
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// WebhookRepository defines the interface for webhook registrations and their delivery log
+type WebhookRepository interface {
+	// SaveWebhook stores a webhook, replacing any previous webhook with the same ID
+	SaveWebhook(ctx context.Context, webhook entity.Webhook) error
+
+	// GetWebhook returns the webhook with the given ID registered for the given survey
+	// Returns ErrNotFound if there is no such webhook
+	GetWebhook(ctx context.Context, surveyID, webhookID string) (*entity.Webhook, error)
+
+	// ListWebhooks returns the webhooks registered for the given survey ordered by creation time
+	ListWebhooks(ctx context.Context, surveyID string) ([]entity.Webhook, error)
+
+	// DeleteWebhook removes the webhook with the given ID registered for the given survey
+	// Returns ErrNotFound if there is no such webhook
+	DeleteWebhook(ctx context.Context, surveyID, webhookID string) error
+
+	// SaveDeliveryAttempt appends an attempt to the survey's delivery log
+	// Only the most recent attempts are kept
+	SaveDeliveryAttempt(ctx context.Context, attempt entity.WebhookDeliveryAttempt) error
+
+	// ListDeliveryAttempts returns the survey's delivery log, oldest first
+	ListDeliveryAttempts(ctx context.Context, surveyID string) ([]entity.WebhookDeliveryAttempt, error)
+}
+
+// WebhookQueueRepository defines the interface for the webhook delivery queue
+// Deliveries have their own queue so slow receivers never hold up report jobs
+type WebhookQueueRepository interface {
+	// PublishDelivery publishes a delivery, delayed until its NotBefore time if set
+	PublishDelivery(ctx context.Context, delivery entity.WebhookDelivery) error
+
+	// ConsumeDeliveries starts consuming deliveries from the queue
+	// The callback function is called for each delivery that is due
+	ConsumeDeliveries(ctx context.Context, callback func(entity.WebhookDelivery) error) error
+
+	// Ping reports whether the connection to the message queue is usable
+	Ping(ctx context.Context) error
+
+	// Close closes the connection to the message queue
+	Close() error
+}
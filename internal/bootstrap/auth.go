@@ -0,0 +1,30 @@
+package bootstrap
+
+import (
+	"fmt"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/config"
+	"github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/jwt"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// NewTokenVerifier creates the verifier of JWT bearer tokens from the
+// configured keys, or returns nil when no keys are configured and only API keys are accepted
+func NewTokenVerifier(cfg *config.Config) (usecase.TokenVerifier, error) {
+	if !cfg.Auth.AcceptsJWTs() {
+		return nil, nil
+	}
+
+	verifier, err := jwt.NewVerifier(jwt.Settings{
+		JWKSFile: cfg.Auth.JWKSFile,
+		KeyFiles: cfg.Auth.JWTKeys,
+		Secret:   cfg.Auth.JWTSecret,
+		Issuer:   cfg.Auth.JWTIssuer,
+		Audience: cfg.Auth.JWTAudience,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JWT keys: %w", err)
+	}
+
+	return verifier, nil
+}
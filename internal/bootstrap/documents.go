@@ -0,0 +1,33 @@
+package bootstrap
+
+import (
+	"fmt"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/config"
+	"github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/render"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// NewDocumentRenderers creates the renderers of the configured report document
+// formats, with the HTML template read from the configured template path
+func NewDocumentRenderers(cfg *config.Config) ([]usecase.DocumentRenderer, error) {
+	var renderers []usecase.DocumentRenderer
+	for _, format := range cfg.Report.Documents {
+		switch usecase.DocumentFormat(format) {
+		case usecase.DocumentHTML:
+			renderer, err := render.NewHTMLRenderer(cfg.Report.Template)
+			if err != nil {
+				return nil, err
+			}
+			renderers = append(renderers, renderer)
+		case usecase.DocumentPDF:
+			renderers = append(renderers, render.NewPDFRenderer())
+		case config.DocumentsNone:
+		default:
+			return nil, fmt.Errorf("unknown report document format %q, expected %q, %q or %q",
+				format, usecase.DocumentHTML, usecase.DocumentPDF, config.DocumentsNone)
+		}
+	}
+
+	return renderers, nil
+}
@@ -0,0 +1,34 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/config"
+	httpHandler "github.com/rfanazhari/distributed-queue-processor/internal/delivery/http"
+)
+
+// NewReadinessChecks returns the checks of the dependencies the configured role
+// needs; the instance stops being ready as soon as ctx is canceled
+func NewReadinessChecks(ctx context.Context, cfg *config.Config, repos *Repositories) []httpHandler.ReadinessCheck {
+	checks := []httpHandler.ReadinessCheck{
+		// An instance that is shutting down should be sent no more work
+		{Name: "running", Check: func(context.Context) error {
+			if ctx.Err() != nil {
+				return errors.New("shutting down")
+			}
+			return nil
+		}},
+		// API instances publish report jobs and workers consume them
+		{Name: "report_queue", Check: repos.Queue.Ping},
+	}
+
+	if repos.pingStore != nil {
+		checks = append(checks, httpHandler.ReadinessCheck{Name: "store", Check: repos.pingStore})
+	}
+	if cfg.RunsWorkers() {
+		checks = append(checks, httpHandler.ReadinessCheck{Name: "webhook_queue", Check: repos.WebhookQueue.Ping})
+	}
+
+	return checks
+}
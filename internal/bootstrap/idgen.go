@@ -0,0 +1,30 @@
+package bootstrap
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/config"
+	"github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/idgen"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// NewIDGenerator creates the configured response ID generator
+func NewIDGenerator(cfg *config.Config) (usecase.IDGenerator, error) {
+	idGenerator, err := idgen.New(cfg.IDs.Generator, cfg.IDs.InstanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ID generator: %w", err)
+	}
+
+	return idGenerator, nil
+}
+
+// NewHolderName returns a name for this instance that is unique across the
+// cluster, for holding leader leases; the hostname keeps it readable
+func NewHolderName(idGenerator usecase.IDGenerator) string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "instance"
+	}
+	return fmt.Sprintf("%s-%s", hostname, idGenerator.NewID())
+}
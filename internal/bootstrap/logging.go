@@ -0,0 +1,29 @@
+package bootstrap
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/config"
+)
+
+// NewLogLevel routes the standard logger through slog at the configured level
+// and returns the level, which can be changed while the service runs
+func NewLogLevel(cfg *config.Config) (*slog.LevelVar, error) {
+	level := new(slog.LevelVar)
+	if err := SetLogLevel(level, cfg); err != nil {
+		return nil, err
+	}
+
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+	return level, nil
+}
+
+// SetLogLevel changes level to the configured one
+func SetLogLevel(level *slog.LevelVar, cfg *config.Config) error {
+	if err := level.UnmarshalText([]byte(cfg.Log.Level)); err != nil {
+		return fmt.Errorf("invalid log level %q: %w", cfg.Log.Level, err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	"github.com/rfanazhari/distributed-queue-processor/internal/config"
+)
+
+// Reloader applies changed reloadable settings to the running components,
+// either when the configuration is read again or when the runtime overrides
+// shared through the store change. Other settings keep their startup value
+type Reloader struct {
+	load      func() (*config.Config, error)
+	overrides repository.RuntimeConfigRepository
+	startup   *config.Config
+
+	mu       sync.Mutex
+	current  *config.Config
+	ignored  []string
+	appliers []func(cfg *config.Config) error
+}
+
+// NewReloader creates a reloader for components started with the startup
+// configuration. load reads the file, environment and flags again, and
+// overrides may be nil when no runtime overrides are shared
+func NewReloader(startup *config.Config, load func() (*config.Config, error), overrides repository.RuntimeConfigRepository) *Reloader {
+	return &Reloader{
+		load:      load,
+		overrides: overrides,
+		startup:   startup,
+		current:   startup,
+	}
+}
+
+// OnReload registers a function applying the reloadable settings of cfg to a component
+func (r *Reloader) OnReload(apply func(cfg *config.Config) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.appliers = append(r.appliers, apply)
+}
+
+// Reload reads the configuration and the runtime overrides again and applies
+// the reloadable settings that changed. The running settings are kept when
+// either is invalid
+func (r *Reloader) Reload(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	loaded, err := r.load()
+	if err != nil {
+		return fmt.Errorf("failed to reload configuration: %w", err)
+	}
+
+	if r.overrides != nil {
+		doc, err := r.overrides.GetRuntimeConfig(ctx)
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+		case err != nil:
+			return fmt.Errorf("failed to read runtime overrides: %w", err)
+		default:
+			if loaded, err = loaded.WithOverrides(doc); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Say once, not on every reload, which changes wait for a restart
+	if _, restart := r.startup.Changes(loaded); !slices.Equal(restart, r.ignored) {
+		r.ignored = restart
+		if len(restart) > 0 {
+			log.Printf("Changes to %s take effect after a restart", strings.Join(restart, ", "))
+		}
+	}
+
+	next := r.startup.WithRuntime(loaded)
+	applied, _ := r.current.Changes(next)
+	if len(applied) == 0 {
+		slog.Debug("Reloaded configuration without changes")
+		return nil
+	}
+
+	var errs []error
+	for _, apply := range r.appliers {
+		errs = append(errs, apply(next))
+	}
+	r.current = next
+	log.Printf("Applied changes to %s", strings.Join(applied, ", "))
+
+	return errors.Join(errs...)
+}
+
+// Watch applies the runtime overrides now, then again every time a change is
+// announced and every pollInterval in case an announcement was missed, until
+// ctx is canceled. It does nothing when no runtime overrides are shared
+func (r *Reloader) Watch(ctx context.Context, pollInterval time.Duration) error {
+	if r.overrides == nil {
+		return nil
+	}
+
+	reload := func() {
+		if err := r.Reload(ctx); err != nil {
+			log.Printf("Error applying runtime overrides: %v", err)
+		}
+	}
+
+	if err := r.overrides.SubscribeRuntimeConfig(ctx, reload); err != nil {
+		return fmt.Errorf("failed to watch runtime overrides: %w", err)
+	}
+	reload()
+
+	if pollInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(pollInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					reload()
+				}
+			}
+		}()
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package bootstrap_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	"github.com/rfanazhari/distributed-queue-processor/internal/bootstrap"
+	"github.com/rfanazhari/distributed-queue-processor/internal/config"
+	"github.com/rfanazhari/distributed-queue-processor/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReloader_AppliesOnlyReloadableChanges(t *testing.T) {
+	startup := config.Default()
+
+	// The file now also changes the HTTP address, which needs a restart
+	load := func() (*config.Config, error) {
+		cfg := config.Default()
+		cfg.HTTP.Addr = ":9090"
+		cfg.Lock.TTL = time.Minute
+		return cfg, nil
+	}
+
+	overrides := mocks.NewRuntimeConfigRepository(t)
+	overrides.On("GetRuntimeConfig", mock.Anything).Return([]byte(`{"worker": {"concurrency": 3}}`), nil)
+
+	reloader := bootstrap.NewReloader(startup, load, overrides)
+	var applied []*config.Config
+	reloader.OnReload(func(cfg *config.Config) error {
+		applied = append(applied, cfg)
+		return nil
+	})
+
+	if err := reloader.Reload(context.Background()); err != nil {
+		t.Fatalf("Expected the reload to succeed, got %v", err)
+	}
+	if len(applied) != 1 {
+		t.Fatalf("Expected the changes to be applied once, got %d", len(applied))
+	}
+	if cfg := applied[0]; cfg.Lock.TTL != time.Minute || cfg.Worker.Concurrency != 3 || cfg.HTTP.Addr != ":8080" {
+		t.Errorf("Expected the file and overrides to change only reloadable settings, got %+v", cfg)
+	}
+
+	// Nothing changed since, so nothing is applied again
+	if err := reloader.Reload(context.Background()); err != nil || len(applied) != 1 {
+		t.Errorf("Expected an unchanged reload to apply nothing, got %d applies and %v", len(applied), err)
+	}
+}
+
+func TestReloader_KeepsSettingsWhenOverridesAreInvalid(t *testing.T) {
+	overrides := mocks.NewRuntimeConfigRepository(t)
+	overrides.On("GetRuntimeConfig", mock.Anything).Return([]byte(`{"mode": "embedded"}`), nil).Once()
+	overrides.On("GetRuntimeConfig", mock.Anything).Return(nil, repository.ErrNotFound).Once()
+
+	reloader := bootstrap.NewReloader(config.Default(), func() (*config.Config, error) { return config.Default(), nil }, overrides)
+	reloader.OnReload(func(cfg *config.Config) error {
+		return errors.New("unexpected apply")
+	})
+
+	if err := reloader.Reload(context.Background()); err == nil {
+		t.Error("Expected overrides of a restart-only setting to be rejected")
+	}
+	if err := reloader.Reload(context.Background()); err != nil {
+		t.Errorf("Expected missing overrides to keep the settings, got %v", err)
+	}
+}
@@ -0,0 +1,241 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	"github.com/rfanazhari/distributed-queue-processor/internal/config"
+	bboltRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/bbolt"
+	filesystemRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/filesystem"
+	memoryRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/memory"
+	rabbitmqRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/rabbitmq"
+	redisRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/redis"
+)
+
+// Repositories holds every repository the application is wired with
+type Repositories struct {
+	Lock         repository.LockRepository
+	Queue        repository.QueueRepository
+	Response     repository.ResponseRepository
+	Report       repository.ReportRepository
+	Idempotency  repository.IdempotencyRepository
+	Job          repository.JobRepository
+	Blob         repository.BlobRepository
+	Webhook      repository.WebhookRepository
+	WebhookQueue repository.WebhookQueueRepository
+	Events       repository.EventBusRepository
+	Schedule     repository.ScheduleRepository
+	Lease        repository.LeaseRepository
+	APIKey       repository.APIKeyRepository
+	Quota        repository.QuotaRepository
+	RateLimit    repository.RateLimitRepository
+
+	// RateLimitLocal keeps rate limits in process: the limit of the instance,
+	// and the shared limits while RateLimit is unreachable. It is RateLimit
+	// itself when that is already kept in process
+	RateLimitLocal repository.RateLimitRepository
+
+	// RuntimeConfig is nil when no runtime overrides are shared
+	RuntimeConfig repository.RuntimeConfigRepository
+
+	// pingStore checks the connection to the shared store, nil when it is a local file
+	pingStore func(ctx context.Context) error
+
+	closers []func() error
+}
+
+// Close releases every connection opened for the repositories, most recent first
+func (r *Repositories) Close() {
+	for i := len(r.closers) - 1; i >= 0; i-- {
+		if err := r.closers[i](); err != nil {
+			log.Printf("Error closing connection: %v", err)
+		}
+	}
+}
+
+// OpenRepositories connects to the backends selected by the configured mode
+func OpenRepositories(ctx context.Context, cfg *config.Config) (*Repositories, error) {
+	repos := &Repositories{}
+
+	var err error
+	switch cfg.Mode {
+	case config.ModeEmbedded:
+		err = repos.openEmbedded(cfg)
+	case config.ModeDistributed:
+		err = repos.openDistributed(ctx, cfg)
+	default:
+		err = fmt.Errorf("unknown mode %q, expected %q or %q", cfg.Mode, config.ModeDistributed, config.ModeEmbedded)
+	}
+	if err == nil {
+		err = repos.openBlobStore(cfg.Blob)
+	}
+	if err != nil {
+		repos.Close()
+		return nil, err
+	}
+
+	return repos, nil
+}
+
+// openBlobStore opens the configured blob store, independent of the mode
+func (r *Repositories) openBlobStore(cfg config.BlobConfig) error {
+	switch cfg.Store {
+	case config.BlobStoreFilesystem:
+		blobRepo, err := filesystemRepo.NewBlobRepository(cfg.Path)
+		if err != nil {
+			return fmt.Errorf("failed to open blob store: %w", err)
+		}
+		r.Blob = blobRepo
+		log.Printf("Storing blobs in %s", cfg.Path)
+		return nil
+	default:
+		return fmt.Errorf("unknown blob store %q, expected %q", cfg.Store, config.BlobStoreFilesystem)
+	}
+}
+
+// openEmbedded opens the local database holding jobs, locks, responses and reports
+func (r *Repositories) openEmbedded(cfg *config.Config) error {
+	dataPath := cfg.Embedded.DataPath
+	db, err := bboltRepo.Open(dataPath)
+	if err != nil {
+		return fmt.Errorf("failed to open embedded store: %w", err)
+	}
+	r.closers = append(r.closers, db.Close)
+	log.Printf("Opened embedded store at %s", dataPath)
+
+	r.Lock = bboltRepo.NewLockRepository(db)
+	r.Queue = bboltRepo.NewQueueRepository(db, cfg.Worker.RetryDelay)
+	r.Response = bboltRepo.NewResponseRepository(db)
+	r.Report = bboltRepo.NewReportRepository(db)
+	r.Idempotency = bboltRepo.NewIdempotencyRepository(db)
+	r.Job = bboltRepo.NewJobRepository(db)
+	r.Webhook = bboltRepo.NewWebhookRepository(db)
+	r.WebhookQueue = bboltRepo.NewWebhookQueueRepository(db)
+	r.Schedule = bboltRepo.NewScheduleRepository(db)
+	r.Lease = bboltRepo.NewLeaseRepository(db)
+	r.APIKey = bboltRepo.NewAPIKeyRepository(db)
+	r.Quota = bboltRepo.NewQuotaRepository(db)
+
+	// Only this process submits, so its rate limits can be kept in memory
+	r.RateLimit = memoryRepo.NewRateLimitRepository(time.Now)
+	r.RateLimitLocal = r.RateLimit
+
+	// Every subscriber lives in this process, so events do not need to leave it
+	r.Events = memoryRepo.NewEventBusRepository()
+
+	return nil
+}
+
+// openDistributed connects to Redis and RabbitMQ
+func (r *Repositories) openDistributed(ctx context.Context, cfg *config.Config) error {
+	// Initialize Redis client
+	redisClient, err := redisRepo.NewClient(redisRepo.ClientConfig{
+		Mode:       cfg.Redis.Mode,
+		Addrs:      cfg.Redis.Addrs,
+		MasterName: cfg.Redis.MasterName,
+		Password:   cfg.Redis.Password,
+	})
+	if err != nil {
+		return fmt.Errorf("invalid Redis configuration: %w", err)
+	}
+	r.closers = append(r.closers, redisClient.Close)
+
+	// Ping Redis to check connection
+	if _, err := redisClient.Ping(ctx).Result(); err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	log.Printf("Connected to Redis at %s", strings.Join(cfg.Redis.Addrs, ","))
+	r.pingStore = func(ctx context.Context) error {
+		return redisClient.Ping(ctx).Err()
+	}
+
+	// Move data stored before the default tenant had a namespace into it
+	moved, err := redisRepo.MigrateDefaultTenant(ctx, redisClient)
+	if err != nil {
+		return fmt.Errorf("failed to migrate default tenant data: %w", err)
+	}
+	if moved > 0 {
+		log.Printf("Moved %d Redis keys of the default tenant into its namespace", moved)
+	}
+
+	// Initialize the lock repository
+	switch cfg.Lock.Mode {
+	case config.LockModeRedis:
+		r.Lock = redisRepo.NewLockRepository(redisClient)
+	case config.LockModeRedlock:
+		if r.Lock, err = r.openRedlock(cfg.Lock.RedlockAddrs); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown lock mode %q, expected %q or %q", cfg.Lock.Mode, config.LockModeRedis, config.LockModeRedlock)
+	}
+
+	// Initialize RabbitMQ repository
+	r.Queue, err = rabbitmqRepo.NewQueueRepository(cfg.RabbitMQ.URL, rabbitmqRepo.QueueOptions{
+		Name:       cfg.Queues.Report,
+		Shards:     cfg.Queues.ReportShards,
+		DelayName:  cfg.Queues.ReportDelay,
+		RetryDelay: cfg.Worker.RetryDelay,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	r.closers = append(r.closers, r.Queue.Close)
+	log.Printf("Connected to RabbitMQ at %s", config.RedactURL(cfg.RabbitMQ.URL))
+
+	// Webhook deliveries use their own connection so slow receivers never hold up report jobs
+	r.WebhookQueue, err = rabbitmqRepo.NewWebhookQueueRepository(cfg.RabbitMQ.URL, rabbitmqRepo.WebhookQueueOptions{
+		Name:        cfg.Queues.WebhookDelivery,
+		DelayPrefix: cfg.Queues.WebhookDelayPrefix,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	r.closers = append(r.closers, r.WebhookQueue.Close)
+
+	// Initialize Redis storage repositories
+	r.Response = redisRepo.NewResponseRepository(redisClient)
+	r.Report = redisRepo.NewReportRepository(redisClient)
+	r.Idempotency = redisRepo.NewIdempotencyRepository(redisClient)
+	r.Job = redisRepo.NewJobRepository(redisClient)
+	r.Webhook = redisRepo.NewWebhookRepository(redisClient)
+	r.Events = redisRepo.NewEventBusRepository(redisClient)
+	r.Schedule = redisRepo.NewScheduleRepository(redisClient)
+	r.Lease = redisRepo.NewLeaseRepository(redisClient)
+	r.APIKey = redisRepo.NewAPIKeyRepository(redisClient)
+	r.Quota = redisRepo.NewQuotaRepository(redisClient)
+	r.RateLimit = redisRepo.NewRateLimitRepository(redisClient)
+	r.RateLimitLocal = memoryRepo.NewRateLimitRepository(time.Now)
+	if cfg.Reload.Key != "" {
+		r.RuntimeConfig = redisRepo.NewRuntimeConfigRepository(redisClient, cfg.Reload.Key)
+	}
+
+	return nil
+}
+
+// openRedlock connects to every Redlock node and builds the lock repository
+func (r *Repositories) openRedlock(addrs []string) (repository.LockRepository, error) {
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("redlock addresses are required when the lock mode is %q", config.LockModeRedlock)
+	}
+
+	clients := make([]goredis.UniversalClient, 0, len(addrs))
+	for _, addr := range addrs {
+		client := goredis.NewClient(&goredis.Options{Addr: addr})
+		r.closers = append(r.closers, client.Close)
+		clients = append(clients, client)
+	}
+
+	lockRepo, err := redisRepo.NewRedlockRepository(clients)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Redlock repository: %w", err)
+	}
+	log.Printf("Using Redlock across %d Redis nodes", len(addrs))
+
+	return lockRepo, nil
+}
@@ -0,0 +1,145 @@
+package bootstrap
+
+import (
+	"github.com/rfanazhari/distributed-queue-processor/internal/config"
+	grpcHandler "github.com/rfanazhari/distributed-queue-processor/internal/delivery/grpc"
+	httpHandler "github.com/rfanazhari/distributed-queue-processor/internal/delivery/http"
+	"github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/election"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// NewReportSettings maps the lock, report and per-survey configuration to the report use case settings
+func NewReportSettings(cfg *config.Config) usecase.ReportSettings {
+	settings := usecase.ReportSettings{
+		LockTTL:           cfg.Lock.TTL,
+		LockKeyPrefix:     cfg.Lock.KeyPrefix,
+		DebounceMode:      usecase.DebounceMode(cfg.Report.DebounceMode),
+		RecomputeEvery:    cfg.Report.RecomputeEvery,
+		RecomputeInterval: cfg.Report.RecomputeInterval,
+	}
+
+	if len(cfg.Surveys) > 0 {
+		settings.Surveys = make(map[string]usecase.SurveySettings, len(cfg.Surveys))
+		for surveyID, survey := range cfg.Surveys {
+			settings.Surveys[surveyID] = usecase.SurveySettings{
+				LockTTL:      survey.LockTTL,
+				DebounceMode: usecase.DebounceMode(survey.DebounceMode),
+			}
+		}
+	}
+
+	return settings
+}
+
+// NewWorkerSettings maps the worker configuration to the report worker settings
+func NewWorkerSettings(cfg *config.Config) usecase.WorkerSettings {
+	return usecase.WorkerSettings{
+		Concurrency:       cfg.Worker.Concurrency,
+		Prefetch:          cfg.Worker.Prefetch,
+		Scheduling:        usecase.Scheduling(cfg.Worker.Scheduling),
+		TenantConcurrency: cfg.Worker.TenantConcurrency,
+	}
+}
+
+// NewQuotaSettings maps the quota and per-tenant configuration to the quota use case settings
+func NewQuotaSettings(cfg *config.Config) usecase.QuotaSettings {
+	settings := usecase.QuotaSettings{
+		ResponsesPerDay: cfg.Quota.ResponsesPerDay,
+	}
+
+	for tenantID, tenant := range cfg.Tenants {
+		if tenant.ResponsesPerDay == 0 {
+			continue
+		}
+		if settings.Tenants == nil {
+			settings.Tenants = make(map[string]int64)
+		}
+		// A negative quota lifts the tenant's quota, which the use case expresses as zero
+		settings.Tenants[tenantID] = max(tenant.ResponsesPerDay, 0)
+	}
+
+	return settings
+}
+
+// NewRateLimitSettings maps the rate limit configuration, with the overrides
+// of individual callers and surveys, to the rate limits
+func NewRateLimitSettings(cfg *config.Config) usecase.RateLimitSettings {
+	settings := usecase.RateLimitSettings{
+		Instance: usecase.RateLimitRule{Rate: cfg.RateLimit.Submit, Burst: cfg.RateLimit.Burst},
+		Client:   usecase.RateLimitRule{Rate: cfg.RateLimit.Client, Burst: cfg.RateLimit.ClientBurst},
+		IP:       usecase.RateLimitRule{Rate: cfg.RateLimit.IP, Burst: cfg.RateLimit.IPBurst},
+		Survey:   usecase.RateLimitRule{Rate: cfg.RateLimit.Survey, Burst: cfg.RateLimit.SurveyBurst},
+	}
+
+	if len(cfg.Clients) > 0 {
+		settings.Clients = make(map[string]usecase.RateLimitRule, len(cfg.Clients))
+		for client, override := range cfg.Clients {
+			settings.Clients[client] = usecase.RateLimitRule{Rate: override.RateLimit, Burst: override.RateLimitBurst}
+		}
+	}
+	for surveyID, survey := range cfg.Surveys {
+		if survey.RateLimit == 0 {
+			continue
+		}
+		if settings.Surveys == nil {
+			settings.Surveys = make(map[string]usecase.RateLimitRule)
+		}
+		settings.Surveys[surveyID] = usecase.RateLimitRule{Rate: survey.RateLimit, Burst: survey.RateLimitBurst}
+	}
+
+	return settings
+}
+
+// NewWebhookSettings maps the webhook configuration to the delivery retry
+// policy and the networks receivers may be in
+func NewWebhookSettings(cfg *config.Config) usecase.WebhookSettings {
+	// The networks were checked when the configuration was validated
+	allowed, _ := cfg.Webhook.AllowedPrefixes()
+	return usecase.WebhookSettings{
+		MaxAttempts:     cfg.Webhook.MaxAttempts,
+		BaseBackoff:     cfg.Webhook.Backoff,
+		MaxBackoff:      cfg.Webhook.MaxBackoff,
+		Timeout:         cfg.Webhook.Timeout,
+		AllowedNetworks: allowed,
+	}
+}
+
+// NewSchedulerSettings maps the scheduler configuration to the report scheduler settings
+func NewSchedulerSettings(cfg *config.Config) usecase.SchedulerSettings {
+	return usecase.SchedulerSettings{
+		TickInterval: cfg.Scheduler.Tick,
+	}
+}
+
+// NewElectionSettings maps the leader configuration to the leader election timing
+func NewElectionSettings(cfg *config.Config) election.Settings {
+	return election.Settings{
+		LeaseTTL:      cfg.Leader.LeaseTTL,
+		RenewInterval: cfg.Leader.RenewInterval,
+		RetryInterval: cfg.Leader.RetryInterval,
+	}
+}
+
+// NewHandlerSettings maps the HTTP and rate limit configuration to the HTTP handler settings
+func NewHandlerSettings(cfg *config.Config) httpHandler.HandlerSettings {
+	return httpHandler.HandlerSettings{
+		RequireAuth:       cfg.Auth.Enabled,
+		MaxBatchSize:      cfg.HTTP.MaxBatchSize,
+		MaxBodyBytes:      cfg.HTTP.MaxBodyBytes,
+		MaxBatchBytes:     cfg.HTTP.MaxBatchBytes,
+		MaxAnswers:        cfg.HTTP.MaxAnswers,
+		MaxAnswerDepth:    cfg.HTTP.MaxAnswerDepth,
+		TrustForwardedFor: cfg.RateLimit.TrustForwardedFor,
+	}
+}
+
+// NewGRPCSettings maps the HTTP request limits and authentication configuration
+// to the gRPC server settings, so both APIs accept the same responses
+func NewGRPCSettings(cfg *config.Config) grpcHandler.ServerSettings {
+	return grpcHandler.ServerSettings{
+		RequireAuth:    cfg.Auth.Enabled,
+		MaxBatchSize:   cfg.HTTP.MaxBatchSize,
+		MaxAnswers:     cfg.HTTP.MaxAnswers,
+		MaxAnswerDepth: cfg.HTTP.MaxAnswerDepth,
+	}
+}
@@ -0,0 +1,277 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv is the environment variable naming the configuration file when no -config flag is given
+const FileEnv = "CONFIG_FILE"
+
+// Load builds the configuration from the defaults, then the YAML or TOML file
+// at path, then the environment, then flagValues keyed by dotted setting path,
+// and validates the result. When path is empty the file named by CONFIG_FILE
+// is read, if any
+func Load(path string, flagValues map[string]string) (*Config, error) {
+	cfg := Default()
+
+	if path == "" {
+		path = os.Getenv(FileEnv)
+	}
+	if path != "" {
+		if err := readFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	settings := settingsOf(cfg)
+	for _, s := range settings {
+		if s.env == "" {
+			continue
+		}
+		// An empty variable counts as unset, as it always has
+		if raw := os.Getenv(s.env); raw != "" {
+			if err := s.set(raw); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", s.env, err)
+			}
+		}
+	}
+
+	byPath := make(map[string]setting, len(settings))
+	for _, s := range settings {
+		byPath[s.path] = s
+	}
+	for path, raw := range flagValues {
+		s, ok := byPath[path]
+		if !ok {
+			return nil, fmt.Errorf("unknown setting %q", path)
+		}
+		if err := s.set(raw); err != nil {
+			return nil, fmt.Errorf("invalid -%s: %w", path, err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	return cfg, nil
+}
+
+// readFile decodes a YAML or TOML file, chosen by its extension, over cfg
+// Unknown keys are rejected so a misspelt setting does not go unnoticed
+func readFile(path string, cfg *Config) error {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open config file: %w", err)
+		}
+		defer f.Close()
+
+		decoder := yaml.NewDecoder(f)
+		decoder.KnownFields(true)
+		if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	case ".toml":
+		meta, err := toml.DecodeFile(path, cfg)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("failed to parse %s: unknown keys %q", path, undecoded)
+		}
+	default:
+		return fmt.Errorf("unsupported config file extension %q, expected .yaml, .yml or .toml", ext)
+	}
+
+	return nil
+}
+
+// Flags holds the command-line flags registered for the configuration
+type Flags struct {
+	path   string
+	values map[string]string
+}
+
+// RegisterFlags registers -config and one flag per setting, named by its dotted
+// path such as -lock.ttl, on fs
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{values: make(map[string]string)}
+	fs.StringVar(&f.path, "config", "", "path of a YAML or TOML config file (env "+FileEnv+")")
+
+	for _, s := range settingsOf(Default()) {
+		if s.value.Kind() == reflect.Map {
+			// Maps can only be set from a file
+			continue
+		}
+		usage := s.help
+		if s.env != "" {
+			usage += " (env " + s.env + ")"
+		}
+		fs.Var(&flagValue{path: s.path, values: f.values, defaultValue: s.String()}, s.path, usage)
+	}
+
+	return f
+}
+
+// Load builds the configuration with the flags that were set taking precedence
+func (f *Flags) Load() (*Config, error) {
+	return Load(f.path, f.values)
+}
+
+// flagValue records the raw value of a setting's flag until the configuration is loaded
+type flagValue struct {
+	path         string
+	values       map[string]string
+	defaultValue string
+}
+
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+	if raw, ok := v.values[v.path]; ok {
+		return raw
+	}
+	return v.defaultValue
+}
+
+func (v *flagValue) Set(raw string) error {
+	// Parse into a scratch setting so a bad value is reported by the flag package
+	if err := setValue(reflect.New(settingType(v.path)).Elem(), raw); err != nil {
+		return err
+	}
+	v.values[v.path] = raw
+	return nil
+}
+
+// setting is one configurable field of a Config
+type setting struct {
+	path   string
+	env    string
+	help   string
+	secret string
+	value  reflect.Value
+}
+
+// set parses raw into the setting's field
+func (s setting) set(raw string) error {
+	return setValue(s.value, raw)
+}
+
+// String formats the setting's value the way it is parsed
+func (s setting) String() string {
+	switch v := s.value.Interface().(type) {
+	case []string:
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// settingsOf lists every setting of cfg ordered by path
+func settingsOf(cfg *Config) []setting {
+	var settings []setting
+	collect(reflect.ValueOf(cfg).Elem(), "", &settings)
+	sort.Slice(settings, func(i, j int) bool { return settings[i].path < settings[j].path })
+	return settings
+}
+
+// collect walks a config struct, adding every leaf field to settings
+func collect(v reflect.Value, prefix string, settings *[]setting) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		path := prefix + strings.Split(field.Tag.Get("yaml"), ",")[0]
+
+		if field.Type.Kind() == reflect.Struct {
+			collect(v.Field(i), path+".", settings)
+			continue
+		}
+
+		*settings = append(*settings, setting{
+			path:   path,
+			env:    field.Tag.Get("env"),
+			help:   field.Tag.Get("help"),
+			secret: field.Tag.Get("secret"),
+			value:  v.Field(i),
+		})
+	}
+}
+
+// settingType returns the type of the setting at path
+func settingType(path string) reflect.Type {
+	for _, s := range settingsOf(Default()) {
+		if s.path == path {
+			return s.value.Type()
+		}
+	}
+	return reflect.TypeOf("")
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setValue parses raw into v according to its type
+func setValue(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", raw)
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", raw)
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		v.Set(reflect.ValueOf(SplitList(raw)))
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
+
+// SplitList splits a comma-separated list, dropping empty entries
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
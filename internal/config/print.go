@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces the value of a secret setting when it is printed
+const redacted = "REDACTED"
+
+// Redacted returns a copy of the configuration with every secret replaced
+// Secrets tagged secret:"url" only have the password of the URL replaced
+func (c *Config) Redacted() *Config {
+	copied := c.clone()
+	for _, s := range settingsOf(copied) {
+		if s.secret == "" || s.value.String() == "" {
+			continue
+		}
+		if s.secret == "url" {
+			s.value.SetString(RedactURL(s.value.String()))
+			continue
+		}
+		s.value.SetString(redacted)
+	}
+
+	return copied
+}
+
+// Print writes the configuration as YAML with every secret redacted
+func (c *Config) Print(w io.Writer) error {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// RedactURL replaces the password of a URL, or the whole value if it does not parse
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return redacted
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redacted)
+	}
+	return u.String()
+}
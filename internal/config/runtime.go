@@ -0,0 +1,147 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// reloadable lists, by path, the settings that are applied again on reload
+// Every other setting only takes effect after a restart
+var reloadable = map[string]bool{
+	"lock.ttl":                       true,
+	"report.debounce_mode":           true,
+	"surveys":                        true,
+	"worker.concurrency":             true,
+	"worker.prefetch":                true,
+	"worker.scheduling":              true,
+	"worker.tenant_concurrency":      true,
+	"quota.responses_per_day":        true,
+	"tenants":                        true,
+	"clients":                        true,
+	"rate_limit.submit":              true,
+	"rate_limit.burst":               true,
+	"rate_limit.client":              true,
+	"rate_limit.client_burst":        true,
+	"rate_limit.ip":                  true,
+	"rate_limit.ip_burst":            true,
+	"rate_limit.survey":              true,
+	"rate_limit.survey_burst":        true,
+	"rate_limit.trust_forwarded_for": true,
+	"log.level":                      true,
+}
+
+// Reloadable reports whether the setting at path, such as lock.ttl, can
+// change while the service runs
+func Reloadable(path string) bool {
+	return reloadable[path]
+}
+
+// WithOverrides returns a copy of the configuration with a YAML or JSON
+// document of runtime overrides decoded over it, laid out like a config file
+// Overriding a setting that is not reloadable is rejected, and the entries
+// of a survey in surveys replace that survey's entry as a whole
+func (c *Config) WithOverrides(doc []byte) (*Config, error) {
+	next := c.clone()
+
+	decoder := yaml.NewDecoder(bytes.NewReader(doc))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(next); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse runtime overrides: %w", err)
+	}
+
+	if _, restart := c.Changes(next); len(restart) > 0 {
+		return nil, fmt.Errorf("runtime overrides may only change reloadable settings, not %q", restart)
+	}
+	if err := next.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid runtime overrides:\n%w", err)
+	}
+
+	return next, nil
+}
+
+// WithRuntime returns a copy of the configuration with every reloadable
+// setting taken from other
+func (c *Config) WithRuntime(other *Config) *Config {
+	next := c.clone()
+	from := other.clone()
+
+	values := make(map[string]reflect.Value)
+	for _, s := range settingsOf(from) {
+		values[s.path] = s.value
+	}
+	for _, s := range settingsOf(next) {
+		if reloadable[s.path] {
+			s.value.Set(values[s.path])
+		}
+	}
+
+	return next
+}
+
+// Changes lists the paths of the settings whose value differs in other,
+// split into those that are applied on reload and those that need a restart
+func (c *Config) Changes(other *Config) (applied, restart []string) {
+	values := make(map[string]reflect.Value)
+	for _, s := range settingsOf(other) {
+		values[s.path] = s.value
+	}
+
+	for _, s := range settingsOf(c) {
+		if equalSetting(s.value, values[s.path]) {
+			continue
+		}
+		if reloadable[s.path] {
+			applied = append(applied, s.path)
+		} else {
+			restart = append(restart, s.path)
+		}
+	}
+
+	return applied, restart
+}
+
+// equalSetting compares two setting values, treating empty and nil lists and maps alike
+func equalSetting(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Slice, reflect.Map:
+		if a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// clone returns a deep copy of the configuration
+func (c *Config) clone() *Config {
+	copied := *c
+	copied.Redis.Addrs = append([]string(nil), c.Redis.Addrs...)
+	copied.Lock.RedlockAddrs = append([]string(nil), c.Lock.RedlockAddrs...)
+	copied.Report.Documents = append([]string(nil), c.Report.Documents...)
+	copied.Auth.JWTKeys = append([]string(nil), c.Auth.JWTKeys...)
+
+	if c.Surveys != nil {
+		copied.Surveys = make(map[string]SurveyConfig, len(c.Surveys))
+		for surveyID, survey := range c.Surveys {
+			copied.Surveys[surveyID] = survey
+		}
+	}
+	if c.Tenants != nil {
+		copied.Tenants = make(map[string]TenantConfig, len(c.Tenants))
+		for tenantID, tenant := range c.Tenants {
+			copied.Tenants[tenantID] = tenant
+		}
+	}
+	if c.Clients != nil {
+		copied.Clients = make(map[string]ClientConfig, len(c.Clients))
+		for client, override := range c.Clients {
+			copied.Clients[client] = override
+		}
+	}
+
+	return &copied
+}
@@ -0,0 +1,171 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+	surveyv1 "github.com/rfanazhari/distributed-queue-processor/proto/survey/v1"
+)
+
+const (
+	// apiKeyMetadata is the metadata key carrying an API key
+	apiKeyMetadata = "x-api-key"
+
+	// tenantMetadata is the metadata key naming the tenant a call acts for
+	tenantMetadata = "x-tenant-id"
+)
+
+// methodScopes maps every method of the survey service to the scope it requires
+var methodScopes = map[string]string{
+	surveyv1.SurveyService_SubmitResponse_FullMethodName: entity.ScopeSubmit,
+	surveyv1.SurveyService_BatchSubmit_FullMethodName:    entity.ScopeSubmit,
+	surveyv1.SurveyService_GetReport_FullMethodName:      entity.ScopeReadReport,
+	surveyv1.SurveyService_GetJobStatus_FullMethodName:   entity.ScopeReadReport,
+	surveyv1.SurveyService_WatchReport_FullMethodName:    entity.ScopeReadReport,
+}
+
+// authorizeUnary runs unary calls with the context authorize returns
+func (s *Server) authorizeUnary(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := s.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, request)
+}
+
+// authorizeStream runs streaming calls with the context authorize returns
+func (s *Server) authorizeStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authorize(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+}
+
+// contextStream is a server stream with a context of its own
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the stream's context
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorize returns the context a call to method runs with, carrying the
+// caller's identity and tenant, or the status rejecting the call when the
+// caller is not granted the method's scope
+// Every caller is let through while authentication is not required
+func (s *Server) authorize(ctx context.Context, method string) (context.Context, error) {
+	scope, ok := methodScopes[method]
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "unknown method %s", method)
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if !s.settings.RequireAuth {
+		tenantID, err := resolveTenant(md, nil)
+		if err != nil {
+			return nil, err
+		}
+		return entity.WithTenant(ctx, tenantID), nil
+	}
+
+	identity, err := s.authenticate(ctx, md)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	if !identity.HasScope(scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "the %s scope is required", scope)
+	}
+	tenantID, err := resolveTenant(md, identity)
+	if err != nil {
+		return nil, err
+	}
+	return entity.WithTenant(usecase.WithIdentity(ctx, identity), tenantID), nil
+}
+
+// resolveTenant returns the tenant the call acts for: the tenant the caller
+// is bound to, else the one named by the x-tenant-id metadata when the caller
+// may act for any tenant, else the default tenant. Only unbound admin
+// credentials may name a tenant; callers without credentials act for the
+// default tenant. It returns the status rejecting the call when the metadata names
+// an invalid tenant or one the caller may not act for
+func resolveTenant(md metadata.MD, identity *entity.Identity) (string, error) {
+	requested := firstValue(md, tenantMetadata)
+	if requested != "" && !entity.ValidTenantID(requested) {
+		return "", status.Error(codes.InvalidArgument, "invalid tenant ID "+strconv.Quote(requested))
+	}
+
+	tenantID := entity.DefaultTenant
+	switch {
+	case identity != nil && identity.TenantID != "":
+		tenantID = identity.TenantID
+	case requested != "" && identity != nil && identity.HasScope(entity.ScopeAdmin):
+		tenantID = requested
+	}
+	if requested != "" && requested != tenantID {
+		return "", status.Error(codes.PermissionDenied, "the credentials are not valid for tenant "+requested)
+	}
+	return tenantID, nil
+}
+
+// authenticate identifies the caller by the API key in the x-api-key
+// metadata, or by the API key or JWT sent as a bearer token
+func (s *Server) authenticate(ctx context.Context, md metadata.MD) (*entity.Identity, error) {
+	if key := firstValue(md, apiKeyMetadata); key != "" {
+		return s.authUseCase.AuthenticateAPIKey(ctx, key)
+	}
+
+	scheme, token, ok := strings.Cut(firstValue(md, "authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, fmt.Errorf("%w: an API key or bearer token is required", usecase.ErrUnauthenticated)
+	}
+	token = strings.TrimSpace(token)
+	if strings.HasPrefix(token, usecase.APIKeyPrefix) {
+		return s.authUseCase.AuthenticateAPIKey(ctx, token)
+	}
+	return s.authUseCase.AuthenticateToken(ctx, token)
+}
+
+// firstValue returns the first value of a metadata key, empty if it has none
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// submitter returns the caller recorded on the responses it submits, empty
+// when the call was not authenticated
+func submitter(ctx context.Context) string {
+	if identity := usecase.IdentityFromContext(ctx); identity != nil {
+		return identity.String()
+	}
+	return ""
+}
+
+// peerIP returns the IP address the call came from, empty if unknown
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
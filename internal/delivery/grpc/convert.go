@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	surveyv1 "github.com/rfanazhari/distributed-queue-processor/proto/survey/v1"
+)
+
+// jobStatuses maps every job status to its protobuf enum value
+var jobStatuses = map[string]surveyv1.Job_Status{
+	entity.JobStatusQueued:    surveyv1.Job_STATUS_QUEUED,
+	entity.JobStatusRunning:   surveyv1.Job_STATUS_RUNNING,
+	entity.JobStatusCompleted: surveyv1.Job_STATUS_COMPLETED,
+	entity.JobStatusSkipped:   surveyv1.Job_STATUS_SKIPPED,
+	entity.JobStatusFailed:    surveyv1.Job_STATUS_FAILED,
+}
+
+// toReport converts a report to its protobuf message
+func toReport(report *entity.Report) *surveyv1.Report {
+	message := &surveyv1.Report{
+		SurveyId:      report.SurveyID,
+		ResponseCount: int64(report.ResponseCount),
+		GeneratedAt:   report.GeneratedAt,
+		Version:       report.Version,
+		Questions:     make(map[string]*surveyv1.QuestionSummary, len(report.Questions)),
+	}
+	for question, summary := range report.Questions {
+		message.Questions[question] = toQuestionSummary(summary)
+	}
+	return message
+}
+
+// toQuestionSummary converts the summary of a question to its protobuf message
+func toQuestionSummary(summary *entity.QuestionSummary) *surveyv1.QuestionSummary {
+	message := &surveyv1.QuestionSummary{
+		Answered: int64(summary.Answered),
+		Distinct: int64(summary.Distinct),
+	}
+	if len(summary.Counts) > 0 {
+		message.Counts = make(map[string]int64, len(summary.Counts))
+		for answer, count := range summary.Counts {
+			message.Counts[answer] = int64(count)
+		}
+	}
+	if numeric := summary.Numeric; numeric != nil {
+		message.Numeric = &surveyv1.NumericSummary{
+			Count:  int64(numeric.Count),
+			Sum:    numeric.Sum,
+			Min:    numeric.Min,
+			Max:    numeric.Max,
+			Mean:   numeric.Mean,
+			StdDev: numeric.StdDev,
+			Median: numeric.Median,
+			P90:    numeric.P90,
+			P99:    numeric.P99,
+		}
+	}
+	return message
+}
+
+// toJob converts a job record to its protobuf message
+func toJob(job entity.JobRecord) *surveyv1.Job {
+	return &surveyv1.Job{
+		Id:          job.ID,
+		SurveyId:    job.SurveyID,
+		Status:      jobStatuses[job.Status],
+		RequestedAt: job.RequestedAt,
+		NotBefore:   job.NotBefore,
+		StartedAt:   job.StartedAt,
+		FinishedAt:  job.FinishedAt,
+		Attempts:    int32(job.Attempts),
+		Error:       job.Error,
+	}
+}
@@ -0,0 +1,297 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+	surveyv1 "github.com/rfanazhari/distributed-queue-processor/proto/survey/v1"
+)
+
+// ServerSettings holds the request limits and access control of the gRPC server
+type ServerSettings struct {
+	// RequireAuth rejects calls without an API key or JWT granting the method's scope
+	RequireAuth bool
+
+	// MaxBatchSize is the maximum number of responses accepted in one batch
+	MaxBatchSize int
+
+	// MaxAnswers is the maximum number of answers accepted in one response
+	MaxAnswers int
+
+	// MaxAnswerDepth is how deeply lists and structs may nest in an answer;
+	// zero only accepts plain values
+	MaxAnswerDepth int
+}
+
+// Server serves the survey API over gRPC on the same use cases as the HTTP handler
+type Server struct {
+	surveyv1.UnimplementedSurveyServiceServer
+
+	reportUseCase    usecase.ReportUseCase
+	idGenerator      usecase.IDGenerator
+	eventUseCase     usecase.EventUseCase
+	authUseCase      usecase.AuthUseCase
+	quotaUseCase     usecase.QuotaUseCase
+	rateLimitUseCase usecase.RateLimitUseCase
+	settings         ServerSettings
+}
+
+// NewServer creates a new gRPC survey service
+func NewServer(
+	reportUseCase usecase.ReportUseCase,
+	idGenerator usecase.IDGenerator,
+	eventUseCase usecase.EventUseCase,
+	authUseCase usecase.AuthUseCase,
+	quotaUseCase usecase.QuotaUseCase,
+	rateLimitUseCase usecase.RateLimitUseCase,
+	settings ServerSettings,
+) *Server {
+	return &Server{
+		reportUseCase:    reportUseCase,
+		idGenerator:      idGenerator,
+		eventUseCase:     eventUseCase,
+		authUseCase:      authUseCase,
+		quotaUseCase:     quotaUseCase,
+		rateLimitUseCase: rateLimitUseCase,
+		settings:         settings,
+	}
+}
+
+// NewGRPCServer creates a gRPC server serving the survey service, with every
+// call recovered from panics and authorized for its method's scope
+func (s *Server) NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(recoverUnary, s.authorizeUnary),
+		grpc.ChainStreamInterceptor(recoverStream, s.authorizeStream),
+	)
+	server := grpc.NewServer(opts...)
+	surveyv1.RegisterSurveyServiceServer(server, s)
+	return server
+}
+
+// Shutdown stops server from accepting calls and waits for the open ones to
+// finish, closing them when ctx is done first
+func Shutdown(ctx context.Context, server *grpc.Server) error {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		server.Stop()
+		return ctx.Err()
+	}
+}
+
+// SubmitResponse submits one survey response
+func (s *Server) SubmitResponse(ctx context.Context, request *surveyv1.SubmitResponseRequest) (*surveyv1.SubmitResponseResponse, error) {
+	if err := usecase.ValidateSurveyID(request.GetSurveyId()); err != nil {
+		return nil, statusError(ctx, err)
+	}
+	answers := request.GetAnswers().AsMap()
+	if err := usecase.CheckAnswerLimits(answers, s.settings.MaxAnswers, s.settings.MaxAnswerDepth); err != nil {
+		return nil, statusError(ctx, err)
+	}
+	if err := s.allowRateLimit(ctx, []string{request.GetSurveyId()}, nil); err != nil {
+		return nil, err
+	}
+	if err := s.consumeQuota(ctx, 1); err != nil {
+		return nil, err
+	}
+
+	response := entity.SurveyResponse{
+		ID:          s.idGenerator.NewID(),
+		SurveyID:    request.GetSurveyId(),
+		Answers:     answers,
+		CreatedAt:   time.Now().Unix(),
+		SubmittedBy: submitter(ctx),
+	}
+	if err := s.reportUseCase.SubmitResponse(ctx, response); err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return &surveyv1.SubmitResponseResponse{Id: response.ID}, nil
+}
+
+// BatchSubmit submits many survey responses at once, accepting or rejecting each on its own
+func (s *Server) BatchSubmit(ctx context.Context, request *surveyv1.BatchSubmitRequest) (*surveyv1.BatchSubmitResponse, error) {
+	requests := request.GetResponses()
+	if len(requests) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "batch must contain at least one response")
+	}
+	if len(requests) > s.settings.MaxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "batch exceeds %d responses", s.settings.MaxBatchSize)
+	}
+
+	// Create a survey response for every valid item within the answer limits
+	now := time.Now().Unix()
+	caller := submitter(ctx)
+	itemErrs := make([]error, len(requests))
+	responses := make([]entity.SurveyResponse, 0, len(requests))
+	indexes := make([]int, 0, len(requests))
+	for i, item := range requests {
+		answers := item.GetAnswers().AsMap()
+		if itemErrs[i] = usecase.CheckAnswerLimits(answers, s.settings.MaxAnswers, s.settings.MaxAnswerDepth); itemErrs[i] != nil {
+			continue
+		}
+		response := entity.SurveyResponse{
+			ID:          s.idGenerator.NewID(),
+			SurveyID:    item.GetSurveyId(),
+			Answers:     answers,
+			CreatedAt:   now,
+			SubmittedBy: caller,
+		}
+		if itemErrs[i] = usecase.ValidateResponse(response); itemErrs[i] != nil {
+			continue
+		}
+		responses = append(responses, response)
+		indexes = append(indexes, i)
+	}
+
+	// Only the items that can be accepted count against the rate limits and the quota
+	surveyIDs, surveyResponses := batchSurveys(responses)
+	if err := s.allowRateLimit(ctx, surveyIDs, surveyResponses); err != nil {
+		return nil, err
+	}
+	if len(responses) > 0 {
+		if err := s.consumeQuota(ctx, len(responses)); err != nil {
+			return nil, err
+		}
+	}
+
+	submitErrs, err := s.reportUseCase.SubmitResponses(ctx, responses)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	results := make([]*surveyv1.BatchItemResult, len(requests))
+	for i, itemErr := range itemErrs {
+		results[i] = &surveyv1.BatchItemResult{Index: int32(i), Status: surveyv1.BatchItemResult_STATUS_REJECTED}
+		if itemErr != nil {
+			results[i].Error = itemErr.Error()
+		}
+	}
+	accepted := 0
+	for j, response := range responses {
+		i := indexes[j]
+		if submitErrs[j] != nil {
+			results[i].Error = submitErrs[j].Error()
+			continue
+		}
+		results[i] = &surveyv1.BatchItemResult{Index: int32(i), Id: response.ID, Status: surveyv1.BatchItemResult_STATUS_ACCEPTED}
+		accepted++
+	}
+
+	return &surveyv1.BatchSubmitResponse{
+		Accepted: int32(accepted),
+		Rejected: int32(len(requests) - accepted),
+		Results:  results,
+	}, nil
+}
+
+// GetReport returns the latest report of a survey
+func (s *Server) GetReport(ctx context.Context, request *surveyv1.GetReportRequest) (*surveyv1.Report, error) {
+	if err := usecase.ValidateSurveyID(request.GetSurveyId()); err != nil {
+		return nil, statusError(ctx, err)
+	}
+	report, err := s.reportUseCase.GetReport(ctx, request.GetSurveyId())
+	if err != nil {
+		return nil, statusError(ctx, fmt.Errorf("report of survey %s: %w", request.GetSurveyId(), err))
+	}
+	return toReport(report), nil
+}
+
+// GetJobStatus returns a report job of a survey, its most recent one when no job ID is given
+func (s *Server) GetJobStatus(ctx context.Context, request *surveyv1.GetJobStatusRequest) (*surveyv1.Job, error) {
+	if err := usecase.ValidateSurveyID(request.GetSurveyId()); err != nil {
+		return nil, statusError(ctx, err)
+	}
+	jobs, err := s.reportUseCase.ListJobs(ctx, request.GetSurveyId())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	// Jobs are listed oldest first
+	for i := len(jobs) - 1; i >= 0; i-- {
+		if request.GetJobId() == "" || jobs[i].ID == request.GetJobId() {
+			return toJob(jobs[i]), nil
+		}
+	}
+	if request.GetJobId() == "" {
+		return nil, statusError(ctx, fmt.Errorf("%w: survey %s has no report jobs", repository.ErrNotFound, request.GetSurveyId()))
+	}
+	return nil, statusError(ctx, fmt.Errorf("%w: job %s of survey %s", repository.ErrNotFound, request.GetJobId(), request.GetSurveyId()))
+}
+
+// WatchReport streams the latest report of a survey, if any, followed by every new version
+// The stream ends with UNAVAILABLE when the caller falls behind or the server
+// shuts down, so the caller watches again and starts from the current report
+func (s *Server) WatchReport(request *surveyv1.WatchReportRequest, stream surveyv1.SurveyService_WatchReportServer) error {
+	ctx := stream.Context()
+	surveyID := request.GetSurveyId()
+	if err := usecase.ValidateSurveyID(surveyID); err != nil {
+		return statusError(ctx, err)
+	}
+
+	// Subscribe before reading the report so no update between the two is missed
+	events, unsubscribe := s.eventUseCase.Subscribe(ctx, surveyID)
+	defer unsubscribe()
+
+	// A survey without a report yet is watched from its first one
+	var version int64
+	report, err := s.reportUseCase.GetReport(ctx, surveyID)
+	switch {
+	case err == nil:
+		if err := stream.Send(toReport(report)); err != nil {
+			return err
+		}
+		version = report.Version
+	case !errors.Is(err, repository.ErrNotFound):
+		return statusError(ctx, fmt.Errorf("report of survey %s: %w", surveyID, err))
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case event, ok := <-events:
+			if !ok {
+				return status.Error(codes.Unavailable, "the report stream ended; watch again to resume")
+			}
+			// Skip job updates and versions the stream already sent
+			if event.Type != entity.EventReportUpdated || event.Report == nil || event.Report.Version <= version {
+				continue
+			}
+			if err := stream.Send(toReport(event.Report)); err != nil {
+				return err
+			}
+			version = event.Report.Version
+		}
+	}
+}
+
+// batchSurveys lists every survey the batch submits to once, in order of
+// appearance, and counts the responses it submits to each
+func batchSurveys(responses []entity.SurveyResponse) ([]string, map[string]int) {
+	var surveyIDs []string
+	counts := make(map[string]int)
+	for _, response := range responses {
+		if counts[response.SurveyID] == 0 {
+			surveyIDs = append(surveyIDs, response.SurveyID)
+		}
+		counts[response.SurveyID]++
+	}
+	return surveyIDs, counts
+}
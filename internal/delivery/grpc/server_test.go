@@ -0,0 +1,465 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	bboltRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/bbolt"
+	"github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/idgen"
+	memoryRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/memory"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+	surveyv1 "github.com/rfanazhari/distributed-queue-processor/proto/survey/v1"
+)
+
+// testServer is the gRPC survey service served over an in-memory connection
+// by real use cases over an embedded store
+type testServer struct {
+	client  surveyv1.SurveyServiceClient
+	server  *grpc.Server
+	db      *bolt.DB
+	reports usecase.ReportUseCase
+	events  usecase.EventUseCase
+	auth    usecase.AuthUseCase
+}
+
+func newTestServer(t *testing.T, settings ServerSettings) *testServer {
+	t.Helper()
+	db, err := bboltRepo.Open(filepath.Join(t.TempDir(), "grpc.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	reportRepo := bboltRepo.NewReportRepository(db)
+	idGenerator := idgen.NewULIDGenerator()
+	eventUseCase := usecase.NewEventUseCase(memoryRepo.NewEventBusRepository(), reportRepo)
+	if err := eventUseCase.StartBroker(context.Background()); err != nil {
+		t.Fatalf("Failed to start event broker: %v", err)
+	}
+	t.Cleanup(func() { eventUseCase.StopBroker() })
+	reportUseCase := usecase.NewReportUseCase(bboltRepo.NewLockRepository(db), bboltRepo.NewQueueRepository(db, time.Second),
+		bboltRepo.NewResponseRepository(db), reportRepo, bboltRepo.NewJobRepository(db), usecase.DefaultReportSettings())
+	authUseCase := usecase.NewAuthUseCase(bboltRepo.NewAPIKeyRepository(db), nil, idGenerator)
+
+	service := NewServer(
+		reportUseCase,
+		idGenerator,
+		eventUseCase,
+		authUseCase,
+		usecase.NewQuotaUseCase(bboltRepo.NewQuotaRepository(db), usecase.QuotaSettings{ResponsesPerDay: 5}),
+		usecase.NewRateLimitUseCase(memoryRepo.NewRateLimitRepository(time.Now), nil, usecase.RateLimitSettings{}),
+		settings,
+	)
+	server := service.NewGRPCServer()
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return &testServer{
+		client:  surveyv1.NewSurveyServiceClient(conn),
+		server:  server,
+		db:      db,
+		reports: reportUseCase,
+		events:  eventUseCase,
+		auth:    authUseCase,
+	}
+}
+
+func testSettings() ServerSettings {
+	return ServerSettings{MaxBatchSize: 3, MaxAnswers: 2, MaxAnswerDepth: 1}
+}
+
+func newAnswers(t *testing.T, answers map[string]interface{}) *structpb.Struct {
+	t.Helper()
+	s, err := structpb.NewStruct(answers)
+	if err != nil {
+		t.Fatalf("Failed to build answers: %v", err)
+	}
+	return s
+}
+
+// expectStatus checks that err is a status of the given code whose
+// ErrorInfo detail, if reason is not empty, has the given reason
+func expectStatus(t *testing.T, err error, code codes.Code, reason string) {
+	t.Helper()
+	st := status.Convert(err)
+	if st.Code() != code {
+		t.Fatalf("Expected %s, got %v", code, err)
+	}
+	if reason == "" {
+		return
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			if info.Reason != reason || info.Domain != errorDomain {
+				t.Errorf("Expected reason %s in domain %s, got %s in %s", reason, errorDomain, info.Reason, info.Domain)
+			}
+			return
+		}
+	}
+	t.Errorf("Expected an ErrorInfo detail with reason %s, got %v", reason, st.Details())
+}
+
+func TestSubmitResponse(t *testing.T) {
+	ts := newTestServer(t, testSettings())
+	ctx := context.Background()
+
+	submitted, err := ts.client.SubmitResponse(ctx, &surveyv1.SubmitResponseRequest{
+		SurveyId: "s1",
+		Answers:  newAnswers(t, map[string]interface{}{"q1": "yes", "q2": 4}),
+	})
+	if err != nil {
+		t.Fatalf("Failed to submit response: %v", err)
+	}
+	if submitted.GetId() == "" {
+		t.Error("Expected the response to be assigned an ID")
+	}
+
+	var stored []entity.SurveyResponse
+	err = ts.reports.ScanResponses(ctx, "s1", func(response entity.SurveyResponse) error {
+		stored = append(stored, response)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to scan responses: %v", err)
+	}
+	if len(stored) != 1 || stored[0].ID != submitted.GetId() || stored[0].Answers["q1"] != "yes" || stored[0].Answers["q2"] != 4.0 {
+		t.Errorf("Expected the submitted response to be stored, got %+v", stored)
+	}
+
+	_, err = ts.client.SubmitResponse(ctx, &surveyv1.SubmitResponseRequest{Answers: newAnswers(t, nil)})
+	expectStatus(t, err, codes.InvalidArgument, "invalid_response")
+
+	_, err = ts.client.SubmitResponse(ctx, &surveyv1.SubmitResponseRequest{
+		SurveyId: "s1",
+		Answers:  newAnswers(t, map[string]interface{}{"q1": []interface{}{[]interface{}{"nested"}}}),
+	})
+	expectStatus(t, err, codes.InvalidArgument, "invalid_response")
+}
+
+func TestSubmitResponse_QuotaExceeded(t *testing.T) {
+	ts := newTestServer(t, testSettings())
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		if _, err := ts.client.SubmitResponse(ctx, &surveyv1.SubmitResponseRequest{SurveyId: "s1"}); err != nil {
+			t.Fatalf("Failed to submit response %d: %v", i, err)
+		}
+	}
+
+	_, err := ts.client.SubmitResponse(ctx, &surveyv1.SubmitResponseRequest{SurveyId: "s1"})
+	expectStatus(t, err, codes.ResourceExhausted, "quota_exceeded")
+	for _, detail := range status.Convert(err).Details() {
+		if retry, ok := detail.(*errdetails.RetryInfo); ok {
+			if retry.GetRetryDelay().AsDuration() <= 0 {
+				t.Errorf("Expected a positive retry delay, got %v", retry.GetRetryDelay().AsDuration())
+			}
+			return
+		}
+	}
+	t.Error("Expected a RetryInfo detail")
+}
+
+func TestBatchSubmit(t *testing.T) {
+	ts := newTestServer(t, testSettings())
+	ctx := context.Background()
+
+	result, err := ts.client.BatchSubmit(ctx, &surveyv1.BatchSubmitRequest{
+		Responses: []*surveyv1.SubmitResponseRequest{
+			{SurveyId: "s1", Answers: newAnswers(t, map[string]interface{}{"q1": "a"})},
+			{Answers: newAnswers(t, map[string]interface{}{"q1": "b"})},
+			{SurveyId: "s1", Answers: newAnswers(t, map[string]interface{}{"q1": "c", "q2": "d", "q3": "e"})},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to submit batch: %v", err)
+	}
+	if result.GetAccepted() != 1 || result.GetRejected() != 2 || len(result.GetResults()) != 3 {
+		t.Fatalf("Expected 1 accepted and 2 rejected, got %+v", result)
+	}
+	for i, item := range result.GetResults() {
+		accepted := item.GetStatus() == surveyv1.BatchItemResult_STATUS_ACCEPTED
+		if item.GetIndex() != int32(i) || accepted != (i == 0) || (item.GetId() != "") != accepted || (item.GetError() == "") != accepted {
+			t.Errorf("Unexpected result for item %d: %+v", i, item)
+		}
+	}
+
+	// Only the accepted item counted against the quota of 5
+	for i := 0; i < 4; i++ {
+		if _, err := ts.client.SubmitResponse(ctx, &surveyv1.SubmitResponseRequest{SurveyId: "s1"}); err != nil {
+			t.Fatalf("Expected rejected items not to use up the quota, got %v on submission %d", err, i)
+		}
+	}
+	_, err = ts.client.SubmitResponse(ctx, &surveyv1.SubmitResponseRequest{SurveyId: "s1"})
+	expectStatus(t, err, codes.ResourceExhausted, "quota_exceeded")
+
+	_, err = ts.client.BatchSubmit(ctx, &surveyv1.BatchSubmitRequest{})
+	expectStatus(t, err, codes.InvalidArgument, "")
+
+	tooMany := make([]*surveyv1.SubmitResponseRequest, 4)
+	for i := range tooMany {
+		tooMany[i] = &surveyv1.SubmitResponseRequest{SurveyId: "s1"}
+	}
+	_, err = ts.client.BatchSubmit(ctx, &surveyv1.BatchSubmitRequest{Responses: tooMany})
+	expectStatus(t, err, codes.InvalidArgument, "")
+}
+
+func TestGetReportAndJobStatus(t *testing.T) {
+	ts := newTestServer(t, testSettings())
+	ctx := context.Background()
+
+	_, err := ts.client.GetReport(ctx, &surveyv1.GetReportRequest{SurveyId: "s1"})
+	expectStatus(t, err, codes.NotFound, "not_found")
+	_, err = ts.client.GetJobStatus(ctx, &surveyv1.GetJobStatusRequest{SurveyId: "s1"})
+	expectStatus(t, err, codes.NotFound, "not_found")
+
+	// A survey ID containing the tenant separator must not reach another tenant's data
+	_, err = ts.client.GetReport(ctx, &surveyv1.GetReportRequest{SurveyId: "acme:s1"})
+	expectStatus(t, err, codes.InvalidArgument, "invalid_survey_id")
+	_, err = ts.client.GetJobStatus(ctx, &surveyv1.GetJobStatusRequest{SurveyId: "acme:s1"})
+	expectStatus(t, err, codes.InvalidArgument, "invalid_survey_id")
+
+	_, err = ts.client.SubmitResponse(ctx, &surveyv1.SubmitResponseRequest{
+		SurveyId: "s1",
+		Answers:  newAnswers(t, map[string]interface{}{"color": "red", "score": 3}),
+	})
+	if err != nil {
+		t.Fatalf("Failed to submit response: %v", err)
+	}
+	queued, err := ts.reports.RequestReport(ctx, "s1")
+	if err != nil {
+		t.Fatalf("Failed to request report: %v", err)
+	}
+
+	job, err := ts.client.GetJobStatus(ctx, &surveyv1.GetJobStatusRequest{SurveyId: "s1"})
+	if err != nil {
+		t.Fatalf("Failed to get latest job: %v", err)
+	}
+	if job.GetId() != queued.ID || job.GetStatus() != surveyv1.Job_STATUS_QUEUED || job.GetRequestedAt() == 0 {
+		t.Errorf("Expected queued job %s, got %+v", queued.ID, job)
+	}
+	if job, err = ts.client.GetJobStatus(ctx, &surveyv1.GetJobStatusRequest{SurveyId: "s1", JobId: queued.ID}); err != nil || job.GetId() != queued.ID {
+		t.Errorf("Expected job %s by ID, got %+v, %v", queued.ID, job, err)
+	}
+	_, err = ts.client.GetJobStatus(ctx, &surveyv1.GetJobStatusRequest{SurveyId: "s1", JobId: "missing"})
+	expectStatus(t, err, codes.NotFound, "not_found")
+
+	if err := ts.reports.GenerateReport(ctx, "s1"); err != nil {
+		t.Fatalf("Failed to generate report: %v", err)
+	}
+	report, err := ts.client.GetReport(ctx, &surveyv1.GetReportRequest{SurveyId: "s1"})
+	if err != nil {
+		t.Fatalf("Failed to get report: %v", err)
+	}
+	if report.GetResponseCount() != 1 || report.GetVersion() != 1 {
+		t.Errorf("Expected version 1 covering 1 response, got %+v", report)
+	}
+	if counts := report.GetQuestions()["color"].GetCounts(); counts["red"] != 1 {
+		t.Errorf("Expected one red answer, got %v", counts)
+	}
+	if numeric := report.GetQuestions()["score"].GetNumeric(); numeric.GetCount() != 1 || numeric.GetMean() != 3 {
+		t.Errorf("Expected a mean score of 3, got %+v", numeric)
+	}
+}
+
+func TestWatchReport(t *testing.T) {
+	ts := newTestServer(t, testSettings())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	generate := func() {
+		t.Helper()
+		if _, err := ts.client.SubmitResponse(ctx, &surveyv1.SubmitResponseRequest{SurveyId: "s1"}); err != nil {
+			t.Fatalf("Failed to submit response: %v", err)
+		}
+		if err := ts.reports.GenerateReport(ctx, "s1"); err != nil {
+			t.Fatalf("Failed to generate report: %v", err)
+		}
+		if err := ts.events.NotifyReportCompleted(ctx, "s1"); err != nil {
+			t.Fatalf("Failed to announce report: %v", err)
+		}
+	}
+	generate()
+
+	stream, err := ts.client.WatchReport(ctx, &surveyv1.WatchReportRequest{SurveyId: "s1"})
+	if err != nil {
+		t.Fatalf("Failed to watch report: %v", err)
+	}
+
+	// The stream opens with the latest report, then follows every new version
+	for version := int64(1); version <= 2; version++ {
+		report, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Failed to receive report version %d: %v", version, err)
+		}
+		if report.GetVersion() != version || report.GetResponseCount() != version {
+			t.Errorf("Expected version %d covering %d responses, got %+v", version, version, report)
+		}
+		if version == 1 {
+			generate()
+		}
+	}
+
+	// Stopping the broker ends the stream so the server can shut down
+	if err := ts.events.StopBroker(); err != nil {
+		t.Fatalf("Failed to stop event broker: %v", err)
+	}
+	_, err = stream.Recv()
+	expectStatus(t, err, codes.Unavailable, "")
+}
+
+func TestWatchReport_FailsWhenReportCannotBeRead(t *testing.T) {
+	ts := newTestServer(t, testSettings())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// A store that cannot be read ends the watch instead of waiting silently
+	if err := ts.db.Close(); err != nil {
+		t.Fatalf("Failed to close database: %v", err)
+	}
+	stream, err := ts.client.WatchReport(ctx, &surveyv1.WatchReportRequest{SurveyId: "s1"})
+	if err != nil {
+		t.Fatalf("Failed to watch report: %v", err)
+	}
+	_, err = stream.Recv()
+	expectStatus(t, err, codes.Internal, "")
+}
+
+func TestAuthorize(t *testing.T) {
+	settings := testSettings()
+	settings.RequireAuth = true
+	ts := newTestServer(t, settings)
+	ctx := context.Background()
+
+	_, err := ts.client.GetReport(ctx, &surveyv1.GetReportRequest{SurveyId: "s1"})
+	expectStatus(t, err, codes.Unauthenticated, "unauthenticated")
+
+	_, submitKey, err := ts.auth.CreateAPIKey(ctx, "ingest", "acme", []string{entity.ScopeSubmit})
+	if err != nil {
+		t.Fatalf("Failed to create API key: %v", err)
+	}
+	_, readKey, err := ts.auth.CreateAPIKey(ctx, "dashboard", "acme", []string{entity.ScopeReadReport})
+	if err != nil {
+		t.Fatalf("Failed to create API key: %v", err)
+	}
+	submitCtx := metadata.AppendToOutgoingContext(ctx, "x-api-key", submitKey)
+	readCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+readKey)
+
+	if _, err := ts.client.SubmitResponse(submitCtx, &surveyv1.SubmitResponseRequest{SurveyId: "s1"}); err != nil {
+		t.Fatalf("Failed to submit with the submit scope: %v", err)
+	}
+	_, err = ts.client.GetReport(submitCtx, &surveyv1.GetReportRequest{SurveyId: "s1"})
+	expectStatus(t, err, codes.PermissionDenied, "")
+
+	_, err = ts.client.GetReport(metadata.AppendToOutgoingContext(readCtx, "x-tenant-id", "other"), &surveyv1.GetReportRequest{SurveyId: "s1"})
+	expectStatus(t, err, codes.PermissionDenied, "")
+
+	// The response was stored for the key's tenant, where the read key finds its job
+	job, err := ts.client.GetJobStatus(readCtx, &surveyv1.GetJobStatusRequest{SurveyId: "s1"})
+	if err != nil {
+		t.Fatalf("Failed to get job with the read:report scope: %v", err)
+	}
+	if job.GetSurveyId() != "s1" {
+		t.Errorf("Expected a job of survey s1, got %+v", job)
+	}
+	_, err = ts.client.GetJobStatus(ctx, &surveyv1.GetJobStatusRequest{SurveyId: "s1"})
+	expectStatus(t, err, codes.Unauthenticated, "unauthenticated")
+}
+
+func TestAuthorize_OnlyAdminsNameTheirTenant(t *testing.T) {
+	settings := testSettings()
+	settings.RequireAuth = true
+	ts := newTestServer(t, settings)
+	ctx := context.Background()
+
+	_, submitKey, err := ts.auth.CreateAPIKey(ctx, "ingest", "", []string{entity.ScopeSubmit})
+	if err != nil {
+		t.Fatalf("Failed to create API key: %v", err)
+	}
+	_, adminKey, err := ts.auth.CreateAPIKey(ctx, "operator", "", []string{entity.ScopeAdmin})
+	if err != nil {
+		t.Fatalf("Failed to create API key: %v", err)
+	}
+	submitCtx := metadata.AppendToOutgoingContext(ctx, "x-api-key", submitKey)
+	adminCtx := metadata.AppendToOutgoingContext(ctx, "x-api-key", adminKey)
+
+	// An unbound key without the admin scope acts for the default tenant only
+	_, err = ts.client.SubmitResponse(metadata.AppendToOutgoingContext(submitCtx, "x-tenant-id", "acme"), &surveyv1.SubmitResponseRequest{SurveyId: "s1"})
+	expectStatus(t, err, codes.PermissionDenied, "")
+	if _, err := ts.client.SubmitResponse(submitCtx, &surveyv1.SubmitResponseRequest{SurveyId: "s1"}); err != nil {
+		t.Fatalf("Failed to submit for the default tenant: %v", err)
+	}
+
+	// An unbound admin key acts for the tenant it names
+	_, err = ts.client.GetJobStatus(metadata.AppendToOutgoingContext(adminCtx, "x-tenant-id", "acme"), &surveyv1.GetJobStatusRequest{SurveyId: "s1"})
+	expectStatus(t, err, codes.NotFound, "not_found")
+	if _, err := ts.client.GetJobStatus(adminCtx, &surveyv1.GetJobStatusRequest{SurveyId: "s1"}); err != nil {
+		t.Errorf("Failed to get the default tenant's job: %v", err)
+	}
+}
+
+func TestAuthorize_AnonymousCallersActForTheDefaultTenant(t *testing.T) {
+	ts := newTestServer(t, testSettings())
+	ctx := context.Background()
+
+	_, err := ts.client.SubmitResponse(metadata.AppendToOutgoingContext(ctx, "x-tenant-id", "acme"), &surveyv1.SubmitResponseRequest{SurveyId: "s1"})
+	expectStatus(t, err, codes.PermissionDenied, "")
+	if _, err := ts.client.SubmitResponse(ctx, &surveyv1.SubmitResponseRequest{SurveyId: "s1"}); err != nil {
+		t.Errorf("Failed to submit for the default tenant: %v", err)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	ts := newTestServer(t, testSettings())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := ts.client.SubmitResponse(ctx, &surveyv1.SubmitResponseRequest{SurveyId: "s1"}); err != nil {
+		t.Fatalf("Failed to submit response: %v", err)
+	}
+	if err := ts.reports.GenerateReport(ctx, "s1"); err != nil {
+		t.Fatalf("Failed to generate report: %v", err)
+	}
+	stream, err := ts.client.WatchReport(ctx, &surveyv1.WatchReportRequest{SurveyId: "s1"})
+	if err != nil {
+		t.Fatalf("Failed to watch report: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Failed to receive the latest report: %v", err)
+	}
+
+	// An open stream holds a graceful stop until the deadline closes it
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer shutdownCancel()
+	if err := Shutdown(shutdownCtx, ts.server); err != context.DeadlineExceeded {
+		t.Errorf("Expected the open stream to hold up shutdown, got %v", err)
+	}
+	if _, err := stream.Recv(); err == nil {
+		t.Error("Expected the stream to be closed")
+	}
+}
@@ -0,0 +1,123 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// errorDomain is the domain of the ErrorInfo detail of every status; its
+// reason is the same stable code the HTTP API puts in its problems
+const errorDomain = "survey-api"
+
+// kindCodes maps every kind of domain error to the status code it is answered with
+var kindCodes = map[entity.ErrorKind]codes.Code{
+	entity.ErrorInvalid:         codes.InvalidArgument,
+	entity.ErrorUnauthenticated: codes.Unauthenticated,
+	entity.ErrorNotFound:        codes.NotFound,
+	entity.ErrorConflict:        codes.AlreadyExists,
+	entity.ErrorExhausted:       codes.ResourceExhausted,
+}
+
+// statusError returns the status err stands for. Domain errors get the code
+// of their kind and their message, with their stable code as the reason of
+// an ErrorInfo detail; a call that was canceled or timed out gets CANCELED or
+// DEADLINE_EXCEEDED; any other error is logged and answered with INTERNAL,
+// hiding its details
+func statusError(ctx context.Context, err error) error {
+	if domainErr := entity.ErrorOf(err); domainErr != nil {
+		if code, ok := kindCodes[domainErr.Kind]; ok {
+			st := status.New(code, err.Error())
+			if detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{Reason: domainErr.Code, Domain: errorDomain}); detailErr == nil {
+				st = detailed
+			}
+			return st.Err()
+		}
+	}
+	if ctx.Err() != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+
+	method, _ := grpc.Method(ctx)
+	log.Printf("Error handling %s: %v", method, err)
+	return status.Error(codes.Internal, "the call could not be completed")
+}
+
+// withRetryDelay adds a RetryInfo detail to a status, telling the caller how
+// long to wait before retrying
+func withRetryDelay(err error, delay time.Duration) error {
+	st, detailErr := status.Convert(err).WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+	if detailErr != nil {
+		return err
+	}
+	return st.Err()
+}
+
+// allowRateLimit counts the submission against the rate limits of its
+// caller, IP address and surveys, shared by every instance, and returns the
+// RESOURCE_EXHAUSTED status rejecting the call when a limit is exceeded
+// surveyResponses counts the responses submitted to each survey, nil for one each
+func (s *Server) allowRateLimit(ctx context.Context, surveyIDs []string, surveyResponses map[string]int) error {
+	result, err := s.rateLimitUseCase.AllowSubmission(ctx, usecase.RateLimitedSubmission{
+		Client:          submitter(ctx),
+		IP:              peerIP(ctx),
+		SurveyIDs:       surveyIDs,
+		SurveyResponses: surveyResponses,
+	})
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, usecase.ErrRateLimited) {
+		// An unavailable rate limiter must not take submissions down with it
+		log.Printf("Error checking rate limits, letting the submission through: %v", err)
+		return nil
+	}
+	return withRetryDelay(statusError(ctx, err), result.RetryAfter)
+}
+
+// consumeQuota counts n responses against the daily quota of the call's
+// tenant, and returns the RESOURCE_EXHAUSTED status rejecting the call until
+// the quota resets when it is used up
+func (s *Server) consumeQuota(ctx context.Context, n int) error {
+	err := s.quotaUseCase.ConsumeResponses(ctx, n)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, usecase.ErrQuotaExceeded) {
+		return withRetryDelay(statusError(ctx, err), time.Until(usecase.QuotaResetAt(time.Now())))
+	}
+	return statusError(ctx, err)
+}
+
+// recoverUnary answers a unary call whose handler panics with INTERNAL
+// instead of crashing the server, logging the panic with its stack
+func recoverUnary(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (response interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = statusError(ctx, fmt.Errorf("panic: %v\n%s", p, debug.Stack()))
+		}
+	}()
+	return handler(ctx, request)
+}
+
+// recoverStream answers a streaming call whose handler panics like recoverUnary
+func recoverStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = statusError(stream.Context(), fmt.Errorf("panic: %v\n%s", p, debug.Stack()))
+		}
+	}()
+	return handler(srv, stream)
+}
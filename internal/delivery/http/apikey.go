@@ -0,0 +1,75 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// createAPIKeyRequest is the body of an API key creation
+type createAPIKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// createdAPIKey is an API key together with the key itself, returned only once
+type createdAPIKey struct {
+	entity.APIKey
+	Key string `json:"key"`
+}
+
+// CreateAPIKey handles creating an API key
+// The response is the only place the key itself is returned
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var request createAPIKeyRequest
+	if !h.decodeJSON(w, r, &request) {
+		return
+	}
+
+	// Keys created over the API are bound to the tenant of the request;
+	// keys that may act for any tenant are created from the command line
+	tenantID := entity.TenantFromContext(r.Context())
+	apiKey, key, err := h.authUseCase.CreateAPIKey(r.Context(), request.Name, tenantID, request.Scopes)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	apiKey.Hash = ""
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(createdAPIKey{APIKey: *apiKey, Key: key})
+}
+
+// ListAPIKeys handles listing the API keys, without their hashes
+func (h *Handler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.authUseCase.ListAPIKeys(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if keys == nil {
+		keys = []entity.APIKey{}
+	}
+	for i := range keys {
+		keys[i].Hash = ""
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"api_keys": keys,
+	})
+}
+
+// RevokeAPIKey handles revoking an API key
+func (h *Handler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	keyID := r.PathValue("keyID")
+	if err := h.authUseCase.RevokeAPIKey(r.Context(), keyID); err != nil {
+		writeError(w, r, fmt.Errorf("API key %s: %w", keyID, err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
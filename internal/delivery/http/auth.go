@@ -0,0 +1,116 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+const (
+	// apiKeyHeader is the request header carrying an API key
+	apiKeyHeader = "X-API-Key"
+
+	// tenantHeader is the request header naming the tenant a request acts for
+	tenantHeader = "X-Tenant-ID"
+)
+
+// authorize wraps next so it only runs for callers granted scope, with the
+// caller's identity and tenant attached to the request context
+// While authentication is not required every caller is let through, except
+// to admin endpoints, which are refused since anyone could reach them
+func (h *Handler) authorize(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !h.settings.Load().RequireAuth {
+			if scope == entity.ScopeAdmin {
+				writeProblem(w, r, http.StatusForbidden, codeForbidden, "Admin endpoints require authentication to be enabled")
+				return
+			}
+			tenantID, ok := resolveTenant(w, r, nil)
+			if !ok {
+				return
+			}
+			next(w, r.WithContext(entity.WithTenant(r.Context(), tenantID)))
+			return
+		}
+
+		identity, err := h.authenticate(r)
+		if err != nil {
+			if errors.Is(err, usecase.ErrUnauthenticated) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="survey-api"`)
+			}
+			writeError(w, r, err)
+			return
+		}
+		if !identity.HasScope(scope) {
+			writeProblem(w, r, http.StatusForbidden, codeForbidden, fmt.Sprintf("The %s scope is required", scope))
+			return
+		}
+		tenantID, ok := resolveTenant(w, r, identity)
+		if !ok {
+			return
+		}
+
+		ctx := entity.WithTenant(usecase.WithIdentity(r.Context(), identity), tenantID)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// resolveTenant returns the tenant the request acts for: the tenant the
+// caller is bound to, else the one named by the X-Tenant-ID header when the
+// caller may act for any tenant, else the default tenant. Only unbound admin
+// credentials may name a tenant; callers without credentials act for the
+// default tenant. It rejects the request and returns false when the header names
+// an invalid tenant or one the caller may not act for
+func resolveTenant(w http.ResponseWriter, r *http.Request, identity *entity.Identity) (string, bool) {
+	requested := r.Header.Get(tenantHeader)
+	if requested != "" && !entity.ValidTenantID(requested) {
+		writeProblem(w, r, http.StatusBadRequest, codeInvalidTenant, "Invalid tenant ID "+strconv.Quote(requested))
+		return "", false
+	}
+
+	tenantID := entity.DefaultTenant
+	switch {
+	case identity != nil && identity.TenantID != "":
+		tenantID = identity.TenantID
+	case requested != "" && identity != nil && identity.HasScope(entity.ScopeAdmin):
+		tenantID = requested
+	}
+	if requested != "" && requested != tenantID {
+		writeProblem(w, r, http.StatusForbidden, codeForbidden, "The credentials are not valid for tenant "+requested)
+		return "", false
+	}
+	return tenantID, true
+}
+
+// authenticate identifies the caller by the API key in the X-API-Key header,
+// or by the API key or JWT sent as a bearer token
+func (h *Handler) authenticate(r *http.Request) (*entity.Identity, error) {
+	if key := r.Header.Get(apiKeyHeader); key != "" {
+		return h.authUseCase.AuthenticateAPIKey(r.Context(), key)
+	}
+
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, fmt.Errorf("%w: an API key or bearer token is required", usecase.ErrUnauthenticated)
+	}
+	token = strings.TrimSpace(token)
+	if strings.HasPrefix(token, usecase.APIKeyPrefix) {
+		return h.authUseCase.AuthenticateAPIKey(r.Context(), token)
+	}
+	return h.authUseCase.AuthenticateToken(r.Context(), token)
+}
+
+// submitter returns the caller recorded on the responses it submits, empty
+// when the request was not authenticated
+func submitter(ctx context.Context) string {
+	if identity := usecase.IdentityFromContext(ctx); identity != nil {
+		return identity.String()
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+func TestResolveTenant(t *testing.T) {
+	submitter := &entity.Identity{Scopes: []string{entity.ScopeSubmit}}
+	admin := &entity.Identity{Scopes: []string{entity.ScopeAdmin}}
+	bound := &entity.Identity{TenantID: "acme", Scopes: []string{entity.ScopeAdmin}}
+
+	tests := []struct {
+		name       string
+		identity   *entity.Identity
+		header     string
+		wantTenant string
+		wantStatus int
+	}{
+		{"open access names a tenant", nil, "acme", "", http.StatusForbidden},
+		{"open access names the default tenant", nil, entity.DefaultTenant, entity.DefaultTenant, 0},
+		{"open access defaults", nil, "", entity.DefaultTenant, 0},
+		{"unbound caller defaults", submitter, "", entity.DefaultTenant, 0},
+		{"unbound caller names the default tenant", submitter, entity.DefaultTenant, entity.DefaultTenant, 0},
+		{"unbound caller names another tenant", submitter, "acme", "", http.StatusForbidden},
+		{"unbound admin names a tenant", admin, "acme", "acme", 0},
+		{"bound caller", bound, "", "acme", 0},
+		{"bound caller names its tenant", bound, "acme", "acme", 0},
+		{"bound caller names another tenant", bound, "other", "", http.StatusForbidden},
+		{"invalid tenant", admin, "a:b", "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/survey/s1/report", nil)
+			if tt.header != "" {
+				r.Header.Set(tenantHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			tenantID, ok := resolveTenant(w, r, tt.identity)
+			if tt.wantStatus != 0 {
+				if ok || w.Code != tt.wantStatus {
+					t.Errorf("Expected status %d, got %d (ok %v)", tt.wantStatus, w.Code, ok)
+				}
+				return
+			}
+			if !ok || tenantID != tt.wantTenant {
+				t.Errorf("Expected tenant %q, got %q (ok %v, status %d)", tt.wantTenant, tenantID, ok, w.Code)
+			}
+		})
+	}
+}
+
+func TestAuthorize_RefusesAdminEndpointsWhileAuthIsDisabled(t *testing.T) {
+	server := newContractServer(t)
+
+	response, err := http.Post(server.URL+APIPrefix+"/keys", "application/json",
+		strings.NewReader(`{"name": "intruder", "scopes": ["admin"]}`))
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, response.StatusCode)
+	}
+
+	keys, err := server.auth.ListAPIKeys(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list API keys: %v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("Expected no API key to be created, got %d", len(keys))
+	}
+}
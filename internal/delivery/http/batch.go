@@ -0,0 +1,206 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+const (
+	// DefaultMaxBatchSize is the maximum number of responses accepted in one batch unless configured
+	DefaultMaxBatchSize = 1000
+)
+
+// batchItemResult is the outcome of one response in a batch submission
+type batchItemResult struct {
+	Index  int    `json:"index"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// SubmitBatch handles the submission of many survey responses at once
+// The body is either a JSON array of responses or NDJSON with one response per line.
+// Items that are malformed or invalid are rejected on their own, and only the
+// items left are counted against the rate limits and the quota
+func (h *Handler) SubmitBatch(w http.ResponseWriter, r *http.Request) {
+	if !requireContentType(w, r, "application/json", "application/x-ndjson") {
+		return
+	}
+
+	settings := h.settings.Load()
+	items, err := decodeBatch(http.MaxBytesReader(w, r.Body, settings.MaxBatchBytes), settings.MaxBatchSize)
+	if err != nil {
+		writeBodyError(w, r, err)
+		return
+	}
+	if len(items) == 0 {
+		writeProblem(w, r, http.StatusBadRequest, codeInvalidRequest, "Batch must contain at least one response")
+		return
+	}
+
+	// Create a survey response for every item that decodes and is valid
+	now := time.Now().Unix()
+	caller := submitter(r.Context())
+	itemErrs := make([]error, len(items))
+	responses := make([]entity.SurveyResponse, 0, len(items))
+	indexes := make([]int, 0, len(items))
+	for i, item := range items {
+		var request submitRequest
+		if request, itemErrs[i] = decodeBatchItem(item); itemErrs[i] != nil {
+			continue
+		}
+		if itemErrs[i] = h.checkAnswers(request.Answers); itemErrs[i] != nil {
+			continue
+		}
+		response := entity.SurveyResponse{
+			ID:          h.idGenerator.NewID(),
+			SurveyID:    request.SurveyID,
+			Answers:     request.Answers,
+			CreatedAt:   now,
+			SubmittedBy: caller,
+		}
+		if itemErrs[i] = usecase.ValidateResponse(response); itemErrs[i] != nil {
+			continue
+		}
+		responses = append(responses, response)
+		indexes = append(indexes, i)
+	}
+
+	surveyIDs, surveyResponses := batchSurveys(responses)
+	if !h.allowRateLimit(w, r, surveyIDs, surveyResponses) {
+		return
+	}
+	if len(responses) > 0 && !h.consumeQuota(w, r, len(responses)) {
+		return
+	}
+
+	// Submit the batch
+	submitErrs, err := h.reportUseCase.SubmitResponses(r.Context(), responses)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	results := make([]batchItemResult, len(items))
+	for i := range items {
+		results[i] = batchItemResult{Index: i, Status: "rejected", Error: errString(itemErrs[i])}
+	}
+	accepted := 0
+	for j, response := range responses {
+		i := indexes[j]
+		if submitErrs[j] != nil {
+			results[i].Error = submitErrs[j].Error()
+			continue
+		}
+		results[i] = batchItemResult{Index: i, ID: response.ID, Status: "accepted"}
+		accepted++
+	}
+
+	// Return per-item results
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"accepted": accepted,
+		"rejected": len(items) - accepted,
+		"results":  results,
+	})
+}
+
+// decodeBatch reads a JSON array or an NDJSON stream of at most maxSize items
+// Only the JSON syntax is checked here; each item is decoded by decodeBatchItem
+// so one malformed item does not reject the whole batch
+func decodeBatch(body io.Reader, maxSize int) ([]json.RawMessage, error) {
+	reader := bufio.NewReader(body)
+
+	// Peek at the first non-whitespace byte to tell an array from NDJSON
+	var first byte
+	for first == 0 {
+		b, err := reader.Peek(1)
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			reader.ReadByte()
+		default:
+			first = b[0]
+		}
+	}
+
+	decoder := json.NewDecoder(reader)
+	if first == '[' {
+		var items []json.RawMessage
+		if err := decoder.Decode(&items); err != nil {
+			return nil, err
+		}
+		if decoder.Decode(&json.RawMessage{}) != io.EOF {
+			return nil, errors.New("body must contain a single JSON array")
+		}
+		if len(items) > maxSize {
+			return nil, fmt.Errorf("batch exceeds %d responses", maxSize)
+		}
+		return items, nil
+	}
+
+	var items []json.RawMessage
+	for {
+		var item json.RawMessage
+		err := decoder.Decode(&item)
+		if err == io.EOF {
+			return items, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", len(items)+1, err)
+		}
+		if len(items) == maxSize {
+			return nil, fmt.Errorf("batch exceeds %d responses", maxSize)
+		}
+		items = append(items, item)
+	}
+}
+
+// decodeBatchItem decodes one item of a batch, rejecting unknown fields as a
+// single submission does
+func decodeBatchItem(item json.RawMessage) (submitRequest, error) {
+	var request submitRequest
+	decoder := json.NewDecoder(bytes.NewReader(item))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		return submitRequest{}, fmt.Errorf("%w: %v", usecase.ErrInvalidResponse, err)
+	}
+	return request, nil
+}
+
+// batchSurveys lists every survey the batch submits to once, in order of
+// appearance, and counts the responses it submits to each
+func batchSurveys(responses []entity.SurveyResponse) ([]string, map[string]int) {
+	var surveyIDs []string
+	counts := make(map[string]int)
+	for _, response := range responses {
+		if counts[response.SurveyID] == 0 {
+			surveyIDs = append(surveyIDs, response.SurveyID)
+		}
+		counts[response.SurveyID]++
+	}
+	return surveyIDs, counts
+}
+
+// errString returns the message of err, empty when it is nil
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
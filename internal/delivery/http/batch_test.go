@@ -0,0 +1,131 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+func TestSubmitBatch_RejectsItemsOnTheirOwn(t *testing.T) {
+	server := newContractServer(t)
+	server.quotas.UpdateSettings(usecase.QuotaSettings{ResponsesPerDay: 3})
+
+	submit := func(contentType, body string) *http.Response {
+		t.Helper()
+		resp, err := http.Post(server.URL+"/api/v1/survey/submit/batch", contentType, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to submit batch: %v", err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  []string
+	}{
+		{
+			"array",
+			"application/json",
+			`[{"survey_id":"s1","answers":{"q1":"yes"}},{"survey_id":"s1","answers":{"q1":"yes"},"extra":1},{"survey_id":5},{"survey_id":"","answers":{}}]`,
+			[]string{"accepted", "rejected", "rejected", "rejected"},
+		},
+		{
+			"NDJSON",
+			"application/x-ndjson",
+			"{\"survey_id\":\"s1\",\"extra\":1}\n{\"survey_id\":\"s1\",\"answers\":{\"q1\":\"no\"}}\n",
+			[]string{"rejected", "accepted"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := submit(tt.contentType, tt.body)
+			if resp.StatusCode != http.StatusAccepted {
+				t.Fatalf("Expected status 202, got %d", resp.StatusCode)
+			}
+			var result struct {
+				Results []batchItemResult `json:"results"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("Failed to decode results: %v", err)
+			}
+			if len(result.Results) != len(tt.wantStatus) {
+				t.Fatalf("Expected %d results, got %+v", len(tt.wantStatus), result.Results)
+			}
+			for i, item := range result.Results {
+				if item.Status != tt.wantStatus[i] || (item.Error == "") != (item.Status == "accepted") {
+					t.Errorf("Expected item %d to be %s, got %+v", i, tt.wantStatus[i], item)
+				}
+			}
+		})
+	}
+
+	// The two accepted items used two of the three responses of the quota
+	if resp := submit("application/json", `[{"survey_id":"s1","answers":{"q1":"yes"}}]`); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("Expected rejected items not to use up the quota, got %d", resp.StatusCode)
+	}
+	if resp := submit("application/json", `[{"survey_id":"s1","answers":{"q1":"yes"}}]`); resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected the quota to be used up, got %d", resp.StatusCode)
+	}
+}
+
+func TestSubmitBatch_CountsEveryItemAgainstTheSurveyLimit(t *testing.T) {
+	server := newContractServer(t)
+	server.rateLimits.UpdateSettings(usecase.RateLimitSettings{Survey: usecase.RateLimitRule{Rate: 0.01, Burst: 3}})
+
+	submit := func(items int) int {
+		t.Helper()
+		body := "[" + strings.TrimSuffix(strings.Repeat(`{"survey_id":"s1","answers":{"q1":"yes"}},`, items), ",") + "]"
+		resp, err := http.Post(server.URL+"/api/v1/survey/submit/batch", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to submit batch: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status := submit(4); status != http.StatusTooManyRequests {
+		t.Errorf("Expected a batch larger than the burst to be refused, got %d", status)
+	}
+	if status := submit(2); status != http.StatusAccepted {
+		t.Errorf("Expected a batch within the burst to be accepted, got %d", status)
+	}
+	if status := submit(2); status != http.StatusTooManyRequests {
+		t.Errorf("Expected a batch above the remaining burst to be refused, got %d", status)
+	}
+	if status := submit(1); status != http.StatusAccepted {
+		t.Errorf("Expected a batch within the remaining burst to be accepted, got %d", status)
+	}
+}
+
+func TestSubmitBatch_RejectsDataAfterTheArray(t *testing.T) {
+	server := newContractServer(t)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"trailing value", `[{"survey_id":"s1","answers":{"q1":"yes"}}] {"survey_id":"s1"}`, http.StatusBadRequest},
+		{"second array", `[{"survey_id":"s1","answers":{"q1":"yes"}}][]`, http.StatusBadRequest},
+		{"trailing garbage", `[{"survey_id":"s1","answers":{"q1":"yes"}}] x`, http.StatusBadRequest},
+		{"trailing whitespace", "[{\"survey_id\":\"s1\",\"answers\":{\"q1\":\"yes\"}}]\n\t ", http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(server.URL+"/api/v1/survey/submit/batch", "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("Failed to submit batch: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// DownloadDocument returns a handler serving the rendered report document in the given format
+// Documents are always sent as attachments so rendered HTML never runs on the API's origin
+func (h *Handler) DownloadDocument(format usecase.DocumentFormat) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		surveyID := r.PathValue("id")
+
+		document, contentType, err := h.documentUseCase.OpenDocument(r.Context(), surveyID, format)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("%s document of survey %s: %w", format, surveyID, err))
+			return
+		}
+		defer document.Close()
+
+		setAttachment(w, contentType, surveyID+"-report."+string(format))
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if _, err := io.Copy(w, document); err != nil {
+			log.Printf("Error sending %s document for survey %s: %v", format, surveyID, err)
+		}
+	}
+}
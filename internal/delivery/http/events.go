@@ -0,0 +1,90 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+const (
+	// heartbeatInterval is how often an idle event stream sends a comment so
+	// proxies and load balancers do not close it
+	heartbeatInterval = 15 * time.Second
+
+	// reconnectDelay is how long browsers wait before reconnecting a dropped stream
+	reconnectDelay = 3 * time.Second
+)
+
+// StreamEvents handles streaming a survey's live job and report updates as Server-Sent Events
+// The stream opens with the latest report, if any, so a client that reconnects
+// after missing events starts again from the current state
+func (h *Handler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	surveyID := r.PathValue("id")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, r, errors.New("streaming is not supported"))
+		return
+	}
+
+	// The stream stays open for as long as the client listens
+	disableWriteTimeout(w)
+
+	// Subscribe before reading the report so no update between the two is missed
+	events, unsubscribe := h.eventUseCase.Subscribe(r.Context(), surveyID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", reconnectDelay.Milliseconds())
+
+	if report, err := h.reportUseCase.GetReport(r.Context(), surveyID); err == nil {
+		snapshot := entity.SurveyEvent{Type: entity.EventReportUpdated, SurveyID: surveyID, Report: report}
+		if err := writeEvent(w, snapshot); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				// The subscriber fell behind or the server is shutting down
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				log.Printf("Error streaming events for survey %s: %v", surveyID, err)
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes an event in the Server-Sent Events format, named after its type
+func writeEvent(w io.Writer, event entity.SurveyEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
@@ -0,0 +1,312 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// Export content types
+	contentTypeCSV   = "text/csv; charset=utf-8"
+	contentTypeXLSX  = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	contentTypeJSONL = "application/x-ndjson"
+
+	// Worksheet names of the XLSX export
+	summarySheet   = "Summary"
+	responsesSheet = "Responses"
+)
+
+// summaryHeader is the header row of the aggregated report table
+var summaryHeader = []interface{}{
+	"question", "answered", "distinct", "value", "count",
+	"numeric_count", "sum", "min", "max", "mean", "std_dev", "median", "p90", "p99",
+}
+
+// rowWriter writes one table row at a time to an export
+type rowWriter interface {
+	WriteRow(cells []interface{}) error
+}
+
+// ExportReportCSV handles exporting the aggregated report as CSV
+// Each row holds the count of one answer value, or the numeric statistics of a question
+func (h *Handler) ExportReportCSV(w http.ResponseWriter, r *http.Request) {
+	surveyID := r.PathValue("id")
+
+	report, ok := h.loadReport(w, r, surveyID)
+	if !ok {
+		return
+	}
+
+	setAttachment(w, contentTypeCSV, surveyID+"-report.csv")
+	cw := &csvRowWriter{w: csv.NewWriter(w)}
+	if err := writeSummary(cw, report); err != nil {
+		log.Printf("Error exporting report for survey %s: %v", surveyID, err)
+		return
+	}
+	if err := cw.Flush(); err != nil {
+		log.Printf("Error exporting report for survey %s: %v", surveyID, err)
+	}
+}
+
+// ExportReportXLSX handles exporting the aggregated report and the raw responses
+// as an XLSX workbook with a Summary sheet and a Responses sheet
+func (h *Handler) ExportReportXLSX(w http.ResponseWriter, r *http.Request) {
+	surveyID := r.PathValue("id")
+
+	report, ok := h.loadReport(w, r, surveyID)
+	if !ok {
+		return
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	// The stream writers spill rows to temporary files once a sheet grows large,
+	// so big surveys are not held in memory while the workbook is assembled
+	if err := f.SetSheetName(f.GetSheetName(0), summarySheet); err != nil {
+		writeError(w, r, fmt.Errorf("failed to build workbook: %w", err))
+		return
+	}
+	if _, err := f.NewSheet(responsesSheet); err != nil {
+		writeError(w, r, fmt.Errorf("failed to build workbook: %w", err))
+		return
+	}
+
+	err := writeSheet(f, summarySheet, func(rw rowWriter) error {
+		return writeSummary(rw, report)
+	})
+	if err == nil {
+		err = writeSheet(f, responsesSheet, func(rw rowWriter) error {
+			return h.writeResponses(r, surveyID, rw)
+		})
+	}
+	if err != nil {
+		writeError(w, r, fmt.Errorf("failed to build workbook: %w", err))
+		return
+	}
+
+	setAttachment(w, contentTypeXLSX, surveyID+"-report.xlsx")
+	if _, err := f.WriteTo(w); err != nil {
+		log.Printf("Error exporting workbook for survey %s: %v", surveyID, err)
+	}
+}
+
+// ExportResponsesCSV handles exporting the raw responses as CSV
+// Nested answers are flattened into one column per dot-separated question key
+func (h *Handler) ExportResponsesCSV(w http.ResponseWriter, r *http.Request) {
+	surveyID := r.PathValue("id")
+
+	disableWriteTimeout(w)
+	setAttachment(w, contentTypeCSV, surveyID+"-responses.csv")
+	cw := &csvRowWriter{w: csv.NewWriter(w)}
+	if err := h.writeResponses(r, surveyID, cw); err != nil {
+		log.Printf("Error exporting responses for survey %s: %v", surveyID, err)
+		return
+	}
+	if err := cw.Flush(); err != nil {
+		log.Printf("Error exporting responses for survey %s: %v", surveyID, err)
+	}
+}
+
+// ExportResponsesJSONL handles exporting the raw responses as JSON Lines
+func (h *Handler) ExportResponsesJSONL(w http.ResponseWriter, r *http.Request) {
+	surveyID := r.PathValue("id")
+
+	disableWriteTimeout(w)
+	setAttachment(w, contentTypeJSONL, surveyID+"-responses.jsonl")
+	enc := json.NewEncoder(w)
+	err := h.reportUseCase.ScanResponses(r.Context(), surveyID, func(response entity.SurveyResponse) error {
+		return enc.Encode(response)
+	})
+	if err != nil {
+		log.Printf("Error exporting responses for survey %s: %v", surveyID, err)
+	}
+}
+
+// loadReport fetches the survey's report, writing the error response if it cannot
+func (h *Handler) loadReport(w http.ResponseWriter, r *http.Request, surveyID string) (*entity.Report, bool) {
+	report, err := h.reportUseCase.GetReport(r.Context(), surveyID)
+	if err != nil {
+		writeError(w, r, fmt.Errorf("report of survey %s: %w", surveyID, err))
+		return nil, false
+	}
+	return report, true
+}
+
+// writeResponses writes one row per stored response with a column per answer key
+// The responses are scanned twice, first to collect the columns and then to
+// write the rows, so only the column names are kept in memory
+func (h *Handler) writeResponses(r *http.Request, surveyID string, rw rowWriter) error {
+	seen := make(map[string]bool)
+	err := h.reportUseCase.ScanResponses(r.Context(), surveyID, func(response entity.SurveyResponse) error {
+		for key := range usecase.FlattenAnswers(response.Answers) {
+			seen[key] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan responses: %w", err)
+	}
+
+	columns := make([]string, 0, len(seen))
+	for key := range seen {
+		columns = append(columns, key)
+	}
+	sort.Strings(columns)
+
+	header := []interface{}{"id", "created_at", "submitted_by"}
+	for _, column := range columns {
+		header = append(header, column)
+	}
+	if err := rw.WriteRow(header); err != nil {
+		return err
+	}
+
+	return h.reportUseCase.ScanResponses(r.Context(), surveyID, func(response entity.SurveyResponse) error {
+		answers := usecase.FlattenAnswers(response.Answers)
+		row := []interface{}{response.ID, time.Unix(response.CreatedAt, 0).UTC().Format(time.RFC3339), response.SubmittedBy}
+		for _, column := range columns {
+			row = append(row, cellValue(answers[column]))
+		}
+		return rw.WriteRow(row)
+	})
+}
+
+// writeSummary writes the aggregated report table, questions and values in sorted order
+func writeSummary(rw rowWriter, report *entity.Report) error {
+	if err := rw.WriteRow(summaryHeader); err != nil {
+		return err
+	}
+
+	questions := make([]string, 0, len(report.Questions))
+	for question := range report.Questions {
+		questions = append(questions, question)
+	}
+	sort.Strings(questions)
+
+	for _, question := range questions {
+		summary := report.Questions[question]
+
+		values := make([]string, 0, len(summary.Counts))
+		for value := range summary.Counts {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+
+		for _, value := range values {
+			if err := rw.WriteRow([]interface{}{question, summary.Answered, summary.Distinct, value, summary.Counts[value]}); err != nil {
+				return err
+			}
+		}
+
+		if n := summary.Numeric; n != nil {
+			row := []interface{}{question, summary.Answered, summary.Distinct, nil, nil,
+				n.Count, n.Sum, n.Min, n.Max, n.Mean, n.StdDev, n.Median, n.P90, n.P99}
+			if err := rw.WriteRow(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeSheet streams the rows written by fill into the named worksheet
+func writeSheet(f *excelize.File, sheet string, fill func(rowWriter) error) error {
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+
+	if err := fill(&xlsxRowWriter{sw: sw}); err != nil {
+		return err
+	}
+
+	return sw.Flush()
+}
+
+// cellValue converts a flattened answer into a table cell
+// Scalars are kept as is and anything else, such as a multiple-choice array, is JSON-encoded
+func cellValue(answer interface{}) interface{} {
+	switch answer.(type) {
+	case nil, string, float64, bool:
+		return answer
+	default:
+		data, _ := json.Marshal(answer)
+		return string(data)
+	}
+}
+
+// setAttachment sets the headers of a file download
+func setAttachment(w http.ResponseWriter, contentType, filename string) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+}
+
+// csvRowWriter writes rows as CSV records
+type csvRowWriter struct {
+	w *csv.Writer
+}
+
+// WriteRow formats every cell as text and writes the record
+func (c *csvRowWriter) WriteRow(cells []interface{}) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		switch v := cell.(type) {
+		case nil:
+		case string:
+			record[i] = escapeFormula(v)
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case int:
+			record[i] = strconv.Itoa(v)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return c.w.Write(record)
+}
+
+// escapeFormula prefixes text that a spreadsheet would read as a formula with
+// a single quote, so a submitted answer such as =HYPERLINK(...) stays text.
+// Numbers are written from their own cell types and are never escaped
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// Flush writes any buffered records and reports any write error
+func (c *csvRowWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// xlsxRowWriter writes rows to a worksheet stream, keeping numbers numeric
+type xlsxRowWriter struct {
+	sw  *excelize.StreamWriter
+	row int
+}
+
+// WriteRow appends the cells as the next worksheet row
+func (x *xlsxRowWriter) WriteRow(cells []interface{}) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.sw.SetRow(cell, cells)
+}
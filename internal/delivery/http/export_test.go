@@ -0,0 +1,34 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+)
+
+func TestCSVRowWriter_EscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	cw := &csvRowWriter{w: csv.NewWriter(&buf)}
+
+	cells := []interface{}{"=HYPERLINK(\"http://evil\")", "+1", "-1", "@SUM(A1)", "\tcmd", "\rcmd", "plain", "a=b", "", nil, float64(-3), 42, true}
+	if err := cw.WriteRow(cells); err != nil {
+		t.Fatalf("Failed to write row: %v", err)
+	}
+	if err := cw.Flush(); err != nil {
+		t.Fatalf("Failed to flush: %v", err)
+	}
+
+	record, err := csv.NewReader(&buf).Read()
+	if err != nil {
+		t.Fatalf("Failed to read record: %v", err)
+	}
+	want := []string{"'=HYPERLINK(\"http://evil\")", "'+1", "'-1", "'@SUM(A1)", "'\tcmd", "'\rcmd", "plain", "a=b", "", "", "-3", "42", "true"}
+	if len(record) != len(want) {
+		t.Fatalf("Expected %d cells, got %d: %q", len(want), len(record), record)
+	}
+	for i := range want {
+		if record[i] != want[i] {
+			t.Errorf("Expected cell %d to be %q, got %q", i, want[i], record[i])
+		}
+	}
+}
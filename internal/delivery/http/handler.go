@@ -1,83 +1,331 @@
 package http
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+	"log"
 	"net/http"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+const (
+	// APIPrefix is the path prefix of the current version of the survey API
+	APIPrefix = "/api/v1"
+
+	// legacyAPIPrefix is the path prefix of the survey API before it was
+	// versioned, still served as an alias of APIPrefix
+	legacyAPIPrefix = "/api"
+
+	// idempotencyKeyHeader is the request header carrying a client-chosen retry key
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// idempotentReplayedHeader marks a response replayed from an earlier request
+	idempotentReplayedHeader = "Idempotent-Replayed"
 )
 
+// HandlerSettings holds the request limits and access control of the HTTP handler
+type HandlerSettings struct {
+	// RequireAuth rejects requests without an API key or JWT granting the endpoint's scope
+	RequireAuth bool
+
+	// MaxBatchSize is the maximum number of responses accepted in one batch
+	MaxBatchSize int
+
+	// MaxBodyBytes is the largest request body accepted, except for batches
+	MaxBodyBytes int64
+
+	// MaxBatchBytes is the largest batch submission body accepted
+	MaxBatchBytes int64
+
+	// MaxAnswers is the maximum number of answers accepted in one response
+	MaxAnswers int
+
+	// MaxAnswerDepth is how deeply arrays and objects may nest in an answer;
+	// zero only accepts plain values
+	MaxAnswerDepth int
+
+	// TrustForwardedFor rate limits requests by the last X-Forwarded-For
+	// address, set by a trusted proxy, instead of the connection's address
+	TrustForwardedFor bool
+}
+
+// DefaultHandlerSettings returns the settings used when none are configured
+func DefaultHandlerSettings() HandlerSettings {
+	return HandlerSettings{
+		MaxBatchSize:   DefaultMaxBatchSize,
+		MaxBodyBytes:   DefaultMaxBodyBytes,
+		MaxBatchBytes:  DefaultMaxBatchBytes,
+		MaxAnswers:     DefaultMaxAnswers,
+		MaxAnswerDepth: DefaultMaxAnswerDepth,
+	}
+}
+
 // Handler handles HTTP requests
 type Handler struct {
-	reportUseCase usecase.ReportUseCase
+	reportUseCase      usecase.ReportUseCase
+	idempotencyUseCase usecase.IdempotencyUseCase
+	idGenerator        usecase.IDGenerator
+	documentUseCase    usecase.DocumentUseCase
+	webhookUseCase     usecase.WebhookUseCase
+	eventUseCase       usecase.EventUseCase
+	schedulerUseCase   usecase.SchedulerUseCase
+	authUseCase        usecase.AuthUseCase
+	quotaUseCase       usecase.QuotaUseCase
+	rateLimitUseCase   usecase.RateLimitUseCase
+	settings           atomic.Pointer[HandlerSettings]
 }
 
 // NewHandler creates a new HTTP handler
-func NewHandler(reportUseCase usecase.ReportUseCase) *Handler {
-	return &Handler{
-		reportUseCase: reportUseCase,
+func NewHandler(
+	reportUseCase usecase.ReportUseCase,
+	idempotencyUseCase usecase.IdempotencyUseCase,
+	idGenerator usecase.IDGenerator,
+	documentUseCase usecase.DocumentUseCase,
+	webhookUseCase usecase.WebhookUseCase,
+	eventUseCase usecase.EventUseCase,
+	schedulerUseCase usecase.SchedulerUseCase,
+	authUseCase usecase.AuthUseCase,
+	quotaUseCase usecase.QuotaUseCase,
+	rateLimitUseCase usecase.RateLimitUseCase,
+	settings HandlerSettings,
+) *Handler {
+	h := &Handler{
+		reportUseCase:      reportUseCase,
+		idempotencyUseCase: idempotencyUseCase,
+		idGenerator:        idGenerator,
+		documentUseCase:    documentUseCase,
+		webhookUseCase:     webhookUseCase,
+		eventUseCase:       eventUseCase,
+		schedulerUseCase:   schedulerUseCase,
+		authUseCase:        authUseCase,
+		quotaUseCase:       quotaUseCase,
+		rateLimitUseCase:   rateLimitUseCase,
 	}
+	h.UpdateSettings(settings)
+	return h
+}
+
+// UpdateSettings replaces the request limits; requests already being handled keep the old ones
+func (h *Handler) UpdateSettings(settings HandlerSettings) {
+	h.settings.Store(&settings)
+}
+
+// submitRequest is the body of a survey response submission
+type submitRequest struct {
+	SurveyID string                 `json:"survey_id"`
+	Answers  map[string]interface{} `json:"answers"`
 }
 
 // SubmitResponse handles the submission of a survey response
 func (h *Handler) SubmitResponse(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	var request submitRequest
+	if !h.decodeJSON(w, r, &request) {
 		return
 	}
 
-	var request struct {
-		SurveyID string                 `json:"survey_id"`
-		Answers  map[string]interface{} `json:"answers"`
+	if err := usecase.ValidateSurveyID(request.SurveyID); err != nil {
+		writeError(w, r, err)
+		return
 	}
-
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if err := h.checkAnswers(request.Answers); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if request.SurveyID == "" {
-		http.Error(w, "Survey ID is required", http.StatusBadRequest)
+	// Replay the original outcome when a client retries with the same Idempotency-Key,
+	// before the rate limits so a replay is not counted against them
+	// Keys are scoped to the tenant and caller so one caller can never replay another's outcome
+	key := r.Header.Get(idempotencyKeyHeader)
+	var fingerprint string
+	if key != "" {
+		if caller := submitter(r.Context()); caller != "" {
+			key = caller + ":" + key
+		}
+		key = entity.TenantScopedID(entity.TenantFromContext(r.Context()), key)
+		fingerprint = requestFingerprint(request)
+		record, err := h.idempotencyUseCase.Begin(r.Context(), key, fingerprint)
+		switch {
+		case err != nil:
+			writeError(w, r, err)
+			return
+		case record != nil:
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set(idempotentReplayedHeader, "true")
+			w.WriteHeader(record.StatusCode)
+			w.Write(record.Body)
+			return
+		}
+	}
+
+	// Count the response against the rate limits and the tenant's quota; a
+	// replay above is not counted again
+	if !h.allowRateLimit(w, r, []string{request.SurveyID}, nil) || !h.consumeQuota(w, r, 1) {
+		if key != "" {
+			if abortErr := h.idempotencyUseCase.Abort(r.Context(), key); abortErr != nil {
+				log.Printf("Error releasing idempotency key: %v", abortErr)
+			}
+		}
 		return
 	}
 
 	// Create a survey response
 	response := entity.SurveyResponse{
-		ID:        generateID(),
-		SurveyID:  request.SurveyID,
-		Answers:   request.Answers,
-		CreatedAt: time.Now().Unix(),
+		ID:          h.idGenerator.NewID(),
+		SurveyID:    request.SurveyID,
+		Answers:     request.Answers,
+		CreatedAt:   time.Now().Unix(),
+		SubmittedBy: submitter(r.Context()),
 	}
 
 	// Submit the response
 	if err := h.reportUseCase.SubmitResponse(r.Context(), response); err != nil {
-		http.Error(w, "Failed to submit response: "+err.Error(), http.StatusInternalServerError)
+		if key != "" {
+			if abortErr := h.idempotencyUseCase.Abort(r.Context(), key); abortErr != nil {
+				log.Printf("Error releasing idempotency key: %v", abortErr)
+			}
+		}
+		writeError(w, r, err)
 		return
 	}
 
-	// Return success response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusAccepted)
-	json.NewEncoder(w).Encode(map[string]string{
+	body, _ := json.Marshal(map[string]string{
 		"message": "Response submitted successfully",
 		"id":      response.ID,
 	})
+
+	// Record the outcome so a retry with the same key returns the same ID
+	if key != "" {
+		if err := h.idempotencyUseCase.Complete(r.Context(), key, fingerprint, http.StatusAccepted, body); err != nil {
+			log.Printf("Error recording idempotency key: %v", err)
+		}
+	}
+
+	// Return success response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	w.Write(body)
+}
+
+// route is an endpoint of the survey API, relative to APIPrefix
+type route struct {
+	method  string
+	path    string
+	scope   string
+	handler http.HandlerFunc
 }
 
-// SetupRoutes sets up the HTTP routes
+// routes lists every endpoint of the survey API, each requiring the scope of
+// what it exposes; openapi.json documents the same endpoints
+func (h *Handler) routes() []route {
+	return []route{
+		{"POST", "/survey/submit", entity.ScopeSubmit, h.SubmitResponse},
+		{"POST", "/survey/submit/batch", entity.ScopeSubmit, h.SubmitBatch},
+		{"GET", "/survey/{id}/report", entity.ScopeReadReport, h.GetReport},
+		{"GET", "/survey/{id}/jobs", entity.ScopeReadReport, h.ListJobs},
+		{"GET", "/survey/{id}/events", entity.ScopeReadReport, h.StreamEvents},
+		{"GET", "/survey/{id}/report.csv", entity.ScopeReadReport, h.ExportReportCSV},
+		{"GET", "/survey/{id}/report.xlsx", entity.ScopeReadReport, h.ExportReportXLSX},
+		{"GET", "/survey/{id}/responses.csv", entity.ScopeReadReport, h.ExportResponsesCSV},
+		{"GET", "/survey/{id}/responses.jsonl", entity.ScopeReadReport, h.ExportResponsesJSONL},
+		{"GET", "/survey/{id}/report.html", entity.ScopeReadReport, h.DownloadDocument(usecase.DocumentHTML)},
+		{"GET", "/survey/{id}/report.pdf", entity.ScopeReadReport, h.DownloadDocument(usecase.DocumentPDF)},
+		{"POST", "/survey/{id}/webhooks", entity.ScopeAdmin, h.RegisterWebhook},
+		{"GET", "/survey/{id}/webhooks", entity.ScopeAdmin, h.ListWebhooks},
+		{"DELETE", "/survey/{id}/webhooks/{webhookID}", entity.ScopeAdmin, h.DeleteWebhook},
+		{"GET", "/survey/{id}/webhooks/deliveries", entity.ScopeAdmin, h.ListWebhookDeliveries},
+		{"POST", "/survey/{id}/schedules", entity.ScopeAdmin, h.CreateSchedule},
+		{"GET", "/survey/{id}/schedules", entity.ScopeAdmin, h.ListSchedules},
+		{"DELETE", "/survey/{id}/schedules/{scheduleID}", entity.ScopeAdmin, h.DeleteSchedule},
+		{"POST", "/keys", entity.ScopeAdmin, h.CreateAPIKey},
+		{"GET", "/keys", entity.ScopeAdmin, h.ListAPIKeys},
+		{"DELETE", "/keys/{keyID}", entity.ScopeAdmin, h.RevokeAPIKey},
+	}
+}
+
+// SetupRoutes sets up the HTTP routes of the survey API under APIPrefix,
+// with every route also served under the legacy /api prefix, and the API's
+// OpenAPI specification and documentation
 func (h *Handler) SetupRoutes() http.Handler {
 	mux := http.NewServeMux()
+	for _, route := range h.routes() {
+		handler := route.handler
+		if strings.Contains(route.path, "{id}") {
+			handler = validSurveyPath(handler)
+		}
+		handler = h.authorize(route.scope, handler)
+		mux.HandleFunc(route.method+" "+APIPrefix+route.path, handler)
+		mux.HandleFunc(route.method+" "+legacyAPIPrefix+route.path, deprecated(handler))
+	}
+
+	// The specification and its documentation are public, like the health probes
+	mux.HandleFunc("GET "+openAPIPath, ServeOpenAPI)
+	mux.HandleFunc("GET "+docsPath, ServeDocs)
+
+	return withRouteProblems(mux)
+}
+
+// validSurveyPath wraps a handler of a route with an {id} path segment so it
+// only serves requests naming a valid survey ID
+func validSurveyPath(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := usecase.ValidateSurveyID(r.PathValue("id")); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// deprecated wraps a handler served under the legacy /api prefix so its
+// responses point clients to the same route under APIPrefix
+func deprecated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		successor := APIPrefix + strings.TrimPrefix(r.URL.Path, legacyAPIPrefix)
+		w.Header().Set("Deprecation", "true")
+		w.Header().Add("Link", "<"+successor+`>; rel="successor-version"`)
+		next(w, r)
+	}
+}
 
-	// Register routes
-	mux.HandleFunc("/api/survey/submit", h.SubmitResponse)
+// withRouteProblems wraps mux so requests that match no route are answered
+// with a problem: 405 Method Not Allowed when the path has routes for other
+// methods, else 404 Not Found
+func withRouteProblems(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
 
-	return mux
+		var allowed []string
+		for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodDelete} {
+			probe := r.WithContext(r.Context())
+			probe.Method = method
+			if _, pattern := mux.Handler(probe); pattern != "" {
+				allowed = append(allowed, method)
+			}
+		}
+		if len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			writeProblem(w, r, http.StatusMethodNotAllowed, codeMethodNotAllowed, r.Method+" is not allowed on "+r.URL.Path)
+			return
+		}
+		writeProblem(w, r, http.StatusNotFound, codeNotFound, "No endpoint at "+r.URL.Path)
+	})
 }
 
-// generateID generates a simple ID for the response
-// In a real application, you would use a more robust ID generation method
-func generateID() string {
-	return time.Now().Format("20060102150405.000000")
+// requestFingerprint hashes the decoded request so retries that only differ
+// in JSON formatting or key order are still recognised as the same request
+func requestFingerprint(request submitRequest) string {
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(request)
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+func TestSubmitResponse_ReplaysWithoutCountingAgainstRateLimits(t *testing.T) {
+	server := newContractServer(t)
+	server.rateLimits.UpdateSettings(usecase.RateLimitSettings{Survey: usecase.RateLimitRule{Rate: 0.01, Burst: 1}})
+
+	submit := func(key string) *http.Response {
+		t.Helper()
+		request, _ := http.NewRequest(http.MethodPost, server.URL+APIPrefix+"/survey/submit",
+			strings.NewReader(`{"survey_id": "s1", "answers": {"color": "red"}}`))
+		request.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			request.Header.Set(idempotencyKeyHeader, key)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatalf("Failed to submit response: %v", err)
+		}
+		response.Body.Close()
+		return response
+	}
+
+	if response := submit("retry-1"); response.StatusCode != http.StatusAccepted {
+		t.Fatalf("Expected the first submission to be accepted, got %d", response.StatusCode)
+	}
+
+	// The survey's only request is used up, yet the retry replays its outcome
+	response := submit("retry-1")
+	if response.StatusCode != http.StatusAccepted || response.Header.Get(idempotentReplayedHeader) != "true" {
+		t.Errorf("Expected the retry to be replayed, got %d", response.StatusCode)
+	}
+	if response := submit("retry-2"); response.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected a new submission to be rate limited, got %d", response.StatusCode)
+	}
+
+	// The refused submission released its key, so it can be retried later
+	server.rateLimits.UpdateSettings(usecase.RateLimitSettings{})
+	if response := submit("retry-2"); response.StatusCode != http.StatusAccepted || response.Header.Get(idempotentReplayedHeader) == "true" {
+		t.Errorf("Expected the refused submission to be accepted once allowed, got %d", response.StatusCode)
+	}
+}
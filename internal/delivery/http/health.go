@@ -0,0 +1,110 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// readinessTimeout bounds how long all readiness checks together may take
+const readinessTimeout = 2 * time.Second
+
+// ReadinessCheck reports whether one dependency the instance needs to do its work is usable
+type ReadinessCheck struct {
+	// Name identifies the check in the readiness report
+	Name string
+
+	// Check returns nil when the dependency is usable
+	Check func(ctx context.Context) error
+}
+
+// HealthHandler serves the liveness and readiness probes
+type HealthHandler struct {
+	role   string
+	checks []ReadinessCheck
+
+	// workerStats reports the counters of the report worker, nil when the instance runs none
+	workerStats func() usecase.WorkerStats
+}
+
+// NewHealthHandler creates a health handler for an instance running the given role
+func NewHealthHandler(role string, checks ...ReadinessCheck) *HealthHandler {
+	return &HealthHandler{
+		role:   role,
+		checks: checks,
+	}
+}
+
+// WithWorkerStats makes the liveness probe report the counters of the
+// report worker the instance runs
+func (h *HealthHandler) WithWorkerStats(stats func() usecase.WorkerStats) *HealthHandler {
+	h.workerStats = stats
+	return h
+}
+
+// Live handles the liveness probe, which succeeds as long as the process serves requests
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	body := map[string]interface{}{
+		"status": "ok",
+		"role":   h.role,
+	}
+	if h.workerStats != nil {
+		body["worker"] = h.workerStats()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+// Ready handles the readiness probe, which runs every check concurrently and
+// answers 503 Service Unavailable when any of them fails. Failed checks are
+// reported as unavailable, as their errors may name internal hosts, and logged
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	results := make(map[string]string, len(h.checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := "ok"
+			if err := check.Check(ctx); err != nil {
+				log.Printf("Readiness check %s failed: %v", check.Name, err)
+				result = "unavailable"
+			}
+			mu.Lock()
+			results[check.Name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	status, code := "ready", http.StatusOK
+	for _, result := range results {
+		if result != "ok" {
+			status, code = "not ready", http.StatusServiceUnavailable
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": status,
+		"role":   h.role,
+		"checks": results,
+	})
+}
+
+// SetupRoutes registers the probes on mux
+func (h *HealthHandler) SetupRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /healthz", h.Live)
+	mux.HandleFunc("GET /readyz", h.Ready)
+}
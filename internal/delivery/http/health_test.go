@@ -0,0 +1,36 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReady_HidesFailedCheckErrors(t *testing.T) {
+	handler := NewHealthHandler("api",
+		ReadinessCheck{Name: "store", Check: func(ctx context.Context) error { return nil }},
+		ReadinessCheck{Name: "report_queue", Check: func(ctx context.Context) error {
+			return errors.New("dial tcp 10.0.0.7:5672: connection refused")
+		}},
+	)
+	w := httptest.NewRecorder()
+
+	handler.Ready(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status 503, got %d", w.Code)
+	}
+	var body struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode readiness: %v", err)
+	}
+	if body.Status != "not ready" || body.Checks["store"] != "ok" || body.Checks["report_queue"] != "unavailable" {
+		t.Errorf("Expected the failed check to be reported as unavailable, got %+v", body)
+	}
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+const (
+	// openAPIPath is where the OpenAPI specification of the survey API is served
+	openAPIPath = "/openapi.json"
+
+	// docsPath is where the Swagger UI page documenting the survey API is served
+	docsPath = "/docs"
+)
+
+// openAPISpec is the OpenAPI 3.1 specification of the survey API; the client
+// package is generated from it and the contract test checks the handlers against it
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// docsPage renders openAPISpec with Swagger UI, loaded from a CDN so the
+// server does not have to bundle it
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Survey API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "` + openAPIPath + `", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
+
+// ServeOpenAPI handles fetching the OpenAPI specification of the survey API
+func ServeOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Write(openAPISpec)
+}
+
+// ServeDocs handles the Swagger UI page documenting the survey API
+func ServeDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(docsPage))
+}
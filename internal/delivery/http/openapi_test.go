@@ -0,0 +1,473 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	bboltRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/bbolt"
+	filesystemRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/filesystem"
+	"github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/idgen"
+	memoryRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/memory"
+	"github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/webhook"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// contractServer is the survey API served by real use cases over an embedded
+// store, so the contract test exercises the same code paths as production
+type contractServer struct {
+	*httptest.Server
+	handler    *Handler
+	reports    usecase.ReportUseCase
+	quotas     usecase.QuotaUseCase
+	auth       usecase.AuthUseCase
+	rateLimits usecase.RateLimitUseCase
+}
+
+func newContractServer(t *testing.T) *contractServer {
+	t.Helper()
+	dir := t.TempDir()
+	db, err := bboltRepo.Open(filepath.Join(dir, "api.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	blobRepo, err := filesystemRepo.NewBlobRepository(filepath.Join(dir, "blobs"))
+	if err != nil {
+		t.Fatalf("Failed to open blob store: %v", err)
+	}
+
+	reportRepo := bboltRepo.NewReportRepository(db)
+	idGenerator := idgen.NewULIDGenerator()
+	eventUseCase := usecase.NewEventUseCase(memoryRepo.NewEventBusRepository(), reportRepo)
+	reportUseCase := usecase.NewReportUseCase(bboltRepo.NewLockRepository(db), bboltRepo.NewQueueRepository(db, time.Second),
+		bboltRepo.NewResponseRepository(db), reportRepo, bboltRepo.NewJobRepository(db), usecase.DefaultReportSettings())
+	webhookUseCase := usecase.NewWebhookUseCase(bboltRepo.NewWebhookRepository(db), bboltRepo.NewWebhookQueueRepository(db),
+		reportRepo, webhook.NewSender(nil), idGenerator, usecase.DefaultWebhookSettings())
+	schedulerUseCase := usecase.NewSchedulerUseCase(bboltRepo.NewScheduleRepository(db), nil, reportUseCase, idGenerator,
+		usecase.DefaultSchedulerSettings())
+	authUseCase := usecase.NewAuthUseCase(bboltRepo.NewAPIKeyRepository(db), nil, idGenerator)
+	quotaUseCase := usecase.NewQuotaUseCase(bboltRepo.NewQuotaRepository(db), usecase.QuotaSettings{})
+	rateLimitUseCase := usecase.NewRateLimitUseCase(memoryRepo.NewRateLimitRepository(time.Now), nil, usecase.RateLimitSettings{})
+
+	handler := NewHandler(
+		reportUseCase,
+		usecase.NewIdempotencyUseCase(bboltRepo.NewIdempotencyRepository(db)),
+		idGenerator,
+		usecase.NewDocumentUseCase(reportRepo, blobRepo),
+		webhookUseCase,
+		eventUseCase,
+		schedulerUseCase,
+		authUseCase,
+		quotaUseCase,
+		rateLimitUseCase,
+		DefaultHandlerSettings(),
+	)
+	server := httptest.NewServer(Trace(Recover(handler.SetupRoutes())))
+	t.Cleanup(server.Close)
+	return &contractServer{Server: server, handler: handler, reports: reportUseCase, quotas: quotaUseCase, auth: authUseCase,
+		rateLimits: rateLimitUseCase}
+}
+
+// openAPIDocument is the embedded specification, decoded into plain values
+type openAPIDocument struct {
+	root map[string]interface{}
+}
+
+func loadOpenAPI(t *testing.T) openAPIDocument {
+	t.Helper()
+	var doc openAPIDocument
+	if err := json.Unmarshal(openAPISpec, &doc.root); err != nil {
+		t.Fatalf("Failed to parse openapi.json: %v", err)
+	}
+	return doc
+}
+
+// lookup follows keys through nested objects, nil if any is missing
+func lookup(value interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		object, _ := value.(map[string]interface{})
+		value = object[key]
+	}
+	return value
+}
+
+// resolve follows a $ref to the component it points to
+func (doc openAPIDocument) resolve(value interface{}) map[string]interface{} {
+	object, _ := value.(map[string]interface{})
+	if ref, ok := object["$ref"].(string); ok {
+		return doc.resolve(lookup(doc.root, strings.Split(strings.TrimPrefix(ref, "#/"), "/")...))
+	}
+	return object
+}
+
+func TestOpenAPI_DocumentsEveryRoute(t *testing.T) {
+	doc := loadOpenAPI(t)
+	h := &Handler{}
+
+	routed := make(map[string]bool)
+	for _, route := range h.routes() {
+		name := route.method + " " + route.path
+		routed[name] = true
+
+		op, _ := lookup(doc.root, "paths", route.path, strings.ToLower(route.method)).(map[string]interface{})
+		if op == nil {
+			t.Errorf("Expected %s to be documented", name)
+			continue
+		}
+
+		// Every way of authenticating must ask for the scope the route requires
+		security, _ := op["security"].([]interface{})
+		if len(security) == 0 {
+			t.Errorf("Expected %s to document its security requirements", name)
+		}
+		for _, requirement := range security {
+			for scheme, scopes := range requirement.(map[string]interface{}) {
+				if len(scopes.([]interface{})) != 1 || scopes.([]interface{})[0] != route.scope {
+					t.Errorf("Expected %s to require scope %s with %s, got %v", name, route.scope, scheme, scopes)
+				}
+			}
+		}
+
+		// Every path parameter of the route must be documented
+		var documented []string
+		for _, param := range op["parameters"].([]interface{}) {
+			if param := doc.resolve(param); param["in"] == "path" {
+				documented = append(documented, param["name"].(string))
+			}
+		}
+		for _, segment := range strings.Split(route.path, "/") {
+			if strings.HasPrefix(segment, "{") && !slices.Contains(documented, strings.Trim(segment, "{}")) {
+				t.Errorf("Expected %s to document path parameter %s", name, segment)
+			}
+		}
+	}
+
+	for path, item := range lookup(doc.root, "paths").(map[string]interface{}) {
+		for method := range item.(map[string]interface{}) {
+			if name := strings.ToUpper(method) + " " + path; !routed[name] {
+				t.Errorf("Expected documented operation %s to be routed", name)
+			}
+		}
+	}
+}
+
+func TestOpenAPI_ServedWithDocs(t *testing.T) {
+	server := newContractServer(t)
+
+	response, err := http.Get(server.URL + openAPIPath)
+	if err != nil {
+		t.Fatalf("Failed to fetch specification: %v", err)
+	}
+	body, _ := io.ReadAll(response.Body)
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK || !bytes.Equal(body, openAPISpec) {
+		t.Errorf("Expected the embedded specification with status 200, got status %d", response.StatusCode)
+	}
+
+	response, err = http.Get(server.URL + docsPath)
+	if err != nil {
+		t.Fatalf("Failed to fetch docs: %v", err)
+	}
+	body, _ = io.ReadAll(response.Body)
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK || !strings.Contains(string(body), openAPIPath) {
+		t.Errorf("Expected a docs page loading %s, got status %d", openAPIPath, response.StatusCode)
+	}
+}
+
+// contractCall is one request of the contract test and the status it must be answered with
+type contractCall struct {
+	method      string
+	path        string
+	template    string
+	contentType string
+	body        string
+	headers     map[string]string
+	status      int
+}
+
+func TestOpenAPI_ResponsesMatchSpec(t *testing.T) {
+	doc := loadOpenAPI(t)
+	server := newContractServer(t)
+	ctx := context.Background()
+
+	call := func(c contractCall) []byte {
+		t.Helper()
+		if c.contentType == "" && c.body != "" {
+			c.contentType = "application/json"
+		}
+		if c.template == "" {
+			c.template = c.path
+		}
+		return server.check(t, doc, c)
+	}
+
+	// Submissions
+	call(contractCall{method: "POST", path: "/survey/submit", body: `{"survey_id": "s1", "answers": {"color": "red", "age": 31}}`, status: 202})
+	retry := contractCall{method: "POST", path: "/survey/submit", body: `{"survey_id": "s1", "answers": {"color": "blue"}}`,
+		headers: map[string]string{"Idempotency-Key": "retry-1"}, status: 202}
+	call(retry)
+	call(retry)
+	retry.body = `{"survey_id": "s1", "answers": {"color": "green"}}`
+	retry.status = 409
+	call(retry)
+	call(contractCall{method: "POST", path: "/survey/submit", body: `{"answers": {}}`, status: 400})
+	call(contractCall{method: "POST", path: "/survey/submit", body: `{"survey_id": "s1", "answers": {}, "extra": 1}`, status: 400})
+	call(contractCall{method: "POST", path: "/survey/submit", contentType: "text/plain", body: `{}`, status: 415})
+	call(contractCall{method: "POST", path: "/survey/submit", body: `{"survey_id": "s1", "answers": {"essay": "` + strings.Repeat("x", DefaultMaxBodyBytes) + `"}}`, status: 413})
+	call(contractCall{method: "POST", path: "/survey/submit/batch", body: `[{"survey_id": "s1", "answers": {"color": "red"}}, {"survey_id": "", "answers": {}}]`, status: 202})
+	call(contractCall{method: "POST", path: "/survey/submit/batch", contentType: "application/x-ndjson",
+		body: "{\"survey_id\": \"s1\", \"answers\": {\"age\": 40}}\n", status: 202})
+
+	// Reports and exports, before and after the report is generated
+	call(contractCall{method: "GET", path: "/survey/s1/report", template: "/survey/{id}/report", status: 404})
+	call(contractCall{method: "GET", path: "/survey/s1/report.csv", template: "/survey/{id}/report.csv", status: 404})
+	if err := server.reports.GenerateReport(ctx, "s1"); err != nil {
+		t.Fatalf("Failed to generate report: %v", err)
+	}
+	call(contractCall{method: "GET", path: "/survey/s1/report", template: "/survey/{id}/report", status: 200})
+	call(contractCall{method: "GET", path: "/survey/s1/jobs", template: "/survey/{id}/jobs", status: 200})
+	call(contractCall{method: "GET", path: "/survey/s1/report.csv", template: "/survey/{id}/report.csv", status: 200})
+	call(contractCall{method: "GET", path: "/survey/s1/report.xlsx", template: "/survey/{id}/report.xlsx", status: 200})
+	call(contractCall{method: "GET", path: "/survey/s1/responses.csv", template: "/survey/{id}/responses.csv", status: 200})
+	call(contractCall{method: "GET", path: "/survey/s1/responses.jsonl", template: "/survey/{id}/responses.jsonl", status: 200})
+	call(contractCall{method: "GET", path: "/survey/s1/report.html", template: "/survey/{id}/report.html", status: 404})
+	call(contractCall{method: "GET", path: "/survey/s1/report.pdf", template: "/survey/{id}/report.pdf", status: 404})
+
+	// Admin endpoints are refused until authentication is enabled
+	call(contractCall{method: "GET", path: "/keys", status: 403})
+	_, adminKey, err := server.auth.CreateAPIKey(ctx, "operator", "", []string{entity.ScopeAdmin})
+	if err != nil {
+		t.Fatalf("Failed to create API key: %v", err)
+	}
+	settings := DefaultHandlerSettings()
+	settings.RequireAuth = true
+	server.handler.UpdateSettings(settings)
+	admin := map[string]string{apiKeyHeader: adminKey}
+
+	// Webhooks
+	var webhook struct{ ID string }
+	json.Unmarshal(call(contractCall{method: "POST", path: "/survey/s1/webhooks", template: "/survey/{id}/webhooks",
+		body: `{"url": "https://example.com/hooks/survey"}`, headers: admin, status: 201}), &webhook)
+	call(contractCall{method: "POST", path: "/survey/s1/webhooks", template: "/survey/{id}/webhooks", body: `{"url": "ftp://example.com"}`, headers: admin, status: 400})
+	call(contractCall{method: "GET", path: "/survey/s1/webhooks", template: "/survey/{id}/webhooks", headers: admin, status: 200})
+	call(contractCall{method: "GET", path: "/survey/s1/webhooks/deliveries", template: "/survey/{id}/webhooks/deliveries", headers: admin, status: 200})
+	call(contractCall{method: "DELETE", path: "/survey/s1/webhooks/" + webhook.ID, template: "/survey/{id}/webhooks/{webhookID}", headers: admin, status: 204})
+	call(contractCall{method: "DELETE", path: "/survey/s1/webhooks/" + webhook.ID, template: "/survey/{id}/webhooks/{webhookID}", headers: admin, status: 404})
+
+	// Schedules
+	var schedule struct{ ID string }
+	json.Unmarshal(call(contractCall{method: "POST", path: "/survey/s1/schedules", template: "/survey/{id}/schedules",
+		body: `{"cron": "@daily"}`, headers: admin, status: 201}), &schedule)
+	call(contractCall{method: "POST", path: "/survey/s1/schedules", template: "/survey/{id}/schedules", body: `{"cron": "every day"}`, headers: admin, status: 400})
+	call(contractCall{method: "GET", path: "/survey/s1/schedules", template: "/survey/{id}/schedules", headers: admin, status: 200})
+	call(contractCall{method: "DELETE", path: "/survey/s1/schedules/" + schedule.ID, template: "/survey/{id}/schedules/{scheduleID}", headers: admin, status: 204})
+	call(contractCall{method: "DELETE", path: "/survey/s1/schedules/" + schedule.ID, template: "/survey/{id}/schedules/{scheduleID}", headers: admin, status: 404})
+
+	// API keys
+	var created struct{ ID, Key string }
+	json.Unmarshal(call(contractCall{method: "POST", path: "/keys", body: `{"name": "ci", "scopes": ["submit"]}`, headers: admin, status: 201}), &created)
+	call(contractCall{method: "POST", path: "/keys", body: `{"name": "ci", "scopes": ["everything"]}`, headers: admin, status: 400})
+	call(contractCall{method: "GET", path: "/keys", headers: admin, status: 200})
+
+	// Authentication and tenants
+	call(contractCall{method: "GET", path: "/keys", status: 401})
+	call(contractCall{method: "GET", path: "/keys", headers: map[string]string{apiKeyHeader: created.Key}, status: 403})
+	call(contractCall{method: "GET", path: "/keys", headers: map[string]string{apiKeyHeader: "sk_invalid"}, status: 401})
+	call(contractCall{method: "POST", path: "/survey/submit", headers: map[string]string{apiKeyHeader: created.Key, tenantHeader: "-"},
+		body: `{"survey_id": "s1", "answers": {}}`, status: 400})
+	call(contractCall{method: "DELETE", path: "/keys/" + created.ID, template: "/keys/{keyID}", headers: admin, status: 204})
+	call(contractCall{method: "DELETE", path: "/keys/" + created.ID, template: "/keys/{keyID}", headers: admin, status: 404})
+}
+
+func TestOpenAPI_EventStreamMatchesSpec(t *testing.T) {
+	doc := loadOpenAPI(t)
+	server := newContractServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	request, _ := http.NewRequestWithContext(ctx, "GET", server.URL+APIPrefix+"/survey/s1/events", nil)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("Failed to open event stream: %v", err)
+	}
+	defer response.Body.Close()
+
+	responseSpec := doc.responseSpec(t, "/survey/{id}/events", "GET", response.StatusCode)
+	mediaType, _, _ := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	if lookup(responseSpec, "content", mediaType) == nil {
+		t.Errorf("Expected GET /survey/{id}/events to document content type %s", mediaType)
+	}
+}
+
+// check sends the call and checks that its status, content type and body
+// are documented for the operation; it returns the body
+func (s *contractServer) check(t *testing.T, doc openAPIDocument, c contractCall) []byte {
+	t.Helper()
+	name := c.method + " " + c.template
+
+	request, _ := http.NewRequest(c.method, s.URL+APIPrefix+c.path, strings.NewReader(c.body))
+	if c.contentType != "" {
+		request.Header.Set("Content-Type", c.contentType)
+	}
+	for header, value := range c.headers {
+		request.Header.Set(header, value)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("%s: request failed: %v", name, err)
+	}
+	body, _ := io.ReadAll(response.Body)
+	response.Body.Close()
+
+	if response.StatusCode != c.status {
+		t.Errorf("%s: expected status %d, got %d: %s", name, c.status, response.StatusCode, body)
+	}
+	responseSpec := doc.responseSpec(t, c.template, c.method, response.StatusCode)
+	if responseSpec == nil {
+		return body
+	}
+
+	content, _ := responseSpec["content"].(map[string]interface{})
+	mediaType, _, _ := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	if len(content) == 0 {
+		if len(body) > 0 {
+			t.Errorf("%s: expected no body with status %d, got %s", name, response.StatusCode, body)
+		}
+		return body
+	}
+	media, ok := content[mediaType].(map[string]interface{})
+	if !ok {
+		t.Errorf("%s: content type %q of status %d is not documented", name, mediaType, response.StatusCode)
+		return body
+	}
+
+	// Check JSON bodies, and every line of JSON Lines exports, against their schemas
+	var values [][]byte
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		values = [][]byte{body}
+	case mediaType == contentTypeJSONL:
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			values = append(values, bytes.Clone(scanner.Bytes()))
+		}
+		media = map[string]interface{}{"schema": map[string]interface{}{"$ref": "#/components/schemas/SurveyResponse"}}
+	}
+	for _, data := range values {
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			t.Errorf("%s: invalid JSON body: %v", name, err)
+			continue
+		}
+		for _, problem := range doc.validate(media["schema"], value, "body") {
+			t.Errorf("%s: status %d: %s", name, response.StatusCode, problem)
+		}
+	}
+	return body
+}
+
+// responseSpec returns the documented response of an operation with the
+// given status, reporting an error if there is none
+func (doc openAPIDocument) responseSpec(t *testing.T, template, method string, status int) map[string]interface{} {
+	t.Helper()
+	op := lookup(doc.root, "paths", template, strings.ToLower(method))
+	if op == nil {
+		t.Errorf("%s %s is not documented", method, template)
+		return nil
+	}
+	responseSpec := doc.resolve(lookup(op, "responses", fmt.Sprint(status)))
+	if responseSpec == nil {
+		t.Errorf("%s %s: status %d is not documented", method, template, status)
+	}
+	return responseSpec
+}
+
+// validate checks a decoded JSON value against a schema and returns every
+// mismatch. Unlike JSON Schema, properties a schema does not list are
+// mismatches too, so fields added to a response must be documented
+func (doc openAPIDocument) validate(schemaValue, value interface{}, path string) []string {
+	schema := doc.resolve(schemaValue)
+	var problems []string
+	fail := func(format string, args ...interface{}) {
+		problems = append(problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && !slices.Contains(enum, value) {
+		fail("%v is not one of %v", value, enum)
+	}
+
+	switch schema["type"] {
+	case nil:
+	case "string":
+		if _, ok := value.(string); !ok {
+			fail("expected a string, got %v", value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("expected a boolean, got %v", value)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			fail("expected a number, got %v", value)
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != math.Trunc(n) {
+			fail("expected an integer, got %v", value)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			fail("expected an array, got %v", value)
+		}
+		for i, item := range items {
+			problems = append(problems, doc.validate(schema["items"], item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			fail("expected an object, got %v", value)
+			break
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		required, _ := schema["required"].([]interface{})
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				fail("missing required property %s", name)
+			}
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			switch propertySchema, ok := properties[key]; {
+			case ok:
+				problems = append(problems, doc.validate(propertySchema, object[key], path+"."+key)...)
+			case schema["additionalProperties"] != nil:
+				problems = append(problems, doc.validate(schema["additionalProperties"], object[key], path+"."+key)...)
+			default:
+				fail("property %s is not documented", key)
+			}
+		}
+	default:
+		fail("unsupported schema type %v", schema["type"])
+	}
+	return problems
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"regexp"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+const (
+	// problemContentType is the media type of RFC 7807 problem details
+	problemContentType = "application/problem+json"
+
+	// problemTypePrefix starts the type URI of every problem, followed by its code
+	problemTypePrefix = "urn:survey-api:problem:"
+
+	// traceIDHeader is the response header carrying the request's trace ID
+	traceIDHeader = "X-Trace-ID"
+)
+
+// Codes of the problems raised by the HTTP layer itself; problems raised by
+// the domain carry the code of their entity.Error
+const (
+	codeInvalidRequest       = "invalid_request"
+	codeInvalidTenant        = "invalid_tenant"
+	codeForbidden            = "forbidden"
+	codeNotFound             = "not_found"
+	codeMethodNotAllowed     = "method_not_allowed"
+	codePayloadTooLarge      = "payload_too_large"
+	codeUnsupportedMediaType = "unsupported_media_type"
+	codeRateLimited          = "rate_limited"
+	codeInternal             = "internal_error"
+)
+
+// kindStatuses maps every kind of domain error to the status it is answered with
+var kindStatuses = map[entity.ErrorKind]int{
+	entity.ErrorInvalid:         http.StatusBadRequest,
+	entity.ErrorUnauthenticated: http.StatusUnauthorized,
+	entity.ErrorNotFound:        http.StatusNotFound,
+	entity.ErrorConflict:        http.StatusConflict,
+	entity.ErrorExhausted:       http.StatusTooManyRequests,
+}
+
+// problem is an RFC 7807 problem details object, extended with a stable
+// error code and the trace ID of the request
+type problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+	TraceID  string `json:"trace_id,omitempty"`
+}
+
+// writeProblem answers the request with a problem of the given status and code
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	w.Header().Set("Content-Type", problemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem{
+		Type:     problemTypePrefix + code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Code:     code,
+		TraceID:  TraceID(r.Context()),
+	})
+}
+
+// writeError answers the request with the problem err stands for. Domain
+// errors are answered with the status of their kind and their message;
+// any other error is logged and answered with 500 Internal Server Error,
+// hiding its details behind the request's trace ID
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	if domainErr := entity.ErrorOf(err); domainErr != nil {
+		if status, ok := kindStatuses[domainErr.Kind]; ok {
+			writeProblem(w, r, status, domainErr.Code, err.Error())
+			return
+		}
+	}
+
+	log.Printf("Error handling %s %s (trace %s): %v", r.Method, r.URL.Path, TraceID(r.Context()), err)
+	writeProblem(w, r, http.StatusInternalServerError, codeInternal,
+		"The request could not be completed; quote the trace ID when reporting this error")
+}
+
+// traceIDKey is the context key of the request's trace ID
+type traceIDKey struct{}
+
+// TraceID returns the trace ID of the request ctx belongs to, empty if none
+func TraceID(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
+// traceparentPattern matches a W3C traceparent header, capturing its trace ID
+var traceparentPattern = regexp.MustCompile(`^[0-9a-f]{2}-([0-9a-f]{32})-[0-9a-f]{16}-[0-9a-f]{2}$`)
+
+// Trace wraps next so every request carries a trace ID, taken from its W3C
+// traceparent header if it has one, and returns it in the X-Trace-ID header
+func Trace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var traceID string
+		if match := traceparentPattern.FindStringSubmatch(r.Header.Get("traceparent")); match != nil && match[1] != "00000000000000000000000000000000" {
+			traceID = match[1]
+		} else {
+			var id [16]byte
+			rand.Read(id[:])
+			traceID = hex.EncodeToString(id[:])
+		}
+
+		w.Header().Set(traceIDHeader, traceID)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), traceIDKey{}, traceID)))
+	})
+}
@@ -0,0 +1,189 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// decodeProblem decodes the problem a response carries, checking its content type
+func decodeProblem(t *testing.T, header http.Header, body string) problem {
+	t.Helper()
+	if got := header.Get("Content-Type"); got != problemContentType {
+		t.Errorf("Expected Content-Type %s, got %q", problemContentType, got)
+	}
+	var p problem
+	if err := json.Unmarshal([]byte(body), &p); err != nil {
+		t.Fatalf("Failed to decode problem: %v", err)
+	}
+	return p
+}
+
+func TestWriteError_AnswersEveryKindWithItsStatus(t *testing.T) {
+	for kind, status := range kindStatuses {
+		t.Run(string(kind), func(t *testing.T) {
+			err := fmt.Errorf("survey s1: %w", entity.NewError(kind, "some_code", "something happened"))
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/survey/s1/report", nil)
+			w := httptest.NewRecorder()
+
+			writeError(w, r, err)
+
+			if w.Code != status {
+				t.Fatalf("Expected status %d, got %d", status, w.Code)
+			}
+			p := decodeProblem(t, w.Header(), w.Body.String())
+			want := problem{
+				Type:     problemTypePrefix + "some_code",
+				Title:    http.StatusText(status),
+				Status:   status,
+				Detail:   err.Error(),
+				Instance: "/api/v1/survey/s1/report",
+				Code:     "some_code",
+			}
+			if p != want {
+				t.Errorf("Expected %+v, got %+v", want, p)
+			}
+		})
+	}
+}
+
+func TestWriteError_HidesInternalDetails(t *testing.T) {
+	handler := Trace(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, errors.New("dial tcp 10.0.0.5:6379: connection refused"))
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/survey/s1/report", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status 500, got %d", w.Code)
+	}
+	p := decodeProblem(t, w.Header(), w.Body.String())
+	if p.Code != codeInternal || strings.Contains(p.Detail, "10.0.0.5") {
+		t.Errorf("Expected an internal error without its cause, got %+v", p)
+	}
+	if traceID := w.Header().Get(traceIDHeader); traceID == "" || p.TraceID != traceID {
+		t.Errorf("Expected the problem to quote the trace ID %q, got %q", traceID, p.TraceID)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	traceIDPattern := regexp.MustCompile(`^[0-9a-f]{32}$`)
+	tests := []struct {
+		name        string
+		traceparent string
+		want        string
+	}{
+		{"from traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"without traceparent", "", ""},
+		{"malformed traceparent", "00-4BF92F3577B34DA6-00f067aa0ba902b7-01", ""},
+		{"all-zero trace ID", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := Trace(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = TraceID(r.Context())
+			}))
+			r := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+			if tt.traceparent != "" {
+				r.Header.Set("traceparent", tt.traceparent)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			header := w.Header().Get(traceIDHeader)
+			if header != seen {
+				t.Errorf("Expected the header to carry the request's trace ID %q, got %q", seen, header)
+			}
+			if tt.want != "" && seen != tt.want {
+				t.Errorf("Expected trace ID %s, got %s", tt.want, seen)
+			}
+			if tt.want == "" && (!traceIDPattern.MatchString(seen) || strings.Trim(seen, "0") == "") {
+				t.Errorf("Expected a new random trace ID, got %q", seen)
+			}
+		})
+	}
+}
+
+func TestSetupRoutes_AnswersUnknownRoutesWithProblems(t *testing.T) {
+	server := newContractServer(t)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantCode   string
+		wantAllow  string
+	}{
+		{"unknown path", http.MethodGet, "/api/v1/nothing", http.StatusNotFound, codeNotFound, ""},
+		{"unknown legacy path", http.MethodGet, "/api/nothing", http.StatusNotFound, codeNotFound, ""},
+		{"wrong method", http.MethodGet, "/api/v1/survey/submit", http.StatusMethodNotAllowed, codeMethodNotAllowed, "POST"},
+		{"wrong legacy method", http.MethodDelete, "/api/survey/submit", http.StatusMethodNotAllowed, codeMethodNotAllowed, "POST"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL+tt.path, nil)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to send request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+			if got := resp.Header.Get("Allow"); got != tt.wantAllow {
+				t.Errorf("Expected Allow %q, got %q", tt.wantAllow, got)
+			}
+			var p problem
+			if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+				t.Fatalf("Failed to decode problem: %v", err)
+			}
+			if p.Code != tt.wantCode || p.Instance != tt.path || p.TraceID != resp.Header.Get(traceIDHeader) {
+				t.Errorf("Expected a %s problem for %s quoting the trace ID, got %+v", tt.wantCode, tt.path, p)
+			}
+		})
+	}
+}
+
+func TestSetupRoutes_DeprecatesLegacyPrefix(t *testing.T) {
+	server := newContractServer(t)
+
+	resp, err := http.Get(server.URL + "/api/survey/s1/jobs")
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected the legacy alias to serve the route, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Deprecation"); got != "true" {
+		t.Errorf("Expected Deprecation: true, got %q", got)
+	}
+	if got := resp.Header.Get("Link"); got != `</api/v1/survey/s1/jobs>; rel="successor-version"` {
+		t.Errorf("Expected a Link to the successor route, got %q", got)
+	}
+
+	resp, err = http.Get(server.URL + "/api/v1/survey/s1/jobs")
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.Header.Get("Deprecation") != "" || resp.Header.Get("Link") != "" {
+		t.Errorf("Expected the current route not to be deprecated, got %v", resp.Header)
+	}
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// allowRateLimit counts the submission against the rate limit of the
+// instance and the rate limits of its caller, IP address and surveys, shared
+// by every instance. It reports the tightest
+// limit in the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset
+// headers, rejects the request with 429 Too Many Requests when a limit is
+// exceeded, and reports whether it may proceed
+// surveyResponses counts the responses submitted to each survey, nil for one each
+func (h *Handler) allowRateLimit(w http.ResponseWriter, r *http.Request, surveyIDs []string, surveyResponses map[string]int) bool {
+	result, err := h.rateLimitUseCase.AllowSubmission(r.Context(), usecase.RateLimitedSubmission{
+		Client:          submitter(r.Context()),
+		IP:              h.clientIP(r),
+		SurveyIDs:       surveyIDs,
+		SurveyResponses: surveyResponses,
+	})
+	if err != nil && !errors.Is(err, usecase.ErrRateLimited) {
+		// An unavailable rate limiter must not take submissions down with it
+		log.Printf("Error checking rate limits, letting the submission through: %v", err)
+		return true
+	}
+
+	setRateLimitHeaders(w, result)
+	if err != nil {
+		w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+		writeError(w, r, err)
+		return false
+	}
+	return true
+}
+
+// setRateLimitHeaders describes a rate limit in the RateLimit-* headers, unless no limit applied
+func setRateLimitHeaders(w http.ResponseWriter, result entity.RateLimitResult) {
+	if result.Limit == 0 {
+		return
+	}
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+}
+
+// ceilSeconds returns d in whole seconds, rounded up
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// clientIP returns the IP address the request came from: the last
+// X-Forwarded-For address when the proxy setting it is trusted, else the
+// address of the connection
+func (h *Handler) clientIP(r *http.Request) string {
+	if h.settings.Load().TrustForwardedFor {
+		if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+			addrs := strings.Split(forwarded[len(forwarded)-1], ",")
+			if ip := strings.TrimSpace(addrs[len(addrs)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// consumeQuota counts n responses against the daily quota of the request's
+// tenant, rejecting the request with 429 Too Many Requests until the quota
+// resets when it is used up, and reports whether it may proceed
+func (h *Handler) consumeQuota(w http.ResponseWriter, r *http.Request, n int) bool {
+	err := h.quotaUseCase.ConsumeResponses(r.Context(), n)
+	if err == nil {
+		return true
+	}
+
+	if errors.Is(err, usecase.ErrQuotaExceeded) {
+		wait := time.Until(usecase.QuotaResetAt(time.Now()))
+		w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(wait)))
+	}
+	writeError(w, r, err)
+	return false
+}
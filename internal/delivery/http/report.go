@@ -0,0 +1,43 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// GetReport handles fetching the latest generated report for a survey
+func (h *Handler) GetReport(w http.ResponseWriter, r *http.Request) {
+	surveyID := r.PathValue("id")
+
+	report, err := h.reportUseCase.GetReport(r.Context(), surveyID)
+	if err != nil {
+		writeError(w, r, fmt.Errorf("report of survey %s: %w", surveyID, err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// ListJobs handles listing the report jobs recorded for a survey
+func (h *Handler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	surveyID := r.PathValue("id")
+
+	jobs, err := h.reportUseCase.ListJobs(r.Context(), surveyID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if jobs == nil {
+		jobs = []entity.JobRecord{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"survey_id": surveyID,
+		"jobs":      jobs,
+	})
+}
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+const (
+	// DefaultMaxBodyBytes is the largest request body accepted unless configured
+	DefaultMaxBodyBytes = 1 << 20
+
+	// DefaultMaxBatchBytes is the largest batch submission body accepted unless configured
+	DefaultMaxBatchBytes = 32 << 20
+
+	// DefaultMaxAnswers is the maximum number of answers in one response unless configured
+	DefaultMaxAnswers = 500
+
+	// DefaultMaxAnswerDepth is how deeply answers may nest unless configured
+	DefaultMaxAnswerDepth = 4
+)
+
+// decodeJSON decodes the JSON request body into v, rejecting bodies that are
+// not JSON with 415 Unsupported Media Type, bodies above the size limit with
+// 413 Request Entity Too Large, and malformed bodies or bodies with unknown
+// fields with 400 Bad Request. Reports whether v was decoded
+func (h *Handler) decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if !requireContentType(w, r, "application/json") {
+		return false
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.settings.Load().MaxBodyBytes))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(v)
+	if err == nil && decoder.Decode(&json.RawMessage{}) != io.EOF {
+		err = errors.New("body must contain a single JSON value")
+	}
+	if err != nil {
+		writeBodyError(w, r, err)
+		return false
+	}
+	return true
+}
+
+// requireContentType rejects the request with 415 Unsupported Media Type
+// unless its body has one of the given media types, and reports whether it may proceed
+func requireContentType(w http.ResponseWriter, r *http.Request, mediaTypes ...string) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	for _, accepted := range mediaTypes {
+		if mediaType == accepted {
+			return true
+		}
+	}
+
+	w.Header().Set("Accept-Post", strings.Join(mediaTypes, ", "))
+	writeProblem(w, r, http.StatusUnsupportedMediaType, codeUnsupportedMediaType, "Content-Type must be "+strings.Join(mediaTypes, " or "))
+	return false
+}
+
+// writeBodyError rejects a request whose body could not be decoded, with 413
+// Request Entity Too Large when it was above the size limit
+func writeBodyError(w http.ResponseWriter, r *http.Request, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeProblem(w, r, http.StatusRequestEntityTooLarge, codePayloadTooLarge, fmt.Sprintf("Request body exceeds %d bytes", tooLarge.Limit))
+		return
+	}
+	writeProblem(w, r, http.StatusBadRequest, codeInvalidRequest, "Invalid request body: "+err.Error())
+}
+
+// checkAnswers checks the answers of a response against the configured
+// number of answers and nesting depth
+func (h *Handler) checkAnswers(answers map[string]interface{}) error {
+	settings := h.settings.Load()
+	return usecase.CheckAnswerLimits(answers, settings.MaxAnswers, settings.MaxAnswerDepth)
+}
+
+// disableWriteTimeout lifts the server's write timeout from a response that
+// streams for longer, such as live updates and exports
+func disableWriteTimeout(w http.ResponseWriter) {
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("Error lifting write timeout: %v", err)
+	}
+}
+
+// Recover wraps next so a panic while handling a request is logged with its
+// stack and answered with 500 Internal Server Error instead of dropping the
+// connection. When the response was already under way it can no longer be
+// replaced, so the connection is aborted to keep the client from taking a
+// truncated response for a complete one
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cw := &committedWriter{ResponseWriter: w}
+		defer func() {
+			err := recover()
+			if err == nil {
+				return
+			}
+			// ErrAbortHandler deliberately aborts the response; let the server handle it
+			if err == http.ErrAbortHandler {
+				panic(err)
+			}
+			if cw.committed {
+				log.Printf("Error handling %s %s (trace %s), aborting the response: panic: %v\n%s", r.Method, r.URL.Path, TraceID(r.Context()), err, debug.Stack())
+				panic(http.ErrAbortHandler)
+			}
+			writeError(w, r, fmt.Errorf("panic: %v\n%s", err, debug.Stack()))
+		}()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// committedWriter records whether the status line of a response was sent
+type committedWriter struct {
+	http.ResponseWriter
+	committed bool
+}
+
+// WriteHeader sends the status line
+func (w *committedWriter) WriteHeader(status int) {
+	w.committed = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write sends body bytes, and the status line first if it was not sent yet
+func (w *committedWriter) Write(b []byte) (int, error) {
+	w.committed = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends what was written so far, for responses that stream
+func (w *committedWriter) Flush() {
+	w.committed = true
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the wrapped writer so http.ResponseController reaches it
+func (w *committedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSubmitResponse_RejectsInvalidBodies(t *testing.T) {
+	server := newContractServer(t)
+	settings := DefaultHandlerSettings()
+	settings.MaxBodyBytes = 256
+	settings.MaxAnswers = 2
+	settings.MaxAnswerDepth = 2
+	server.handler.UpdateSettings(settings)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+		wantCode    string
+	}{
+		{"accepted", "application/json", `{"survey_id":"s1","answers":{"q1":"yes"}}`, http.StatusAccepted, ""},
+		{"charset parameter", "application/json; charset=utf-8", `{"survey_id":"s1","answers":{"q1":"yes"}}`, http.StatusAccepted, ""},
+		{"body too large", "application/json", `{"survey_id":"s1","answers":{"q1":"` + strings.Repeat("x", 300) + `"}}`, http.StatusRequestEntityTooLarge, codePayloadTooLarge},
+		{"not JSON", "text/plain", `{"survey_id":"s1","answers":{"q1":"yes"}}`, http.StatusUnsupportedMediaType, codeUnsupportedMediaType},
+		{"no content type", "", `{"survey_id":"s1","answers":{"q1":"yes"}}`, http.StatusUnsupportedMediaType, codeUnsupportedMediaType},
+		{"unknown field", "application/json", `{"survey_id":"s1","answers":{"q1":"yes"},"extra":1}`, http.StatusBadRequest, codeInvalidRequest},
+		{"two JSON values", "application/json", `{"survey_id":"s1","answers":{"q1":"yes"}} {}`, http.StatusBadRequest, codeInvalidRequest},
+		{"answers at the limit", "application/json", `{"survey_id":"s1","answers":{"q1":1,"q2":2}}`, http.StatusAccepted, ""},
+		{"too many answers", "application/json", `{"survey_id":"s1","answers":{"q1":1,"q2":2,"q3":3}}`, http.StatusBadRequest, "invalid_response"},
+		{"nesting at the limit", "application/json", `{"survey_id":"s1","answers":{"q1":{"a":[1]}}}`, http.StatusAccepted, ""},
+		{"nesting one over the limit", "application/json", `{"survey_id":"s1","answers":{"q1":{"a":[[1]]}}}`, http.StatusBadRequest, "invalid_response"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/api/v1/survey/submit", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to submit: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			var problem struct {
+				Code string `json:"code"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil || problem.Code != tt.wantCode {
+				t.Errorf("Expected code %s, got %q (%v)", tt.wantCode, problem.Code, err)
+			}
+			if tt.wantStatus == http.StatusUnsupportedMediaType && resp.Header.Get("Accept-Post") != "application/json" {
+				t.Errorf("Expected Accept-Post to name the accepted media type, got %q", resp.Header.Get("Accept-Post"))
+			}
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	t.Run("before the response", func(t *testing.T) {
+		handler := Trace(Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/survey/s1/report", nil))
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("Expected status 500, got %d", w.Code)
+		}
+		if body := w.Body.String(); !strings.Contains(body, codeInternal) || strings.Contains(body, "boom") {
+			t.Errorf("Expected an internal error problem hiding the panic, got %s", body)
+		}
+	})
+
+	t.Run("after the response started", func(t *testing.T) {
+		handler := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"partial":`))
+			panic("boom")
+		}))
+		w := httptest.NewRecorder()
+
+		defer func() {
+			if err := recover(); err != http.ErrAbortHandler {
+				t.Errorf("Expected the response to be aborted, got %v", err)
+			}
+			if w.Code != http.StatusOK || !bytes.Equal(w.Body.Bytes(), []byte(`{"partial":`)) {
+				t.Errorf("Expected nothing to be written after the partial response, got %d %s", w.Code, w.Body)
+			}
+		}()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/survey/s1/responses.jsonl", nil))
+	})
+
+	t.Run("deliberate abort", func(t *testing.T) {
+		handler := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+		defer func() {
+			if err := recover(); err != http.ErrAbortHandler {
+				t.Errorf("Expected ErrAbortHandler to pass through, got %v", err)
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
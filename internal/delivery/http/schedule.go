@@ -0,0 +1,67 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// createScheduleRequest is the body of a schedule creation; exactly one field is set
+type createScheduleRequest struct {
+	Cron string `json:"cron"`
+	// At is a one-off run time in Unix seconds
+	At int64 `json:"at"`
+}
+
+// CreateSchedule handles scheduling report generation for a survey
+func (h *Handler) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	surveyID := r.PathValue("id")
+
+	var request createScheduleRequest
+	if !h.decodeJSON(w, r, &request) {
+		return
+	}
+
+	schedule, err := h.schedulerUseCase.CreateSchedule(r.Context(), surveyID, request.Cron, request.At)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(schedule)
+}
+
+// ListSchedules handles listing the report schedules of a survey
+func (h *Handler) ListSchedules(w http.ResponseWriter, r *http.Request) {
+	surveyID := r.PathValue("id")
+
+	schedules, err := h.schedulerUseCase.ListSchedules(r.Context(), surveyID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if schedules == nil {
+		schedules = []entity.Schedule{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"survey_id": surveyID,
+		"schedules": schedules,
+	})
+}
+
+// DeleteSchedule handles removing a report schedule from a survey
+func (h *Handler) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	scheduleID := r.PathValue("scheduleID")
+	if err := h.schedulerUseCase.DeleteSchedule(r.Context(), r.PathValue("id"), scheduleID); err != nil {
+		writeError(w, r, fmt.Errorf("schedule %s: %w", scheduleID, err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
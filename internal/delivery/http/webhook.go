@@ -0,0 +1,91 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// registerWebhookRequest is the body of a webhook registration
+type registerWebhookRequest struct {
+	URL string `json:"url"`
+	// Secret is optional; a random one is generated and returned when it is empty
+	Secret string `json:"secret"`
+}
+
+// RegisterWebhook handles registering a webhook for a survey
+// The response is the only place the signing secret is returned
+func (h *Handler) RegisterWebhook(w http.ResponseWriter, r *http.Request) {
+	surveyID := r.PathValue("id")
+
+	var request registerWebhookRequest
+	if !h.decodeJSON(w, r, &request) {
+		return
+	}
+
+	webhook, err := h.webhookUseCase.RegisterWebhook(r.Context(), surveyID, request.URL, request.Secret)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// ListWebhooks handles listing the webhooks registered for a survey, without their secrets
+func (h *Handler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	surveyID := r.PathValue("id")
+
+	webhooks, err := h.webhookUseCase.ListWebhooks(r.Context(), surveyID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if webhooks == nil {
+		webhooks = []entity.Webhook{}
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"survey_id": surveyID,
+		"webhooks":  webhooks,
+	})
+}
+
+// DeleteWebhook handles removing a webhook from a survey
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookID := r.PathValue("webhookID")
+	if err := h.webhookUseCase.DeleteWebhook(r.Context(), r.PathValue("id"), webhookID); err != nil {
+		writeError(w, r, fmt.Errorf("webhook %s: %w", webhookID, err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries handles listing the webhook delivery log of a survey
+func (h *Handler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	surveyID := r.PathValue("id")
+
+	deliveries, err := h.webhookUseCase.ListDeliveries(r.Context(), surveyID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []entity.WebhookDeliveryAttempt{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"survey_id":  surveyID,
+		"deliveries": deliveries,
+	})
+}
@@ -0,0 +1,99 @@
+package bbolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	bolt "go.etcd.io/bbolt"
+)
+
+// APIKeyRepository implements the repository.APIKeyRepository interface using bbolt
+type APIKeyRepository struct {
+	db *bolt.DB
+}
+
+// NewAPIKeyRepository creates a new bbolt API key repository
+func NewAPIKeyRepository(db *bolt.DB) repository.APIKeyRepository {
+	return &APIKeyRepository{
+		db: db,
+	}
+}
+
+// SaveAPIKey stores an API key in the API key bucket
+func (r *APIKeyRepository) SaveAPIKey(ctx context.Context, key entity.APIKey) error {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal API key: %w", err)
+	}
+
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(apiKeysBucket).Put([]byte(key.Hash), data)
+	})
+}
+
+// GetAPIKeyByHash returns the API key stored under hash
+func (r *APIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*entity.APIKey, error) {
+	var key *entity.APIKey
+	err := r.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(apiKeysBucket).Get([]byte(hash))
+		if value == nil {
+			return nil
+		}
+		key = &entity.APIKey{}
+		if err := json.Unmarshal(value, key); err != nil {
+			return fmt.Errorf("failed to unmarshal API key: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, repository.ErrNotFound
+	}
+
+	return key, nil
+}
+
+// ListAPIKeys returns every API key ordered by creation time
+func (r *APIKeyRepository) ListAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	var keys []entity.APIKey
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(apiKeysBucket).ForEach(func(_, value []byte) error {
+			var key entity.APIKey
+			if err := json.Unmarshal(value, &key); err != nil {
+				return fmt.Errorf("failed to unmarshal API key: %w", err)
+			}
+			keys = append(keys, key)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt < keys[j].CreatedAt })
+	return keys, nil
+}
+
+// DeleteAPIKey removes the API key with the given ID from the API key bucket
+func (r *APIKeyRepository) DeleteAPIKey(ctx context.Context, id string) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(apiKeysBucket)
+		c := b.Cursor()
+		for hash, value := c.First(); hash != nil; hash, value = c.Next() {
+			var key entity.APIKey
+			if err := json.Unmarshal(value, &key); err != nil {
+				return fmt.Errorf("failed to unmarshal API key: %w", err)
+			}
+			if key.ID == id {
+				return c.Delete()
+			}
+		}
+		return repository.ErrNotFound
+	})
+}
@@ -0,0 +1,248 @@
+package bbolt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+var (
+	// jobsBucket holds pending report jobs keyed by their publish sequence
+	jobsBucket = []byte("report_jobs")
+
+	// delayedJobsBucket holds report jobs that are not ready yet keyed by when they become ready
+	delayedJobsBucket = []byte("report_jobs_delayed")
+
+	// locksBucket holds lock keys mapped to their expiry time
+	locksBucket = []byte("locks")
+
+	// responsesBucket holds one nested bucket of responses per survey
+	responsesBucket = []byte("responses")
+
+	// reportsBucket holds the latest report per survey
+	reportsBucket = []byte("reports")
+
+	// watermarksBucket holds the watermark of the latest completed report per survey
+	watermarksBucket = []byte("watermarks")
+
+	// aggregatesBucket holds the partial report aggregates per survey
+	aggregatesBucket = []byte("aggregates")
+
+	// idempotencyBucket holds idempotency records keyed by Idempotency-Key
+	idempotencyBucket = []byte("idempotency")
+
+	// jobRecordsBucket holds one nested bucket of job status records per survey
+	jobRecordsBucket = []byte("job_records")
+
+	// webhooksBucket holds one nested bucket of webhooks per survey
+	webhooksBucket = []byte("webhooks")
+
+	// deliveryLogBucket holds one nested bucket of webhook delivery attempts per survey
+	deliveryLogBucket = []byte("webhook_deliveries")
+
+	// deliveryQueueBucket holds pending webhook deliveries keyed by their publish sequence
+	deliveryQueueBucket = []byte("webhook_delivery_queue")
+
+	// delayedDeliveriesBucket holds webhook deliveries that are not ready yet keyed by when they become ready
+	delayedDeliveriesBucket = []byte("webhook_delivery_queue_delayed")
+
+	// schedulesBucket holds every report schedule keyed by ID
+	schedulesBucket = []byte("schedules")
+
+	// leasesBucket holds lease keys mapped to their holder and expiry time
+	leasesBucket = []byte("leases")
+
+	// apiKeysBucket holds every API key keyed by the hash of the key
+	apiKeysBucket = []byte("api_keys")
+
+	// quotasBucket holds quota usage counters mapped to their usage and expiry time
+	quotasBucket = []byte("quotas")
+)
+
+// buckets lists every top-level bucket created when the database is opened
+var buckets = [][]byte{
+	jobsBucket,
+	delayedJobsBucket,
+	locksBucket,
+	responsesBucket,
+	reportsBucket,
+	watermarksBucket,
+	aggregatesBucket,
+	idempotencyBucket,
+	jobRecordsBucket,
+	webhooksBucket,
+	deliveryLogBucket,
+	deliveryQueueBucket,
+	delayedDeliveriesBucket,
+	schedulesBucket,
+	leasesBucket,
+	apiKeysBucket,
+	quotasBucket,
+}
+
+// surveyBuckets lists the top-level buckets keyed by the storage key of a
+// survey, see entity.TenantScopedID
+var surveyBuckets = [][]byte{
+	responsesBucket,
+	reportsBucket,
+	watermarksBucket,
+	aggregatesBucket,
+	jobRecordsBucket,
+	webhooksBucket,
+	deliveryLogBucket,
+}
+
+// queueBuckets maps the bucket of every queue's ready messages to the bucket
+// of its delayed ones
+var queueBuckets = map[string][]byte{
+	string(jobsBucket):          delayedJobsBucket,
+	string(deliveryQueueBucket): delayedDeliveriesBucket,
+}
+
+// Open opens the bbolt database at the given path, creating the file and
+// all buckets used by the embedded repositories if they do not exist yet,
+// moving data of the default tenant stored under bare survey IDs into its
+// namespace, and moving delayed messages queued among the ready ones to
+// their own bucket
+func Open(path string) (*bolt.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		if err := migrateDefaultTenant(tx); err != nil {
+			return err
+		}
+		return migrateDelayedMessages(tx, time.Now())
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to prepare buckets: %w", err)
+	}
+
+	return db, nil
+}
+
+// migrateDefaultTenant moves the data of default tenant surveys stored before
+// the default tenant had a namespace, under bare survey IDs, to keys in its
+// namespace. Keys that already exist in the namespace are left alone
+func migrateDefaultTenant(tx *bolt.Tx) error {
+	for _, name := range surveyBuckets {
+		b := tx.Bucket(name)
+
+		// Collect the keys first, as a bucket must not change while iterated
+		var legacy [][]byte
+		err := b.ForEach(func(k, _ []byte) error {
+			if entity.LegacySurveyKey(string(k)) {
+				legacy = append(legacy, bytes.Clone(k))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, key := range legacy {
+			target := []byte(entity.TenantScopedID(entity.DefaultTenant, string(key)))
+			if b.Get(target) != nil || b.Bucket(target) != nil {
+				continue
+			}
+			if nested := b.Bucket(key); nested != nil {
+				moved, err := b.CreateBucket(target)
+				if err != nil {
+					return err
+				}
+				if err := copyBucket(nested, moved); err != nil {
+					return err
+				}
+				if err := b.DeleteBucket(key); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := b.Put(target, bytes.Clone(b.Get(key))); err != nil {
+				return err
+			}
+			if err := b.Delete(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// migrateDelayedMessages moves the messages of every queue that are not
+// ready at now, stored among the ready ones before delayed messages had a
+// bucket of their own, to the queue's bucket of delayed messages
+func migrateDelayedMessages(tx *bolt.Tx, now time.Time) error {
+	for name, delayedName := range queueBuckets {
+		ready, delayed := tx.Bucket([]byte(name)), tx.Bucket(delayedName)
+
+		// Collect the keys first, as a bucket must not change while iterated
+		var keys [][]byte
+		var notBefore []int64
+		err := ready.ForEach(func(k, v []byte) error {
+			var s schedule
+			if json.Unmarshal(v, &s) == nil && s.NotBefore > now.UnixNano() {
+				keys = append(keys, bytes.Clone(k))
+				notBefore = append(notBefore, s.NotBefore)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for i, key := range keys {
+			if err := putDelayed(delayed, notBefore[i], bytes.Clone(ready.Get(key))); err != nil {
+				return err
+			}
+			if err := ready.Delete(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// copyBucket copies every key, nested bucket and the sequence of src into dst
+func copyBucket(src, dst *bolt.Bucket) error {
+	if err := dst.SetSequence(src.Sequence()); err != nil {
+		return err
+	}
+	return src.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return dst.Put(bytes.Clone(k), bytes.Clone(v))
+		}
+		nested, err := dst.CreateBucket(bytes.Clone(k))
+		if err != nil {
+			return err
+		}
+		return copyBucket(src.Bucket(k), nested)
+	})
+}
+
+// itob encodes a sequence number as a big-endian key so keys sort in insertion order
+func itob(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
@@ -0,0 +1,153 @@
+package bbolt_test
+
+import (
+	"context"
+	"encoding/binary"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	bboltRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/bbolt"
+)
+
+func TestOpen_MovesDefaultTenantDataIntoItsNamespace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+
+	// Store data the way it was stored before the default tenant had a namespace
+	legacy, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	err = legacy.Update(func(tx *bolt.Tx) error {
+		responses, err := tx.CreateBucket([]byte("responses"))
+		if err != nil {
+			return err
+		}
+		survey, err := responses.CreateBucket([]byte("survey-123"))
+		if err != nil {
+			return err
+		}
+		for _, id := range []string{"1", "2"} {
+			seq, _ := survey.NextSequence()
+			key := make([]byte, 8)
+			binary.BigEndian.PutUint64(key, seq)
+			if err := survey.Put(key, []byte(`{"id":"`+id+`","survey_id":"survey-123"}`)); err != nil {
+				return err
+			}
+		}
+
+		reports, err := tx.CreateBucket([]byte("reports"))
+		if err != nil {
+			return err
+		}
+		if err := reports.Put([]byte("survey-123"), []byte(`{"survey_id":"survey-123","response_count":2}`)); err != nil {
+			return err
+		}
+		return reports.Put([]byte("acme:survey-123"), []byte(`{"survey_id":"survey-123","response_count":7}`))
+	})
+	legacy.Close()
+	if err != nil {
+		t.Fatalf("Failed to store legacy data: %v", err)
+	}
+
+	db, err := bboltRepo.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	// Responses stored after the move must follow the moved ones
+	ctx := context.Background()
+	responseRepo := bboltRepo.NewResponseRepository(db)
+	if err := responseRepo.SaveResponse(ctx, entity.SurveyResponse{ID: "3", SurveyID: "survey-123"}); err != nil {
+		t.Fatalf("Failed to save response: %v", err)
+	}
+	responses, err := responseRepo.ListResponses(ctx, "survey-123")
+	if err != nil {
+		t.Fatalf("Failed to list responses: %v", err)
+	}
+	if len(responses) != 3 || responses[0].ID != "1" || responses[1].ID != "2" || responses[2].ID != "3" {
+		t.Errorf("Expected responses 1, 2 and 3 in order, got %+v", responses)
+	}
+
+	reportRepo := bboltRepo.NewReportRepository(db)
+	report, err := reportRepo.GetReport(ctx, "survey-123")
+	if err != nil {
+		t.Fatalf("Failed to get report: %v", err)
+	}
+	if report.ResponseCount != 2 {
+		t.Errorf("Expected the default tenant's report, got %+v", report)
+	}
+	acme, err := reportRepo.GetReport(entity.WithTenant(ctx, "acme"), "survey-123")
+	if err != nil {
+		t.Fatalf("Failed to get report of acme: %v", err)
+	}
+	if acme.ResponseCount != 7 {
+		t.Errorf("Expected acme's report to be left alone, got %+v", acme)
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte("responses")).Bucket([]byte("survey-123")) != nil || tx.Bucket([]byte("reports")).Get([]byte("survey-123")) != nil {
+			t.Errorf("Expected the bare survey keys to be removed")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to read database: %v", err)
+	}
+}
+
+func TestOpen_MovesDelayedJobsOutOfTheReadyQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+
+	// Queue a delayed job among the ready ones, the way jobs were queued before
+	legacy, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	notBefore := time.Now().Add(time.Hour).UnixNano()
+	err = legacy.Update(func(tx *bolt.Tx) error {
+		jobs, err := tx.CreateBucket([]byte("report_jobs"))
+		if err != nil {
+			return err
+		}
+		for i, body := range []string{
+			`{"survey_id":"delayed","not_before":` + strconv.FormatInt(notBefore, 10) + `}`,
+			`{"survey_id":"ready"}`,
+		} {
+			key := make([]byte, 8)
+			binary.BigEndian.PutUint64(key, uint64(i+1))
+			if err := jobs.Put(key, []byte(body)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	legacy.Close()
+	if err != nil {
+		t.Fatalf("Failed to store legacy data: %v", err)
+	}
+
+	db, err := bboltRepo.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	err = db.View(func(tx *bolt.Tx) error {
+		if n := tx.Bucket([]byte("report_jobs")).Stats().KeyN; n != 1 {
+			t.Errorf("Expected only the ready job to stay in the ready queue, got %d jobs", n)
+		}
+		if n := tx.Bucket([]byte("report_jobs_delayed")).Stats().KeyN; n != 1 {
+			t.Errorf("Expected the delayed job to be moved, got %d delayed jobs", n)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to read database: %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package bbolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	bolt "go.etcd.io/bbolt"
+)
+
+// IdempotencyRepository implements the repository.IdempotencyRepository interface using bbolt
+type IdempotencyRepository struct {
+	db *bolt.DB
+}
+
+// NewIdempotencyRepository creates a new bbolt idempotency repository
+func NewIdempotencyRepository(db *bolt.DB) repository.IdempotencyRepository {
+	return &IdempotencyRepository{
+		db: db,
+	}
+}
+
+// storedRecord wraps an idempotency record with its expiry time
+type storedRecord struct {
+	Record    entity.IdempotencyRecord `json:"record"`
+	ExpiresAt int64                    `json:"expires_at"`
+}
+
+// Reserve stores the record only if no live record exists for its key
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record entity.IdempotencyRecord, ttl time.Duration) (*entity.IdempotencyRecord, error) {
+	var existing *entity.IdempotencyRecord
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(idempotencyBucket)
+		if value := b.Get([]byte(record.Key)); value != nil {
+			var stored storedRecord
+			if err := json.Unmarshal(value, &stored); err != nil {
+				return fmt.Errorf("failed to unmarshal idempotency record: %w", err)
+			}
+			if stored.ExpiresAt > time.Now().UnixNano() {
+				existing = &stored.Record
+				return nil
+			}
+		}
+		return putRecord(b, record, ttl)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// Save stores the record, replacing any existing record for its key
+func (r *IdempotencyRepository) Save(ctx context.Context, record entity.IdempotencyRecord, ttl time.Duration) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return putRecord(tx.Bucket(idempotencyBucket), record, ttl)
+	})
+}
+
+// Delete removes the record for the given key
+func (r *IdempotencyRepository) Delete(ctx context.Context, key string) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(idempotencyBucket).Delete([]byte(key))
+	})
+}
+
+// putRecord stores a record in the bucket together with its expiry time
+func putRecord(b *bolt.Bucket, record entity.IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(storedRecord{
+		Record:    record,
+		ExpiresAt: time.Now().Add(ttl).UnixNano(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+	return b.Put([]byte(record.Key), data)
+}
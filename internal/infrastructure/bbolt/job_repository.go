@@ -0,0 +1,93 @@
+package bbolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	bolt "go.etcd.io/bbolt"
+)
+
+// JobRepository implements the repository.JobRepository interface using bbolt
+type JobRepository struct {
+	db *bolt.DB
+}
+
+// NewJobRepository creates a new bbolt job repository
+func NewJobRepository(db *bolt.DB) repository.JobRepository {
+	return &JobRepository{
+		db: db,
+	}
+}
+
+// SaveJob stores a job record in the survey's job bucket
+func (r *JobRepository) SaveJob(ctx context.Context, job entity.JobRecord) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	return r.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket(jobRecordsBucket).CreateBucketIfNotExists([]byte(entity.SurveyKey(ctx, job.SurveyID)))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(job.ID), data)
+	})
+}
+
+// GetJob returns the job record with the given ID for the given survey
+func (r *JobRepository) GetJob(ctx context.Context, surveyID, jobID string) (*entity.JobRecord, error) {
+	var job *entity.JobRecord
+	err := r.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(jobRecordsBucket).Bucket([]byte(entity.SurveyKey(ctx, surveyID)))
+		if b == nil {
+			return nil
+		}
+		value := b.Get([]byte(jobID))
+		if value == nil {
+			return nil
+		}
+		job = &entity.JobRecord{}
+		if err := json.Unmarshal(value, job); err != nil {
+			return fmt.Errorf("failed to unmarshal job: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, repository.ErrNotFound
+	}
+
+	return job, nil
+}
+
+// ListJobs returns the job records for the given survey ordered by request time
+func (r *JobRepository) ListJobs(ctx context.Context, surveyID string) ([]entity.JobRecord, error) {
+	var jobs []entity.JobRecord
+	err := r.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(jobRecordsBucket).Bucket([]byte(entity.SurveyKey(ctx, surveyID)))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, value []byte) error {
+			var job entity.JobRecord
+			if err := json.Unmarshal(value, &job); err != nil {
+				return fmt.Errorf("failed to unmarshal job: %w", err)
+			}
+			jobs = append(jobs, job)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].RequestedAt < jobs[j].RequestedAt })
+	return jobs, nil
+}
@@ -0,0 +1,73 @@
+package bbolt
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	bolt "go.etcd.io/bbolt"
+)
+
+// lease is the stored state of a lease
+type lease struct {
+	Holder string `json:"holder"`
+	// ExpiresAt is when the lease lapses unless renewed, in Unix nanoseconds
+	ExpiresAt int64 `json:"expires_at"`
+}
+
+// LeaseRepository implements the repository.LeaseRepository interface using bbolt
+type LeaseRepository struct {
+	db *bolt.DB
+}
+
+// NewLeaseRepository creates a new bbolt lease repository
+func NewLeaseRepository(db *bolt.DB) repository.LeaseRepository {
+	return &LeaseRepository{
+		db: db,
+	}
+}
+
+// AcquireLease takes the lease if it is free or expired, or extends it for its holder
+func (r *LeaseRepository) AcquireLease(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	acquired := false
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(leasesBucket)
+		now := time.Now()
+
+		var current lease
+		if value := b.Get([]byte(key)); value != nil {
+			// A lease that cannot be read is treated as free
+			if json.Unmarshal(value, &current) == nil && current.Holder != holder && current.ExpiresAt > now.UnixNano() {
+				return nil
+			}
+		}
+
+		data, err := json.Marshal(lease{Holder: holder, ExpiresAt: now.Add(ttl).UnixNano()})
+		if err != nil {
+			return err
+		}
+		if err := b.Put([]byte(key), data); err != nil {
+			return err
+		}
+		acquired = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return acquired, nil
+}
+
+// ReleaseLease deletes the lease if holder still has it
+func (r *LeaseRepository) ReleaseLease(ctx context.Context, key, holder string) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(leasesBucket)
+		var current lease
+		if value := b.Get([]byte(key)); value == nil || json.Unmarshal(value, &current) != nil || current.Holder != holder {
+			return nil
+		}
+		return b.Delete([]byte(key))
+	})
+}
@@ -0,0 +1,75 @@
+package bbolt
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	bolt "go.etcd.io/bbolt"
+)
+
+// LockRepository implements the repository.LockRepository interface using bbolt
+type LockRepository struct {
+	db *bolt.DB
+}
+
+// NewLockRepository creates a new bbolt lock repository
+func NewLockRepository(db *bolt.DB) repository.LockRepository {
+	return &LockRepository{
+		db: db,
+	}
+}
+
+// SetLock attempts to set a lock with the given key and TTL
+// An existing lock whose TTL has passed is treated as absent
+func (r *LockRepository) SetLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	locked := false
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(locksBucket)
+		now := time.Now()
+		if isLive(b.Get([]byte(key)), now) {
+			return nil
+		}
+
+		expiry := make([]byte, 8)
+		binary.BigEndian.PutUint64(expiry, uint64(now.Add(ttl).UnixNano()))
+		if err := b.Put([]byte(key), expiry); err != nil {
+			return err
+		}
+		locked = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return locked, nil
+}
+
+// ReleaseLock releases a lock with the given key
+func (r *LockRepository) ReleaseLock(ctx context.Context, key string) (bool, error) {
+	released := false
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(locksBucket)
+		value := b.Get([]byte(key))
+		if value == nil {
+			return nil
+		}
+		released = isLive(value, time.Now())
+		return b.Delete([]byte(key))
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return released, nil
+}
+
+// isLive reports whether a stored lock expiry is still in the future
+func isLive(expiry []byte, now time.Time) bool {
+	if len(expiry) != 8 {
+		return false
+	}
+	return int64(binary.BigEndian.Uint64(expiry)) > now.UnixNano()
+}
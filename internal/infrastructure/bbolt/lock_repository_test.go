@@ -0,0 +1,34 @@
+package bbolt_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bboltRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/bbolt"
+)
+
+func TestLockRepository_ExpiredLockCanBeReacquired(t *testing.T) {
+	db, err := bboltRepo.Open(filepath.Join(t.TempDir(), "lock.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	locks := bboltRepo.NewLockRepository(db)
+
+	if locked, err := locks.SetLock(ctx, "report:lock:survey-123", 50*time.Millisecond); err != nil || !locked {
+		t.Fatalf("Expected first SetLock to succeed, got locked=%v err=%v", locked, err)
+	}
+	if locked, _ := locks.SetLock(ctx, "report:lock:survey-123", time.Minute); locked {
+		t.Errorf("Expected second SetLock to fail while the lock is held")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if locked, err := locks.SetLock(ctx, "report:lock:survey-123", time.Minute); err != nil || !locked {
+		t.Errorf("Expected SetLock to succeed after expiry, got locked=%v err=%v", locked, err)
+	}
+}
@@ -0,0 +1,269 @@
+package bbolt
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// pollInterval is how often the consumer checks for messages when it has not been notified
+	pollInterval = time.Second
+
+	// defaultRetryDelay is how long a failed message waits before it is handled again
+	defaultRetryDelay = time.Second
+)
+
+// queue is a durable FIFO of JSON messages kept in a bucket of ready
+// messages, keyed by their publish sequence, and a bucket of delayed
+// messages, keyed by the time they become ready
+// Messages stay in the buckets until they are handled successfully, so
+// pending messages survive a restart and are picked up again by the next
+// consumer
+type queue struct {
+	db         *bolt.DB
+	bucket     []byte
+	delayed    []byte
+	notify     chan struct{}
+	retryDelay time.Duration
+	// limit is how many messages are handled at the same time
+	limit atomic.Int64
+}
+
+// schedule holds the delivery time every queued message may carry
+type schedule struct {
+	// NotBefore delays delivery until this time, in Unix nanoseconds
+	NotBefore int64 `json:"not_before,omitempty"`
+}
+
+// handled is the outcome of handling one message
+type handled struct {
+	key  []byte
+	body []byte
+	err  error
+}
+
+// newQueue creates a queue over the given buckets of ready and delayed
+// messages, handling one message at a time, whose failed messages are
+// handled again after retryDelay
+func newQueue(db *bolt.DB, bucket, delayed []byte, retryDelay time.Duration) *queue {
+	q := &queue{
+		db:         db,
+		bucket:     bucket,
+		delayed:    delayed,
+		notify:     make(chan struct{}, 1),
+		retryDelay: retryDelay,
+	}
+	q.limit.Store(1)
+	return q
+}
+
+// setLimit sets how many messages are handled at the same time, at least one
+func (q *queue) setLimit(limit int) {
+	q.limit.Store(int64(max(limit, 1)))
+	q.wake()
+}
+
+// publish appends a message to the queue, or to the delayed messages when
+// it is not ready yet
+func (q *queue) publish(body []byte) error {
+	var s schedule
+	json.Unmarshal(body, &s)
+
+	err := q.db.Update(func(tx *bolt.Tx) error {
+		if s.NotBefore > time.Now().UnixNano() {
+			return putDelayed(tx.Bucket(q.delayed), s.NotBefore, body)
+		}
+		return putReady(tx.Bucket(q.bucket), body)
+	})
+	if err != nil {
+		return err
+	}
+
+	q.wake()
+	return nil
+}
+
+// wake wakes up the consumer without blocking if it is already awake
+func (q *queue) wake() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// consume calls handle for every message that is ready, up to the queue's
+// limit at the same time, until ctx is canceled and the messages being
+// handled are done
+// A message is removed once handle returns nil and delayed by the retry
+// delay when it returns an error, leaving the messages behind it unaffected
+func (q *queue) consume(ctx context.Context, handle func(body []byte) error) {
+	inFlight := make(map[string]bool)
+	done := make(chan handled)
+	finish := func(h handled) {
+		delete(inFlight, string(h.key))
+		if h.err != nil {
+			if err := q.retry(h.key, h.body); err != nil {
+				fmt.Printf("Error requeuing message: %v\n", err)
+			}
+			return
+		}
+		// Remove the message now that it has been handled
+		q.remove(h.key)
+	}
+
+	for {
+		wait := pollInterval
+		for int64(len(inFlight)) < q.limit.Load() && ctx.Err() == nil {
+			key, body, delay, err := q.next(time.Now(), inFlight)
+			if err != nil {
+				fmt.Printf("Error reading message: %v\n", err)
+			}
+			if key == nil {
+				wait = delay
+				break
+			}
+
+			inFlight[string(key)] = true
+			go func() {
+				done <- handled{key: key, body: body, err: handle(body)}
+			}()
+		}
+
+		select {
+		case <-ctx.Done():
+			for len(inFlight) > 0 {
+				finish(<-done)
+			}
+			return
+		case h := <-done:
+			finish(h)
+		case <-q.notify:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// next returns the oldest ready message that is not in flight, without
+// removing it, after moving the delayed messages that are due at now to the
+// ready ones. When no message is ready it returns how long to wait before
+// checking again
+func (q *queue) next(now time.Time, inFlight map[string]bool) ([]byte, []byte, time.Duration, error) {
+	if err := q.promote(now); err != nil {
+		return nil, nil, pollInterval, err
+	}
+
+	var key, body []byte
+	wait := pollInterval
+	err := q.db.View(func(tx *bolt.Tx) error {
+		if k, _ := tx.Bucket(q.delayed).Cursor().First(); k != nil {
+			wait = min(wait, max(time.Duration(delayedUntil(k)-now.UnixNano()), 0))
+		}
+
+		// Only the messages being handled are skipped, at most the limit of them
+		c := tx.Bucket(q.bucket).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if inFlight[string(k)] {
+				continue
+			}
+			key = append([]byte(nil), k...)
+			body = append([]byte(nil), v...)
+			return nil
+		}
+		return nil
+	})
+	return key, body, wait, err
+}
+
+// promote moves the delayed messages that are due at now to the back of the
+// ready ones, in the order they became due
+func (q *queue) promote(now time.Time) error {
+	due := func(k []byte) bool {
+		return k != nil && delayedUntil(k) <= now.UnixNano()
+	}
+
+	// Most checks find nothing due, which needs no write transaction
+	var pending bool
+	err := q.db.View(func(tx *bolt.Tx) error {
+		k, _ := tx.Bucket(q.delayed).Cursor().First()
+		pending = due(k)
+		return nil
+	})
+	if err != nil || !pending {
+		return err
+	}
+
+	return q.db.Update(func(tx *bolt.Tx) error {
+		delayed, ready := tx.Bucket(q.delayed), tx.Bucket(q.bucket)
+
+		// Collect the messages first, as a bucket must not change while iterated
+		var keys, bodies [][]byte
+		c := delayed.Cursor()
+		for k, v := c.First(); due(k); k, v = c.Next() {
+			keys = append(keys, append([]byte(nil), k...))
+			bodies = append(bodies, append([]byte(nil), v...))
+		}
+		for i, k := range keys {
+			if err := putReady(ready, bodies[i]); err != nil {
+				return err
+			}
+			if err := delayed.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// remove deletes a message from the queue
+func (q *queue) remove(key []byte) {
+	err := q.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(q.bucket).Delete(key)
+	})
+	if err != nil {
+		fmt.Printf("Error removing message: %v\n", err)
+	}
+}
+
+// retry moves a failed message to the delayed ones, to be ready again after
+// the retry delay
+func (q *queue) retry(key, body []byte) error {
+	return q.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(q.bucket).Delete(key); err != nil {
+			return err
+		}
+		return putDelayed(tx.Bucket(q.delayed), time.Now().Add(q.retryDelay).UnixNano(), body)
+	})
+}
+
+// putReady appends a message to a bucket of ready messages
+func putReady(b *bolt.Bucket, body []byte) error {
+	seq, err := b.NextSequence()
+	if err != nil {
+		return err
+	}
+	return b.Put(itob(seq), body)
+}
+
+// putDelayed adds a message that becomes ready at notBefore, in Unix
+// nanoseconds, to a bucket of delayed messages
+// Keys are the time followed by a sequence, so messages due at the same time
+// keep their order
+func putDelayed(b *bolt.Bucket, notBefore int64, body []byte) error {
+	seq, err := b.NextSequence()
+	if err != nil {
+		return err
+	}
+	key := append(itob(uint64(max(notBefore, 0))), itob(seq)...)
+	return b.Put(key, body)
+}
+
+// delayedUntil returns when the delayed message under key becomes ready, in Unix nanoseconds
+func delayedUntil(key []byte) int64 {
+	return int64(binary.BigEndian.Uint64(key[:8]))
+}
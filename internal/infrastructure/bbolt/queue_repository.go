@@ -0,0 +1,80 @@
+package bbolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	bolt "go.etcd.io/bbolt"
+)
+
+// QueueRepository implements the repository.QueueRepository interface using bbolt
+// Jobs stay in the bucket until they are processed successfully, so pending jobs
+// survive a restart and are picked up again by the next consumer
+type QueueRepository struct {
+	queue *queue
+}
+
+// NewQueueRepository creates a new bbolt queue repository
+// Jobs are handled one at a time until SetPrefetch allows more; a failed job
+// is retried after retryDelay
+func NewQueueRepository(db *bolt.DB, retryDelay time.Duration) repository.QueueRepository {
+	return &QueueRepository{
+		queue: newQueue(db, jobsBucket, delayedJobsBucket, retryDelay),
+	}
+}
+
+// PublishReportJob appends a report job to the queue
+func (r *QueueRepository) PublishReportJob(ctx context.Context, job entity.ReportJob) error {
+	body, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	if err := r.queue.publish(body); err != nil {
+		return fmt.Errorf("failed to publish a job: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeReportJobs starts consuming report jobs from the queue
+func (r *QueueRepository) ConsumeReportJobs(ctx context.Context, callback func(entity.ReportJob) error) error {
+	go r.queue.consume(ctx, func(body []byte) error {
+		var job entity.ReportJob
+		if err := json.Unmarshal(body, &job); err != nil {
+			// Log error and remove the job from the queue
+			fmt.Printf("Error unmarshaling job: %v\n", err)
+			return nil
+		}
+
+		// Process the job; an error delays it by the retry delay to retry later
+		if err := callback(job); err != nil {
+			fmt.Printf("Error processing job: %v\n", err)
+			return err
+		}
+
+		return nil
+	})
+
+	return nil
+}
+
+// SetPrefetch sets how many jobs are handed to the consumer at the same time
+func (r *QueueRepository) SetPrefetch(prefetch int) error {
+	r.queue.setLimit(prefetch)
+	return nil
+}
+
+// Ping always succeeds: the queue lives in the database file this process holds open
+func (r *QueueRepository) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close is a no-op because the database is owned by the caller
+func (r *QueueRepository) Close() error {
+	return nil
+}
@@ -0,0 +1,183 @@
+package bbolt_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	bboltRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/bbolt"
+)
+
+func TestQueueRepository_JobsSurviveRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.db")
+
+	// Publish a job and close the database without consuming it
+	db, err := bboltRepo.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	queue := bboltRepo.NewQueueRepository(db, time.Second)
+	if err := queue.PublishReportJob(context.Background(), entity.ReportJob{SurveyID: "survey-123"}); err != nil {
+		t.Fatalf("Failed to publish job: %v", err)
+	}
+	db.Close()
+
+	// Reopen the database and expect the job to be delivered
+	db, err = bboltRepo.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan entity.ReportJob, 1)
+	queue = bboltRepo.NewQueueRepository(db, time.Second)
+	err = queue.ConsumeReportJobs(ctx, func(job entity.ReportJob) error {
+		received <- job
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to consume jobs: %v", err)
+	}
+
+	select {
+	case job := <-received:
+		if job.SurveyID != "survey-123" {
+			t.Errorf("Expected SurveyID=%s, got %s", "survey-123", job.SurveyID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the job published before the restart to be delivered")
+	}
+}
+
+// openQueue opens a database in a temporary directory and returns a queue over it
+func openQueue(t *testing.T, retryDelay time.Duration) repository.QueueRepository {
+	t.Helper()
+	db, err := bboltRepo.Open(filepath.Join(t.TempDir(), "queue.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return bboltRepo.NewQueueRepository(db, retryDelay)
+}
+
+func TestQueueRepository_FailedJobDoesNotHoldUpTheOthers(t *testing.T) {
+	queue := openQueue(t, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, surveyID := range []string{"failing", "survey-1", "survey-2"} {
+		if err := queue.PublishReportJob(ctx, entity.ReportJob{SurveyID: surveyID}); err != nil {
+			t.Fatalf("Failed to publish job: %v", err)
+		}
+	}
+	received := make(chan string, 10)
+	err := queue.ConsumeReportJobs(ctx, func(job entity.ReportJob) error {
+		received <- job.SurveyID
+		if job.SurveyID == "failing" {
+			return errors.New("generation failed")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to consume jobs: %v", err)
+	}
+
+	// The failed job waits out its retry delay while the others go ahead
+	for _, want := range []string{"failing", "survey-1", "survey-2"} {
+		select {
+		case got := <-received:
+			if got != want {
+				t.Errorf("Expected job %s, got %s", want, got)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected job %s to be delivered", want)
+		}
+	}
+}
+
+func TestQueueRepository_DelayedJobWaitsForItsTime(t *testing.T) {
+	queue := openQueue(t, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	delay := 300 * time.Millisecond
+	published := time.Now()
+	delayed := entity.ReportJob{SurveyID: "delayed", NotBefore: published.Add(delay).UnixNano()}
+	for _, job := range []entity.ReportJob{delayed, {SurveyID: "ready"}} {
+		if err := queue.PublishReportJob(ctx, job); err != nil {
+			t.Fatalf("Failed to publish job: %v", err)
+		}
+	}
+	received := make(chan string, 2)
+	times := make(chan time.Time, 2)
+	queue.ConsumeReportJobs(ctx, func(job entity.ReportJob) error {
+		received <- job.SurveyID
+		times <- time.Now()
+		return nil
+	})
+
+	for _, want := range []string{"ready", "delayed"} {
+		select {
+		case got := <-received:
+			at := <-times
+			if got != want {
+				t.Errorf("Expected job %s, got %s", want, got)
+			}
+			if got == "delayed" && at.Sub(published) < delay {
+				t.Errorf("Expected the delayed job after %s, got it after %s", delay, at.Sub(published))
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("Expected job %s to be delivered", want)
+		}
+	}
+}
+
+func TestQueueRepository_HandlesUpToThePrefetchAtOnce(t *testing.T) {
+	queue := openQueue(t, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := queue.SetPrefetch(2); err != nil {
+		t.Fatalf("Failed to set prefetch: %v", err)
+	}
+	for _, surveyID := range []string{"survey-1", "survey-2", "survey-3"} {
+		if err := queue.PublishReportJob(ctx, entity.ReportJob{SurveyID: surveyID}); err != nil {
+			t.Fatalf("Failed to publish job: %v", err)
+		}
+	}
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 3)
+	queue.ConsumeReportJobs(ctx, func(job entity.ReportJob) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+
+	// Two jobs start without waiting for each other, the third waits for a free slot
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected 2 jobs to run at once, got %d", i)
+		}
+	}
+	select {
+	case <-started:
+		t.Error("Expected the third job to wait for a free slot")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the third job to run once a slot was free")
+	}
+}
@@ -0,0 +1,66 @@
+package bbolt
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	bolt "go.etcd.io/bbolt"
+)
+
+// QuotaRepository implements the repository.QuotaRepository interface using bbolt
+type QuotaRepository struct {
+	db *bolt.DB
+}
+
+// NewQuotaRepository creates a new bbolt quota repository
+func NewQuotaRepository(db *bolt.DB) repository.QuotaRepository {
+	return &QuotaRepository{
+		db: db,
+	}
+}
+
+// ConsumeQuota adds n to the usage under key unless that would exceed limit
+// Counters are stored as their usage followed by their expiry; expired
+// counters are dropped whenever a counter is consumed
+func (r *QuotaRepository) ConsumeQuota(ctx context.Context, key string, n, limit int64, expireAt time.Time) (int64, bool, error) {
+	var used int64
+	consumed := false
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(quotasBucket)
+		now := time.Now().UnixNano()
+
+		var expired [][]byte
+		c := b.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if len(v) != 16 || int64(binary.BigEndian.Uint64(v[8:])) <= now {
+				expired = append(expired, append([]byte(nil), k...))
+			}
+		}
+		for _, k := range expired {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		if value := b.Get([]byte(key)); value != nil {
+			used = int64(binary.BigEndian.Uint64(value))
+		}
+		if used+n > limit {
+			return nil
+		}
+
+		used += n
+		value := make([]byte, 16)
+		binary.BigEndian.PutUint64(value, uint64(used))
+		binary.BigEndian.PutUint64(value[8:], uint64(expireAt.UnixNano()))
+		consumed = true
+		return b.Put([]byte(key), value)
+	})
+	if err != nil {
+		return 0, false, err
+	}
+
+	return used, consumed, nil
+}
@@ -0,0 +1,125 @@
+package bbolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ReportRepository implements the repository.ReportRepository interface using bbolt
+type ReportRepository struct {
+	db *bolt.DB
+}
+
+// NewReportRepository creates a new bbolt report repository
+func NewReportRepository(db *bolt.DB) repository.ReportRepository {
+	return &ReportRepository{
+		db: db,
+	}
+}
+
+// SaveReport stores a report, replacing any previous report for the same survey
+func (r *ReportRepository) SaveReport(ctx context.Context, report entity.Report) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	return r.put(reportsBucket, entity.SurveyKey(ctx, report.SurveyID), data)
+}
+
+// GetReport returns the latest report for the given survey ID
+func (r *ReportRepository) GetReport(ctx context.Context, surveyID string) (*entity.Report, error) {
+	data, err := r.get(reportsBucket, entity.SurveyKey(ctx, surveyID))
+	if err != nil {
+		return nil, err
+	}
+
+	var report entity.Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// SaveWatermark records how far the latest completed report for a survey reaches
+func (r *ReportRepository) SaveWatermark(ctx context.Context, watermark entity.ReportWatermark) error {
+	data, err := json.Marshal(watermark)
+	if err != nil {
+		return fmt.Errorf("failed to marshal watermark: %w", err)
+	}
+
+	return r.put(watermarksBucket, entity.SurveyKey(ctx, watermark.SurveyID), data)
+}
+
+// GetWatermark returns the watermark of the latest completed report for the given survey ID
+func (r *ReportRepository) GetWatermark(ctx context.Context, surveyID string) (*entity.ReportWatermark, error) {
+	data, err := r.get(watermarksBucket, entity.SurveyKey(ctx, surveyID))
+	if err != nil {
+		return nil, err
+	}
+
+	var watermark entity.ReportWatermark
+	if err := json.Unmarshal(data, &watermark); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal watermark: %w", err)
+	}
+
+	return &watermark, nil
+}
+
+// SaveAggregate stores the partial aggregates of a survey
+func (r *ReportRepository) SaveAggregate(ctx context.Context, aggregate entity.ReportAggregate) error {
+	data, err := json.Marshal(aggregate)
+	if err != nil {
+		return fmt.Errorf("failed to marshal aggregate: %w", err)
+	}
+
+	return r.put(aggregatesBucket, entity.SurveyKey(ctx, aggregate.SurveyID), data)
+}
+
+// GetAggregate returns the partial aggregates of the given survey ID
+func (r *ReportRepository) GetAggregate(ctx context.Context, surveyID string) (*entity.ReportAggregate, error) {
+	data, err := r.get(aggregatesBucket, entity.SurveyKey(ctx, surveyID))
+	if err != nil {
+		return nil, err
+	}
+
+	var aggregate entity.ReportAggregate
+	if err := json.Unmarshal(data, &aggregate); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal aggregate: %w", err)
+	}
+
+	return &aggregate, nil
+}
+
+// put stores a value under the survey key in the given bucket
+func (r *ReportRepository) put(bucket []byte, surveyKey string, data []byte) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put([]byte(surveyKey), data)
+	})
+}
+
+// get returns a copy of the value stored under the survey key in the given bucket
+// Returns ErrNotFound if there is no such value
+func (r *ReportRepository) get(bucket []byte, surveyKey string) ([]byte, error) {
+	var data []byte
+	err := r.db.View(func(tx *bolt.Tx) error {
+		if value := tx.Bucket(bucket).Get([]byte(surveyKey)); value != nil {
+			data = append([]byte(nil), value...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, repository.ErrNotFound
+	}
+
+	return data, nil
+}
@@ -0,0 +1,147 @@
+package bbolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// scanPageSize is the number of responses read per transaction while scanning,
+	// so a slow consumer does not hold a read transaction open for the whole scan
+	scanPageSize = 500
+)
+
+// ResponseRepository implements the repository.ResponseRepository interface using bbolt
+type ResponseRepository struct {
+	db *bolt.DB
+}
+
+// NewResponseRepository creates a new bbolt response repository
+func NewResponseRepository(db *bolt.DB) repository.ResponseRepository {
+	return &ResponseRepository{
+		db: db,
+	}
+}
+
+// SaveResponse appends a survey response to the survey's response bucket
+func (r *ResponseRepository) SaveResponse(ctx context.Context, response entity.SurveyResponse) error {
+	return r.SaveResponses(ctx, []entity.SurveyResponse{response})
+}
+
+// SaveResponses appends several survey responses in a single transaction
+func (r *ResponseRepository) SaveResponses(ctx context.Context, responses []entity.SurveyResponse) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		for _, response := range responses {
+			data, err := json.Marshal(response)
+			if err != nil {
+				return fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			b, err := tx.Bucket(responsesBucket).CreateBucketIfNotExists([]byte(entity.SurveyKey(ctx, response.SurveyID)))
+			if err != nil {
+				return err
+			}
+			seq, err := b.NextSequence()
+			if err != nil {
+				return err
+			}
+			if err := b.Put(itob(seq), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListResponses returns all stored responses for the given survey ID
+func (r *ResponseRepository) ListResponses(ctx context.Context, surveyID string) ([]entity.SurveyResponse, error) {
+	var responses []entity.SurveyResponse
+	err := r.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(responsesBucket).Bucket([]byte(entity.SurveyKey(ctx, surveyID)))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, value []byte) error {
+			var response entity.SurveyResponse
+			if err := json.Unmarshal(value, &response); err != nil {
+				return fmt.Errorf("failed to unmarshal response: %w", err)
+			}
+			responses = append(responses, response)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return responses, nil
+}
+
+// ScanResponses calls fn for every stored response for the given survey ID after offset, page by page
+// Responses are keyed by their sequence number, starting at 1, so the scan
+// starts right after the key of the offset-th response
+func (r *ResponseRepository) ScanResponses(ctx context.Context, surveyID string, offset int64, fn func(entity.SurveyResponse) error) error {
+	var after []byte
+	if offset > 0 {
+		after = itob(uint64(offset))
+	}
+	for {
+		page := make([]entity.SurveyResponse, 0, scanPageSize)
+		err := r.db.View(func(tx *bolt.Tx) error {
+			b := tx.Bucket(responsesBucket).Bucket([]byte(entity.SurveyKey(ctx, surveyID)))
+			if b == nil {
+				return nil
+			}
+
+			c := b.Cursor()
+			k, v := c.First()
+			if after != nil {
+				k, v = c.Seek(after)
+				if k != nil && bytes.Equal(k, after) {
+					k, v = c.Next()
+				}
+			}
+			for ; k != nil && len(page) < scanPageSize; k, v = c.Next() {
+				var response entity.SurveyResponse
+				if err := json.Unmarshal(v, &response); err != nil {
+					return fmt.Errorf("failed to unmarshal response: %w", err)
+				}
+				page = append(page, response)
+				after = append(after[:0], k...)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, response := range page {
+			if err := fn(response); err != nil {
+				return err
+			}
+		}
+
+		if len(page) < scanPageSize {
+			return nil
+		}
+	}
+}
+
+// CountResponses returns the sequence of the survey's response bucket, which
+// is the number of responses stored since responses are never deleted
+func (r *ResponseRepository) CountResponses(ctx context.Context, surveyID string) (int64, error) {
+	var count int64
+	err := r.db.View(func(tx *bolt.Tx) error {
+		if b := tx.Bucket(responsesBucket).Bucket([]byte(entity.SurveyKey(ctx, surveyID))); b != nil {
+			count = int64(b.Sequence())
+		}
+		return nil
+	})
+	return count, err
+}
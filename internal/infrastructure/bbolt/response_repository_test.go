@@ -0,0 +1,122 @@
+package bbolt_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	bboltRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/bbolt"
+)
+
+func TestResponseRepository_ScanResponsesAcrossPages(t *testing.T) {
+	db, err := bboltRepo.Open(filepath.Join(t.TempDir(), "responses.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	// Store more than one page of responses, interleaved with another survey
+	ctx := context.Background()
+	repo := bboltRepo.NewResponseRepository(db)
+	const total = 1234
+	var responses []entity.SurveyResponse
+	for i := 0; i < total; i++ {
+		responses = append(responses,
+			entity.SurveyResponse{ID: fmt.Sprint(i), SurveyID: "survey-123"},
+			entity.SurveyResponse{ID: fmt.Sprint(i), SurveyID: "survey-456"},
+		)
+	}
+	if err := repo.SaveResponses(ctx, responses); err != nil {
+		t.Fatalf("Failed to save responses: %v", err)
+	}
+
+	var ids []string
+	err = repo.ScanResponses(ctx, "survey-123", 0, func(response entity.SurveyResponse) error {
+		if response.SurveyID != "survey-123" {
+			t.Errorf("Unexpected survey ID %q", response.SurveyID)
+		}
+		ids = append(ids, response.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to scan responses: %v", err)
+	}
+
+	if len(ids) != total {
+		t.Fatalf("Expected %d responses, got %d", total, len(ids))
+	}
+	for i, id := range ids {
+		if id != fmt.Sprint(i) {
+			t.Fatalf("Expected response %d in submission order, got %s", i, id)
+		}
+	}
+
+	// Scanning from an offset skips the responses before it, across page boundaries
+	var rest []string
+	err = repo.ScanResponses(ctx, "survey-123", 1000, func(response entity.SurveyResponse) error {
+		rest = append(rest, response.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to scan responses: %v", err)
+	}
+	if len(rest) != total-1000 || rest[0] != "1000" {
+		t.Errorf("Expected %d responses starting at 1000, got %d starting at %v", total-1000, len(rest), rest[:min(len(rest), 1)])
+	}
+
+	// An error from the callback stops the scan
+	stop := errors.New("stop")
+	seen := 0
+	err = repo.ScanResponses(ctx, "survey-123", 0, func(entity.SurveyResponse) error {
+		seen++
+		return stop
+	})
+	if !errors.Is(err, stop) || seen != 1 {
+		t.Errorf("Expected the scan to stop after the first response, got %v after %d", err, seen)
+	}
+
+	// Scanning a survey without responses calls nothing
+	err = repo.ScanResponses(ctx, "survey-789", 0, func(entity.SurveyResponse) error {
+		t.Errorf("Unexpected response")
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Expected no error for an unknown survey, got %v", err)
+	}
+
+	// Counting matches the offsets scans start from
+	for surveyID, want := range map[string]int64{"survey-123": total, "survey-789": 0} {
+		count, err := repo.CountResponses(ctx, surveyID)
+		if err != nil || count != want {
+			t.Errorf("Expected %d responses for %s, got %d (%v)", want, surveyID, count, err)
+		}
+	}
+}
+
+func TestResponseRepository_KeepsTenantsApart(t *testing.T) {
+	db, err := bboltRepo.Open(filepath.Join(t.TempDir(), "responses.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	repo := bboltRepo.NewResponseRepository(db)
+	ctx := context.Background()
+	acme := entity.WithTenant(ctx, "acme")
+	if err := repo.SaveResponse(ctx, entity.SurveyResponse{ID: "default-1", SurveyID: "survey-123"}); err != nil {
+		t.Fatalf("Failed to save response: %v", err)
+	}
+	if err := repo.SaveResponse(acme, entity.SurveyResponse{ID: "acme-1", SurveyID: "survey-123", TenantID: "acme"}); err != nil {
+		t.Fatalf("Failed to save response: %v", err)
+	}
+
+	for tenantCtx, want := range map[context.Context]string{ctx: "default-1", acme: "acme-1"} {
+		responses, err := repo.ListResponses(tenantCtx, "survey-123")
+		if err != nil || len(responses) != 1 || responses[0].ID != want {
+			t.Errorf("Expected only %s for tenant %s, got %+v, %v", want, entity.TenantFromContext(tenantCtx), responses, err)
+		}
+	}
+}
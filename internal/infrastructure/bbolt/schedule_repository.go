@@ -0,0 +1,133 @@
+package bbolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ScheduleRepository implements the repository.ScheduleRepository interface using bbolt
+// Lookups by survey and by due time scan every schedule, which is fine for the
+// handful of schedules of an embedded install
+type ScheduleRepository struct {
+	db *bolt.DB
+}
+
+// NewScheduleRepository creates a new bbolt schedule repository
+func NewScheduleRepository(db *bolt.DB) repository.ScheduleRepository {
+	return &ScheduleRepository{
+		db: db,
+	}
+}
+
+// SaveSchedule stores a schedule in the schedules bucket
+func (r *ScheduleRepository) SaveSchedule(ctx context.Context, schedule entity.Schedule) error {
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedule: %w", err)
+	}
+
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(schedulesBucket).Put([]byte(schedule.ID), data)
+	})
+}
+
+// UpdateSchedule replaces a schedule in the schedules bucket if the bucket still holds it
+func (r *ScheduleRepository) UpdateSchedule(ctx context.Context, schedule entity.Schedule) error {
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedule: %w", err)
+	}
+
+	return r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(schedulesBucket)
+		if bucket.Get([]byte(schedule.ID)) == nil {
+			return repository.ErrNotFound
+		}
+		return bucket.Put([]byte(schedule.ID), data)
+	})
+}
+
+// GetSchedule returns the schedule with the given ID for the given survey of the context's tenant
+func (r *ScheduleRepository) GetSchedule(ctx context.Context, surveyID, scheduleID string) (*entity.Schedule, error) {
+	var schedule *entity.Schedule
+	err := r.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(schedulesBucket).Get([]byte(scheduleID))
+		if value == nil {
+			return nil
+		}
+		schedule = &entity.Schedule{}
+		if err := json.Unmarshal(value, schedule); err != nil {
+			return fmt.Errorf("failed to unmarshal schedule: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil || schedule.SurveyKey() != entity.SurveyKey(ctx, surveyID) {
+		return nil, repository.ErrNotFound
+	}
+
+	return schedule, nil
+}
+
+// ListSchedules returns the schedules of the given survey ordered by creation time
+func (r *ScheduleRepository) ListSchedules(ctx context.Context, surveyID string) ([]entity.Schedule, error) {
+	schedules, err := r.scan(func(schedule entity.Schedule) bool {
+		return schedule.SurveyKey() == entity.SurveyKey(ctx, surveyID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].CreatedAt < schedules[j].CreatedAt })
+	return schedules, nil
+}
+
+// ListDueSchedules returns the schedules whose next run is at or before now
+func (r *ScheduleRepository) ListDueSchedules(ctx context.Context, now int64) ([]entity.Schedule, error) {
+	schedules, err := r.scan(func(schedule entity.Schedule) bool {
+		return schedule.NextRunAt > 0 && schedule.NextRunAt <= now
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].NextRunAt < schedules[j].NextRunAt })
+	return schedules, nil
+}
+
+// DeleteSchedule removes the schedule from the schedules bucket
+func (r *ScheduleRepository) DeleteSchedule(ctx context.Context, surveyID, scheduleID string) error {
+	if _, err := r.GetSchedule(ctx, surveyID, scheduleID); err != nil {
+		return err
+	}
+
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(schedulesBucket).Delete([]byte(scheduleID))
+	})
+}
+
+// scan returns every schedule that match accepts
+func (r *ScheduleRepository) scan(match func(entity.Schedule) bool) ([]entity.Schedule, error) {
+	var schedules []entity.Schedule
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(schedulesBucket).ForEach(func(_, value []byte) error {
+			var schedule entity.Schedule
+			if err := json.Unmarshal(value, &schedule); err != nil {
+				return fmt.Errorf("failed to unmarshal schedule: %w", err)
+			}
+			if match(schedule) {
+				schedules = append(schedules, schedule)
+			}
+			return nil
+		})
+	})
+	return schedules, err
+}
@@ -0,0 +1,68 @@
+package bbolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	bolt "go.etcd.io/bbolt"
+)
+
+// WebhookQueueRepository implements the repository.WebhookQueueRepository interface using bbolt
+type WebhookQueueRepository struct {
+	queue *queue
+}
+
+// NewWebhookQueueRepository creates a new bbolt webhook delivery queue
+func NewWebhookQueueRepository(db *bolt.DB) repository.WebhookQueueRepository {
+	return &WebhookQueueRepository{
+		queue: newQueue(db, deliveryQueueBucket, delayedDeliveriesBucket, defaultRetryDelay),
+	}
+}
+
+// PublishDelivery appends a delivery to the queue
+func (r *WebhookQueueRepository) PublishDelivery(ctx context.Context, delivery entity.WebhookDelivery) error {
+	body, err := json.Marshal(delivery)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delivery: %w", err)
+	}
+
+	if err := r.queue.publish(body); err != nil {
+		return fmt.Errorf("failed to publish a delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeDeliveries starts consuming deliveries from the queue
+func (r *WebhookQueueRepository) ConsumeDeliveries(ctx context.Context, callback func(entity.WebhookDelivery) error) error {
+	go r.queue.consume(ctx, func(body []byte) error {
+		var delivery entity.WebhookDelivery
+		if err := json.Unmarshal(body, &delivery); err != nil {
+			// Log error and remove the delivery from the queue
+			fmt.Printf("Error unmarshaling delivery: %v\n", err)
+			return nil
+		}
+
+		if err := callback(delivery); err != nil {
+			fmt.Printf("Error processing delivery: %v\n", err)
+			return err
+		}
+
+		return nil
+	})
+
+	return nil
+}
+
+// Ping always succeeds: the queue lives in the database file this process holds open
+func (r *WebhookQueueRepository) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close is a no-op because the database is owned by the caller
+func (r *WebhookQueueRepository) Close() error {
+	return nil
+}
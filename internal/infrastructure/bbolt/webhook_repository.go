@@ -0,0 +1,170 @@
+package bbolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	bolt "go.etcd.io/bbolt"
+)
+
+// deliveryLogSize is the number of delivery attempts kept per survey
+const deliveryLogSize = 1000
+
+// WebhookRepository implements the repository.WebhookRepository interface using bbolt
+type WebhookRepository struct {
+	db *bolt.DB
+}
+
+// NewWebhookRepository creates a new bbolt webhook repository
+func NewWebhookRepository(db *bolt.DB) repository.WebhookRepository {
+	return &WebhookRepository{
+		db: db,
+	}
+}
+
+// SaveWebhook stores a webhook in the survey's webhook bucket
+func (r *WebhookRepository) SaveWebhook(ctx context.Context, webhook entity.Webhook) error {
+	data, err := json.Marshal(webhook)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook: %w", err)
+	}
+
+	return r.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket(webhooksBucket).CreateBucketIfNotExists([]byte(entity.SurveyKey(ctx, webhook.SurveyID)))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(webhook.ID), data)
+	})
+}
+
+// GetWebhook returns the webhook with the given ID registered for the given survey
+func (r *WebhookRepository) GetWebhook(ctx context.Context, surveyID, webhookID string) (*entity.Webhook, error) {
+	var webhook *entity.Webhook
+	err := r.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(webhooksBucket).Bucket([]byte(entity.SurveyKey(ctx, surveyID)))
+		if b == nil {
+			return nil
+		}
+		value := b.Get([]byte(webhookID))
+		if value == nil {
+			return nil
+		}
+		webhook = &entity.Webhook{}
+		if err := json.Unmarshal(value, webhook); err != nil {
+			return fmt.Errorf("failed to unmarshal webhook: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return nil, repository.ErrNotFound
+	}
+
+	return webhook, nil
+}
+
+// ListWebhooks returns the webhooks registered for the given survey ordered by creation time
+func (r *WebhookRepository) ListWebhooks(ctx context.Context, surveyID string) ([]entity.Webhook, error) {
+	var webhooks []entity.Webhook
+	err := r.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(webhooksBucket).Bucket([]byte(entity.SurveyKey(ctx, surveyID)))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, value []byte) error {
+			var webhook entity.Webhook
+			if err := json.Unmarshal(value, &webhook); err != nil {
+				return fmt.Errorf("failed to unmarshal webhook: %w", err)
+			}
+			webhooks = append(webhooks, webhook)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].CreatedAt < webhooks[j].CreatedAt })
+	return webhooks, nil
+}
+
+// DeleteWebhook removes the webhook from the survey's webhook bucket
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, surveyID, webhookID string) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(webhooksBucket).Bucket([]byte(entity.SurveyKey(ctx, surveyID)))
+		if b == nil || b.Get([]byte(webhookID)) == nil {
+			return repository.ErrNotFound
+		}
+		return b.Delete([]byte(webhookID))
+	})
+}
+
+// SaveDeliveryAttempt appends an attempt to the survey's delivery log bucket,
+// dropping the oldest attempts beyond deliveryLogSize
+func (r *WebhookRepository) SaveDeliveryAttempt(ctx context.Context, attempt entity.WebhookDeliveryAttempt) error {
+	data, err := json.Marshal(attempt)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delivery attempt: %w", err)
+	}
+
+	return r.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket(deliveryLogBucket).CreateBucketIfNotExists([]byte(entity.SurveyKey(ctx, attempt.SurveyID)))
+		if err != nil {
+			return err
+		}
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		if err := b.Put(itob(seq), data); err != nil {
+			return err
+		}
+
+		if seq <= deliveryLogSize {
+			return nil
+		}
+
+		// Keys are sequence numbers, so every key below the cutoff is older
+		// than the most recent deliveryLogSize attempts
+		cutoff := itob(seq - deliveryLogSize + 1)
+		c := b.Cursor()
+		for k, _ := c.First(); k != nil && bytes.Compare(k, cutoff) < 0; k, _ = c.First() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListDeliveryAttempts returns the survey's delivery log, oldest first
+func (r *WebhookRepository) ListDeliveryAttempts(ctx context.Context, surveyID string) ([]entity.WebhookDeliveryAttempt, error) {
+	var attempts []entity.WebhookDeliveryAttempt
+	err := r.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(deliveryLogBucket).Bucket([]byte(entity.SurveyKey(ctx, surveyID)))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, value []byte) error {
+			var attempt entity.WebhookDeliveryAttempt
+			if err := json.Unmarshal(value, &attempt); err != nil {
+				return fmt.Errorf("failed to unmarshal delivery attempt: %w", err)
+			}
+			attempts = append(attempts, attempt)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return attempts, nil
+}
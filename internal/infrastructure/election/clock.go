@@ -0,0 +1,30 @@
+package election
+
+import "time"
+
+// Clock defines the interface the elector reads time and waits through
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// After returns a channel that receives the time once d has passed
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock implements Clock with the time package
+type systemClock struct{}
+
+// SystemClock returns the clock backed by the system time
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+// Now returns the current system time
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After waits for d on the system clock
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
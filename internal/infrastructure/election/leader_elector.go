@@ -0,0 +1,203 @@
+package election
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// Settings holds the timing of a leader elector
+type Settings struct {
+	// LeaseTTL is how long leadership outlives a leader that stopped renewing it,
+	// and so the longest a crashed leader's task goes unrun
+	LeaseTTL time.Duration
+
+	// RenewInterval is how often the leader renews its lease; it must be well
+	// below LeaseTTL so a few failed renewals do not cost leadership
+	RenewInterval time.Duration
+
+	// RetryInterval is how often a candidate tries to take the lease
+	RetryInterval time.Duration
+}
+
+// DefaultSettings returns the settings used when none are configured
+func DefaultSettings() Settings {
+	return Settings{
+		LeaseTTL:      15 * time.Second,
+		RenewInterval: 5 * time.Second,
+		RetryInterval: 5 * time.Second,
+	}
+}
+
+// leaderElector implements the repository.LeaderElector interface on a lease
+// The instance holding the lease leads; it renews the lease while it leads
+// and steps down before a lease it failed to renew could pass to another instance
+type leaderElector struct {
+	leaseRepo  repository.LeaseRepository
+	key        string
+	holder     string
+	settings   Settings
+	clock      Clock
+	leadership chan bool
+
+	mu     sync.Mutex
+	leader bool
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewLeaderElector creates a leader elector campaigning for the lease key as holder
+// holder must be unique across instances; clock may be nil to use the system clock
+func NewLeaderElector(leaseRepo repository.LeaseRepository, key, holder string, settings Settings, clock Clock) repository.LeaderElector {
+	if clock == nil {
+		clock = SystemClock()
+	}
+
+	return &leaderElector{
+		leaseRepo:  leaseRepo,
+		key:        key,
+		holder:     holder,
+		settings:   settings,
+		clock:      clock,
+		leadership: make(chan bool, 1),
+	}
+}
+
+// Campaign tries to take the lease every RetryInterval until it succeeds or ctx is done
+func (e *leaderElector) Campaign(ctx context.Context) error {
+	for {
+		// The lease runs from no earlier than the request, so measure its expiry from here
+		start := e.clock.Now()
+		acquired, err := e.leaseRepo.AcquireLease(ctx, e.key, e.holder, e.settings.LeaseTTL)
+		if err != nil && ctx.Err() == nil {
+			fmt.Printf("Error campaigning for %s: %v\n", e.key, err)
+		}
+		if err == nil && acquired {
+			e.elected(start)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-e.clock.After(e.settings.RetryInterval):
+		}
+	}
+}
+
+// Resign stops renewing the lease and releases it so a candidate can take it
+// on its next try instead of waiting for it to expire
+func (e *leaderElector) Resign(ctx context.Context) error {
+	e.mu.Lock()
+	if !e.leader {
+		e.mu.Unlock()
+		return nil
+	}
+	stop, done := e.stop, e.done
+	e.setLeader(false)
+	e.mu.Unlock()
+
+	// Wait for an in-flight renewal so it cannot take the lease back after the release
+	close(stop)
+	<-done
+
+	if err := e.leaseRepo.ReleaseLease(ctx, e.key, e.holder); err != nil {
+		return fmt.Errorf("failed to release lease %s: %w", e.key, err)
+	}
+
+	fmt.Printf("%s resigned as leader of %s\n", e.holder, e.key)
+	return nil
+}
+
+// IsLeader reports whether this instance currently leads
+func (e *leaderElector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leader
+}
+
+// Leadership returns the channel leadership changes are announced on
+func (e *leaderElector) Leadership() <-chan bool {
+	return e.leadership
+}
+
+// elected starts renewing a lease taken at renewedAt
+func (e *leaderElector) elected(renewedAt time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// Campaigning while already leading only extended the running lease
+	if e.leader {
+		return
+	}
+
+	e.stop = make(chan struct{})
+	e.done = make(chan struct{})
+	e.setLeader(true)
+	go e.renew(e.stop, e.done, renewedAt)
+
+	fmt.Printf("%s is now the leader of %s\n", e.holder, e.key)
+}
+
+// renew extends the lease every RenewInterval until stop is closed or leadership is lost
+// A renewal that fails with an error is retried, but the leader steps down once
+// the next attempt would come after the lease expired, since another instance
+// may take the lease from then on
+func (e *leaderElector) renew(stop, done chan struct{}, renewedAt time.Time) {
+	defer close(done)
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-e.clock.After(e.settings.RenewInterval):
+		}
+
+		start := e.clock.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), e.settings.RenewInterval)
+		acquired, err := e.leaseRepo.AcquireLease(ctx, e.key, e.holder, e.settings.LeaseTTL)
+		cancel()
+
+		switch {
+		case err == nil && acquired:
+			renewedAt = start
+		case err == nil:
+			e.stepDown(stop, "its lease was taken over")
+			return
+		default:
+			fmt.Printf("Error renewing lease %s: %v\n", e.key, err)
+			if !e.clock.Now().Add(e.settings.RenewInterval).Before(renewedAt.Add(e.settings.LeaseTTL)) {
+				e.stepDown(stop, "its lease could not be renewed")
+				return
+			}
+		}
+	}
+}
+
+// stepDown gives up leadership of the term stop belongs to, unless it already ended
+func (e *leaderElector) stepDown(stop chan struct{}, reason string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.leader || e.stop != stop {
+		return
+	}
+	e.setLeader(false)
+
+	fmt.Printf("%s is no longer the leader of %s: %s\n", e.holder, e.key, reason)
+}
+
+// setLeader records a leadership change and announces it, replacing a change
+// the reader has not picked up yet. The caller must hold e.mu
+func (e *leaderElector) setLeader(leader bool) {
+	e.leader = leader
+
+	select {
+	case <-e.leadership:
+	default:
+	}
+	e.leadership <- leader
+}
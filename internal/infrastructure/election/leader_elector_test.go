@@ -0,0 +1,266 @@
+package election_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	"github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/election"
+	memoryRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/memory"
+)
+
+// fakeClock is a Clock that only moves when the test advances it
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(1735689600, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward and wakes every waiter that is due
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+// waitForWaiters blocks until n goroutines are waiting on the clock, so every
+// goroutine has finished reacting to the previous Advance
+func (c *fakeClock) waitForWaiters(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		c.mu.Lock()
+		waiting := len(c.waiters)
+		c.mu.Unlock()
+		if waiting == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d goroutines waiting on the clock, got %d", n, waiting)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// partitionedLeases fails every call once it is cut off, like an instance that lost its connection
+type partitionedLeases struct {
+	repository.LeaseRepository
+	cut atomic.Bool
+}
+
+func (p *partitionedLeases) AcquireLease(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	if p.cut.Load() {
+		return false, errors.New("connection lost")
+	}
+	return p.LeaseRepository.AcquireLease(ctx, key, holder, ttl)
+}
+
+var testSettings = election.Settings{
+	LeaseTTL:      15 * time.Second,
+	RenewInterval: 5 * time.Second,
+	RetryInterval: time.Second,
+}
+
+// expectLeadership waits for the elector to announce the given leadership state
+func expectLeadership(t *testing.T, elector repository.LeaderElector, leader bool) {
+	t.Helper()
+	select {
+	case got := <-elector.Leadership():
+		if got != leader {
+			t.Fatalf("Expected leadership %v, got %v", leader, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected leadership %v to be announced", leader)
+	}
+	if elector.IsLeader() != leader {
+		t.Fatalf("Expected IsLeader to be %v", leader)
+	}
+}
+
+func expectNoLeadershipChange(t *testing.T, elector repository.LeaderElector) {
+	t.Helper()
+	select {
+	case got := <-elector.Leadership():
+		t.Fatalf("Expected no leadership change, got %v", got)
+	default:
+	}
+}
+
+// campaign runs Campaign in the background and returns its result channel
+func campaign(t *testing.T, elector repository.LeaderElector) <-chan error {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	result := make(chan error, 1)
+	go func() { result <- elector.Campaign(ctx) }()
+	return result
+}
+
+func TestLeaderElector_FailsOverAfterLeaseExpires(t *testing.T) {
+	clock := newFakeClock()
+	leases := memoryRepo.NewLeaseRepository(clock.Now)
+	partitioned := &partitionedLeases{LeaseRepository: leases}
+
+	leader := election.NewLeaderElector(partitioned, "leader", "a", testSettings, clock)
+	candidate := election.NewLeaderElector(leases, "leader", "b", testSettings, clock)
+
+	if err := leader.Campaign(context.Background()); err != nil {
+		t.Fatalf("Expected a to be elected, got %v", err)
+	}
+	expectLeadership(t, leader, true)
+	campaign(t, candidate)
+
+	// a loses its connection right after taking the lease at t=0
+	partitioned.cut.Store(true)
+
+	// a's renewals at t=5 and t=10 fail; the next one at t=15 would come after
+	// its lease expired, so it steps down at t=10 while b is still waiting
+	for second := 1; second <= 10; second++ {
+		clock.waitForWaiters(t, 2)
+		clock.Advance(time.Second)
+	}
+	expectLeadership(t, leader, false)
+	expectNoLeadershipChange(t, candidate)
+
+	for second := 11; second <= 14; second++ {
+		clock.waitForWaiters(t, 1)
+		clock.Advance(time.Second)
+		if candidate.IsLeader() {
+			t.Fatalf("Expected b not to lead before a's lease expires, but it did at t=%d", second)
+		}
+	}
+
+	// The lease taken at t=0 expires at t=15, when b's next try takes it
+	clock.waitForWaiters(t, 1)
+	clock.Advance(time.Second)
+	expectLeadership(t, candidate, true)
+}
+
+func TestLeaderElector_RenewalKeepsLeadership(t *testing.T) {
+	clock := newFakeClock()
+	leases := memoryRepo.NewLeaseRepository(clock.Now)
+
+	leader := election.NewLeaderElector(leases, "leader", "a", testSettings, clock)
+	candidate := election.NewLeaderElector(leases, "leader", "b", testSettings, clock)
+
+	if err := leader.Campaign(context.Background()); err != nil {
+		t.Fatalf("Expected a to be elected, got %v", err)
+	}
+	expectLeadership(t, leader, true)
+	campaign(t, candidate)
+
+	// Well past the lease TTL, a keeps renewing and b keeps waiting
+	for second := 1; second <= 60; second++ {
+		clock.waitForWaiters(t, 2)
+		clock.Advance(time.Second)
+	}
+	clock.waitForWaiters(t, 2)
+
+	if !leader.IsLeader() || candidate.IsLeader() {
+		t.Fatalf("Expected a to keep leading, got a=%v b=%v", leader.IsLeader(), candidate.IsLeader())
+	}
+	expectNoLeadershipChange(t, leader)
+	expectNoLeadershipChange(t, candidate)
+}
+
+func TestLeaderElector_ResignHandsOverOnNextTry(t *testing.T) {
+	clock := newFakeClock()
+	leases := memoryRepo.NewLeaseRepository(clock.Now)
+
+	leader := election.NewLeaderElector(leases, "leader", "a", testSettings, clock)
+	candidate := election.NewLeaderElector(leases, "leader", "b", testSettings, clock)
+
+	if err := leader.Campaign(context.Background()); err != nil {
+		t.Fatalf("Expected a to be elected, got %v", err)
+	}
+	expectLeadership(t, leader, true)
+	result := campaign(t, candidate)
+
+	clock.waitForWaiters(t, 2)
+	if err := leader.Resign(context.Background()); err != nil {
+		t.Fatalf("Expected a to resign, got %v", err)
+	}
+	expectLeadership(t, leader, false)
+
+	// b takes the released lease on its next try instead of waiting for the TTL
+	clock.Advance(testSettings.RetryInterval)
+	expectLeadership(t, candidate, true)
+	if err := <-result; err != nil {
+		t.Errorf("Expected b's campaign to succeed, got %v", err)
+	}
+
+	// a can campaign again, but only takes over once b gives up the lease
+	result = campaign(t, leader)
+	// The renewal timer a abandoned when it resigned still counts as a waiter
+	clock.waitForWaiters(t, 3)
+	if err := candidate.Resign(context.Background()); err != nil {
+		t.Fatalf("Expected b to resign, got %v", err)
+	}
+	clock.Advance(testSettings.RetryInterval)
+	expectLeadership(t, leader, true)
+	if err := <-result; err != nil {
+		t.Errorf("Expected a's campaign to succeed, got %v", err)
+	}
+}
+
+func TestLeaderElector_CampaignStopsWithContext(t *testing.T) {
+	clock := newFakeClock()
+	leases := memoryRepo.NewLeaseRepository(clock.Now)
+	leases.AcquireLease(context.Background(), "leader", "other", time.Hour)
+
+	elector := election.NewLeaderElector(leases, "leader", "a", testSettings, clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() { result <- elector.Campaign(ctx) }()
+
+	clock.waitForWaiters(t, 1)
+	cancel()
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the campaign to stop")
+	}
+	if elector.IsLeader() {
+		t.Error("Expected the elector not to lead")
+	}
+}
@@ -0,0 +1,108 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// BlobRepository implements the repository.BlobRepository interface on a local directory
+// Instances sharing blobs must share the directory, e.g. through a mounted volume
+type BlobRepository struct {
+	root string
+}
+
+// NewBlobRepository creates a new filesystem blob repository rooted at the given directory
+func NewBlobRepository(root string) (repository.BlobRepository, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	return &BlobRepository{
+		root: root,
+	}, nil
+}
+
+// Put writes the blob to a temporary file and renames it into place
+func (r *BlobRepository) Put(ctx context.Context, key string, src io.Reader) error {
+	path, err := r.path(key)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, src); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store blob: %w", err)
+	}
+
+	return nil
+}
+
+// Get opens the blob stored under key
+func (r *BlobRepository) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := r.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+
+	return f, nil
+}
+
+// Delete removes the blob stored under key
+func (r *BlobRepository) Delete(ctx context.Context, key string) error {
+	path, err := r.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+
+	return nil
+}
+
+// path maps a key to a file below the root, rejecting keys that would escape it
+func (r *BlobRepository) path(key string) (string, error) {
+	local := filepath.FromSlash(key)
+	if !filepath.IsLocal(local) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+
+	return filepath.Join(r.root, local), nil
+}
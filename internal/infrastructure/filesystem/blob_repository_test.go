@@ -0,0 +1,65 @@
+package filesystem_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	"github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/filesystem"
+)
+
+func TestBlobRepository_PutGetDelete(t *testing.T) {
+	ctx := context.Background()
+	repo, err := filesystem.NewBlobRepository(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	if _, err := repo.Get(ctx, "reports/survey-123/report.html"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound before Put, got %v", err)
+	}
+
+	// A second Put replaces the first
+	for _, content := range []string{"first", "second"} {
+		if err := repo.Put(ctx, "reports/survey-123/report.html", strings.NewReader(content)); err != nil {
+			t.Fatalf("Failed to put blob: %v", err)
+		}
+	}
+
+	blob, err := repo.Get(ctx, "reports/survey-123/report.html")
+	if err != nil {
+		t.Fatalf("Failed to get blob: %v", err)
+	}
+	data, _ := io.ReadAll(blob)
+	blob.Close()
+	if string(data) != "second" {
+		t.Errorf("Expected the latest content, got %q", data)
+	}
+
+	if err := repo.Delete(ctx, "reports/survey-123/report.html"); err != nil {
+		t.Fatalf("Failed to delete blob: %v", err)
+	}
+	if err := repo.Delete(ctx, "reports/survey-123/report.html"); err != nil {
+		t.Errorf("Expected deleting a missing blob to succeed, got %v", err)
+	}
+	if _, err := repo.Get(ctx, "reports/survey-123/report.html"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after Delete, got %v", err)
+	}
+}
+
+func TestBlobRepository_RejectsKeysOutsideRoot(t *testing.T) {
+	ctx := context.Background()
+	repo, err := filesystem.NewBlobRepository(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	for _, key := range []string{"../escape", "reports/../../escape", "/etc/passwd", ""} {
+		if err := repo.Put(ctx, key, strings.NewReader("x")); err == nil {
+			t.Errorf("Expected key %q to be rejected", key)
+		}
+	}
+}
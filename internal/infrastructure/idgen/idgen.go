@@ -0,0 +1,33 @@
+package idgen
+
+import (
+	"fmt"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+const (
+	// KindULID selects time-sortable ULIDs
+	KindULID = "ulid"
+
+	// KindUUIDv7 selects time-sortable version 7 UUIDs
+	KindUUIDv7 = "uuidv7"
+
+	// KindSnowflake selects Snowflake-style IDs that embed the instance ID
+	KindSnowflake = "snowflake"
+)
+
+// New creates an ID generator of the given kind
+// The instance ID is only used by Snowflake generators
+func New(kind string, instanceID int64) (usecase.IDGenerator, error) {
+	switch kind {
+	case KindULID, "":
+		return NewULIDGenerator(), nil
+	case KindUUIDv7:
+		return NewUUIDv7Generator(), nil
+	case KindSnowflake:
+		return NewSnowflakeGenerator(instanceID)
+	default:
+		return nil, fmt.Errorf("unknown ID generator %q, expected %q, %q or %q", kind, KindULID, KindUUIDv7, KindSnowflake)
+	}
+}
@@ -0,0 +1,110 @@
+package idgen_test
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/idgen"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// generate creates n IDs sequentially
+func generate(g usecase.IDGenerator, n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = g.NewID()
+	}
+	return ids
+}
+
+func TestGenerators_SequentialIDsAreUniqueAndSorted(t *testing.T) {
+	for _, kind := range []string{idgen.KindULID, idgen.KindUUIDv7} {
+		t.Run(kind, func(t *testing.T) {
+			g, err := idgen.New(kind, 0)
+			if err != nil {
+				t.Fatalf("Failed to create generator: %v", err)
+			}
+
+			ids := generate(g, 10000)
+			if !sort.StringsAreSorted(ids) {
+				t.Errorf("Expected IDs to sort in creation order")
+			}
+			assertUnique(t, ids)
+		})
+	}
+}
+
+func TestGenerators_ConcurrentIDsAreUnique(t *testing.T) {
+	for _, kind := range []string{idgen.KindULID, idgen.KindUUIDv7, idgen.KindSnowflake} {
+		t.Run(kind, func(t *testing.T) {
+			g, err := idgen.New(kind, 1)
+			if err != nil {
+				t.Fatalf("Failed to create generator: %v", err)
+			}
+
+			var mu sync.Mutex
+			var ids []string
+			var wg sync.WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					batch := generate(g, 2000)
+					mu.Lock()
+					ids = append(ids, batch...)
+					mu.Unlock()
+				}()
+			}
+			wg.Wait()
+
+			assertUnique(t, ids)
+		})
+	}
+}
+
+func TestGenerators_Format(t *testing.T) {
+	ulid, _ := idgen.New(idgen.KindULID, 0)
+	if id := ulid.NewID(); !regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`).MatchString(id) {
+		t.Errorf("Expected a 26-character Crockford base32 ULID, got %q", id)
+	}
+
+	uuid, _ := idgen.New(idgen.KindUUIDv7, 0)
+	if id := uuid.NewID(); !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(id) {
+		t.Errorf("Expected a version 7 UUID, got %q", id)
+	}
+}
+
+func TestSnowflake_DifferentInstancesNeverCollide(t *testing.T) {
+	first, _ := idgen.NewSnowflakeGenerator(1)
+	second, _ := idgen.NewSnowflakeGenerator(2)
+
+	ids := append(generate(first, 5000), generate(second, 5000)...)
+	assertUnique(t, ids)
+
+	id, err := strconv.ParseInt(generate(second, 1)[0], 10, 64)
+	if err != nil {
+		t.Fatalf("Expected a decimal ID, got %v", err)
+	}
+	if instance := (id >> 12) & idgen.MaxInstanceID; instance != 2 {
+		t.Errorf("Expected instance ID 2 to be embedded, got %d", instance)
+	}
+
+	if _, err := idgen.NewSnowflakeGenerator(idgen.MaxInstanceID + 1); err == nil {
+		t.Errorf("Expected an error for an out-of-range instance ID")
+	}
+}
+
+// assertUnique fails the test if any ID appears twice
+func assertUnique(t *testing.T, ids []string) {
+	t.Helper()
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			t.Fatalf("Duplicate ID %q", id)
+		}
+		seen[id] = true
+	}
+}
@@ -0,0 +1,73 @@
+package idgen
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+const (
+	// snowflakeEpoch is the custom epoch (2024-01-01T00:00:00Z) in Unix milliseconds
+	snowflakeEpoch = 1704067200000
+
+	// instanceBits is the number of bits reserved for the instance ID
+	instanceBits = 10
+
+	// sequenceBits is the number of bits reserved for the per-millisecond sequence
+	sequenceBits = 12
+
+	// MaxInstanceID is the largest instance ID a Snowflake generator accepts
+	MaxInstanceID = 1<<instanceBits - 1
+
+	maxSequence = 1<<sequenceBits - 1
+)
+
+// SnowflakeGenerator implements the usecase.IDGenerator interface with
+// Snowflake-style IDs: 41 bits of milliseconds, 10 bits of instance ID and
+// 12 bits of sequence. IDs are unique across instances as long as every
+// instance is configured with a different instance ID
+type SnowflakeGenerator struct {
+	instanceID int64
+
+	mu       sync.Mutex
+	lastMS   int64
+	sequence int64
+}
+
+// NewSnowflakeGenerator creates a new Snowflake generator for the given instance ID
+func NewSnowflakeGenerator(instanceID int64) (usecase.IDGenerator, error) {
+	if instanceID < 0 || instanceID > MaxInstanceID {
+		return nil, fmt.Errorf("instance ID must be between 0 and %d, got %d", MaxInstanceID, instanceID)
+	}
+
+	return &SnowflakeGenerator{
+		instanceID: instanceID,
+	}, nil
+}
+
+// NewID returns a new Snowflake ID as a decimal string
+func (g *SnowflakeGenerator) NewID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := time.Now().UnixMilli() - snowflakeEpoch
+	if ms <= g.lastMS {
+		// Same millisecond (or the clock went back): continue the sequence
+		ms = g.lastMS
+		g.sequence++
+		if g.sequence > maxSequence {
+			// The sequence is exhausted, so borrow the next millisecond
+			ms++
+			g.sequence = 0
+		}
+	} else {
+		g.sequence = 0
+	}
+	g.lastMS = ms
+
+	id := ms<<(instanceBits+sequenceBits) | g.instanceID<<sequenceBits | g.sequence
+	return strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,93 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// crockford is the Crockford base32 alphabet used by ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULIDGenerator implements the usecase.IDGenerator interface with ULIDs
+// IDs sort by creation time, and IDs created within the same millisecond
+// increment the random part so they still sort in creation order
+type ULIDGenerator struct {
+	mu      sync.Mutex
+	lastMS  uint64
+	entropy [10]byte
+}
+
+// NewULIDGenerator creates a new ULID generator
+func NewULIDGenerator() usecase.IDGenerator {
+	return &ULIDGenerator{}
+}
+
+// NewID returns a new 26-character ULID
+func (g *ULIDGenerator) NewID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(time.Now().UnixMilli())
+	if ms <= g.lastMS {
+		// Same millisecond (or the clock went back): increment the random part
+		ms = g.lastMS
+		if !incrementBytes(g.entropy[:]) {
+			// The 80-bit random part overflowed, so move on to the next millisecond
+			ms++
+			readRandom(g.entropy[:])
+		}
+	} else {
+		readRandom(g.entropy[:])
+	}
+	g.lastMS = ms
+
+	var id [16]byte
+	for i := 5; i >= 0; i-- {
+		id[i] = byte(ms)
+		ms >>= 8
+	}
+	copy(id[6:], g.entropy[:])
+
+	return encodeCrockford(id)
+}
+
+// readRandom fills b with cryptographically secure random bytes
+func readRandom(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("idgen: failed to read random bytes: %v", err))
+	}
+}
+
+// incrementBytes adds one to a big-endian number and reports whether it did not overflow
+func incrementBytes(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeCrockford encodes 128 bits as 26 Crockford base32 characters
+func encodeCrockford(id [16]byte) string {
+	out := make([]byte, 26)
+	// 26 characters hold 130 bits, so the first character only carries the top 3 bits
+	var acc uint64
+	bits := 2
+	pos := 0
+	for _, b := range id {
+		acc = acc<<8 | uint64(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out[pos] = crockford[(acc>>uint(bits))&0x1f]
+			pos++
+		}
+	}
+	return string(out)
+}
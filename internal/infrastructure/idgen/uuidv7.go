@@ -0,0 +1,66 @@
+package idgen
+
+import (
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// UUIDv7Generator implements the usecase.IDGenerator interface with RFC 9562 version 7 UUIDs
+// The 12-bit rand_a field is used as a counter within each millisecond so IDs
+// created by one generator sort in creation order
+type UUIDv7Generator struct {
+	mu      sync.Mutex
+	lastMS  uint64
+	counter uint16
+}
+
+// NewUUIDv7Generator creates a new UUIDv7 generator
+func NewUUIDv7Generator() usecase.IDGenerator {
+	return &UUIDv7Generator{}
+}
+
+// NewID returns a new UUIDv7 in its canonical 36-character form
+func (g *UUIDv7Generator) NewID() string {
+	g.mu.Lock()
+	ms := uint64(time.Now().UnixMilli())
+	if ms <= g.lastMS {
+		ms = g.lastMS
+		g.counter++
+		if g.counter > 0x0fff {
+			// The counter overflowed, so move on to the next millisecond
+			ms++
+			g.counter = 0
+		}
+	} else {
+		g.counter = 0
+	}
+	g.lastMS = ms
+	counter := g.counter
+	g.mu.Unlock()
+
+	var id [16]byte
+	for i := 5; i >= 0; i-- {
+		id[i] = byte(ms)
+		ms >>= 8
+	}
+	id[6] = 0x70 | byte(counter>>8)
+	id[7] = byte(counter)
+	readRandom(id[8:])
+	id[8] = 0x80 | id[8]&0x3f
+
+	var out [36]byte
+	hex.Encode(out[0:8], id[0:4])
+	out[8] = '-'
+	hex.Encode(out[9:13], id[4:6])
+	out[13] = '-'
+	hex.Encode(out[14:18], id[6:8])
+	out[18] = '-'
+	hex.Encode(out[19:23], id[8:10])
+	out[23] = '-'
+	hex.Encode(out[24:], id[10:])
+
+	return string(out[:])
+}
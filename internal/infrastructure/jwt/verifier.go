@@ -0,0 +1,179 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// Settings holds the keys tokens are verified with and the claims they must carry
+type Settings struct {
+	// JWKSFile is the path of a JWKS file; its keys are selected by the token's kid
+	JWKSFile string
+
+	// KeyFiles are paths of PEM public keys, tried for tokens without a kid
+	KeyFiles []string
+
+	// Secret is a shared secret for HS256, HS384 and HS512 tokens
+	Secret string
+
+	// Issuer is the required iss claim; empty accepts any issuer
+	Issuer string
+
+	// Audience is a required aud claim; empty accepts any audience
+	Audience string
+}
+
+// Verifier implements the usecase.TokenVerifier interface with a fixed set of keys
+type Verifier struct {
+	keys   []key
+	parser *gojwt.Parser
+}
+
+// NewVerifier loads the configured keys and creates a token verifier
+// Only the algorithms of the loaded key types are accepted, so a token can
+// never pick a weaker algorithm than the key it is verified with
+func NewVerifier(settings Settings) (usecase.TokenVerifier, error) {
+	var keys []key
+	if settings.JWKSFile != "" {
+		jwks, err := loadJWKS(settings.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, jwks...)
+	}
+	for _, path := range settings.KeyFiles {
+		k, err := loadPEM(path)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	if settings.Secret != "" {
+		keys = append(keys, key{value: []byte(settings.Secret)})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no JWT verification keys are configured")
+	}
+
+	var methods []string
+	for _, k := range keys {
+		for _, method := range methodsFor(k.value) {
+			if !slices.Contains(methods, method) {
+				methods = append(methods, method)
+			}
+		}
+	}
+
+	options := []gojwt.ParserOption{
+		gojwt.WithValidMethods(methods),
+		gojwt.WithExpirationRequired(),
+	}
+	if settings.Issuer != "" {
+		options = append(options, gojwt.WithIssuer(settings.Issuer))
+	}
+	if settings.Audience != "" {
+		options = append(options, gojwt.WithAudience(settings.Audience))
+	}
+
+	return &Verifier{
+		keys:   keys,
+		parser: gojwt.NewParser(options...),
+	}, nil
+}
+
+// methodsFor returns the signing algorithms a key of value's type verifies
+func methodsFor(value interface{}) []string {
+	switch value.(type) {
+	case *rsa.PublicKey:
+		return []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+	case *ecdsa.PublicKey:
+		return []string{"ES256", "ES384", "ES512"}
+	case ed25519.PublicKey:
+		return []string{"EdDSA"}
+	case []byte:
+		return []string{"HS256", "HS384", "HS512"}
+	default:
+		return nil
+	}
+}
+
+// claims are the registered claims plus the granted scopes and tenant
+type claims struct {
+	gojwt.RegisteredClaims
+
+	// Scope is a space-separated string or a list of scopes
+	Scope interface{} `json:"scope"`
+
+	// Tenant binds the caller to one tenant; callers without one may act for any
+	Tenant string `json:"tenant"`
+}
+
+// VerifyToken checks the token's signature, expiry, issuer and audience and
+// returns the caller named by its sub claim with the scopes of its scope claim,
+// bound to the tenant of its tenant claim
+func (v *Verifier) VerifyToken(ctx context.Context, token string) (*entity.Identity, error) {
+	var c claims
+	if _, err := v.parser.ParseWithClaims(token, &c, v.keyFor); err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	if c.Subject == "" {
+		return nil, errors.New("invalid token: sub claim is required")
+	}
+	if c.Tenant != "" && !entity.ValidTenantID(c.Tenant) {
+		return nil, fmt.Errorf("invalid token: invalid tenant claim %q", c.Tenant)
+	}
+
+	return &entity.Identity{
+		Method:   entity.AuthMethodJWT,
+		Subject:  c.Subject,
+		Scopes:   scopesOf(c.Scope),
+		TenantID: c.Tenant,
+	}, nil
+}
+
+// keyFor returns the key named by the token's kid, or every key without a kid
+func (v *Verifier) keyFor(token *gojwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	var set gojwt.VerificationKeySet
+	for _, k := range v.keys {
+		if kid != "" && k.id == kid {
+			return k.value, nil
+		}
+		if k.id == "" {
+			set.Keys = append(set.Keys, k.value)
+		}
+	}
+	if len(set.Keys) == 0 {
+		return nil, fmt.Errorf("unknown key ID %q", kid)
+	}
+	return set, nil
+}
+
+// scopesOf reads a scope claim given as a space-separated string or a list
+func scopesOf(claim interface{}) []string {
+	switch scope := claim.(type) {
+	case string:
+		return strings.Fields(scope)
+	case []interface{}:
+		scopes := make([]string, 0, len(scope))
+		for _, s := range scope {
+			if s, ok := s.(string); ok {
+				scopes = append(scopes, s)
+			}
+		}
+		return scopes
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,157 @@
+package jwt_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/jwt"
+)
+
+// writeJWKS writes a JWKS file holding the RSA key under kid
+func writeJWKS(t *testing.T, kid string, public *rsa.PublicKey) string {
+	t.Helper()
+	encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	data, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"n":   encode(public.N.Bytes()),
+			"e":   encode(big.NewInt(int64(public.E)).Bytes()),
+		}},
+	})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("Failed to write JWKS file: %v", err)
+	}
+	return path
+}
+
+// sign signs claims with key using method, naming kid in the header if set
+func sign(t *testing.T, method gojwt.SigningMethod, key interface{}, kid string, claims gojwt.MapClaims) string {
+	t.Helper()
+	token := gojwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func TestVerifier_VerifiesJWKSTokens(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	verifier, err := jwt.NewVerifier(jwt.Settings{
+		JWKSFile: writeJWKS(t, "key-1", &private.PublicKey),
+		Issuer:   "https://issuer.example",
+		Audience: "survey-api",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %v", err)
+	}
+	ctx := context.Background()
+	valid := gojwt.MapClaims{
+		"sub":    "dashboard",
+		"iss":    "https://issuer.example",
+		"aud":    "survey-api",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"scope":  "read-report submit",
+		"tenant": "acme",
+	}
+
+	identity, err := verifier.VerifyToken(ctx, sign(t, gojwt.SigningMethodRS256, private, "key-1", valid))
+	if err != nil {
+		t.Fatalf("Expected the token to verify, got %v", err)
+	}
+	if identity.Method != entity.AuthMethodJWT || identity.Subject != "dashboard" || !identity.HasScope(entity.ScopeReadReport) || identity.HasScope(entity.ScopeAdmin) {
+		t.Errorf("Expected dashboard with read-report and submit, got %+v", identity)
+	}
+	if identity.TenantID != "acme" {
+		t.Errorf("Expected the caller to be bound to acme, got %q", identity.TenantID)
+	}
+
+	rejected := map[string]string{
+		"unknown kid":  sign(t, gojwt.SigningMethodRS256, private, "key-2", valid),
+		"expired":      sign(t, gojwt.SigningMethodRS256, private, "key-1", with(valid, "exp", time.Now().Add(-time.Minute).Unix())),
+		"no expiry":    sign(t, gojwt.SigningMethodRS256, private, "key-1", with(valid, "exp", nil)),
+		"wrong issuer": sign(t, gojwt.SigningMethodRS256, private, "key-1", with(valid, "iss", "https://other.example")),
+		"no subject":   sign(t, gojwt.SigningMethodRS256, private, "key-1", with(valid, "sub", nil)),
+		"bad tenant":   sign(t, gojwt.SigningMethodRS256, private, "key-1", with(valid, "tenant", "acme:eu")),
+		// The RSA public key is public, so it must never be accepted as an HMAC secret
+		"hmac with the public key": sign(t, gojwt.SigningMethodHS256, x509.MarshalPKCS1PublicKey(&private.PublicKey), "key-1", valid),
+	}
+	for name, token := range rejected {
+		if _, err := verifier.VerifyToken(ctx, token); err == nil {
+			t.Errorf("Expected the token with %s to be rejected", name)
+		}
+	}
+}
+
+func TestVerifier_VerifiesStaticKeys(t *testing.T) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	der, _ := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+
+	verifier, err := jwt.NewVerifier(jwt.Settings{KeyFiles: []string{path}, Secret: "shared-secret"})
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %v", err)
+	}
+	claims := gojwt.MapClaims{
+		"sub":   "ops",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": []string{"admin"},
+	}
+
+	for name, token := range map[string]string{
+		"ES256": sign(t, gojwt.SigningMethodES256, private, "", claims),
+		"HS256": sign(t, gojwt.SigningMethodHS256, []byte("shared-secret"), "", claims),
+	} {
+		identity, err := verifier.VerifyToken(context.Background(), token)
+		if err != nil || identity.Subject != "ops" || !identity.HasScope(entity.ScopeSubmit) {
+			t.Errorf("Expected the %s token to verify as an admin, got %+v, %v", name, identity, err)
+		}
+	}
+
+	if _, err := verifier.VerifyToken(context.Background(), sign(t, gojwt.SigningMethodHS256, []byte("other-secret"), "", claims)); err == nil {
+		t.Error("Expected a token signed with another secret to be rejected")
+	}
+}
+
+// with returns a copy of claims with one claim replaced, or removed when value is nil
+func with(claims gojwt.MapClaims, name string, value interface{}) gojwt.MapClaims {
+	copied := gojwt.MapClaims{}
+	for k, v := range claims {
+		copied[k] = v
+	}
+	if value == nil {
+		delete(copied, name)
+	} else {
+		copied[name] = value
+	}
+	return copied
+}
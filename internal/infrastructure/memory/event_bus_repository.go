@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// EventBusRepository implements the repository.EventBusRepository interface in process
+// It only reaches subscribers in the same process, which is every subscriber in embedded mode
+type EventBusRepository struct {
+	mu          sync.RWMutex
+	nextID      int
+	subscribers map[int]func(entity.SurveyEvent)
+}
+
+// NewEventBusRepository creates a new in-process event bus
+func NewEventBusRepository() repository.EventBusRepository {
+	return &EventBusRepository{
+		subscribers: make(map[int]func(entity.SurveyEvent)),
+	}
+}
+
+// PublishEvent calls every subscriber's callback with the event
+func (r *EventBusRepository) PublishEvent(ctx context.Context, event entity.SurveyEvent) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, callback := range r.subscribers {
+		callback(event)
+	}
+	return nil
+}
+
+// SubscribeEvents registers the callback until ctx is canceled
+func (r *EventBusRepository) SubscribeEvents(ctx context.Context, callback func(entity.SurveyEvent)) error {
+	r.mu.Lock()
+	id := r.nextID
+	r.nextID++
+	r.subscribers[id] = callback
+	r.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		r.mu.Lock()
+		delete(r.subscribers, id)
+		r.mu.Unlock()
+	}()
+
+	return nil
+}
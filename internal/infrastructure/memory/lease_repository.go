@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// lease is the state of a lease held in memory
+type lease struct {
+	holder    string
+	expiresAt time.Time
+}
+
+// LeaseRepository implements the repository.LeaseRepository interface in process
+// Leases expire by the given clock, so tests can drive expiry without sleeping
+type LeaseRepository struct {
+	mu     sync.Mutex
+	now    func() time.Time
+	leases map[string]lease
+}
+
+// NewLeaseRepository creates a new in-process lease repository that reads the time from now
+func NewLeaseRepository(now func() time.Time) repository.LeaseRepository {
+	return &LeaseRepository{
+		now:    now,
+		leases: make(map[string]lease),
+	}
+}
+
+// AcquireLease takes the lease if it is free or expired, or extends it for its holder
+func (r *LeaseRepository) AcquireLease(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if current, ok := r.leases[key]; ok && current.holder != holder && now.Before(current.expiresAt) {
+		return false, nil
+	}
+
+	r.leases[key] = lease{holder: holder, expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+// ReleaseLease deletes the lease if holder still has it
+func (r *LeaseRepository) ReleaseLease(ctx context.Context, key, holder string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if current, ok := r.leases[key]; ok && current.holder == holder {
+		delete(r.leases, key)
+	}
+	return nil
+}
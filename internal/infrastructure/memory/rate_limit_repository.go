@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// rateLimitSweepInterval is how often buckets that have fully replenished are dropped
+const rateLimitSweepInterval = time.Minute
+
+// bucket is the token bucket of one rate limit key
+type bucket struct {
+	tokens float64
+	last   time.Time
+
+	// fullAt is when the bucket will have replenished to its burst
+	fullAt time.Time
+}
+
+// RateLimitRepository implements the repository.RateLimitRepository interface
+// with token buckets held in process, so every instance limits on its own
+type RateLimitRepository struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewRateLimitRepository creates a new in-process rate limit repository that reads the time from now
+func NewRateLimitRepository(now func() time.Time) repository.RateLimitRepository {
+	return &RateLimitRepository{
+		now:     now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// TakeRateLimit takes cost tokens from the bucket under key if it holds them
+func (r *RateLimitRepository) TakeRateLimit(ctx context.Context, key string, rate float64, burst, cost int) (entity.RateLimitResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	r.sweep(now)
+
+	b, ok := r.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), last: now}
+		r.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	result := entity.RateLimitResult{Limit: burst}
+	if b.tokens >= float64(cost) {
+		b.tokens -= float64(cost)
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((float64(cost) - b.tokens) / rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.ResetAfter = seconds((float64(burst) - b.tokens) / rate)
+	b.fullAt = now.Add(result.ResetAfter)
+
+	return result, nil
+}
+
+// ReturnRateLimit puts cost tokens back into the bucket under key
+func (r *RateLimitRepository) ReturnRateLimit(ctx context.Context, key string, rate float64, burst, cost int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.buckets[key]
+	if !ok {
+		// A swept bucket had fully replenished, so nothing is owed to it
+		return nil
+	}
+	now := r.now()
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*rate+float64(cost))
+	b.last = now
+	b.fullAt = now.Add(seconds((float64(burst) - b.tokens) / rate))
+
+	return nil
+}
+
+// sweep drops the buckets that have fully replenished, which behave as new ones
+func (r *RateLimitRepository) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	r.lastSweep = now
+
+	for key, b := range r.buckets {
+		if !now.Before(b.fullAt) {
+			delete(r.buckets, key)
+		}
+	}
+}
+
+// seconds converts a number of seconds to a duration, rounded up to the microsecond
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s*1e6)) * time.Microsecond
+}
@@ -0,0 +1,52 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	// delayQueueIdle is how long an unused delay queue is kept before RabbitMQ deletes it
+	delayQueueIdle = time.Hour
+)
+
+// queueDeclarer declares queues, like an AMQP channel
+type queueDeclarer interface {
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+}
+
+// delayTTL rounds a delay up to the second, so delays share a few delay queues
+func delayTTL(delay time.Duration) time.Duration {
+	return ((delay + time.Second - 1) / time.Second) * time.Second
+}
+
+// declareDelayQueue declares the queue holding messages for the given delay,
+// rounded up by delayTTL, before dead-lettering them into target, and returns
+// its name, {prefix}{ttl}ms. Messages only expire at the head of a queue, so
+// each delay gets its own queue with a fixed TTL and a long delay never holds
+// up a short one
+func declareDelayQueue(ch queueDeclarer, prefix, target string, delay time.Duration) (string, error) {
+	ttl := delayTTL(delay)
+	name := fmt.Sprintf("%s%dms", prefix, ttl.Milliseconds())
+
+	_, err := ch.QueueDeclare(
+		name,  // name
+		true,  // durable
+		false, // delete when unused
+		false, // exclusive
+		false, // no-wait
+		amqp.Table{
+			"x-message-ttl":             ttl.Milliseconds(),
+			"x-expires":                 (ttl + delayQueueIdle).Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": target,
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to declare the delay queue: %w", err)
+	}
+
+	return name, nil
+}
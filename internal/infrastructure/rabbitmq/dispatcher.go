@@ -0,0 +1,146 @@
+package rabbitmq
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// dispatcher hands the deliveries of several queues to a handler, taking
+// turns between the queues and keeping at most limit deliveries in hand, so a
+// queue with a backlog never holds up the deliveries of the others
+type dispatcher struct {
+	mu     sync.Mutex
+	limit  int
+	inHand int
+
+	// freed wakes run when a delivery was handled or the limit was raised
+	freed chan struct{}
+}
+
+// newDispatcher creates a dispatcher keeping at most limit deliveries in hand
+func newDispatcher(limit int) *dispatcher {
+	return &dispatcher{
+		limit: max(limit, 1),
+		freed: make(chan struct{}, 1),
+	}
+}
+
+// setLimit changes how many deliveries are kept in hand at most; deliveries
+// in hand over a lowered limit are handled before new ones are taken
+func (d *dispatcher) setLimit(limit int) {
+	d.mu.Lock()
+	d.limit = max(limit, 1)
+	d.mu.Unlock()
+	d.wake()
+}
+
+// run passes every delivery of sources to handle in its own goroutine until
+// ctx is canceled or every source is closed. After serving a source it
+// looks at the next one first, so every source with deliveries waiting is
+// served in turn
+func (d *dispatcher) run(ctx context.Context, sources []<-chan amqp.Delivery, handle func(amqp.Delivery)) {
+	sources = append([]<-chan amqp.Delivery(nil), sources...)
+	next := 0
+	for {
+		if !d.take(ctx) {
+			return
+		}
+		msg, served, ok := receive(ctx, sources, next)
+		if !ok {
+			d.release()
+			return
+		}
+		next = (served + 1) % len(sources)
+
+		go func() {
+			defer d.release()
+			handle(msg)
+		}()
+	}
+}
+
+// take waits until a delivery can be taken in hand, false when ctx is canceled first
+func (d *dispatcher) take(ctx context.Context) bool {
+	for {
+		d.mu.Lock()
+		if d.inHand < d.limit {
+			d.inHand++
+			d.mu.Unlock()
+			return true
+		}
+		d.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-d.freed:
+		}
+	}
+}
+
+// release hands back a delivery taken by take
+func (d *dispatcher) release() {
+	d.mu.Lock()
+	d.inHand--
+	d.mu.Unlock()
+	d.wake()
+}
+
+// wake lets a waiting take check for room again
+func (d *dispatcher) wake() {
+	select {
+	case d.freed <- struct{}{}:
+	default:
+	}
+}
+
+// receive returns the next delivery of sources, looking at them in turn from
+// start, with the index of its source. Closed sources are set to nil so they
+// are skipped; it returns false when ctx is canceled or every source is closed
+func receive(ctx context.Context, sources []<-chan amqp.Delivery, start int) (amqp.Delivery, int, bool) {
+	// Take a waiting delivery from the first source in turn that has one
+	for i := range sources {
+		index := (start + i) % len(sources)
+		if sources[index] == nil {
+			continue
+		}
+		select {
+		case msg, ok := <-sources[index]:
+			if ok {
+				return msg, index, true
+			}
+			sources[index] = nil
+		default:
+		}
+	}
+
+	// Else wait for the first delivery of any source
+	for {
+		cases := make([]reflect.SelectCase, 0, len(sources)+1)
+		indexes := make([]int, 0, len(sources))
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+		for index, source := range sources {
+			if source != nil {
+				cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(source)})
+				indexes = append(indexes, index)
+			}
+		}
+		if len(indexes) == 0 {
+			return amqp.Delivery{}, 0, false
+		}
+
+		chosen, value, ok := reflect.Select(cases)
+		if chosen == 0 {
+			return amqp.Delivery{}, 0, false
+		}
+		index := indexes[chosen-1]
+		if !ok {
+			sources[index] = nil
+			continue
+		}
+		return value.Interface().(amqp.Delivery), index, true
+	}
+}
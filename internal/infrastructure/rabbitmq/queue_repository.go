@@ -3,25 +3,66 @@ package rabbitmq
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
 	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
 )
 
-const (
-	queueName = "generate_report_queue"
-)
+// QueueOptions holds the settings of the report job queues
+type QueueOptions struct {
+	// Name is the queue report jobs were published to before jobs were
+	// spread over shards; it is still consumed, so no job left in it is lost
+	Name string
+
+	// Shards is the number of queues report jobs are spread over, named
+	// {Name}.{shard}. Every job of a tenant goes to the same shard, and shards
+	// are consumed in turn, so a tenant's backlog only holds up the tenants
+	// sharing its shard
+	Shards int
+
+	// DelayName is the queue that held delayed jobs before jobs were spread
+	// over shards, dead-lettering them into Name. Delayed jobs of a shard wait
+	// in a delay queue per delay, named {DelayName}.{shard}.{ttl}ms, since
+	// messages only expire at the head of a queue and debounce windows differ
+	// between surveys and change at runtime
+	DelayName string
+
+	// RetryDelay is how long a job whose handling failed is held before it is returned to the queue
+	RetryDelay time.Duration
+}
+
+// jobChannel is the part of an AMQP channel report jobs are published on
+type jobChannel interface {
+	queueDeclarer
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	IsClosed() bool
+	Close() error
+}
 
 // QueueRepository implements the repository.QueueRepository interface using RabbitMQ
 type QueueRepository struct {
 	conn    *amqp.Connection
-	channel *amqp.Channel
+	channel jobChannel
+	options QueueOptions
+
+	// mu serializes publishing with the delay queue declarations it may need
+	mu sync.Mutex
+
+	// consumers holds a channel per consumed queue, so every queue gets the
+	// prefetch count of its own
+	consumers  []*amqp.Channel
+	dispatcher *dispatcher
 }
 
 // NewQueueRepository creates a new RabbitMQ queue repository
-func NewQueueRepository(url string) (repository.QueueRepository, error) {
+func NewQueueRepository(url string, options QueueOptions) (repository.QueueRepository, error) {
 	conn, err := amqp.Dial(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
@@ -33,45 +74,151 @@ func NewQueueRepository(url string) (repository.QueueRepository, error) {
 		return nil, fmt.Errorf("failed to open a channel: %w", err)
 	}
 
-	// Declare the queue
-	_, err = ch.QueueDeclare(
-		queueName, // name
-		true,      // durable
-		false,     // delete when unused
-		false,     // exclusive
-		false,     // no-wait
-		nil,       // arguments
+	r := &QueueRepository{
+		conn:       conn,
+		channel:    ch,
+		options:    options,
+		dispatcher: newDispatcher(1),
+	}
+
+	// Declare the queues; delay queues are declared as delayed jobs need them
+	for _, name := range r.queues() {
+		if err := r.declareQueue(name); err != nil {
+			r.Close()
+			return nil, err
+		}
+		consumer, err := conn.Channel()
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("failed to open a channel: %w", err)
+		}
+		r.consumers = append(r.consumers, consumer)
+	}
+
+	// Take one job at a time until the consumer asks for more
+	if err := r.SetPrefetch(1); err != nil {
+		r.Close()
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// queues lists every queue report jobs are consumed from: the queue of jobs
+// published before jobs were spread over shards, then every shard
+func (r *QueueRepository) queues() []string {
+	names := []string{r.options.Name}
+	for shard := 0; shard < r.options.Shards; shard++ {
+		names = append(names, r.shardQueue(shard))
+	}
+	return names
+}
+
+// shardQueue returns the name of a shard's queue
+func (r *QueueRepository) shardQueue(shard int) string {
+	return fmt.Sprintf("%s.%d", r.options.Name, shard)
+}
+
+// declareDelayQueue declares the delay queue holding jobs of the given queue
+// for the given delay, and returns its name
+func (r *QueueRepository) declareDelayQueue(queue string, delay time.Duration) (string, error) {
+	prefix := r.options.DelayName + strings.TrimPrefix(queue, r.options.Name) + "."
+	return declareDelayQueue(r.channel, prefix, queue, delay)
+}
+
+// tenantQueue returns the queue the jobs of a tenant are published to
+func (r *QueueRepository) tenantQueue(tenantID string) string {
+	if tenantID == "" {
+		tenantID = entity.DefaultTenant
+	}
+	h := fnv.New32a()
+	h.Write([]byte(tenantID))
+	return r.shardQueue(int(h.Sum32() % uint32(max(r.options.Shards, 1))))
+}
+
+// declareQueue declares a job queue
+// The queue of jobs published before jobs were spread over shards keeps the
+// delay queue its delayed jobs still expire from
+func (r *QueueRepository) declareQueue(name string) error {
+	_, err := r.channel.QueueDeclare(
+		name,  // name
+		true,  // durable
+		false, // delete when unused
+		false, // exclusive
+		false, // no-wait
+		nil,   // arguments
 	)
 	if err != nil {
-		ch.Close()
-		conn.Close()
-		return nil, fmt.Errorf("failed to declare a queue: %w", err)
+		return fmt.Errorf("failed to declare a queue: %w", err)
+	}
+	if name != r.options.Name {
+		return nil
+	}
+
+	_, err = r.channel.QueueDeclare(
+		r.options.DelayName, // name
+		true,                // durable
+		false,               // delete when unused
+		false,               // exclusive
+		false,               // no-wait
+		amqp.Table{
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": name,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare the delay queue: %w", err)
 	}
+	return nil
+}
 
-	return &QueueRepository{
-		conn:    conn,
-		channel: ch,
-	}, nil
+// SetPrefetch limits the jobs in flight so the other instances get their share
+// Every queue may deliver up to prefetch jobs ahead, of which prefetch at a
+// time are handed to the consumer, taking turns between the queues
+// The limit is set for the whole channel of each queue, which only has the
+// one consumer, because RabbitMQ applies a changed per-consumer limit to new
+// consumers only
+func (r *QueueRepository) SetPrefetch(prefetch int) error {
+	for _, consumer := range r.consumers {
+		if err := consumer.Qos(max(prefetch, 1), 0, true); err != nil {
+			return fmt.Errorf("failed to set the prefetch count: %w", err)
+		}
+	}
+	r.dispatcher.setLimit(prefetch)
+	return nil
 }
 
-// PublishReportJob publishes a report job to the queue
+// PublishReportJob publishes a report job to the queue of its tenant's shard
 func (r *QueueRepository) PublishReportJob(ctx context.Context, job entity.ReportJob) error {
 	body, err := json.Marshal(job)
 	if err != nil {
 		return fmt.Errorf("failed to marshal job: %w", err)
 	}
 
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		Body:         body,
+		DeliveryMode: amqp.Persistent, // Make message persistent
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Route delayed jobs through the delay queue of the shard matching their delay
+	routingKey := r.tenantQueue(job.TenantID)
+	if delay := time.Until(time.Unix(0, job.NotBefore)); job.NotBefore != 0 && delay > 0 {
+		if routingKey, err = r.declareDelayQueue(routingKey, delay); err != nil {
+			return err
+		}
+	}
+
 	err = r.channel.PublishWithContext(
 		ctx,
-		"",        // exchange
-		queueName, // routing key
-		false,     // mandatory
-		false,     // immediate
-		amqp.Publishing{
-			ContentType:  "application/json",
-			Body:         body,
-			DeliveryMode: amqp.Persistent, // Make message persistent
-		},
+		"",         // exchange
+		routingKey, // routing key
+		false,      // mandatory
+		false,      // immediate
+		msg,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to publish a message: %w", err)
@@ -80,58 +227,80 @@ func (r *QueueRepository) PublishReportJob(ctx context.Context, job entity.Repor
 	return nil
 }
 
-// ConsumeReportJobs starts consuming report jobs from the queue
+// ConsumeReportJobs starts consuming report jobs from every queue
+// Every delivered job is handled in its own goroutine, so the prefetch count
+// bounds how many jobs are handled at once
 func (r *QueueRepository) ConsumeReportJobs(ctx context.Context, callback func(entity.ReportJob) error) error {
-	msgs, err := r.channel.Consume(
-		queueName, // queue
-		"",        // consumer
-		false,     // auto-ack
-		false,     // exclusive
-		false,     // no-local
-		false,     // no-wait
-		nil,       // args
-	)
-	if err != nil {
-		return fmt.Errorf("failed to register a consumer: %w", err)
-	}
-
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case msg, ok := <-msgs:
-				if !ok {
-					return
-				}
-
-				var job entity.ReportJob
-				if err := json.Unmarshal(msg.Body, &job); err != nil {
-					// Log error and acknowledge message to remove it from queue
-					fmt.Printf("Error unmarshaling job: %v\n", err)
-					msg.Ack(false)
-					continue
-				}
-
-				// Process the job
-				if err := callback(job); err != nil {
-					// Log error but don't acknowledge to retry later
-					fmt.Printf("Error processing job: %v\n", err)
-					msg.Nack(false, true)
-					continue
-				}
-
-				// Acknowledge the message
-				msg.Ack(false)
-			}
+	sources := make([]<-chan amqp.Delivery, 0, len(r.consumers))
+	for i, name := range r.queues() {
+		msgs, err := r.consumers[i].Consume(
+			name,  // queue
+			"",    // consumer
+			false, // auto-ack
+			false, // exclusive
+			false, // no-local
+			false, // no-wait
+			nil,   // args
+		)
+		if err != nil {
+			return fmt.Errorf("failed to register a consumer: %w", err)
 		}
-	}()
+		sources = append(sources, msgs)
+	}
+
+	go r.dispatcher.run(ctx, sources, func(msg amqp.Delivery) {
+		r.handle(ctx, msg, callback)
+	})
+
+	return nil
+}
+
+// handle passes one delivery to the callback and acknowledges it once handled
+func (r *QueueRepository) handle(ctx context.Context, msg amqp.Delivery, callback func(entity.ReportJob) error) {
+	var job entity.ReportJob
+	if err := json.Unmarshal(msg.Body, &job); err != nil {
+		// Log error and acknowledge message to remove it from queue
+		fmt.Printf("Error unmarshaling job: %v\n", err)
+		msg.Ack(false)
+		return
+	}
+
+	// Process the job
+	if err := callback(job); err != nil {
+		// Log error but don't acknowledge to retry later, after a pause so a
+		// failing job does not spin through the queue
+		fmt.Printf("Error processing job: %v\n", err)
+		select {
+		case <-ctx.Done():
+		case <-time.After(r.options.RetryDelay):
+		}
+		msg.Nack(false, true)
+		return
+	}
+
+	// Acknowledge the message
+	msg.Ack(false)
+}
 
+// Ping reports an error once the connection or one of its channels has been
+// closed, after which nothing is published or consumed until the instance restarts
+func (r *QueueRepository) Ping(ctx context.Context) error {
+	if r.conn.IsClosed() || r.channel.IsClosed() {
+		return errors.New("connection to RabbitMQ is closed")
+	}
+	for _, consumer := range r.consumers {
+		if consumer.IsClosed() {
+			return errors.New("connection to RabbitMQ is closed")
+		}
+	}
 	return nil
 }
 
 // Close closes the connection to RabbitMQ
 func (r *QueueRepository) Close() error {
+	for _, consumer := range r.consumers {
+		consumer.Close()
+	}
 	if r.channel != nil {
 		r.channel.Close()
 	}
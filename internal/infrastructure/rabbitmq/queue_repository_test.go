@@ -0,0 +1,165 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+func TestConsumeReportJobs_TenantBacklogDoesNotHoldUpOtherTenants(t *testing.T) {
+	r := &QueueRepository{options: QueueOptions{Name: "jobs", Shards: 8}}
+	tenantA, tenantB := "acme", "globex"
+	if r.tenantQueue(tenantA) == r.tenantQueue(tenantB) {
+		t.Fatalf("Expected %s and %s to be on different shards", tenantA, tenantB)
+	}
+
+	// Fill every queue the way the broker delivers it: tenant A has a
+	// backlog of more jobs than the prefetch, and tenant B one job queued after it
+	const prefetch = 2
+	queues := make(map[string]chan amqp.Delivery)
+	var sources []<-chan amqp.Delivery
+	for _, name := range r.queues() {
+		queues[name] = make(chan amqp.Delivery, 10)
+		sources = append(sources, queues[name])
+	}
+	publish := func(job entity.ReportJob) {
+		body, err := json.Marshal(job)
+		if err != nil {
+			t.Fatalf("Failed to marshal job: %v", err)
+		}
+		queues[r.tenantQueue(job.TenantID)] <- amqp.Delivery{Body: body}
+	}
+	for i := 0; i < 3*prefetch; i++ {
+		publish(entity.ReportJob{SurveyID: "backlog", TenantID: tenantA})
+	}
+	publish(entity.ReportJob{SurveyID: "waiting", TenantID: tenantB})
+
+	// Handle jobs until they are released, so at most prefetch run at once
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	started := make(chan entity.ReportJob, 10)
+	release := make(chan struct{})
+	d := newDispatcher(prefetch)
+	go d.run(ctx, sources, func(msg amqp.Delivery) {
+		var job entity.ReportJob
+		json.Unmarshal(msg.Body, &job)
+		started <- job
+		<-release
+	})
+
+	var running []string
+	for len(running) < prefetch {
+		select {
+		case job := <-started:
+			running = append(running, job.TenantID)
+		case <-time.After(time.Second):
+			t.Fatalf("Expected %d jobs to start, got %v", prefetch, running)
+		}
+	}
+	if running[0] != tenantB && running[1] != tenantB {
+		t.Errorf("Expected the job of %s to start alongside the backlog of %s, got %v", tenantB, tenantA, running)
+	}
+	select {
+	case job := <-started:
+		t.Errorf("Expected at most %d jobs in hand, got another of %s", prefetch, job.TenantID)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Freeing a slot takes the next job of the backlog
+	release <- struct{}{}
+	select {
+	case job := <-started:
+		if job.TenantID != tenantA {
+			t.Errorf("Expected the backlog of %s to continue, got a job of %s", tenantA, job.TenantID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a job to start once a slot was freed")
+	}
+	close(release)
+}
+
+func TestTenantQueue_SpreadsTenantsOverShards(t *testing.T) {
+	r := &QueueRepository{options: QueueOptions{Name: "jobs", Shards: 4}}
+	if r.tenantQueue("") != r.tenantQueue(entity.DefaultTenant) {
+		t.Error("Expected jobs without a tenant to go to the default tenant's queue")
+	}
+
+	used := make(map[string]bool)
+	for _, tenantID := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+		used[r.tenantQueue(tenantID)] = true
+	}
+	if len(used) < 2 {
+		t.Errorf("Expected tenants to be spread over several shards, got %v", used)
+	}
+}
+
+// recordingChannel records the queues declared and the messages published on it
+type recordingChannel struct {
+	declared  map[string]amqp.Table
+	published map[string][]amqp.Publishing
+}
+
+func newRecordingChannel() *recordingChannel {
+	return &recordingChannel{declared: make(map[string]amqp.Table), published: make(map[string][]amqp.Publishing)}
+}
+
+func (c *recordingChannel) QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+	c.declared[name] = args
+	return amqp.Queue{Name: name}, nil
+}
+
+func (c *recordingChannel) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	c.published[key] = append(c.published[key], msg)
+	return nil
+}
+
+func (c *recordingChannel) IsClosed() bool { return false }
+
+func (c *recordingChannel) Close() error { return nil }
+
+func TestPublishReportJob_DelaysEachWindowInItsOwnQueue(t *testing.T) {
+	ch := newRecordingChannel()
+	r := &QueueRepository{channel: ch, options: QueueOptions{Name: "jobs", Shards: 1, DelayName: "jobs_delay"}}
+	ctx := context.Background()
+
+	// A survey with a long window delays its job first, then one with a short window
+	now := time.Now()
+	jobs := []entity.ReportJob{
+		{SurveyID: "long", NotBefore: now.Add(time.Minute).UnixNano()},
+		{SurveyID: "short", NotBefore: now.Add(5 * time.Second).UnixNano()},
+		{SurveyID: "short", NotBefore: now.Add(5 * time.Second).UnixNano()},
+		{SurveyID: "now"},
+	}
+	for _, job := range jobs {
+		if err := r.PublishReportJob(ctx, job); err != nil {
+			t.Fatalf("Failed to publish job: %v", err)
+		}
+	}
+
+	// The short window must not wait behind the long one at the head of a shared queue
+	for queue, ttl := range map[string]int64{"jobs_delay.0.60000ms": 60000, "jobs_delay.0.5000ms": 5000} {
+		args, ok := ch.declared[queue]
+		if !ok {
+			t.Errorf("Expected delay queue %s to be declared, got %v", queue, ch.declared)
+			continue
+		}
+		if args["x-message-ttl"] != ttl || args["x-dead-letter-routing-key"] != "jobs.0" {
+			t.Errorf("Expected %s to hold jobs for %dms before moving them to jobs.0, got %v", queue, ttl, args)
+		}
+		for _, msg := range ch.published[queue] {
+			if msg.Expiration != "" {
+				t.Errorf("Expected jobs in %s to expire by the queue's TTL, got expiration %s", queue, msg.Expiration)
+			}
+		}
+	}
+	if len(ch.published["jobs_delay.0.60000ms"]) != 1 || len(ch.published["jobs_delay.0.5000ms"]) != 2 {
+		t.Errorf("Expected each job to wait in the queue of its window, got %v", ch.published)
+	}
+	if len(ch.published["jobs.0"]) != 1 {
+		t.Errorf("Expected the undelayed job to go to the shard directly, got %v", ch.published)
+	}
+}
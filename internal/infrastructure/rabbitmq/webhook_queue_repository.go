@@ -0,0 +1,182 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// WebhookQueueOptions holds the queue names of the webhook delivery queue
+type WebhookQueueOptions struct {
+	// Name is the queue deliveries are consumed from
+	Name string
+
+	// DelayPrefix names the delay queues retried deliveries wait in.
+	// Messages only expire at the head of a queue, so each delay gets its own
+	// queue with a fixed TTL and a long backoff never holds up a short one
+	DelayPrefix string
+}
+
+// WebhookQueueRepository implements the repository.WebhookQueueRepository interface using RabbitMQ
+type WebhookQueueRepository struct {
+	conn    *amqp.Connection
+	channel *amqp.Channel
+	options WebhookQueueOptions
+
+	// mu serializes publishing with the delay queue declarations it may need
+	mu sync.Mutex
+}
+
+// NewWebhookQueueRepository creates a new RabbitMQ webhook delivery queue
+func NewWebhookQueueRepository(url string, options WebhookQueueOptions) (repository.WebhookQueueRepository, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open a channel: %w", err)
+	}
+
+	// Declare the queue
+	_, err = ch.QueueDeclare(
+		options.Name, // name
+		true,         // durable
+		false,        // delete when unused
+		false,        // exclusive
+		false,        // no-wait
+		nil,          // arguments
+	)
+	if err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, fmt.Errorf("failed to declare a queue: %w", err)
+	}
+
+	return &WebhookQueueRepository{
+		conn:    conn,
+		channel: ch,
+		options: options,
+	}, nil
+}
+
+// PublishDelivery publishes a delivery to the queue
+// Delayed deliveries are routed through the delay queue matching their delay
+func (r *WebhookQueueRepository) PublishDelivery(ctx context.Context, delivery entity.WebhookDelivery) error {
+	body, err := json.Marshal(delivery)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delivery: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	routingKey := r.options.Name
+	if delay := time.Until(time.Unix(0, delivery.NotBefore)); delivery.NotBefore != 0 && delay > 0 {
+		if routingKey, err = r.declareDelayQueue(delay); err != nil {
+			return err
+		}
+	}
+
+	err = r.channel.PublishWithContext(
+		ctx,
+		"",         // exchange
+		routingKey, // routing key
+		false,      // mandatory
+		false,      // immediate
+		amqp.Publishing{
+			ContentType:  "application/json",
+			Body:         body,
+			DeliveryMode: amqp.Persistent, // Make message persistent
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to publish a message: %w", err)
+	}
+
+	return nil
+}
+
+// declareDelayQueue declares the delay queue for the given delay and returns its name
+func (r *WebhookQueueRepository) declareDelayQueue(delay time.Duration) (string, error) {
+	return declareDelayQueue(r.channel, r.options.DelayPrefix, r.options.Name, delay)
+}
+
+// ConsumeDeliveries starts consuming deliveries from the queue
+func (r *WebhookQueueRepository) ConsumeDeliveries(ctx context.Context, callback func(entity.WebhookDelivery) error) error {
+	msgs, err := r.channel.Consume(
+		r.options.Name, // queue
+		"",             // consumer
+		false,          // auto-ack
+		false,          // exclusive
+		false,          // no-local
+		false,          // no-wait
+		nil,            // args
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register a consumer: %w", err)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+
+				var delivery entity.WebhookDelivery
+				if err := json.Unmarshal(msg.Body, &delivery); err != nil {
+					// Log error and acknowledge message to remove it from queue
+					fmt.Printf("Error unmarshaling delivery: %v\n", err)
+					msg.Ack(false)
+					continue
+				}
+
+				// Process the delivery
+				if err := callback(delivery); err != nil {
+					// Log error but don't acknowledge to retry later
+					fmt.Printf("Error processing delivery: %v\n", err)
+					msg.Nack(false, true)
+					continue
+				}
+
+				// Acknowledge the message
+				msg.Ack(false)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Ping reports an error once the connection or its channel has been closed,
+// after which nothing is published or consumed until the instance restarts
+func (r *WebhookQueueRepository) Ping(ctx context.Context) error {
+	if r.conn.IsClosed() || r.channel.IsClosed() {
+		return errors.New("connection to RabbitMQ is closed")
+	}
+	return nil
+}
+
+// Close closes the connection to RabbitMQ
+func (r *WebhookQueueRepository) Close() error {
+	if r.channel != nil {
+		r.channel.Close()
+	}
+	if r.conn != nil {
+		r.conn.Close()
+	}
+	return nil
+}
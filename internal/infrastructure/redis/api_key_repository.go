@@ -0,0 +1,91 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// apiKeysKey is the Redis hash holding every API key, keyed by the hash of the key
+const apiKeysKey = "auth:api_keys"
+
+// APIKeyRepository implements the repository.APIKeyRepository interface using Redis
+type APIKeyRepository struct {
+	client redis.UniversalClient
+}
+
+// NewAPIKeyRepository creates a new Redis API key repository
+func NewAPIKeyRepository(client redis.UniversalClient) repository.APIKeyRepository {
+	return &APIKeyRepository{
+		client: client,
+	}
+}
+
+// SaveAPIKey stores an API key in the API key hash
+func (r *APIKeyRepository) SaveAPIKey(ctx context.Context, key entity.APIKey) error {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal API key: %w", err)
+	}
+
+	return r.client.HSet(ctx, apiKeysKey, key.Hash, data).Err()
+}
+
+// GetAPIKeyByHash returns the API key stored under hash
+func (r *APIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*entity.APIKey, error) {
+	data, err := r.client.HGet(ctx, apiKeysKey, hash).Bytes()
+	if err == redis.Nil {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var key entity.APIKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal API key: %w", err)
+	}
+
+	return &key, nil
+}
+
+// ListAPIKeys returns every API key ordered by creation time
+func (r *APIKeyRepository) ListAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	values, err := r.client.HGetAll(ctx, apiKeysKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]entity.APIKey, 0, len(values))
+	for _, value := range values {
+		var key entity.APIKey
+		if err := json.Unmarshal([]byte(value), &key); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal API key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt < keys[j].CreatedAt })
+	return keys, nil
+}
+
+// DeleteAPIKey removes the API key with the given ID from the API key hash
+func (r *APIKeyRepository) DeleteAPIKey(ctx context.Context, id string) error {
+	keys, err := r.ListAPIKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if key.ID != id {
+			continue
+		}
+		return r.client.HDel(ctx, apiKeysKey, key.Hash).Err()
+	}
+	return repository.ErrNotFound
+}
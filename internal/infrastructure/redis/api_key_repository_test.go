@@ -0,0 +1,51 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	redisRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/redis"
+)
+
+func TestAPIKeyRepository_LooksUpKeysByHash(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	repo := redisRepo.NewAPIKeyRepository(client)
+	ctx := context.Background()
+
+	keys := []entity.APIKey{
+		{ID: "key-2", Name: "reports", Hash: "hash-2", Scopes: []string{entity.ScopeReadReport}, CreatedAt: 2},
+		{ID: "key-1", Name: "ingest", Hash: "hash-1", Scopes: []string{entity.ScopeSubmit}, CreatedAt: 1},
+	}
+	for _, key := range keys {
+		if err := repo.SaveAPIKey(ctx, key); err != nil {
+			t.Fatalf("Failed to save API key: %v", err)
+		}
+	}
+
+	key, err := repo.GetAPIKeyByHash(ctx, "hash-1")
+	if err != nil || key.ID != "key-1" || key.Scopes[0] != entity.ScopeSubmit {
+		t.Errorf("Expected key-1 to be found by its hash, got %+v, %v", key, err)
+	}
+
+	listed, err := repo.ListAPIKeys(ctx)
+	if err != nil || len(listed) != 2 || listed[0].ID != "key-1" || listed[1].ID != "key-2" {
+		t.Errorf("Expected key-1 then key-2, got %+v, %v", listed, err)
+	}
+
+	if err := repo.DeleteAPIKey(ctx, "key-1"); err != nil {
+		t.Fatalf("Failed to delete API key: %v", err)
+	}
+	if _, err := repo.GetAPIKeyByHash(ctx, "hash-1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected the deleted key to be gone, got %v", err)
+	}
+	if err := repo.DeleteAPIKey(ctx, "key-1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected deleting it again to report ErrNotFound, got %v", err)
+	}
+}
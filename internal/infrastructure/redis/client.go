@@ -0,0 +1,65 @@
+package redis
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// ClientModeSingle connects to a single Redis server
+	ClientModeSingle = "single"
+
+	// ClientModeSentinel connects to the master of a Sentinel-managed deployment
+	ClientModeSentinel = "sentinel"
+
+	// ClientModeCluster connects to a Redis Cluster
+	ClientModeCluster = "cluster"
+)
+
+// ClientConfig describes how to connect to Redis
+type ClientConfig struct {
+	// Mode is one of ClientModeSingle, ClientModeSentinel or ClientModeCluster
+	Mode string
+
+	// Addrs is the server address in single mode, the Sentinel addresses in
+	// sentinel mode, or the seed node addresses in cluster mode
+	Addrs []string
+
+	// MasterName is the Sentinel master name, required in sentinel mode
+	MasterName string
+
+	// Password is the Redis password, if any
+	Password string
+}
+
+// NewClient creates a Redis client for the configured deployment mode
+func NewClient(cfg ClientConfig) (redis.UniversalClient, error) {
+	if len(cfg.Addrs) == 0 {
+		return nil, fmt.Errorf("at least one Redis address is required")
+	}
+
+	switch cfg.Mode {
+	case ClientModeSingle, "":
+		return redis.NewClient(&redis.Options{
+			Addr:     cfg.Addrs[0],
+			Password: cfg.Password,
+		}), nil
+	case ClientModeSentinel:
+		if cfg.MasterName == "" {
+			return nil, fmt.Errorf("a master name is required in sentinel mode")
+		}
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:    cfg.MasterName,
+			SentinelAddrs: cfg.Addrs,
+			Password:      cfg.Password,
+		}), nil
+	case ClientModeCluster:
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:    cfg.Addrs,
+			Password: cfg.Password,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown Redis mode %q", cfg.Mode)
+	}
+}
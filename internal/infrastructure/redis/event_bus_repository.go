@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// eventChannel is the Redis pub/sub channel survey events are broadcast on
+const eventChannel = "survey:events"
+
+// EventBusRepository implements the repository.EventBusRepository interface using Redis pub/sub
+type EventBusRepository struct {
+	client redis.UniversalClient
+}
+
+// NewEventBusRepository creates a new Redis event bus
+func NewEventBusRepository(client redis.UniversalClient) repository.EventBusRepository {
+	return &EventBusRepository{
+		client: client,
+	}
+}
+
+// PublishEvent publishes an event on the event channel
+func (r *EventBusRepository) PublishEvent(ctx context.Context, event entity.SurveyEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	return r.client.Publish(ctx, eventChannel, data).Err()
+}
+
+// SubscribeEvents subscribes to the event channel until ctx is canceled
+// The client reconnects and resubscribes by itself after a connection loss,
+// events published in the meantime are missed
+func (r *EventBusRepository) SubscribeEvents(ctx context.Context, callback func(entity.SurveyEvent)) error {
+	pubsub := r.client.Subscribe(ctx, eventChannel)
+
+	// Wait for the subscription to be confirmed so no event published after this returns is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return fmt.Errorf("failed to subscribe to events: %w", err)
+	}
+
+	go func() {
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var event entity.SurveyEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					fmt.Printf("Error unmarshaling event: %v\n", err)
+					continue
+				}
+				callback(event)
+			}
+		}
+	}()
+
+	return nil
+}
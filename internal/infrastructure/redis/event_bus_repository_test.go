@@ -0,0 +1,47 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	redisRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/redis"
+)
+
+func TestEventBus_FansOutAcrossInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	// Two instances, each with its own connection to the shared Redis
+	newBus := func() repository.EventBusRepository {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { client.Close() })
+		return redisRepo.NewEventBusRepository(client)
+	}
+	publisher, subscriber := newBus(), newBus()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan entity.SurveyEvent, 1)
+	if err := subscriber.SubscribeEvents(ctx, func(event entity.SurveyEvent) { received <- event }); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	job := &entity.JobRecord{ID: "job-1", SurveyID: "survey-123", Status: entity.JobStatusCompleted}
+	if err := publisher.PublishEvent(ctx, entity.SurveyEvent{Type: entity.EventJobUpdated, SurveyID: "survey-123", Job: job}); err != nil {
+		t.Fatalf("Failed to publish: %v", err)
+	}
+
+	select {
+	case event := <-received:
+		if event.Job == nil || event.Job.ID != "job-1" || event.Job.Status != entity.JobStatusCompleted {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the event published by the other instance to be received")
+	}
+}
@@ -0,0 +1,79 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+const (
+	// idempotencyKeyPrefix is the prefix for the Redis key holding an idempotency record
+	idempotencyKeyPrefix = "idempotency:"
+)
+
+// IdempotencyRepository implements the repository.IdempotencyRepository interface using Redis
+type IdempotencyRepository struct {
+	client redis.UniversalClient
+}
+
+// NewIdempotencyRepository creates a new Redis idempotency repository
+func NewIdempotencyRepository(client redis.UniversalClient) repository.IdempotencyRepository {
+	return &IdempotencyRepository{
+		client: client,
+	}
+}
+
+// Reserve stores the record only if no record exists for its key
+// It uses Redis SETNX so concurrent retries cannot both reserve the key
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record entity.IdempotencyRecord, ttl time.Duration) (*entity.IdempotencyRecord, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+
+	key := idempotencyKeyPrefix + record.Key
+	for {
+		reserved, err := r.client.SetNX(ctx, key, data, ttl).Result()
+		if err != nil {
+			return nil, err
+		}
+		if reserved {
+			return nil, nil
+		}
+
+		existing, err := r.client.Get(ctx, key).Bytes()
+		if err == redis.Nil {
+			// The record expired between the two calls, so try to reserve again
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var stored entity.IdempotencyRecord
+		if err := json.Unmarshal(existing, &stored); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal idempotency record: %w", err)
+		}
+		return &stored, nil
+	}
+}
+
+// Save stores the record, replacing any existing record for its key
+func (r *IdempotencyRepository) Save(ctx context.Context, record entity.IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+
+	return r.client.Set(ctx, idempotencyKeyPrefix+record.Key, data, ttl).Err()
+}
+
+// Delete removes the record for the given key
+func (r *IdempotencyRepository) Delete(ctx context.Context, key string) error {
+	return r.client.Del(ctx, idempotencyKeyPrefix+key).Err()
+}
@@ -0,0 +1,86 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+const (
+	// jobKeyPrefix is the prefix for the Redis hash holding a survey's job records
+	jobKeyPrefix = "survey:jobs:"
+
+	// jobRetention is how long job records are kept after the survey's last job update
+	jobRetention = 7 * 24 * time.Hour
+)
+
+// JobRepository implements the repository.JobRepository interface using Redis
+type JobRepository struct {
+	client redis.UniversalClient
+}
+
+// NewJobRepository creates a new Redis job repository
+func NewJobRepository(client redis.UniversalClient) repository.JobRepository {
+	return &JobRepository{
+		client: client,
+	}
+}
+
+// SaveJob stores a job record in the survey's job hash
+func (r *JobRepository) SaveJob(ctx context.Context, job entity.JobRecord) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	key := jobKeyPrefix + entity.SurveyKey(ctx, job.SurveyID)
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, key, job.ID, data)
+	pipe.Expire(ctx, key, jobRetention)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// GetJob returns the job record with the given ID for the given survey
+func (r *JobRepository) GetJob(ctx context.Context, surveyID, jobID string) (*entity.JobRecord, error) {
+	data, err := r.client.HGet(ctx, jobKeyPrefix+entity.SurveyKey(ctx, surveyID), jobID).Bytes()
+	if err == redis.Nil {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var job entity.JobRecord
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// ListJobs returns the job records for the given survey ordered by request time
+func (r *JobRepository) ListJobs(ctx context.Context, surveyID string) ([]entity.JobRecord, error) {
+	values, err := r.client.HGetAll(ctx, jobKeyPrefix+entity.SurveyKey(ctx, surveyID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]entity.JobRecord, 0, len(values))
+	for _, value := range values {
+		var job entity.JobRecord
+		if err := json.Unmarshal([]byte(value), &job); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].RequestedAt < jobs[j].RequestedAt })
+	return jobs, nil
+}
@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// acquireLeaseScript sets the key to the holder if it is free and extends its
+// expiry if the holder already has it
+var acquireLeaseScript = redis.NewScript(`
+local holder = redis.call("GET", KEYS[1])
+if holder == false then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+end
+if holder == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// LeaseRepository implements the repository.LeaseRepository interface using Redis
+type LeaseRepository struct {
+	client redis.UniversalClient
+}
+
+// NewLeaseRepository creates a new Redis lease repository
+func NewLeaseRepository(client redis.UniversalClient) repository.LeaseRepository {
+	return &LeaseRepository{
+		client: client,
+	}
+}
+
+// AcquireLease takes or extends the lease for holder
+func (r *LeaseRepository) AcquireLease(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	result, err := acquireLeaseScript.Run(ctx, r.client, []string{key}, holder, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return result == 1, nil
+}
+
+// ReleaseLease deletes the key if it still holds the holder
+func (r *LeaseRepository) ReleaseLease(ctx context.Context, key, holder string) error {
+	return releaseScript.Run(ctx, r.client, []string{key}, holder).Err()
+}
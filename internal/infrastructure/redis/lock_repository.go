@@ -10,11 +10,11 @@ import (
 
 // LockRepository implements the repository.LockRepository interface using Redis
 type LockRepository struct {
-	client *redis.Client
+	client redis.UniversalClient
 }
 
 // NewLockRepository creates a new Redis lock repository
-func NewLockRepository(client *redis.Client) repository.LockRepository {
+func NewLockRepository(client redis.UniversalClient) repository.LockRepository {
 	return &LockRepository{
 		client: client,
 	}
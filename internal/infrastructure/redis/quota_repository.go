@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// consumeQuotaScript adds ARGV[1] to the counter unless that would take it
+// above ARGV[2], and expires the counter at ARGV[3]
+var consumeQuotaScript = redis.NewScript(`
+local used = tonumber(redis.call("GET", KEYS[1]) or "0")
+if used + tonumber(ARGV[1]) > tonumber(ARGV[2]) then
+	return {used, 0}
+end
+used = redis.call("INCRBY", KEYS[1], ARGV[1])
+redis.call("EXPIREAT", KEYS[1], ARGV[3])
+return {used, 1}
+`)
+
+// QuotaRepository implements the repository.QuotaRepository interface using Redis
+type QuotaRepository struct {
+	client redis.UniversalClient
+}
+
+// NewQuotaRepository creates a new Redis quota repository
+func NewQuotaRepository(client redis.UniversalClient) repository.QuotaRepository {
+	return &QuotaRepository{
+		client: client,
+	}
+}
+
+// ConsumeQuota adds n to the usage under key unless that would exceed limit
+func (r *QuotaRepository) ConsumeQuota(ctx context.Context, key string, n, limit int64, expireAt time.Time) (int64, bool, error) {
+	result, err := consumeQuotaScript.Run(ctx, r.client, []string{key}, n, limit, expireAt.Unix()).Int64Slice()
+	if err != nil {
+		return 0, false, err
+	}
+
+	return result[0], result[1] == 1, nil
+}
@@ -0,0 +1,39 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	redisRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/redis"
+)
+
+func TestQuotaRepository_ConsumesUpToTheLimit(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	repo := redisRepo.NewQuotaRepository(client)
+	ctx := context.Background()
+	expireAt := time.Now().Add(time.Hour)
+
+	if used, ok, err := repo.ConsumeQuota(ctx, "quota:acme", 3, 5, expireAt); err != nil || !ok || used != 3 {
+		t.Fatalf("Expected 3 of 5 to be used, got %d, %v, %v", used, ok, err)
+	}
+	// A request that would go above the limit is refused as a whole
+	if used, ok, err := repo.ConsumeQuota(ctx, "quota:acme", 3, 5, expireAt); err != nil || ok || used != 3 {
+		t.Errorf("Expected 3 more to be refused with 3 used, got %d, %v, %v", used, ok, err)
+	}
+	if used, ok, err := repo.ConsumeQuota(ctx, "quota:acme", 2, 5, expireAt); err != nil || !ok || used != 5 {
+		t.Errorf("Expected the last 2 to be consumed, got %d, %v, %v", used, ok, err)
+	}
+	if ttl := server.TTL("quota:acme"); ttl <= 0 || ttl > time.Hour {
+		t.Errorf("Expected the counter to expire within the hour, got %v", ttl)
+	}
+
+	server.FastForward(time.Hour + time.Second)
+	if used, ok, err := repo.ConsumeQuota(ctx, "quota:acme", 1, 5, expireAt.Add(time.Hour)); err != nil || !ok || used != 1 {
+		t.Errorf("Expected usage to start again once the counter expired, got %d, %v, %v", used, ok, err)
+	}
+}
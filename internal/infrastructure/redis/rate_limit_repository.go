@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// takeRateLimitScript implements the generic cell rate algorithm (GCRA). The
+// key holds the theoretical arrival time (TAT) in microseconds on the Redis
+// clock: the time at which the limit is fully replenished. ARGV[1] is the
+// emission interval, the microseconds one request takes to replenish, ARGV[2]
+// the burst and ARGV[3] the cost. Returns whether the requests were counted,
+// the remaining requests, and the microseconds until a retry and until reset
+var takeRateLimitScript = redis.NewScript(`
+redis.replicate_commands()
+local emission = tonumber(ARGV[1])
+local tolerance = emission * tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local tat = math.max(tonumber(redis.call("GET", KEYS[1]) or "0"), now)
+local next_tat = tat + emission * tonumber(ARGV[3])
+local allow_at = next_tat - tolerance
+if allow_at > now then
+	return {0, math.floor((tolerance - (tat - now)) / emission), math.ceil(allow_at - now), math.ceil(tat - now)}
+end
+
+redis.call("SET", KEYS[1], string.format("%d", math.ceil(next_tat)), "PX", math.ceil((next_tat - now) / 1000))
+return {1, math.floor((tolerance - (next_tat - now)) / emission), 0, math.ceil(next_tat - now)}
+`)
+
+// returnRateLimitScript moves the theoretical arrival time under KEYS[1] back
+// by ARGV[2] requests of ARGV[1] microseconds each, dropping the key once the
+// limit is fully replenished
+var returnRateLimitScript = redis.NewScript(`
+redis.replicate_commands()
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local tat = tonumber(redis.call("GET", KEYS[1]) or "0") - tonumber(ARGV[1]) * tonumber(ARGV[2])
+if tat <= now then
+	redis.call("DEL", KEYS[1])
+	return 0
+end
+
+redis.call("SET", KEYS[1], string.format("%d", math.ceil(tat)), "PX", math.ceil((tat - now) / 1000))
+return 1
+`)
+
+// RateLimitRepository implements the repository.RateLimitRepository interface using Redis
+type RateLimitRepository struct {
+	client redis.UniversalClient
+}
+
+// NewRateLimitRepository creates a new Redis rate limit repository
+func NewRateLimitRepository(client redis.UniversalClient) repository.RateLimitRepository {
+	return &RateLimitRepository{
+		client: client,
+	}
+}
+
+// TakeRateLimit counts cost requests against the limit under key, timed by
+// the Redis clock so instances with skewed clocks share one limit
+func (r *RateLimitRepository) TakeRateLimit(ctx context.Context, key string, rate float64, burst, cost int) (entity.RateLimitResult, error) {
+	emission := float64(time.Second/time.Microsecond) / rate
+	result, err := takeRateLimitScript.Run(ctx, r.client, []string{key}, emission, burst, cost).Int64Slice()
+	if err != nil {
+		return entity.RateLimitResult{}, err
+	}
+
+	return entity.RateLimitResult{
+		Allowed:    result[0] == 1,
+		Limit:      burst,
+		Remaining:  int(max(result[1], 0)),
+		RetryAfter: time.Duration(result[2]) * time.Microsecond,
+		ResetAfter: time.Duration(result[3]) * time.Microsecond,
+	}, nil
+}
+
+// ReturnRateLimit gives back cost requests counted against the limit under key
+func (r *RateLimitRepository) ReturnRateLimit(ctx context.Context, key string, rate float64, burst, cost int) error {
+	emission := float64(time.Second/time.Microsecond) / rate
+	return returnRateLimitScript.Run(ctx, r.client, []string{key}, emission, cost).Err()
+}
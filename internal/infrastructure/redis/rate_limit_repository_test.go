@@ -0,0 +1,87 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	redisRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/redis"
+)
+
+func TestRateLimitRepository_AllowsBurstThenRate(t *testing.T) {
+	server := miniredis.RunT(t)
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	server.SetTime(now)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	repo := redisRepo.NewRateLimitRepository(client)
+	ctx := context.Background()
+
+	// 2 per second with a burst of 3
+	for i := 2; i >= 0; i-- {
+		result, err := repo.TakeRateLimit(ctx, "ratelimit:ip:1", 2, 3, 1)
+		if err != nil || !result.Allowed || result.Remaining != i || result.Limit != 3 {
+			t.Fatalf("Expected the burst to be allowed with %d remaining, got %+v, %v", i, result, err)
+		}
+	}
+	result, err := repo.TakeRateLimit(ctx, "ratelimit:ip:1", 2, 3, 1)
+	if err != nil || result.Allowed || result.Remaining != 0 {
+		t.Fatalf("Expected the request after the burst to be refused, got %+v, %v", result, err)
+	}
+	if result.RetryAfter != 500*time.Millisecond || result.ResetAfter != 1500*time.Millisecond {
+		t.Errorf("Expected a retry in 500ms and a reset in 1.5s, got %+v", result)
+	}
+	if ttl := server.TTL("ratelimit:ip:1"); ttl <= 0 || ttl > 1500*time.Millisecond {
+		t.Errorf("Expected the key to expire once the limit replenished, got %v", ttl)
+	}
+
+	// Other keys have their own limit
+	if result, err := repo.TakeRateLimit(ctx, "ratelimit:ip:2", 2, 3, 3); err != nil || !result.Allowed {
+		t.Errorf("Expected another key to be allowed its whole burst, got %+v, %v", result, err)
+	}
+
+	server.SetTime(now.Add(time.Second))
+	if result, err := repo.TakeRateLimit(ctx, "ratelimit:ip:1", 2, 3, 2); err != nil || !result.Allowed || result.Remaining != 0 {
+		t.Errorf("Expected two requests to be replenished after a second, got %+v, %v", result, err)
+	}
+	// Requests that do not fit are not counted
+	if result, err := repo.TakeRateLimit(ctx, "ratelimit:ip:1", 2, 3, 4); err != nil || result.Allowed {
+		t.Errorf("Expected a cost above the burst to be refused, got %+v, %v", result, err)
+	}
+	server.SetTime(now.Add(1500 * time.Millisecond))
+	if result, err := repo.TakeRateLimit(ctx, "ratelimit:ip:1", 2, 3, 1); err != nil || !result.Allowed {
+		t.Errorf("Expected the refused requests not to be counted, got %+v, %v", result, err)
+	}
+}
+
+func TestRateLimitRepository_ReturnsRequests(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.SetTime(time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC))
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	repo := redisRepo.NewRateLimitRepository(client)
+	ctx := context.Background()
+
+	// 1 per second with a burst of 2, used up
+	if result, err := repo.TakeRateLimit(ctx, "ratelimit:ip:1", 1, 2, 2); err != nil || !result.Allowed {
+		t.Fatalf("Expected the burst to be allowed, got %+v, %v", result, err)
+	}
+
+	if err := repo.ReturnRateLimit(ctx, "ratelimit:ip:1", 1, 2, 1); err != nil {
+		t.Fatalf("Failed to return a request: %v", err)
+	}
+	result, err := repo.TakeRateLimit(ctx, "ratelimit:ip:1", 1, 2, 1)
+	if err != nil || !result.Allowed || result.Remaining != 0 {
+		t.Errorf("Expected the returned request to be allowed again, got %+v, %v", result, err)
+	}
+
+	// Returning every request drops the key
+	if err := repo.ReturnRateLimit(ctx, "ratelimit:ip:1", 1, 2, 2); err != nil {
+		t.Fatalf("Failed to return requests: %v", err)
+	}
+	if server.Exists("ratelimit:ip:1") {
+		t.Error("Expected the fully replenished limit to be dropped")
+	}
+}
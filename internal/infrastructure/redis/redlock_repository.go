@@ -0,0 +1,182 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// defaultDriftFactor is the fraction of the TTL allowed for clock drift between nodes
+	defaultDriftFactor = 0.01
+
+	// defaultNodeTimeout bounds how long a single node may take to answer
+	defaultNodeTimeout = 50 * time.Millisecond
+
+	// driftMargin is added to the drift allowance to cover Redis' expiry precision
+	driftMargin = 2 * time.Millisecond
+)
+
+// releaseScript deletes the key only if it still holds our token
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedlockRepository implements the repository.LockRepository interface using
+// the Redlock algorithm across N independent Redis nodes. A lock is held only
+// when a quorum of nodes accepted it and its validity time is still positive,
+// so losing a minority of nodes does not break debouncing
+type RedlockRepository struct {
+	clients     []redis.UniversalClient
+	quorum      int
+	driftFactor float64
+	nodeTimeout time.Duration
+
+	mu     sync.Mutex
+	tokens map[string]heldLock
+}
+
+// heldLock is the token of a lock this repository acquired and the time
+// its validity runs out, after which the token is no longer worth keeping
+type heldLock struct {
+	token     string
+	expiresAt time.Time
+}
+
+// NewRedlockRepository creates a new Redlock repository over independent Redis nodes
+func NewRedlockRepository(clients []redis.UniversalClient) (*RedlockRepository, error) {
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("redlock requires at least one Redis node")
+	}
+
+	return &RedlockRepository{
+		clients:     clients,
+		quorum:      len(clients)/2 + 1,
+		driftFactor: defaultDriftFactor,
+		nodeTimeout: defaultNodeTimeout,
+		tokens:      make(map[string]heldLock),
+	}, nil
+}
+
+// SetLock attempts to set a lock with the given key and TTL on a quorum of nodes
+func (r *RedlockRepository) SetLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	_, locked, err := r.Acquire(ctx, key, ttl)
+	return locked, err
+}
+
+// Acquire attempts to set the lock on every node and returns the remaining
+// validity time of the lock when it was acquired on a quorum of nodes.
+// It returns an error only when too many nodes failed for a quorum to be possible
+func (r *RedlockRepository) Acquire(ctx context.Context, key string, ttl time.Duration) (time.Duration, bool, error) {
+	token, err := newToken()
+	if err != nil {
+		return 0, false, err
+	}
+
+	start := time.Now()
+	acquired, failed := 0, 0
+	var lastErr error
+	for _, client := range r.clients {
+		ok, err := r.lockNode(ctx, client, key, token, ttl)
+		switch {
+		case err != nil:
+			failed++
+			lastErr = err
+		case ok:
+			acquired++
+		}
+	}
+
+	validity := validityTime(ttl, time.Since(start), r.driftFactor)
+	if acquired >= r.quorum && validity > 0 {
+		now := time.Now()
+		r.mu.Lock()
+		r.pruneExpired(now)
+		r.tokens[key] = heldLock{token: token, expiresAt: now.Add(validity)}
+		r.mu.Unlock()
+		return validity, true, nil
+	}
+
+	// Undo any partial acquisition so the key frees up as soon as possible
+	r.unlockAll(ctx, key, token)
+
+	if failed > len(r.clients)-r.quorum {
+		return 0, false, fmt.Errorf("redlock: %d of %d nodes unavailable: %w", failed, len(r.clients), lastErr)
+	}
+
+	return 0, false, nil
+}
+
+// ReleaseLock releases a lock previously acquired by this repository
+// Returns true if the lock was released on a quorum of nodes
+func (r *RedlockRepository) ReleaseLock(ctx context.Context, key string) (bool, error) {
+	r.mu.Lock()
+	held, ok := r.tokens[key]
+	delete(r.tokens, key)
+	r.mu.Unlock()
+
+	// A lock past its validity may already belong to someone else
+	if !ok || !time.Now().Before(held.expiresAt) {
+		return false, nil
+	}
+
+	return r.unlockAll(ctx, key, held.token) >= r.quorum, nil
+}
+
+// pruneExpired forgets the tokens of locks that were never released and
+// have since expired, so keys that are only locked once do not pile up.
+// The caller must hold r.mu
+func (r *RedlockRepository) pruneExpired(now time.Time) {
+	for key, held := range r.tokens {
+		if !now.Before(held.expiresAt) {
+			delete(r.tokens, key)
+		}
+	}
+}
+
+// lockNode sets the key on a single node, bounded by the per-node timeout
+func (r *RedlockRepository) lockNode(ctx context.Context, client redis.UniversalClient, key, token string, ttl time.Duration) (bool, error) {
+	nodeCtx, cancel := context.WithTimeout(ctx, r.nodeTimeout)
+	defer cancel()
+
+	return client.SetNX(nodeCtx, key, token, ttl).Result()
+}
+
+// unlockAll removes the key from every node that still holds our token
+// and returns the number of nodes it was removed from
+func (r *RedlockRepository) unlockAll(ctx context.Context, key, token string) int {
+	released := 0
+	for _, client := range r.clients {
+		nodeCtx, cancel := context.WithTimeout(ctx, r.nodeTimeout)
+		result, err := releaseScript.Run(nodeCtx, client, []string{key}, token).Int()
+		cancel()
+		if err == nil && result == 1 {
+			released++
+		}
+	}
+	return released
+}
+
+// validityTime returns how long a lock acquired with the given TTL remains
+// safely held after acquisition took elapsed, allowing for clock drift
+func validityTime(ttl, elapsed time.Duration, driftFactor float64) time.Duration {
+	drift := time.Duration(float64(ttl)*driftFactor) + driftMargin
+	return ttl - elapsed - drift
+}
+
+// newToken returns a random value identifying one lock acquisition
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
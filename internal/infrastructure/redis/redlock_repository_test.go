@@ -0,0 +1,128 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	redisRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/redis"
+)
+
+// startNodes starts n independent in-memory Redis servers
+func startNodes(t *testing.T, n int) ([]*miniredis.Miniredis, []redis.UniversalClient) {
+	servers := make([]*miniredis.Miniredis, n)
+	clients := make([]redis.UniversalClient, n)
+	for i := range servers {
+		servers[i] = miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: servers[i].Addr()})
+		t.Cleanup(func() { client.Close() })
+		clients[i] = client
+	}
+	return servers, clients
+}
+
+func TestRedlock_AcquiresOnQuorum(t *testing.T) {
+	servers, clients := startNodes(t, 3)
+	servers[2].Close()
+
+	locks, err := redisRepo.NewRedlockRepository(clients)
+	if err != nil {
+		t.Fatalf("Failed to create redlock: %v", err)
+	}
+
+	ctx := context.Background()
+	validity, locked, err := locks.Acquire(ctx, "report:lock:survey-123", 10*time.Second)
+	if err != nil || !locked {
+		t.Fatalf("Expected lock on 2 of 3 nodes, got locked=%v err=%v", locked, err)
+	}
+	if validity <= 0 || validity > 10*time.Second {
+		t.Errorf("Expected validity within the TTL, got %v", validity)
+	}
+
+	if locked, _ := locks.SetLock(ctx, "report:lock:survey-123", 10*time.Second); locked {
+		t.Errorf("Expected second SetLock to fail while the lock is held")
+	}
+
+	released, err := locks.ReleaseLock(ctx, "report:lock:survey-123")
+	if err != nil || !released {
+		t.Errorf("Expected release on quorum, got released=%v err=%v", released, err)
+	}
+	if servers[0].Exists("report:lock:survey-123") || servers[1].Exists("report:lock:survey-123") {
+		t.Errorf("Expected the key to be removed from every live node")
+	}
+}
+
+func TestRedlock_MinorityHeldElsewhereIsRolledBack(t *testing.T) {
+	servers, clients := startNodes(t, 3)
+
+	// Another holder owns the key on two nodes, so we can only reach one
+	servers[0].Set("report:lock:survey-123", "other")
+	servers[1].Set("report:lock:survey-123", "other")
+
+	locks, err := redisRepo.NewRedlockRepository(clients)
+	if err != nil {
+		t.Fatalf("Failed to create redlock: %v", err)
+	}
+
+	locked, err := locks.SetLock(context.Background(), "report:lock:survey-123", 10*time.Second)
+	if err != nil || locked {
+		t.Fatalf("Expected lock to be refused without error, got locked=%v err=%v", locked, err)
+	}
+	if servers[2].Exists("report:lock:survey-123") {
+		t.Errorf("Expected the partial acquisition on the third node to be rolled back")
+	}
+	if got, _ := servers[0].Get("report:lock:survey-123"); got != "other" {
+		t.Errorf("Expected the other holder's lock to be untouched, got %q", got)
+	}
+}
+
+func TestRedlock_ErrorsWhenQuorumUnreachable(t *testing.T) {
+	servers, clients := startNodes(t, 3)
+	servers[1].Close()
+	servers[2].Close()
+
+	locks, err := redisRepo.NewRedlockRepository(clients)
+	if err != nil {
+		t.Fatalf("Failed to create redlock: %v", err)
+	}
+
+	if _, err := locks.SetLock(context.Background(), "report:lock:survey-123", 10*time.Second); err == nil {
+		t.Errorf("Expected an error when a majority of nodes is down")
+	}
+}
+
+func TestRedlock_ForgetsExpiredLocks(t *testing.T) {
+	servers, clients := startNodes(t, 3)
+
+	locks, err := redisRepo.NewRedlockRepository(clients)
+	if err != nil {
+		t.Fatalf("Failed to create redlock: %v", err)
+	}
+
+	ctx := context.Background()
+	if locked, err := locks.SetLock(ctx, "report:lock:survey-123", 100*time.Millisecond); err != nil || !locked {
+		t.Fatalf("Failed to acquire lock: locked=%v err=%v", locked, err)
+	}
+
+	// Let the lock expire and another holder take the key
+	time.Sleep(150 * time.Millisecond)
+	for _, server := range servers {
+		server.FastForward(150 * time.Millisecond)
+		server.Set("report:lock:survey-123", "other")
+	}
+	if locked, err := locks.SetLock(ctx, "report:lock:survey-456", 10*time.Second); err != nil || !locked {
+		t.Fatalf("Failed to acquire another lock: locked=%v err=%v", locked, err)
+	}
+
+	released, err := locks.ReleaseLock(ctx, "report:lock:survey-123")
+	if err != nil || released {
+		t.Errorf("Expected the expired lock not to be released, got released=%v err=%v", released, err)
+	}
+	for i, server := range servers {
+		if got, _ := server.Get("report:lock:survey-123"); got != "other" {
+			t.Errorf("Expected node %d to keep the other holder's lock, got %q", i, got)
+		}
+	}
+}
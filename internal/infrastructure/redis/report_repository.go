@@ -0,0 +1,118 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+const (
+	// reportKeyPrefix is the prefix for the Redis key holding a survey's latest report
+	reportKeyPrefix = "survey:report:"
+
+	// watermarkKeyPrefix is the prefix for the Redis key holding a survey's report watermark
+	watermarkKeyPrefix = "survey:watermark:"
+
+	// aggregateKeyPrefix is the prefix for the Redis key holding a survey's partial aggregates
+	aggregateKeyPrefix = "survey:aggregate:"
+)
+
+// ReportRepository implements the repository.ReportRepository interface using Redis
+type ReportRepository struct {
+	client redis.UniversalClient
+}
+
+// NewReportRepository creates a new Redis report repository
+func NewReportRepository(client redis.UniversalClient) repository.ReportRepository {
+	return &ReportRepository{
+		client: client,
+	}
+}
+
+// SaveReport stores a report, replacing any previous report for the same survey
+func (r *ReportRepository) SaveReport(ctx context.Context, report entity.Report) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	return r.client.Set(ctx, reportKeyPrefix+entity.SurveyKey(ctx, report.SurveyID), data, 0).Err()
+}
+
+// GetReport returns the latest report for the given survey ID
+func (r *ReportRepository) GetReport(ctx context.Context, surveyID string) (*entity.Report, error) {
+	data, err := r.client.Get(ctx, reportKeyPrefix+entity.SurveyKey(ctx, surveyID)).Bytes()
+	if err == redis.Nil {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var report entity.Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// SaveWatermark records how far the latest completed report for a survey reaches
+func (r *ReportRepository) SaveWatermark(ctx context.Context, watermark entity.ReportWatermark) error {
+	data, err := json.Marshal(watermark)
+	if err != nil {
+		return fmt.Errorf("failed to marshal watermark: %w", err)
+	}
+
+	return r.client.Set(ctx, watermarkKeyPrefix+entity.SurveyKey(ctx, watermark.SurveyID), data, 0).Err()
+}
+
+// GetWatermark returns the watermark of the latest completed report for the given survey ID
+func (r *ReportRepository) GetWatermark(ctx context.Context, surveyID string) (*entity.ReportWatermark, error) {
+	data, err := r.client.Get(ctx, watermarkKeyPrefix+entity.SurveyKey(ctx, surveyID)).Bytes()
+	if err == redis.Nil {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var watermark entity.ReportWatermark
+	if err := json.Unmarshal(data, &watermark); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal watermark: %w", err)
+	}
+
+	return &watermark, nil
+}
+
+// SaveAggregate stores the partial aggregates of a survey
+func (r *ReportRepository) SaveAggregate(ctx context.Context, aggregate entity.ReportAggregate) error {
+	data, err := json.Marshal(aggregate)
+	if err != nil {
+		return fmt.Errorf("failed to marshal aggregate: %w", err)
+	}
+
+	return r.client.Set(ctx, aggregateKeyPrefix+entity.SurveyKey(ctx, aggregate.SurveyID), data, 0).Err()
+}
+
+// GetAggregate returns the partial aggregates of the given survey ID
+func (r *ReportRepository) GetAggregate(ctx context.Context, surveyID string) (*entity.ReportAggregate, error) {
+	data, err := r.client.Get(ctx, aggregateKeyPrefix+entity.SurveyKey(ctx, surveyID)).Bytes()
+	if err == redis.Nil {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var aggregate entity.ReportAggregate
+	if err := json.Unmarshal(data, &aggregate); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal aggregate: %w", err)
+	}
+
+	return &aggregate, nil
+}
@@ -0,0 +1,104 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+const (
+	// responseKeyPrefix is the prefix for the Redis list holding a survey's responses
+	responseKeyPrefix = "survey:responses:"
+
+	// scanPageSize is the number of responses read per round trip while scanning
+	scanPageSize = 500
+)
+
+// ResponseRepository implements the repository.ResponseRepository interface using Redis
+type ResponseRepository struct {
+	client redis.UniversalClient
+}
+
+// NewResponseRepository creates a new Redis response repository
+func NewResponseRepository(client redis.UniversalClient) repository.ResponseRepository {
+	return &ResponseRepository{
+		client: client,
+	}
+}
+
+// SaveResponse appends a survey response to the survey's response list
+func (r *ResponseRepository) SaveResponse(ctx context.Context, response entity.SurveyResponse) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return r.client.RPush(ctx, responseKeyPrefix+entity.SurveyKey(ctx, response.SurveyID), data).Err()
+}
+
+// SaveResponses appends several survey responses in a single pipeline
+func (r *ResponseRepository) SaveResponses(ctx context.Context, responses []entity.SurveyResponse) error {
+	pipe := r.client.Pipeline()
+	for _, response := range responses {
+		data, err := json.Marshal(response)
+		if err != nil {
+			return fmt.Errorf("failed to marshal response: %w", err)
+		}
+		pipe.RPush(ctx, responseKeyPrefix+entity.SurveyKey(ctx, response.SurveyID), data)
+	}
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// ListResponses returns all stored responses for the given survey ID
+func (r *ResponseRepository) ListResponses(ctx context.Context, surveyID string) ([]entity.SurveyResponse, error) {
+	values, err := r.client.LRange(ctx, responseKeyPrefix+entity.SurveyKey(ctx, surveyID), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]entity.SurveyResponse, 0, len(values))
+	for _, value := range values {
+		var response entity.SurveyResponse
+		if err := json.Unmarshal([]byte(value), &response); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+// ScanResponses calls fn for every stored response for the given survey ID after offset, page by page
+func (r *ResponseRepository) ScanResponses(ctx context.Context, surveyID string, offset int64, fn func(entity.SurveyResponse) error) error {
+	for start := offset; ; start += scanPageSize {
+		values, err := r.client.LRange(ctx, responseKeyPrefix+entity.SurveyKey(ctx, surveyID), start, start+scanPageSize-1).Result()
+		if err != nil {
+			return err
+		}
+
+		for _, value := range values {
+			var response entity.SurveyResponse
+			if err := json.Unmarshal([]byte(value), &response); err != nil {
+				return fmt.Errorf("failed to unmarshal response: %w", err)
+			}
+			if err := fn(response); err != nil {
+				return err
+			}
+		}
+
+		if len(values) < scanPageSize {
+			return nil
+		}
+	}
+}
+
+// CountResponses returns the length of the survey's response list
+func (r *ResponseRepository) CountResponses(ctx context.Context, surveyID string) (int64, error) {
+	return r.client.LLen(ctx, responseKeyPrefix+entity.SurveyKey(ctx, surveyID)).Result()
+}
@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// RuntimeConfigRepository implements the repository.RuntimeConfigRepository interface using Redis
+// The overrides are a string key, and changes are announced on the pub/sub
+// channel named after the key with a ":changed" suffix
+type RuntimeConfigRepository struct {
+	client  redis.UniversalClient
+	key     string
+	channel string
+}
+
+// NewRuntimeConfigRepository creates a new Redis runtime config repository reading the given key
+func NewRuntimeConfigRepository(client redis.UniversalClient, key string) repository.RuntimeConfigRepository {
+	return &RuntimeConfigRepository{
+		client:  client,
+		key:     key,
+		channel: RuntimeConfigChannel(key),
+	}
+}
+
+// RuntimeConfigChannel returns the pub/sub channel changes of the overrides at key are announced on
+func RuntimeConfigChannel(key string) string {
+	return key + ":changed"
+}
+
+// GetRuntimeConfig reads the overrides document
+func (r *RuntimeConfigRepository) GetRuntimeConfig(ctx context.Context) ([]byte, error) {
+	doc, err := r.client.Get(ctx, r.key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get runtime config: %w", err)
+	}
+
+	return doc, nil
+}
+
+// SubscribeRuntimeConfig subscribes to change announcements until ctx is canceled
+// Announcements made while the client reconnects are missed, so callers
+// should also read the overrides again from time to time
+func (r *RuntimeConfigRepository) SubscribeRuntimeConfig(ctx context.Context, callback func()) error {
+	pubsub := r.client.Subscribe(ctx, r.channel)
+
+	// Wait for the subscription to be confirmed so no change announced after this returns is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return fmt.Errorf("failed to subscribe to runtime config changes: %w", err)
+	}
+
+	go func() {
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-messages:
+				if !ok {
+					return
+				}
+				callback()
+			}
+		}
+	}()
+
+	return nil
+}
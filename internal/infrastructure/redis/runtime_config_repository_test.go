@@ -0,0 +1,47 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	redisRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/redis"
+)
+
+func TestRuntimeConfigRepository_ReadsAndAnnouncesOverrides(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	repo := redisRepo.NewRuntimeConfigRepository(client, "config:runtime")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if _, err := repo.GetRuntimeConfig(ctx); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound before overrides are set, got %v", err)
+	}
+
+	changed := make(chan struct{}, 1)
+	if err := repo.SubscribeRuntimeConfig(ctx, func() { changed <- struct{}{} }); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	// An operator sets the key and announces the change, as documented
+	server.Set("config:runtime", `{"lock": {"ttl": "1m"}}`)
+	server.Publish(redisRepo.RuntimeConfigChannel("config:runtime"), "")
+
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the change to be announced")
+	}
+
+	doc, err := repo.GetRuntimeConfig(ctx)
+	if err != nil || string(doc) != `{"lock": {"ttl": "1m"}}` {
+		t.Errorf("Expected the overrides to be read, got %q and %v", doc, err)
+	}
+}
@@ -0,0 +1,183 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// The schedule keys share the {schedules} hash tag so they live in the same
+// Redis Cluster slot and can be updated in a single transaction
+const (
+	// schedulesKey is the Redis hash holding every schedule by ID
+	schedulesKey = "{schedules}:all"
+
+	// dueSchedulesKey is the Redis sorted set of schedule IDs scored by their next run time
+	dueSchedulesKey = "{schedules}:due"
+
+	// surveySchedulesKeyPrefix is the prefix for the Redis set holding a survey's schedule IDs
+	surveySchedulesKeyPrefix = "{schedules}:survey:"
+)
+
+// updateScheduleScript replaces the schedule in the hash and moves it in the due
+// set, but only if the hash still holds it
+var updateScheduleScript = redis.NewScript(`
+if redis.call("HEXISTS", KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+if tonumber(ARGV[3]) > 0 then
+	redis.call("ZADD", KEYS[2], ARGV[3], ARGV[1])
+else
+	redis.call("ZREM", KEYS[2], ARGV[1])
+end
+return 1
+`)
+
+// ScheduleRepository implements the repository.ScheduleRepository interface using Redis
+type ScheduleRepository struct {
+	client redis.UniversalClient
+}
+
+// NewScheduleRepository creates a new Redis schedule repository
+func NewScheduleRepository(client redis.UniversalClient) repository.ScheduleRepository {
+	return &ScheduleRepository{
+		client: client,
+	}
+}
+
+// SaveSchedule stores a schedule and indexes it by survey and next run time
+func (r *ScheduleRepository) SaveSchedule(ctx context.Context, schedule entity.Schedule) error {
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedule: %w", err)
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, schedulesKey, schedule.ID, data)
+	pipe.SAdd(ctx, surveySchedulesKeyPrefix+entity.SurveyKey(ctx, schedule.SurveyID), schedule.ID)
+	if schedule.NextRunAt > 0 {
+		pipe.ZAdd(ctx, dueSchedulesKey, &redis.Z{Score: float64(schedule.NextRunAt), Member: schedule.ID})
+	} else {
+		pipe.ZRem(ctx, dueSchedulesKey, schedule.ID)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// UpdateSchedule replaces a stored schedule and its due entry in one script
+func (r *ScheduleRepository) UpdateSchedule(ctx context.Context, schedule entity.Schedule) error {
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedule: %w", err)
+	}
+
+	updated, err := updateScheduleScript.Run(ctx, r.client, []string{schedulesKey, dueSchedulesKey},
+		schedule.ID, data, schedule.NextRunAt).Int()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetSchedule returns the schedule with the given ID for the given survey of the context's tenant
+func (r *ScheduleRepository) GetSchedule(ctx context.Context, surveyID, scheduleID string) (*entity.Schedule, error) {
+	data, err := r.client.HGet(ctx, schedulesKey, scheduleID).Bytes()
+	if err == redis.Nil {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule entity.Schedule
+	if err := json.Unmarshal(data, &schedule); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schedule: %w", err)
+	}
+	if schedule.SurveyKey() != entity.SurveyKey(ctx, surveyID) {
+		return nil, repository.ErrNotFound
+	}
+
+	return &schedule, nil
+}
+
+// ListSchedules returns the schedules of the given survey ordered by creation time
+func (r *ScheduleRepository) ListSchedules(ctx context.Context, surveyID string) ([]entity.Schedule, error) {
+	ids, err := r.client.SMembers(ctx, surveySchedulesKeyPrefix+entity.SurveyKey(ctx, surveyID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := r.getSchedules(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].CreatedAt < schedules[j].CreatedAt })
+	return schedules, nil
+}
+
+// ListDueSchedules returns the schedules whose next run is at or before now
+func (r *ScheduleRepository) ListDueSchedules(ctx context.Context, now int64) ([]entity.Schedule, error) {
+	ids, err := r.client.ZRangeByScore(ctx, dueSchedulesKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now, 10),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getSchedules(ctx, ids)
+}
+
+// DeleteSchedule removes the schedule and its index entries
+func (r *ScheduleRepository) DeleteSchedule(ctx context.Context, surveyID, scheduleID string) error {
+	if _, err := r.GetSchedule(ctx, surveyID, scheduleID); err != nil {
+		return err
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.HDel(ctx, schedulesKey, scheduleID)
+	pipe.SRem(ctx, surveySchedulesKeyPrefix+entity.SurveyKey(ctx, surveyID), scheduleID)
+	pipe.ZRem(ctx, dueSchedulesKey, scheduleID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// getSchedules returns the schedules with the given IDs in the same order,
+// skipping IDs whose schedule was deleted in the meantime
+func (r *ScheduleRepository) getSchedules(ctx context.Context, ids []string) ([]entity.Schedule, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	values, err := r.client.HMGet(ctx, schedulesKey, ids...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]entity.Schedule, 0, len(values))
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var schedule entity.Schedule
+		if err := json.Unmarshal([]byte(data), &schedule); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal schedule: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, nil
+}
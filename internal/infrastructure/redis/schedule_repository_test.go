@@ -0,0 +1,132 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	redisRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/redis"
+)
+
+func TestScheduleRepository_ListsDueSchedulesInRunOrder(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	repo := redisRepo.NewScheduleRepository(client)
+	ctx := context.Background()
+
+	schedules := []entity.Schedule{
+		{ID: "late", SurveyID: "survey-123", Cron: "@daily", NextRunAt: 300, CreatedAt: 1},
+		{ID: "early", SurveyID: "survey-456", Cron: "@hourly", NextRunAt: 100, CreatedAt: 2},
+		{ID: "fired", SurveyID: "survey-123", At: 50, NextRunAt: 0, CreatedAt: 3},
+	}
+	for _, schedule := range schedules {
+		if err := repo.SaveSchedule(ctx, schedule); err != nil {
+			t.Fatalf("Failed to save schedule: %v", err)
+		}
+	}
+
+	due, err := repo.ListDueSchedules(ctx, 300)
+	if err != nil {
+		t.Fatalf("Failed to list due schedules: %v", err)
+	}
+	if len(due) != 2 || due[0].ID != "early" || due[1].ID != "late" {
+		t.Errorf("Expected early then late to be due, got %+v", due)
+	}
+
+	// Advancing a schedule past now takes it off the due list
+	schedules[1].NextRunAt = 400
+	if err := repo.UpdateSchedule(ctx, schedules[1]); err != nil {
+		t.Fatalf("Failed to update schedule: %v", err)
+	}
+	if due, _ := repo.ListDueSchedules(ctx, 300); len(due) != 1 || due[0].ID != "late" {
+		t.Errorf("Expected only late to be due, got %+v", due)
+	}
+
+	listed, err := repo.ListSchedules(ctx, "survey-123")
+	if err != nil {
+		t.Fatalf("Failed to list schedules: %v", err)
+	}
+	if len(listed) != 2 || listed[0].ID != "late" || listed[1].ID != "fired" {
+		t.Errorf("Expected the survey's schedules oldest first, got %+v", listed)
+	}
+
+	if err := repo.DeleteSchedule(ctx, "survey-456", "late"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting another survey's schedule, got %v", err)
+	}
+	if err := repo.DeleteSchedule(ctx, "survey-123", "late"); err != nil {
+		t.Fatalf("Failed to delete schedule: %v", err)
+	}
+	if due, _ := repo.ListDueSchedules(ctx, 300); len(due) != 0 {
+		t.Errorf("Expected no due schedules after deletion, got %+v", due)
+	}
+
+	// A run that finishes after the deletion must not bring the schedule back
+	if err := repo.UpdateSchedule(ctx, schedules[0]); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound updating a deleted schedule, got %v", err)
+	}
+	if _, err := repo.GetSchedule(ctx, "survey-123", "late"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected the deleted schedule to stay deleted, got %v", err)
+	}
+	if due, _ := repo.ListDueSchedules(ctx, 300); len(due) != 0 {
+		t.Errorf("Expected no due schedules after the update, got %+v", due)
+	}
+}
+
+func TestLeaseRepository_RenewsAndExpires(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	repo := redisRepo.NewLeaseRepository(client)
+	ctx := context.Background()
+
+	acquire := func(holder string) bool {
+		t.Helper()
+		acquired, err := repo.AcquireLease(ctx, "leader", holder, 10*time.Second)
+		if err != nil {
+			t.Fatalf("Failed to acquire lease: %v", err)
+		}
+		return acquired
+	}
+
+	if !acquire("a") {
+		t.Fatal("Expected a to acquire the free lease")
+	}
+	if acquire("b") {
+		t.Fatal("Expected b not to acquire a lease held by a")
+	}
+
+	// Renewing pushes the expiry out again
+	server.FastForward(8 * time.Second)
+	if !acquire("a") {
+		t.Fatal("Expected a to renew its lease")
+	}
+	server.FastForward(8 * time.Second)
+	if acquire("b") {
+		t.Fatal("Expected the renewed lease to still be held by a")
+	}
+
+	server.FastForward(3 * time.Second)
+	if !acquire("b") {
+		t.Fatal("Expected b to acquire the expired lease")
+	}
+
+	// Releasing as a former holder leaves the new holder's lease alone
+	if err := repo.ReleaseLease(ctx, "leader", "a"); err != nil {
+		t.Fatalf("Failed to release lease: %v", err)
+	}
+	if acquire("a") {
+		t.Fatal("Expected b to keep the lease")
+	}
+	if err := repo.ReleaseLease(ctx, "leader", "b"); err != nil {
+		t.Fatalf("Failed to release lease: %v", err)
+	}
+	if !acquire("a") {
+		t.Fatal("Expected a to acquire the released lease")
+	}
+}
@@ -0,0 +1,178 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+const (
+	// migrationScanCount is how many keys one SCAN call of the migration asks for
+	migrationScanCount = 1000
+
+	// migrationLockKey is held by the instance migrating, so instances
+	// starting together never copy the same key twice
+	migrationLockKey = "migration:default_tenant"
+
+	// migrationLockTTL bounds how long a crashed migration keeps others out
+	migrationLockTTL = 5 * time.Minute
+)
+
+// surveyKeyPrefixes lists the prefix of every key holding data of one survey,
+// followed by the survey's storage key, see entity.TenantScopedID
+var surveyKeyPrefixes = []string{
+	responseKeyPrefix,
+	reportKeyPrefix,
+	watermarkKeyPrefix,
+	aggregateKeyPrefix,
+	jobKeyPrefix,
+	webhookKeyPrefix,
+	deliveryLogKeyPrefix,
+	surveySchedulesKeyPrefix,
+}
+
+// MigrateDefaultTenant moves the data of default tenant surveys stored before
+// the default tenant had a namespace, under bare survey IDs, to keys in its
+// namespace, and returns how many keys it moved. Data already stored in the
+// namespace is kept: moved list entries go before it, and hash fields and
+// values it already has are not overwritten. Running it again moves nothing
+// twice, and while one instance migrates, others move nothing
+// Keys are copied rather than renamed, as a key and its new name may live on
+// different nodes of a cluster
+func MigrateDefaultTenant(ctx context.Context, client redis.UniversalClient) (int, error) {
+	locked, err := client.SetNX(ctx, migrationLockKey, 1, migrationLockTTL).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock migration: %w", err)
+	}
+	if !locked {
+		return 0, nil
+	}
+	defer client.Del(ctx, migrationLockKey)
+
+	moved := 0
+	for _, prefix := range surveyKeyPrefixes {
+		keys, err := scanKeys(ctx, client, prefix+"*")
+		if err != nil {
+			return moved, fmt.Errorf("failed to scan %s keys: %w", prefix, err)
+		}
+
+		for _, key := range keys {
+			surveyKey := strings.TrimPrefix(key, prefix)
+			if !entity.LegacySurveyKey(surveyKey) {
+				continue
+			}
+			ok, err := moveKey(ctx, client, key, prefix+entity.TenantScopedID(entity.DefaultTenant, surveyKey))
+			if err != nil {
+				return moved, fmt.Errorf("failed to move %s: %w", key, err)
+			}
+			if ok {
+				moved++
+			}
+		}
+	}
+	return moved, nil
+}
+
+// scanKeys returns every key matching pattern, on every master of a cluster
+func scanKeys(ctx context.Context, client redis.UniversalClient, pattern string) ([]string, error) {
+	cluster, ok := client.(*redis.ClusterClient)
+	if !ok {
+		return scanNode(ctx, client, pattern)
+	}
+
+	var mu sync.Mutex
+	var keys []string
+	err := cluster.ForEachMaster(ctx, func(ctx context.Context, master *redis.Client) error {
+		found, err := scanNode(ctx, master, pattern)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		keys = append(keys, found...)
+		mu.Unlock()
+		return nil
+	})
+	return keys, err
+}
+
+// scanNode returns every key matching pattern on one Redis server
+func scanNode(ctx context.Context, client redis.Cmdable, pattern string) ([]string, error) {
+	var keys []string
+	iter := client.Scan(ctx, 0, pattern, migrationScanCount).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
+}
+
+// moveKey merges the value of src into dst, keeping its expiry, and deletes
+// src. It reports false when src no longer exists, moved by another instance
+func moveKey(ctx context.Context, client redis.UniversalClient, src, dst string) (bool, error) {
+	kind, err := client.Type(ctx, src).Result()
+	if err != nil {
+		return false, err
+	}
+	if kind == "none" {
+		return false, nil
+	}
+
+	if err := mergeKey(ctx, client, kind, src, dst); err != nil {
+		return false, err
+	}
+	if ttl, err := client.PTTL(ctx, src).Result(); err == nil && ttl > 0 {
+		if err := client.PExpire(ctx, dst, ttl).Err(); err != nil {
+			return false, err
+		}
+	}
+	if err := client.Del(ctx, src).Err(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// mergeKey merges the value of src, of the given Redis type, into dst
+func mergeKey(ctx context.Context, client redis.UniversalClient, kind, src, dst string) error {
+	switch kind {
+	case "string":
+		value, err := client.Get(ctx, src).Result()
+		if err != nil {
+			return err
+		}
+		return client.SetNX(ctx, dst, value, 0).Err()
+	case "list":
+		values, err := client.LRange(ctx, src, 0, -1).Result()
+		if err != nil || len(values) == 0 {
+			return err
+		}
+		// Push in reverse, so the moved entries keep their order ahead of any in dst
+		head := make([]interface{}, len(values))
+		for i, value := range values {
+			head[len(values)-1-i] = value
+		}
+		return client.LPush(ctx, dst, head...).Err()
+	case "hash":
+		fields, err := client.HGetAll(ctx, src).Result()
+		if err != nil {
+			return err
+		}
+		pipe := client.Pipeline()
+		for field, value := range fields {
+			pipe.HSetNX(ctx, dst, field, value)
+		}
+		_, err = pipe.Exec(ctx)
+		return err
+	case "set":
+		members, err := client.SMembers(ctx, src).Result()
+		if err != nil || len(members) == 0 {
+			return err
+		}
+		return client.SAdd(ctx, dst, members).Err()
+	default:
+		return fmt.Errorf("unexpected %s key", kind)
+	}
+}
@@ -0,0 +1,80 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	redisRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/redis"
+)
+
+func TestMigrateDefaultTenant_MovesBareSurveyKeys(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	ctx := context.Background()
+
+	// Data stored before the default tenant had a namespace, next to a response
+	// already stored in the namespace and data of another tenant
+	server.RPush("survey:responses:survey-123", `{"id":"1","survey_id":"survey-123"}`, `{"id":"2","survey_id":"survey-123"}`)
+	server.RPush("survey:responses:default:survey-123", `{"id":"3","survey_id":"survey-123"}`)
+	server.Set("survey:report:survey-123", `{"survey_id":"survey-123","response_count":2}`)
+	server.HSet("survey:jobs:survey-123", "job-1", `{"id":"job-1","survey_id":"survey-123"}`)
+	server.SetTTL("survey:jobs:survey-123", time.Hour)
+	server.SAdd("{schedules}:survey:survey-123", "schedule-1")
+	server.Set("survey:report:acme:survey-123", `{"survey_id":"survey-123","response_count":7}`)
+
+	moved, err := redisRepo.MigrateDefaultTenant(ctx, client)
+	if err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if moved != 4 {
+		t.Errorf("Expected 4 keys to be moved, got %d", moved)
+	}
+
+	responses, err := redisRepo.NewResponseRepository(client).ListResponses(ctx, "survey-123")
+	if err != nil {
+		t.Fatalf("Failed to get responses: %v", err)
+	}
+	if len(responses) != 3 || responses[0].ID != "1" || responses[1].ID != "2" || responses[2].ID != "3" {
+		t.Errorf("Expected responses 1, 2 and 3 in order, got %+v", responses)
+	}
+
+	report, err := redisRepo.NewReportRepository(client).GetReport(ctx, "survey-123")
+	if err != nil {
+		t.Fatalf("Failed to get report: %v", err)
+	}
+	if report.ResponseCount != 2 {
+		t.Errorf("Expected the default tenant's report, got %+v", report)
+	}
+	acme, err := redisRepo.NewReportRepository(client).GetReport(entity.WithTenant(ctx, "acme"), "survey-123")
+	if err != nil {
+		t.Fatalf("Failed to get report of acme: %v", err)
+	}
+	if acme.ResponseCount != 7 {
+		t.Errorf("Expected acme's report to be left alone, got %+v", acme)
+	}
+
+	if ttl := server.TTL("survey:jobs:default:survey-123"); ttl != time.Hour {
+		t.Errorf("Expected the job records to keep their expiry, got %v", ttl)
+	}
+	if members, _ := server.SMembers("{schedules}:survey:default:survey-123"); len(members) != 1 {
+		t.Errorf("Expected the schedule index to be moved, got %v", members)
+	}
+	for _, key := range []string{"survey:responses:survey-123", "survey:report:survey-123", "survey:jobs:survey-123", "{schedules}:survey:survey-123"} {
+		if server.Exists(key) {
+			t.Errorf("Expected %s to be removed", key)
+		}
+	}
+
+	moved, err = redisRepo.MigrateDefaultTenant(ctx, client)
+	if err != nil {
+		t.Fatalf("Failed to migrate again: %v", err)
+	}
+	if moved != 0 {
+		t.Errorf("Expected nothing to be moved twice, got %d keys", moved)
+	}
+}
@@ -0,0 +1,130 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+const (
+	// webhookKeyPrefix is the prefix for the Redis hash holding a survey's webhooks
+	webhookKeyPrefix = "survey:webhooks:"
+
+	// deliveryLogKeyPrefix is the prefix for the Redis list holding a survey's delivery log
+	deliveryLogKeyPrefix = "survey:webhook_deliveries:"
+
+	// deliveryLogSize is the number of delivery attempts kept per survey
+	deliveryLogSize = 1000
+)
+
+// WebhookRepository implements the repository.WebhookRepository interface using Redis
+type WebhookRepository struct {
+	client redis.UniversalClient
+}
+
+// NewWebhookRepository creates a new Redis webhook repository
+func NewWebhookRepository(client redis.UniversalClient) repository.WebhookRepository {
+	return &WebhookRepository{
+		client: client,
+	}
+}
+
+// SaveWebhook stores a webhook in the survey's webhook hash
+func (r *WebhookRepository) SaveWebhook(ctx context.Context, webhook entity.Webhook) error {
+	data, err := json.Marshal(webhook)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook: %w", err)
+	}
+
+	return r.client.HSet(ctx, webhookKeyPrefix+entity.SurveyKey(ctx, webhook.SurveyID), webhook.ID, data).Err()
+}
+
+// GetWebhook returns the webhook with the given ID registered for the given survey
+func (r *WebhookRepository) GetWebhook(ctx context.Context, surveyID, webhookID string) (*entity.Webhook, error) {
+	data, err := r.client.HGet(ctx, webhookKeyPrefix+entity.SurveyKey(ctx, surveyID), webhookID).Bytes()
+	if err == redis.Nil {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var webhook entity.Webhook
+	if err := json.Unmarshal(data, &webhook); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+// ListWebhooks returns the webhooks registered for the given survey ordered by creation time
+func (r *WebhookRepository) ListWebhooks(ctx context.Context, surveyID string) ([]entity.Webhook, error) {
+	values, err := r.client.HGetAll(ctx, webhookKeyPrefix+entity.SurveyKey(ctx, surveyID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := make([]entity.Webhook, 0, len(values))
+	for _, value := range values {
+		var webhook entity.Webhook
+		if err := json.Unmarshal([]byte(value), &webhook); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal webhook: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].CreatedAt < webhooks[j].CreatedAt })
+	return webhooks, nil
+}
+
+// DeleteWebhook removes the webhook from the survey's webhook hash
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, surveyID, webhookID string) error {
+	removed, err := r.client.HDel(ctx, webhookKeyPrefix+entity.SurveyKey(ctx, surveyID), webhookID).Result()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// SaveDeliveryAttempt appends an attempt to the survey's delivery log list,
+// trimming it to the most recent deliveryLogSize attempts
+func (r *WebhookRepository) SaveDeliveryAttempt(ctx context.Context, attempt entity.WebhookDeliveryAttempt) error {
+	data, err := json.Marshal(attempt)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delivery attempt: %w", err)
+	}
+
+	key := deliveryLogKeyPrefix + entity.SurveyKey(ctx, attempt.SurveyID)
+	pipe := r.client.TxPipeline()
+	pipe.RPush(ctx, key, data)
+	pipe.LTrim(ctx, key, -deliveryLogSize, -1)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// ListDeliveryAttempts returns the survey's delivery log, oldest first
+func (r *WebhookRepository) ListDeliveryAttempts(ctx context.Context, surveyID string) ([]entity.WebhookDeliveryAttempt, error) {
+	values, err := r.client.LRange(ctx, deliveryLogKeyPrefix+entity.SurveyKey(ctx, surveyID), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := make([]entity.WebhookDeliveryAttempt, 0, len(values))
+	for _, value := range values {
+		var attempt entity.WebhookDeliveryAttempt
+		if err := json.Unmarshal([]byte(value), &attempt); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal delivery attempt: %w", err)
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, nil
+}
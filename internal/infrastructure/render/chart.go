@@ -0,0 +1,54 @@
+package render
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+const (
+	// Bar chart geometry in SVG user units
+	chartLabelWidth = 160
+	chartBarWidth   = 320
+	chartCountWidth = 60
+	chartRowHeight  = 24
+	chartBarHeight  = 16
+)
+
+// barChart renders the bars as a horizontal SVG bar chart
+func barChart(bars []Bar) template.HTML {
+	if len(bars) == 0 {
+		return ""
+	}
+
+	width := chartLabelWidth + chartBarWidth + chartCountWidth
+	height := len(bars) * chartRowHeight
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" class="chart" width="%d" height="%d" viewBox="0 0 %d %d" role="img">`,
+		width, height, width, height)
+	for i, bar := range bars {
+		y := i * chartRowHeight
+		textY := y + chartRowHeight/2 + 4
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end">%s</text>`,
+			chartLabelWidth-8, textY, template.HTMLEscapeString(truncate(bar.Label, 24)))
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%.1f" height="%d"><title>%s: %d</title></rect>`,
+			chartLabelWidth, y+(chartRowHeight-chartBarHeight)/2, bar.Fraction*chartBarWidth, chartBarHeight,
+			template.HTMLEscapeString(bar.Label), bar.Count)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d">%d</text>`,
+			chartLabelWidth+bar.Fraction*chartBarWidth+6, textY, bar.Count)
+	}
+	b.WriteString(`</svg>`)
+
+	// Every value interpolated above is a number or escaped
+	return template.HTML(b.String())
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
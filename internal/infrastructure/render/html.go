@@ -0,0 +1,65 @@
+package render
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"path/filepath"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// defaultTemplate is the name of the built-in HTML report template
+const defaultTemplate = "report.html.tmpl"
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+// templateFuncs are the functions available to report templates
+var templateFuncs = template.FuncMap{
+	"barChart": barChart,
+}
+
+// HTMLRenderer implements the usecase.DocumentRenderer interface with an html/template
+type HTMLRenderer struct {
+	tmpl *template.Template
+}
+
+// NewHTMLRenderer creates an HTML renderer from the template file at path,
+// or from the built-in template when path is empty.
+// Templates receive a ReportView and may call {{barChart .Bars}} to draw a question's SVG chart
+func NewHTMLRenderer(path string) (usecase.DocumentRenderer, error) {
+	var (
+		tmpl *template.Template
+		err  error
+	)
+	if path == "" {
+		tmpl, err = template.New(defaultTemplate).Funcs(templateFuncs).ParseFS(templates, "templates/"+defaultTemplate)
+	} else {
+		tmpl, err = template.New(filepath.Base(path)).Funcs(templateFuncs).ParseFiles(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse report template: %w", err)
+	}
+
+	return &HTMLRenderer{
+		tmpl: tmpl,
+	}, nil
+}
+
+// Format returns the HTML document format
+func (r *HTMLRenderer) Format() usecase.DocumentFormat {
+	return usecase.DocumentHTML
+}
+
+// ContentType returns the MIME type of HTML documents
+func (r *HTMLRenderer) ContentType() string {
+	return "text/html; charset=utf-8"
+}
+
+// Render executes the template for the report
+func (r *HTMLRenderer) Render(w io.Writer, report *entity.Report) error {
+	return r.tmpl.Execute(w, newReportView(report))
+}
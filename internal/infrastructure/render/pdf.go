@@ -0,0 +1,124 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+const (
+	// PDF layout in millimetres on an A4 page
+	pdfMargin      = 15.0
+	pdfLabelWidth  = 50.0
+	pdfBarWidth    = 100.0
+	pdfRowHeight   = 6.0
+	pdfStatsWidth  = 22.5
+	pdfSectionSkip = 4.0
+)
+
+// PDFRenderer implements the usecase.DocumentRenderer interface with a pure-Go PDF writer
+type PDFRenderer struct{}
+
+// NewPDFRenderer creates a PDF renderer
+func NewPDFRenderer() usecase.DocumentRenderer {
+	return &PDFRenderer{}
+}
+
+// Format returns the PDF document format
+func (r *PDFRenderer) Format() usecase.DocumentFormat {
+	return usecase.DocumentPDF
+}
+
+// ContentType returns the MIME type of PDF documents
+func (r *PDFRenderer) ContentType() string {
+	return "application/pdf"
+}
+
+// Render lays out the report with the same sections as the HTML template,
+// drawing each question's bar chart with filled rectangles
+func (r *PDFRenderer) Render(w io.Writer, report *entity.Report) error {
+	view := newReportView(report)
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetTitle("Survey report: "+view.SurveyID, true)
+	pdf.AddPage()
+
+	// The core fonts are single-byte, so translate UTF-8 text to their code page
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.MultiCell(0, 8, tr("Survey report: "+view.SurveyID), "", "L", false)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.CellFormat(0, 6, fmt.Sprintf("%d responses - generated %s", view.ResponseCount, view.GeneratedAt.Format("2006-01-02 15:04 MST")), "", 1, "L", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+
+	if len(view.Questions) == 0 {
+		pdf.Ln(pdfSectionSkip)
+		pdf.CellFormat(0, 6, "No responses yet.", "", 1, "L", false, 0, "")
+	}
+
+	for _, question := range view.Questions {
+		pdf.Ln(pdfSectionSkip)
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.MultiCell(0, 7, tr(question.Name), "", "L", false)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.SetTextColor(100, 100, 100)
+		pdf.CellFormat(0, 5, fmt.Sprintf("%d answered - about %d distinct", question.Answered, question.Distinct), "", 1, "L", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+
+		if n := question.Numeric; n != nil {
+			writeStats(pdf, n)
+		}
+		for _, bar := range question.Bars {
+			writeBar(pdf, tr, bar)
+		}
+	}
+
+	return pdf.Output(w)
+}
+
+// writeStats writes a question's numeric statistics as a small table
+func writeStats(pdf *fpdf.Fpdf, n *entity.NumericSummary) {
+	headers := []string{"Count", "Mean", "Std dev", "Min", "Median", "P90", "Max", "Sum"}
+	values := []string{
+		strconv.Itoa(n.Count),
+		strconv.FormatFloat(n.Mean, 'f', 2, 64),
+		strconv.FormatFloat(n.StdDev, 'f', 2, 64),
+		strconv.FormatFloat(n.Min, 'f', -1, 64),
+		strconv.FormatFloat(n.Median, 'f', 2, 64),
+		strconv.FormatFloat(n.P90, 'f', 2, 64),
+		strconv.FormatFloat(n.Max, 'f', -1, 64),
+		strconv.FormatFloat(n.Sum, 'f', -1, 64),
+	}
+
+	pdf.SetFillColor(245, 245, 245)
+	for _, header := range headers {
+		pdf.CellFormat(pdfStatsWidth, pdfRowHeight, header, "1", 0, "R", true, 0, "")
+	}
+	pdf.Ln(-1)
+	for _, value := range values {
+		pdf.CellFormat(pdfStatsWidth, pdfRowHeight, value, "1", 0, "R", false, 0, "")
+	}
+	pdf.Ln(pdfRowHeight + 2)
+}
+
+// writeBar writes one labelled bar of a question's chart
+func writeBar(pdf *fpdf.Fpdf, tr func(string) string, bar Bar) {
+	// The label cell triggers any page break, so the bar is drawn on the label's row
+	pdf.CellFormat(pdfLabelWidth, pdfRowHeight, tr(truncate(bar.Label, 28)), "", 0, "R", false, 0, "")
+
+	x, y := pdf.GetX()+2, pdf.GetY()
+	length := bar.Fraction * pdfBarWidth
+	pdf.SetFillColor(74, 123, 208)
+	pdf.Rect(x, y+1, length, pdfRowHeight-2, "F")
+
+	pdf.SetX(x + length + 2)
+	pdf.CellFormat(0, pdfRowHeight, strconv.Itoa(bar.Count), "", 1, "L", false, 0, "")
+}
@@ -0,0 +1,92 @@
+package render_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/render"
+)
+
+func testReport() *entity.Report {
+	counts := map[string]int{"<script>alert(1)</script>": 1}
+	for i := 0; i < 20; i++ {
+		counts[fmt.Sprintf("value-%02d", i)] = 20 - i
+	}
+
+	return &entity.Report{
+		SurveyID:      "survey-123",
+		ResponseCount: 42,
+		GeneratedAt:   1700000000,
+		Questions: map[string]*entity.QuestionSummary{
+			"color": {Answered: 211, Counts: counts},
+			"rating": {
+				Answered: 42,
+				Numeric:  &entity.NumericSummary{Count: 42, Sum: 168, Min: 1, Max: 5, Mean: 4},
+			},
+		},
+	}
+}
+
+func TestHTMLRenderer_DefaultTemplate(t *testing.T) {
+	renderer, err := render.NewHTMLRenderer("")
+	if err != nil {
+		t.Fatalf("Failed to create renderer: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, testReport()); err != nil {
+		t.Fatalf("Failed to render: %v", err)
+	}
+	html := buf.String()
+
+	for _, want := range []string{"survey-123", "42 responses", "<svg", "value-00", ">Other<", "4.00"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected the document to contain %q", want)
+		}
+	}
+
+	// Values beyond the charted bars are folded into Other, and answers are escaped
+	if strings.Contains(html, "value-19") {
+		t.Errorf("Expected values beyond the chart limit to be folded into Other")
+	}
+	if strings.Contains(html, "<script>") {
+		t.Errorf("Expected answer values to be escaped")
+	}
+}
+
+func TestHTMLRenderer_CustomTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom.tmpl")
+	tmpl := `{{.SurveyID}}:{{range .Questions}}{{.Name}}={{.Answered}};{{end}}`
+	if err := os.WriteFile(path, []byte(tmpl), 0o644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	renderer, err := render.NewHTMLRenderer(path)
+	if err != nil {
+		t.Fatalf("Failed to create renderer: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, testReport()); err != nil {
+		t.Fatalf("Failed to render: %v", err)
+	}
+	if got, want := buf.String(), "survey-123:color=211;rating=42;"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestPDFRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	if err := render.NewPDFRenderer().Render(&buf, testReport()); err != nil {
+		t.Fatalf("Failed to render: %v", err)
+	}
+
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) {
+		t.Errorf("Expected a PDF document, got %q", buf.Bytes()[:min(buf.Len(), 16)])
+	}
+}
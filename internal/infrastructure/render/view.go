@@ -0,0 +1,99 @@
+package render
+
+import (
+	"sort"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+const (
+	// maxBars is the number of answer values charted per question; the rest are
+	// summed into a single "Other" bar so free-text questions stay readable
+	maxBars = 15
+
+	// otherLabel labels the bar holding the values beyond maxBars
+	otherLabel = "Other"
+)
+
+// ReportView is the data passed to report templates
+type ReportView struct {
+	SurveyID      string
+	ResponseCount int
+	GeneratedAt   time.Time
+	Questions     []QuestionView
+}
+
+// QuestionView is the data of one question in a report template
+type QuestionView struct {
+	Name     string
+	Answered int
+	Distinct int
+	Bars     []Bar
+	Numeric  *entity.NumericSummary
+}
+
+// Bar is one bar of a question's answer chart
+type Bar struct {
+	Label string
+	Count int
+	// Fraction is the bar's length relative to the longest bar, between 0 and 1
+	Fraction float64
+}
+
+// newReportView builds the template data for a report, questions sorted by name
+// and bars sorted by count, most frequent first
+func newReportView(report *entity.Report) ReportView {
+	view := ReportView{
+		SurveyID:      report.SurveyID,
+		ResponseCount: report.ResponseCount,
+		GeneratedAt:   time.Unix(report.GeneratedAt, 0).UTC(),
+	}
+
+	for name, summary := range report.Questions {
+		view.Questions = append(view.Questions, QuestionView{
+			Name:     name,
+			Answered: summary.Answered,
+			Distinct: summary.Distinct,
+			Bars:     newBars(summary.Counts),
+			Numeric:  summary.Numeric,
+		})
+	}
+	sort.Slice(view.Questions, func(i, j int) bool { return view.Questions[i].Name < view.Questions[j].Name })
+
+	return view
+}
+
+// newBars turns answer counts into chart bars
+func newBars(counts map[string]int) []Bar {
+	bars := make([]Bar, 0, len(counts))
+	for label, count := range counts {
+		bars = append(bars, Bar{Label: label, Count: count})
+	}
+	sort.Slice(bars, func(i, j int) bool {
+		if bars[i].Count != bars[j].Count {
+			return bars[i].Count > bars[j].Count
+		}
+		return bars[i].Label < bars[j].Label
+	})
+
+	if len(bars) > maxBars {
+		other := Bar{Label: otherLabel}
+		for _, bar := range bars[maxBars-1:] {
+			other.Count += bar.Count
+		}
+		bars = append(bars[:maxBars-1], other)
+	}
+
+	longest := 0
+	for _, bar := range bars {
+		longest = max(longest, bar.Count)
+	}
+	for i := range bars {
+		if longest > 0 {
+			bars[i].Fraction = float64(bars[i].Count) / float64(longest)
+		}
+	}
+
+	return bars
+}
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// maxDrain is how much of a receiver's response body is read so the connection can be reused
+const maxDrain = 64 << 10
+
+// ErrAddressNotAllowed is returned when a receiver resolves to an address webhooks may not be delivered to
+var ErrAddressNotAllowed = errors.New("webhook receiver address is not allowed")
+
+// Sender implements the usecase.WebhookSender interface with an HTTP client
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender creates a new webhook sender that only connects to public
+// addresses and to those in the allowed networks
+// Addresses are checked once resolved, so a host name cannot point a payload
+// at an internal service, and neither redirects nor proxies are followed so a
+// payload is only ever posted to the registered URL
+func NewSender(allowed []netip.Prefix) usecase.WebhookSender {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil || !usecase.WebhookAddressAllowed(addr, allowed) {
+				return fmt.Errorf("%w: %s", ErrAddressNotAllowed, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Sender{
+		client: &http.Client{
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send posts body with the given headers to url and returns the response status code
+func (s *Sender) Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrain))
+
+	return resp.StatusCode, nil
+}
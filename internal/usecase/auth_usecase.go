@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// APIKeyPrefix starts every generated API key, which tells keys apart from JWTs
+// and makes leaked keys easy to recognise
+const APIKeyPrefix = "dqp_"
+
+var (
+	// ErrUnauthenticated is returned when a request carries no valid API key or JWT
+	ErrUnauthenticated = entity.NewError(entity.ErrorUnauthenticated, "unauthenticated", "unauthenticated")
+
+	// ErrInvalidAPIKey is returned when an API key request is rejected
+	ErrInvalidAPIKey = entity.NewError(entity.ErrorInvalid, "invalid_api_key", "invalid API key")
+)
+
+// TokenVerifier defines the interface for verifying JWT bearer tokens
+type TokenVerifier interface {
+	// VerifyToken checks the token's signature and claims and returns the caller it identifies
+	VerifyToken(ctx context.Context, token string) (*entity.Identity, error)
+}
+
+// AuthUseCase defines the interface for authenticating callers and managing API keys
+type AuthUseCase interface {
+	// AuthenticateAPIKey returns the identity of the caller presenting key
+	// Returns ErrUnauthenticated if the key is unknown or revoked
+	AuthenticateAPIKey(ctx context.Context, key string) (*entity.Identity, error)
+
+	// AuthenticateToken returns the identity of the caller presenting a JWT
+	// Returns ErrUnauthenticated if the token is invalid or JWTs are not accepted
+	AuthenticateToken(ctx context.Context, token string) (*entity.Identity, error)
+
+	// CreateAPIKey creates an API key with the given scopes, bound to the given
+	// tenant unless it is empty, and returns it together with the key itself,
+	// which is not stored and cannot be retrieved again.
+	// Returns ErrInvalidAPIKey if the name is empty, a scope is unknown, or the
+	// caller is bound to another tenant
+	CreateAPIKey(ctx context.Context, name, tenantID string, scopes []string) (*entity.APIKey, string, error)
+
+	// ListAPIKeys returns every API key ordered by creation time, only those of
+	// its own tenant for a caller bound to one
+	ListAPIKeys(ctx context.Context) ([]entity.APIKey, error)
+
+	// RevokeAPIKey deletes an API key so it is no longer accepted
+	// Returns repository.ErrNotFound if there is no such key, or the caller is
+	// bound to another tenant than the key
+	RevokeAPIKey(ctx context.Context, id string) error
+}
+
+// identityKey is the context key of the authenticated caller
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying the authenticated caller
+func WithIdentity(ctx context.Context, identity *entity.Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the authenticated caller carried by ctx, or nil
+// when the request was not authenticated
+func IdentityFromContext(ctx context.Context) *entity.Identity {
+	identity, _ := ctx.Value(identityKey{}).(*entity.Identity)
+	return identity
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// knownScopes lists every scope an API key can be granted
+var knownScopes = []string{entity.ScopeSubmit, entity.ScopeReadReport, entity.ScopeAdmin}
+
+// authUseCase implements the AuthUseCase interface
+type authUseCase struct {
+	apiKeyRepo  repository.APIKeyRepository
+	verifier    TokenVerifier
+	idGenerator IDGenerator
+}
+
+// NewAuthUseCase creates a new auth use case; verifier may be nil to accept API keys only
+func NewAuthUseCase(apiKeyRepo repository.APIKeyRepository, verifier TokenVerifier, idGenerator IDGenerator) AuthUseCase {
+	return &authUseCase{
+		apiKeyRepo:  apiKeyRepo,
+		verifier:    verifier,
+		idGenerator: idGenerator,
+	}
+}
+
+// AuthenticateAPIKey looks the key up by its hash
+func (uc *authUseCase) AuthenticateAPIKey(ctx context.Context, key string) (*entity.Identity, error) {
+	apiKey, err := uc.apiKeyRepo.GetAPIKeyByHash(ctx, hashAPIKey(key))
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: unknown API key", ErrUnauthenticated)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	return &entity.Identity{
+		Method:   entity.AuthMethodAPIKey,
+		Subject:  apiKey.ID,
+		Scopes:   apiKey.Scopes,
+		TenantID: apiKey.TenantID,
+	}, nil
+}
+
+// AuthenticateToken verifies the JWT with the configured keys
+func (uc *authUseCase) AuthenticateToken(ctx context.Context, token string) (*entity.Identity, error) {
+	if uc.verifier == nil {
+		return nil, fmt.Errorf("%w: bearer tokens are not accepted", ErrUnauthenticated)
+	}
+
+	identity, err := uc.verifier.VerifyToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+	return identity, nil
+}
+
+// CreateAPIKey generates a random key and stores its hash
+func (uc *authUseCase) CreateAPIKey(ctx context.Context, name, tenantID string, scopes []string) (*entity.APIKey, string, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, "", fmt.Errorf("%w: name is required", ErrInvalidAPIKey)
+	}
+	if tenantID != "" && !entity.ValidTenantID(tenantID) {
+		return nil, "", fmt.Errorf("%w: invalid tenant ID %q", ErrInvalidAPIKey, tenantID)
+	}
+	if caller := IdentityFromContext(ctx); caller != nil && caller.TenantID != "" && caller.TenantID != tenantID {
+		return nil, "", fmt.Errorf("%w: a caller bound to tenant %s can only create keys bound to it", ErrInvalidAPIKey, caller.TenantID)
+	}
+	if len(scopes) == 0 {
+		return nil, "", fmt.Errorf("%w: at least one scope is required", ErrInvalidAPIKey)
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(knownScopes, scope) {
+			return nil, "", fmt.Errorf("%w: unknown scope %q, expected one of %q", ErrInvalidAPIKey, scope, knownScopes)
+		}
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key := APIKeyPrefix + hex.EncodeToString(buf)
+
+	apiKey := entity.APIKey{
+		ID:        uc.idGenerator.NewID(),
+		Name:      name,
+		Hash:      hashAPIKey(key),
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(scopes))),
+		TenantID:  tenantID,
+		CreatedAt: time.Now().Unix(),
+	}
+	if caller := IdentityFromContext(ctx); caller != nil {
+		apiKey.CreatedBy = caller.String()
+	}
+	if err := uc.apiKeyRepo.SaveAPIKey(ctx, apiKey); err != nil {
+		return nil, "", fmt.Errorf("failed to save API key: %w", err)
+	}
+
+	return &apiKey, key, nil
+}
+
+// ListAPIKeys returns the API keys the caller may see ordered by creation time
+func (uc *authUseCase) ListAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	keys, err := uc.apiKeyRepo.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	caller := IdentityFromContext(ctx)
+	if caller == nil || caller.TenantID == "" {
+		return keys, nil
+	}
+	return slices.DeleteFunc(keys, func(key entity.APIKey) bool {
+		return key.TenantID != caller.TenantID
+	}), nil
+}
+
+// RevokeAPIKey deletes an API key the caller may see
+func (uc *authUseCase) RevokeAPIKey(ctx context.Context, id string) error {
+	if caller := IdentityFromContext(ctx); caller != nil && caller.TenantID != "" {
+		keys, err := uc.ListAPIKeys(ctx)
+		if err != nil {
+			return err
+		}
+		if !slices.ContainsFunc(keys, func(key entity.APIKey) bool { return key.ID == id }) {
+			return repository.ErrNotFound
+		}
+	}
+
+	return uc.apiKeyRepo.DeleteAPIKey(ctx, id)
+}
+
+// hashAPIKey returns the hex-encoded SHA-256 hash API keys are stored and looked up by
+// Keys are long random strings, so a fast unsalted hash is enough
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,144 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// memoryAPIKeyRepository is an in-memory APIKeyRepository for tests
+type memoryAPIKeyRepository struct {
+	keys map[string]entity.APIKey
+}
+
+func newMemoryAPIKeyRepository() *memoryAPIKeyRepository {
+	return &memoryAPIKeyRepository{keys: make(map[string]entity.APIKey)}
+}
+
+func (m *memoryAPIKeyRepository) SaveAPIKey(ctx context.Context, key entity.APIKey) error {
+	m.keys[key.Hash] = key
+	return nil
+}
+
+func (m *memoryAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*entity.APIKey, error) {
+	key, ok := m.keys[hash]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &key, nil
+}
+
+func (m *memoryAPIKeyRepository) ListAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	var keys []entity.APIKey
+	for _, key := range m.keys {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (m *memoryAPIKeyRepository) DeleteAPIKey(ctx context.Context, id string) error {
+	for hash, key := range m.keys {
+		if key.ID == id {
+			delete(m.keys, hash)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func TestAuth_AuthenticatesCreatedAPIKeys(t *testing.T) {
+	repo := newMemoryAPIKeyRepository()
+	uc := usecase.NewAuthUseCase(repo, nil, &sequenceIDGenerator{})
+	admin := &entity.Identity{Method: entity.AuthMethodJWT, Subject: "ops", Scopes: []string{entity.ScopeAdmin}}
+	ctx := usecase.WithIdentity(context.Background(), admin)
+
+	apiKey, key, err := uc.CreateAPIKey(ctx, "ingest", "", []string{entity.ScopeSubmit, entity.ScopeSubmit})
+	if err != nil {
+		t.Fatalf("Failed to create API key: %v", err)
+	}
+	if !strings.HasPrefix(key, "dqp_") || apiKey.CreatedBy != "jwt:ops" || len(apiKey.Scopes) != 1 {
+		t.Errorf("Expected a dqp_ key created by jwt:ops with one scope, got %q, %+v", key, apiKey)
+	}
+	for _, stored := range repo.keys {
+		if stored.Hash == key {
+			t.Error("Expected only the hash of the key to be stored")
+		}
+	}
+
+	identity, err := uc.AuthenticateAPIKey(ctx, key)
+	if err != nil {
+		t.Fatalf("Expected the key to authenticate, got %v", err)
+	}
+	if identity.String() != "api_key:"+apiKey.ID || !identity.HasScope(entity.ScopeSubmit) || identity.HasScope(entity.ScopeReadReport) {
+		t.Errorf("Expected the key's identity with only the submit scope, got %+v", identity)
+	}
+
+	if err := uc.RevokeAPIKey(ctx, apiKey.ID); err != nil {
+		t.Fatalf("Failed to revoke API key: %v", err)
+	}
+	if _, err := uc.AuthenticateAPIKey(ctx, key); !errors.Is(err, usecase.ErrUnauthenticated) {
+		t.Errorf("Expected the revoked key to be rejected, got %v", err)
+	}
+}
+
+func TestAuth_RejectsInvalidRequests(t *testing.T) {
+	uc := usecase.NewAuthUseCase(newMemoryAPIKeyRepository(), nil, &sequenceIDGenerator{})
+	ctx := context.Background()
+
+	if _, _, err := uc.CreateAPIKey(ctx, "ingest", "", []string{"write"}); !errors.Is(err, usecase.ErrInvalidAPIKey) {
+		t.Errorf("Expected the unknown scope to be rejected, got %v", err)
+	}
+	if _, _, err := uc.CreateAPIKey(ctx, " ", "", []string{entity.ScopeSubmit}); !errors.Is(err, usecase.ErrInvalidAPIKey) {
+		t.Errorf("Expected the empty name to be rejected, got %v", err)
+	}
+
+	if _, _, err := uc.CreateAPIKey(ctx, "ingest", "acme:eu", []string{entity.ScopeSubmit}); !errors.Is(err, usecase.ErrInvalidAPIKey) {
+		t.Errorf("Expected the invalid tenant to be rejected, got %v", err)
+	}
+
+	// Without a verifier only API keys are accepted
+	if _, err := uc.AuthenticateToken(ctx, "eyJ.eyJ.sig"); !errors.Is(err, usecase.ErrUnauthenticated) {
+		t.Errorf("Expected bearer tokens to be rejected, got %v", err)
+	}
+}
+
+func TestAuth_ScopesKeysToTheirTenant(t *testing.T) {
+	repo := newMemoryAPIKeyRepository()
+	uc := usecase.NewAuthUseCase(repo, nil, &sequenceIDGenerator{})
+	ctx := context.Background()
+
+	operatorKey, _, _ := uc.CreateAPIKey(ctx, "ops", "", []string{entity.ScopeAdmin})
+	acmeAdmin, key, err := uc.CreateAPIKey(ctx, "acme-admin", "acme", []string{entity.ScopeAdmin})
+	if err != nil {
+		t.Fatalf("Failed to create API key: %v", err)
+	}
+
+	identity, err := uc.AuthenticateAPIKey(ctx, key)
+	if err != nil || identity.TenantID != "acme" {
+		t.Fatalf("Expected the key to authenticate as bound to acme, got %+v, %v", identity, err)
+	}
+	acmeCtx := usecase.WithIdentity(ctx, identity)
+
+	if _, _, err := uc.CreateAPIKey(acmeCtx, "other", "globex", []string{entity.ScopeSubmit}); !errors.Is(err, usecase.ErrInvalidAPIKey) {
+		t.Errorf("Expected a caller bound to acme not to create keys for globex, got %v", err)
+	}
+	if _, _, err := uc.CreateAPIKey(acmeCtx, "ingest", "acme", []string{entity.ScopeSubmit}); err != nil {
+		t.Errorf("Expected a caller bound to acme to create keys for acme, got %v", err)
+	}
+
+	keys, err := uc.ListAPIKeys(acmeCtx)
+	if err != nil || len(keys) != 2 {
+		t.Errorf("Expected only the two acme keys to be listed, got %+v, %v", keys, err)
+	}
+	if err := uc.RevokeAPIKey(acmeCtx, operatorKey.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected a caller bound to acme not to revoke the operator key, got %v", err)
+	}
+	if err := uc.RevokeAPIKey(acmeCtx, acmeAdmin.ID); err != nil {
+		t.Errorf("Expected a caller bound to acme to revoke its own key, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"io"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// DocumentFormat identifies the file format of a rendered report document
+type DocumentFormat string
+
+const (
+	// DocumentHTML is a standalone HTML page with inline SVG charts
+	DocumentHTML DocumentFormat = "html"
+
+	// DocumentPDF is a PDF document
+	DocumentPDF DocumentFormat = "pdf"
+)
+
+// ErrDocumentFormatDisabled is returned when no renderer is configured for a document format
+var ErrDocumentFormatDisabled = entity.NewError(entity.ErrorNotFound, "document_format_disabled", "document format is not enabled")
+
+// DocumentRenderer defines the interface for rendering a report into a shareable document
+type DocumentRenderer interface {
+	// Format returns the document format the renderer produces
+	Format() DocumentFormat
+
+	// ContentType returns the MIME type of the rendered document
+	ContentType() string
+
+	// Render writes the document for the report to w
+	Render(w io.Writer, report *entity.Report) error
+}
+
+// DocumentUseCase defines the interface for rendered report documents
+type DocumentUseCase interface {
+	// RenderDocuments renders the latest report for the given survey ID in every
+	// configured format and stores the documents, replacing the previous ones
+	RenderDocuments(ctx context.Context, surveyID string) error
+
+	// OpenDocument opens the stored document for the given survey ID and format
+	// and returns it with its content type; the caller must close it.
+	// Returns repository.ErrNotFound if no document has been rendered yet
+	// and ErrDocumentFormatDisabled if the format is not configured
+	OpenDocument(ctx context.Context, surveyID string, format DocumentFormat) (io.ReadCloser, string, error)
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// documentUseCase implements the DocumentUseCase interface
+type documentUseCase struct {
+	reportRepo repository.ReportRepository
+	blobRepo   repository.BlobRepository
+	renderers  []DocumentRenderer
+}
+
+// NewDocumentUseCase creates a new document use case rendering with the given renderers
+func NewDocumentUseCase(
+	reportRepo repository.ReportRepository,
+	blobRepo repository.BlobRepository,
+	renderers ...DocumentRenderer,
+) DocumentUseCase {
+	return &documentUseCase{
+		reportRepo: reportRepo,
+		blobRepo:   blobRepo,
+		renderers:  renderers,
+	}
+}
+
+// RenderDocuments renders and stores the latest report in every configured format
+// A format that fails to render does not prevent the others from being stored
+func (uc *documentUseCase) RenderDocuments(ctx context.Context, surveyID string) error {
+	report, err := uc.reportRepo.GetReport(ctx, surveyID)
+	if err != nil {
+		return fmt.Errorf("failed to get report: %w", err)
+	}
+
+	var errs []error
+	for _, renderer := range uc.renderers {
+		var buf bytes.Buffer
+		if err := renderer.Render(&buf, report); err != nil {
+			errs = append(errs, fmt.Errorf("failed to render %s document: %w", renderer.Format(), err))
+			continue
+		}
+		if err := uc.blobRepo.Put(ctx, documentKey(ctx, surveyID, renderer.Format()), &buf); err != nil {
+			errs = append(errs, fmt.Errorf("failed to store %s document: %w", renderer.Format(), err))
+			continue
+		}
+		fmt.Printf("Rendered %s report document for survey ID: %s\n", renderer.Format(), surveyID)
+	}
+
+	return errors.Join(errs...)
+}
+
+// OpenDocument opens the stored document for the given survey ID and format
+func (uc *documentUseCase) OpenDocument(ctx context.Context, surveyID string, format DocumentFormat) (io.ReadCloser, string, error) {
+	for _, renderer := range uc.renderers {
+		if renderer.Format() != format {
+			continue
+		}
+
+		blob, err := uc.blobRepo.Get(ctx, documentKey(ctx, surveyID, format))
+		if err != nil {
+			return nil, "", err
+		}
+		return blob, renderer.ContentType(), nil
+	}
+
+	return nil, "", ErrDocumentFormatDisabled
+}
+
+// documentKey returns the blob key of the report document of a survey of the
+// tenant ctx acts for. Documents of other tenants than the default one live
+// under a directory named after the tenant.
+// The survey ID is escaped, dots included, so it always maps to a single path segment
+func documentKey(ctx context.Context, surveyID string, format DocumentFormat) string {
+	segment := strings.ReplaceAll(url.PathEscape(surveyID), ".", "%2E")
+	if tenantID := tenantOf(ctx); tenantID != "" {
+		segment = "tenants/" + tenantID + "/" + segment
+	}
+	return "reports/" + segment + "/report." + string(format)
+}
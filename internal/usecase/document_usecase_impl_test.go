@@ -0,0 +1,110 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// memoryBlobRepository is an in-memory BlobRepository for tests
+type memoryBlobRepository struct {
+	blobs map[string][]byte
+}
+
+func newMemoryBlobRepository() *memoryBlobRepository {
+	return &memoryBlobRepository{blobs: make(map[string][]byte)}
+}
+
+func (m *memoryBlobRepository) Put(ctx context.Context, key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	m.blobs[key] = data
+	return nil
+}
+
+func (m *memoryBlobRepository) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := m.blobs[key]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memoryBlobRepository) Delete(ctx context.Context, key string) error {
+	delete(m.blobs, key)
+	return nil
+}
+
+// fakeRenderer renders a fixed prefix followed by the survey ID, or fails with err
+type fakeRenderer struct {
+	format usecase.DocumentFormat
+	err    error
+}
+
+func (f *fakeRenderer) Format() usecase.DocumentFormat { return f.format }
+
+func (f *fakeRenderer) ContentType() string { return "text/" + string(f.format) }
+
+func (f *fakeRenderer) Render(w io.Writer, report *entity.Report) error {
+	if f.err != nil {
+		return f.err
+	}
+	_, err := io.WriteString(w, string(f.format)+":"+report.SurveyID)
+	return err
+}
+
+func TestRenderDocuments_StoresEveryFormat(t *testing.T) {
+	reportRepo := newUnusedReportRepository(t)
+	reportRepo.getReportFunc = func(ctx context.Context, surveyID string) (*entity.Report, error) {
+		return &entity.Report{SurveyID: surveyID}, nil
+	}
+
+	// A failing renderer is reported but does not stop the other formats
+	blobs := newMemoryBlobRepository()
+	uc := usecase.NewDocumentUseCase(reportRepo, blobs,
+		&fakeRenderer{format: usecase.DocumentPDF, err: errors.New("font missing")},
+		&fakeRenderer{format: usecase.DocumentHTML},
+	)
+
+	if err := uc.RenderDocuments(context.Background(), "survey/../123"); err == nil {
+		t.Errorf("Expected the PDF rendering error to be returned")
+	}
+
+	document, contentType, err := uc.OpenDocument(context.Background(), "survey/../123", usecase.DocumentHTML)
+	if err != nil {
+		t.Fatalf("Expected the HTML document to be stored, got %v", err)
+	}
+	data, _ := io.ReadAll(document)
+	document.Close()
+	if string(data) != "html:survey/../123" || contentType != "text/html" {
+		t.Errorf("Unexpected document %q with content type %q", data, contentType)
+	}
+	for key := range blobs.blobs {
+		if key != "reports/survey%2F%2E%2E%2F123/report.html" {
+			t.Errorf("Expected the survey ID to be escaped into one key segment, got %q", key)
+		}
+	}
+
+	if _, _, err := uc.OpenDocument(context.Background(), "survey/../123", usecase.DocumentPDF); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for the failed PDF, got %v", err)
+	}
+}
+
+func TestOpenDocument_DisabledFormat(t *testing.T) {
+	uc := usecase.NewDocumentUseCase(newUnusedReportRepository(t), newMemoryBlobRepository(),
+		&fakeRenderer{format: usecase.DocumentHTML},
+	)
+
+	_, _, err := uc.OpenDocument(context.Background(), "survey-123", usecase.DocumentPDF)
+	if !errors.Is(err, usecase.ErrDocumentFormatDisabled) {
+		t.Errorf("Expected ErrDocumentFormatDisabled, got %v", err)
+	}
+}
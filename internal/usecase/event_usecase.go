@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// EventUseCase defines the interface for live survey events
+// Events are broadcast to every instance, so a subscriber sees the updates of
+// jobs run by any instance
+type EventUseCase interface {
+	ReportNotifier
+
+	// PublishEvent broadcasts an event to the subscribers on every instance
+	PublishEvent(ctx context.Context, event entity.SurveyEvent) error
+
+	// Subscribe returns a channel of the events for the given survey ID of the
+	// tenant ctx acts for, and a function that ends the subscription. The
+	// channel is closed when the subscriber falls too far behind or the broker stops
+	Subscribe(ctx context.Context, surveyID string) (<-chan entity.SurveyEvent, func())
+
+	// StartBroker subscribes to the event bus and starts fanning events out to local subscribers
+	StartBroker(ctx context.Context) error
+
+	// StopBroker stops the broker and closes every subscription
+	StopBroker() error
+}
@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before it is disconnected
+const subscriberBuffer = 64
+
+// eventUseCase implements the EventUseCase interface
+type eventUseCase struct {
+	busRepo    repository.EventBusRepository
+	reportRepo repository.ReportRepository
+	ctx        context.Context
+	cancelFunc context.CancelFunc
+
+	mu          sync.Mutex
+	stopped     bool
+	subscribers map[string]map[chan entity.SurveyEvent]struct{}
+}
+
+// NewEventUseCase creates a new event use case
+func NewEventUseCase(busRepo repository.EventBusRepository, reportRepo repository.ReportRepository) EventUseCase {
+	return &eventUseCase{
+		busRepo:     busRepo,
+		reportRepo:  reportRepo,
+		subscribers: make(map[string]map[chan entity.SurveyEvent]struct{}),
+	}
+}
+
+// PublishEvent broadcasts an event through the event bus
+// Events that name no tenant belong to the tenant ctx acts for
+func (uc *eventUseCase) PublishEvent(ctx context.Context, event entity.SurveyEvent) error {
+	if event.TenantID == "" {
+		event.TenantID = tenantOf(ctx)
+	}
+	if err := uc.busRepo.PublishEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+	return nil
+}
+
+// NotifyReportCompleted publishes the survey's new report version
+func (uc *eventUseCase) NotifyReportCompleted(ctx context.Context, surveyID string) error {
+	report, err := uc.reportRepo.GetReport(ctx, surveyID)
+	if err != nil {
+		return fmt.Errorf("failed to get report: %w", err)
+	}
+
+	return uc.PublishEvent(ctx, entity.SurveyEvent{
+		Type:     entity.EventReportUpdated,
+		SurveyID: surveyID,
+		Report:   report,
+	})
+}
+
+// NotifyReportFailed does nothing; subscribers learn about failures from the job's failed state
+func (uc *eventUseCase) NotifyReportFailed(ctx context.Context, surveyID string, cause error) error {
+	return nil
+}
+
+// Subscribe returns a channel of the events for the given survey ID of the tenant ctx acts for
+// Subscribers are keyed by the survey's storage key, so tenants sharing a survey ID never see each other's events
+func (uc *eventUseCase) Subscribe(ctx context.Context, surveyID string) (<-chan entity.SurveyEvent, func()) {
+	ch := make(chan entity.SurveyEvent, subscriberBuffer)
+	surveyKey := entity.SurveyKey(ctx, surveyID)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.stopped {
+		close(ch)
+		return ch, func() {}
+	}
+	if uc.subscribers[surveyKey] == nil {
+		uc.subscribers[surveyKey] = make(map[chan entity.SurveyEvent]struct{})
+	}
+	uc.subscribers[surveyKey][ch] = struct{}{}
+
+	return ch, func() {
+		uc.mu.Lock()
+		defer uc.mu.Unlock()
+		uc.unsubscribe(surveyKey, ch)
+	}
+}
+
+// StartBroker subscribes to the event bus
+func (uc *eventUseCase) StartBroker(ctx context.Context) error {
+	uc.ctx, uc.cancelFunc = context.WithCancel(ctx)
+
+	if err := uc.busRepo.SubscribeEvents(uc.ctx, uc.dispatch); err != nil {
+		return fmt.Errorf("failed to start event broker: %w", err)
+	}
+
+	fmt.Println("Event broker started successfully")
+	return nil
+}
+
+// StopBroker stops the broker and closes every subscription
+func (uc *eventUseCase) StopBroker() error {
+	if uc.cancelFunc != nil {
+		uc.cancelFunc()
+	}
+
+	uc.mu.Lock()
+	uc.stopped = true
+	for surveyKey, subscribers := range uc.subscribers {
+		for ch := range subscribers {
+			uc.unsubscribe(surveyKey, ch)
+		}
+	}
+	uc.mu.Unlock()
+
+	fmt.Println("Event broker stopped")
+	return nil
+}
+
+// dispatch hands an event to the survey's local subscribers without blocking
+// A subscriber whose buffer is full is disconnected rather than silently
+// missing events, so its client reconnects and starts again from the latest report
+func (uc *eventUseCase) dispatch(event entity.SurveyEvent) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	surveyKey := entity.TenantScopedID(event.TenantID, event.SurveyID)
+	for ch := range uc.subscribers[surveyKey] {
+		select {
+		case ch <- event:
+		default:
+			fmt.Printf("Disconnecting slow event subscriber for survey ID %s\n", event.SurveyID)
+			uc.unsubscribe(surveyKey, ch)
+		}
+	}
+}
+
+// unsubscribe removes and closes a subscription; the caller must hold mu
+func (uc *eventUseCase) unsubscribe(surveyKey string, ch chan entity.SurveyEvent) {
+	subscribers := uc.subscribers[surveyKey]
+	if _, ok := subscribers[ch]; !ok {
+		return
+	}
+
+	delete(subscribers, ch)
+	close(ch)
+	if len(subscribers) == 0 {
+		delete(uc.subscribers, surveyKey)
+	}
+}
+
+// eventJobRepository publishes a job.updated event after every saved job record
+type eventJobRepository struct {
+	repository.JobRepository
+	events EventUseCase
+}
+
+// NewEventJobRepository wraps a job repository so every job state change is
+// published as a job.updated event. Publishing failures are logged because
+// live updates must never block report jobs
+func NewEventJobRepository(jobRepo repository.JobRepository, events EventUseCase) repository.JobRepository {
+	return &eventJobRepository{
+		JobRepository: jobRepo,
+		events:        events,
+	}
+}
+
+// SaveJob stores the job record and publishes it
+func (r *eventJobRepository) SaveJob(ctx context.Context, job entity.JobRecord) error {
+	if err := r.JobRepository.SaveJob(ctx, job); err != nil {
+		return err
+	}
+
+	err := r.events.PublishEvent(ctx, entity.SurveyEvent{
+		Type:     entity.EventJobUpdated,
+		SurveyID: job.SurveyID,
+		Job:      &job,
+	})
+	if err != nil {
+		fmt.Printf("Error publishing update of report job %s: %v\n", job.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/memory"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// newStartedEventUseCase returns an event use case whose broker runs on an in-process bus
+func newStartedEventUseCase(t *testing.T, reportRepo *MockReportRepository) usecase.EventUseCase {
+	events := usecase.NewEventUseCase(memory.NewEventBusRepository(), reportRepo)
+	if err := events.StartBroker(context.Background()); err != nil {
+		t.Fatalf("Expected broker to start, got %v", err)
+	}
+	t.Cleanup(func() { events.StopBroker() })
+	return events
+}
+
+// nextEvent returns the next event of a subscription or fails the test
+func nextEvent(t *testing.T, ch <-chan entity.SurveyEvent) entity.SurveyEvent {
+	select {
+	case event, ok := <-ch:
+		if !ok {
+			t.Fatal("Expected an event, the subscription was closed")
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("Expected an event, got none")
+	}
+	return entity.SurveyEvent{}
+}
+
+func TestEvents_PublishesJobAndReportUpdates(t *testing.T) {
+	reportRepo := newStoringReportRepository()
+	events := newStartedEventUseCase(t, reportRepo)
+
+	ctx := context.Background()
+	ch, unsubscribe := events.Subscribe(ctx, "survey-123")
+	defer unsubscribe()
+	other, unsubscribeOther := events.Subscribe(ctx, "survey-456")
+	defer unsubscribeOther()
+	otherTenant, unsubscribeOtherTenant := events.Subscribe(entity.WithTenant(ctx, "acme"), "survey-123")
+	defer unsubscribeOtherTenant()
+
+	jobRepo := usecase.NewEventJobRepository(newMemoryJobRepository(), events)
+	if err := jobRepo.SaveJob(ctx, entity.JobRecord{ID: "job-1", SurveyID: "survey-123", Status: entity.JobStatusRunning}); err != nil {
+		t.Fatalf("Expected job to be saved, got %v", err)
+	}
+	if event := nextEvent(t, ch); event.Type != entity.EventJobUpdated || event.Job == nil || event.Job.Status != entity.JobStatusRunning {
+		t.Errorf("Expected a running job update, got %+v", event)
+	}
+
+	reportRepo.SaveReport(ctx, entity.Report{SurveyID: "survey-123", ResponseCount: 5, Version: 2})
+	if err := events.NotifyReportCompleted(ctx, "survey-123"); err != nil {
+		t.Fatalf("Expected report update to be published, got %v", err)
+	}
+	if event := nextEvent(t, ch); event.Type != entity.EventReportUpdated || event.Report == nil || event.Report.Version != 2 {
+		t.Errorf("Expected report version 2, got %+v", event)
+	}
+
+	select {
+	case event := <-other:
+		t.Errorf("Expected no events for another survey, got %+v", event)
+	case event := <-otherTenant:
+		t.Errorf("Expected no events for the same survey ID of another tenant, got %+v", event)
+	default:
+	}
+}
+
+func TestEvents_DisconnectsSlowSubscriber(t *testing.T) {
+	events := newStartedEventUseCase(t, newStoringReportRepository())
+
+	ctx := context.Background()
+	ch, unsubscribe := events.Subscribe(ctx, "survey-123")
+	defer unsubscribe()
+
+	// Publish more events than the subscriber buffers without reading any
+	for i := 0; i < 100; i++ {
+		events.PublishEvent(ctx, entity.SurveyEvent{Type: entity.EventJobUpdated, SurveyID: "survey-123"})
+	}
+
+	received := 0
+	for range ch {
+		received++
+	}
+	if received == 0 || received >= 100 {
+		t.Errorf("Expected the buffered events and then a closed channel, got %d events", received)
+	}
+}
+
+func TestEvents_StopClosesSubscriptions(t *testing.T) {
+	events := newStartedEventUseCase(t, newStoringReportRepository())
+
+	ctx := context.Background()
+	ch, unsubscribe := events.Subscribe(ctx, "survey-123")
+	defer unsubscribe()
+	events.StopBroker()
+
+	if _, ok := <-ch; ok {
+		t.Error("Expected the subscription to be closed when the broker stops")
+	}
+
+	late, _ := events.Subscribe(ctx, "survey-123")
+	if _, ok := <-late; ok {
+		t.Error("Expected subscriptions after stopping to be closed")
+	}
+}
@@ -0,0 +1,7 @@
+package usecase
+
+// IDGenerator defines the interface for generating unique response IDs
+type IDGenerator interface {
+	// NewID returns an ID that is unique across every instance of the service
+	NewID() string
+}
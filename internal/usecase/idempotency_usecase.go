@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+var (
+	// ErrIdempotencyKeyReused is returned when an Idempotency-Key is reused with a different request
+	ErrIdempotencyKeyReused = entity.NewError(entity.ErrorConflict, "idempotency_key_reused", "idempotency key was already used with a different request")
+
+	// ErrIdempotencyKeyInProgress is returned when a request with the same Idempotency-Key is still being processed
+	ErrIdempotencyKeyInProgress = entity.NewError(entity.ErrorConflict, "idempotency_key_in_progress", "a request with this idempotency key is still in progress")
+)
+
+// IdempotencyUseCase defines the interface for replaying retried requests
+type IdempotencyUseCase interface {
+	// Begin reserves the key for a request with the given fingerprint
+	// Returns the recorded outcome when the same request already completed,
+	// or nil when the caller should process the request and then call Complete or Abort
+	Begin(ctx context.Context, key, fingerprint string) (*entity.IdempotencyRecord, error)
+
+	// Complete records the outcome of a request so retries can replay it
+	Complete(ctx context.Context, key, fingerprint string, statusCode int, body []byte) error
+
+	// Abort releases the key after a failed request so it can be retried
+	Abort(ctx context.Context, key string) error
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+const (
+	// IdempotencyKeyTTL is how long an Idempotency-Key and its recorded outcome are kept
+	IdempotencyKeyTTL = 24 * time.Hour
+
+	// idempotencyPendingTTL bounds how long a crashed request can hold its key
+	idempotencyPendingTTL = time.Minute
+)
+
+// idempotencyUseCase implements the IdempotencyUseCase interface
+type idempotencyUseCase struct {
+	idempotencyRepo repository.IdempotencyRepository
+}
+
+// NewIdempotencyUseCase creates a new idempotency use case
+func NewIdempotencyUseCase(idempotencyRepo repository.IdempotencyRepository) IdempotencyUseCase {
+	return &idempotencyUseCase{
+		idempotencyRepo: idempotencyRepo,
+	}
+}
+
+// Begin reserves the key for a request with the given fingerprint
+func (uc *idempotencyUseCase) Begin(ctx context.Context, key, fingerprint string) (*entity.IdempotencyRecord, error) {
+	existing, err := uc.idempotencyRepo.Reserve(ctx, entity.IdempotencyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		Status:      entity.IdempotencyStatusPending,
+	}, idempotencyPendingTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	// The key is ours, so the caller should process the request
+	if existing == nil {
+		return nil, nil
+	}
+
+	if existing.Fingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if existing.Status != entity.IdempotencyStatusCompleted {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	return existing, nil
+}
+
+// Complete records the outcome of a request so retries can replay it
+func (uc *idempotencyUseCase) Complete(ctx context.Context, key, fingerprint string, statusCode int, body []byte) error {
+	err := uc.idempotencyRepo.Save(ctx, entity.IdempotencyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		Status:      entity.IdempotencyStatusCompleted,
+		StatusCode:  statusCode,
+		Body:        body,
+	}, IdempotencyKeyTTL)
+	if err != nil {
+		return fmt.Errorf("failed to save idempotency record: %w", err)
+	}
+
+	return nil
+}
+
+// Abort releases the key after a failed request so it can be retried
+func (uc *idempotencyUseCase) Abort(ctx context.Context, key string) error {
+	if err := uc.idempotencyRepo.Delete(ctx, key); err != nil {
+		return fmt.Errorf("failed to delete idempotency record: %w", err)
+	}
+
+	return nil
+}
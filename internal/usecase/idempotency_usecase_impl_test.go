@@ -0,0 +1,91 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// memoryIdempotencyRepository is an in-memory IdempotencyRepository for tests
+type memoryIdempotencyRepository struct {
+	records map[string]entity.IdempotencyRecord
+}
+
+func newMemoryIdempotencyRepository() *memoryIdempotencyRepository {
+	return &memoryIdempotencyRepository{records: make(map[string]entity.IdempotencyRecord)}
+}
+
+func (m *memoryIdempotencyRepository) Reserve(ctx context.Context, record entity.IdempotencyRecord, ttl time.Duration) (*entity.IdempotencyRecord, error) {
+	if existing, ok := m.records[record.Key]; ok {
+		return &existing, nil
+	}
+	m.records[record.Key] = record
+	return nil, nil
+}
+
+func (m *memoryIdempotencyRepository) Save(ctx context.Context, record entity.IdempotencyRecord, ttl time.Duration) error {
+	m.records[record.Key] = record
+	return nil
+}
+
+func (m *memoryIdempotencyRepository) Delete(ctx context.Context, key string) error {
+	delete(m.records, key)
+	return nil
+}
+
+func TestIdempotency_ReplaysCompletedRequest(t *testing.T) {
+	ctx := context.Background()
+	uc := usecase.NewIdempotencyUseCase(newMemoryIdempotencyRepository())
+
+	record, err := uc.Begin(ctx, "key-1", "fingerprint-a")
+	if err != nil || record != nil {
+		t.Fatalf("Expected first request to proceed, got record=%v err=%v", record, err)
+	}
+
+	// A concurrent retry must not be processed twice
+	if _, err := uc.Begin(ctx, "key-1", "fingerprint-a"); !errors.Is(err, usecase.ErrIdempotencyKeyInProgress) {
+		t.Errorf("Expected ErrIdempotencyKeyInProgress, got %v", err)
+	}
+
+	if err := uc.Complete(ctx, "key-1", "fingerprint-a", 202, []byte(`{"id":"resp-1"}`)); err != nil {
+		t.Fatalf("Expected Complete to succeed, got %v", err)
+	}
+
+	record, err = uc.Begin(ctx, "key-1", "fingerprint-a")
+	if err != nil || record == nil {
+		t.Fatalf("Expected the completed record to be replayed, got record=%v err=%v", record, err)
+	}
+	if record.StatusCode != 202 || string(record.Body) != `{"id":"resp-1"}` {
+		t.Errorf("Expected original status and body, got %d %s", record.StatusCode, record.Body)
+	}
+}
+
+func TestIdempotency_RejectsDifferentBody(t *testing.T) {
+	ctx := context.Background()
+	uc := usecase.NewIdempotencyUseCase(newMemoryIdempotencyRepository())
+
+	uc.Begin(ctx, "key-1", "fingerprint-a")
+	uc.Complete(ctx, "key-1", "fingerprint-a", 202, []byte(`{}`))
+
+	if _, err := uc.Begin(ctx, "key-1", "fingerprint-b"); !errors.Is(err, usecase.ErrIdempotencyKeyReused) {
+		t.Errorf("Expected ErrIdempotencyKeyReused, got %v", err)
+	}
+}
+
+func TestIdempotency_AbortAllowsRetry(t *testing.T) {
+	ctx := context.Background()
+	uc := usecase.NewIdempotencyUseCase(newMemoryIdempotencyRepository())
+
+	uc.Begin(ctx, "key-1", "fingerprint-a")
+	if err := uc.Abort(ctx, "key-1"); err != nil {
+		t.Fatalf("Expected Abort to succeed, got %v", err)
+	}
+
+	if record, err := uc.Begin(ctx, "key-1", "fingerprint-a"); err != nil || record != nil {
+		t.Errorf("Expected retry after abort to proceed, got record=%v err=%v", record, err)
+	}
+}
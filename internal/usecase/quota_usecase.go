@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// ErrQuotaExceeded is returned when a tenant has used up its quota
+var ErrQuotaExceeded = entity.NewError(entity.ErrorExhausted, "quota_exceeded", "quota exceeded")
+
+// QuotaUseCase defines the interface for enforcing per-tenant quotas
+type QuotaUseCase interface {
+	// ConsumeResponses counts n submitted responses against the daily quota of
+	// the tenant ctx acts for. Returns ErrQuotaExceeded, counting none of them,
+	// when they would take the tenant above its quota
+	ConsumeResponses(ctx context.Context, n int) error
+
+	// UpdateSettings replaces the quotas from the next submission on
+	UpdateSettings(settings QuotaSettings)
+}
+
+// QuotaSettings holds the quotas of every tenant
+type QuotaSettings struct {
+	// ResponsesPerDay is the number of responses a tenant may submit per UTC
+	// day; zero disables the quota
+	ResponsesPerDay int64
+
+	// Tenants overrides ResponsesPerDay for individual tenants, keyed by tenant
+	// ID; zero disables the tenant's quota
+	Tenants map[string]int64
+}
+
+// limit returns the daily response quota of the tenant, zero if it has none
+func (s QuotaSettings) limit(tenantID string) int64 {
+	if limit, ok := s.Tenants[tenantID]; ok {
+		return limit
+	}
+	return s.ResponsesPerDay
+}
+
+// QuotaResetAt returns when the daily quotas counted at now start again from
+// zero, which is the next midnight UTC
+func QuotaResetAt(now time.Time) time.Time {
+	return now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// quotaKeyPrefix is the prefix of the daily response usage counter of a tenant
+const quotaKeyPrefix = "quota:responses:"
+
+// quotaUseCase implements the QuotaUseCase interface
+type quotaUseCase struct {
+	quotaRepo repository.QuotaRepository
+	settings  atomic.Pointer[QuotaSettings]
+}
+
+// NewQuotaUseCase creates a new quota use case
+func NewQuotaUseCase(quotaRepo repository.QuotaRepository, settings QuotaSettings) QuotaUseCase {
+	uc := &quotaUseCase{
+		quotaRepo: quotaRepo,
+	}
+	uc.settings.Store(&settings)
+	return uc
+}
+
+// UpdateSettings replaces the quotas from the next submission on
+func (uc *quotaUseCase) UpdateSettings(settings QuotaSettings) {
+	uc.settings.Store(&settings)
+}
+
+// ConsumeResponses counts n responses against the tenant's quota for the current UTC day
+func (uc *quotaUseCase) ConsumeResponses(ctx context.Context, n int) error {
+	tenantID := entity.TenantFromContext(ctx)
+	limit := uc.settings.Load().limit(tenantID)
+	if limit <= 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	key := fmt.Sprintf("%s%s:%s", quotaKeyPrefix, tenantID, now.Format(time.DateOnly))
+	resetAt := QuotaResetAt(now)
+
+	used, ok, err := uc.quotaRepo.ConsumeQuota(ctx, key, int64(n), limit, resetAt)
+	if err != nil {
+		return fmt.Errorf("failed to consume quota: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("%w: tenant %s has submitted %d of its %d responses per day, which resets at %s",
+			ErrQuotaExceeded, tenantID, used, limit, resetAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
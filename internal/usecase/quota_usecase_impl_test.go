@@ -0,0 +1,73 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// memoryQuotaRepository is an in-memory QuotaRepository for tests that never expires counters
+type memoryQuotaRepository struct {
+	used map[string]int64
+}
+
+func (m *memoryQuotaRepository) ConsumeQuota(ctx context.Context, key string, n, limit int64, expireAt time.Time) (int64, bool, error) {
+	if m.used[key]+n > limit {
+		return m.used[key], false, nil
+	}
+	m.used[key] += n
+	return m.used[key], true, nil
+}
+
+func TestQuota_LimitsEachTenantSeparately(t *testing.T) {
+	repo := &memoryQuotaRepository{used: make(map[string]int64)}
+	uc := usecase.NewQuotaUseCase(repo, usecase.QuotaSettings{
+		ResponsesPerDay: 3,
+		Tenants:         map[string]int64{"globex": 0},
+	})
+	ctx := context.Background()
+	acme := entity.WithTenant(ctx, "acme")
+
+	if err := uc.ConsumeResponses(acme, 2); err != nil {
+		t.Fatalf("Expected 2 of 3 responses to be allowed, got %v", err)
+	}
+	if err := uc.ConsumeResponses(acme, 2); !errors.Is(err, usecase.ErrQuotaExceeded) {
+		t.Errorf("Expected a batch above the quota to be refused, got %v", err)
+	}
+	if err := uc.ConsumeResponses(acme, 1); err != nil {
+		t.Errorf("Expected the refused batch not to be counted, got %v", err)
+	}
+
+	// Other tenants keep their own quota, and globex has none
+	if err := uc.ConsumeResponses(ctx, 3); err != nil {
+		t.Errorf("Expected the default tenant to have its own quota, got %v", err)
+	}
+	if err := uc.ConsumeResponses(entity.WithTenant(ctx, "globex"), 100); err != nil {
+		t.Errorf("Expected globex to be unlimited, got %v", err)
+	}
+
+	today := time.Now().UTC().Format(time.DateOnly)
+	if repo.used["quota:responses:acme:"+today] != 3 {
+		t.Errorf("Expected acme's usage to be counted under today's key, got %v", repo.used)
+	}
+
+	uc.UpdateSettings(usecase.QuotaSettings{ResponsesPerDay: 5})
+	if err := uc.ConsumeResponses(acme, 2); err != nil {
+		t.Errorf("Expected the raised quota to apply, got %v", err)
+	}
+}
+
+func TestQuotaResetAt_IsNextMidnightUTC(t *testing.T) {
+	now := time.Date(2026, 3, 14, 23, 59, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+	if reset := usecase.QuotaResetAt(now); !reset.Equal(time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the quota to reset at midnight UTC on March 15, got %v", reset)
+	}
+	if !strings.HasSuffix(usecase.QuotaResetAt(now).Format(time.RFC3339), "Z") {
+		t.Error("Expected the reset time to be in UTC")
+	}
+}
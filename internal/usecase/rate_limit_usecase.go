@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"math"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// ErrRateLimited is returned when a submission exceeds one of its rate limits
+var ErrRateLimited = entity.NewError(entity.ErrorExhausted, "rate_limited", "rate limit exceeded")
+
+// RateLimitUseCase defines the interface for enforcing rate limits shared by every instance
+type RateLimitUseCase interface {
+	// AllowSubmission counts a submission request against the limit of the
+	// instance and the limits of its caller, IP address and every survey it
+	// submits to, stopping at the first limit it exceeds. A refused submission
+	// is not counted against any limit. Returns the state of the most
+	// restrictive limit, and ErrRateLimited when the submission was refused
+	AllowSubmission(ctx context.Context, submission RateLimitedSubmission) (entity.RateLimitResult, error)
+
+	// UpdateSettings replaces the limits from the next submission on
+	UpdateSettings(settings RateLimitSettings)
+}
+
+// RateLimitedSubmission identifies the submission request being rate limited
+type RateLimitedSubmission struct {
+	// Client identifies the caller, such as api_key:01J...; empty when the
+	// request was not authenticated, which exempts it from the client limit
+	Client string
+
+	// IP is the address the request came from
+	IP string
+
+	// SurveyIDs lists every survey the request submits responses to
+	SurveyIDs []string
+
+	// SurveyResponses counts the responses the request submits to each survey
+	// of SurveyIDs, every one of which is counted against the survey's limit;
+	// a survey missing from it counts once
+	SurveyResponses map[string]int
+}
+
+// RateLimitSettings holds the rate limits of submission requests
+type RateLimitSettings struct {
+	// Instance limits the requests this instance accepts, whoever sends them
+	Instance RateLimitRule
+
+	// Client limits the requests of one API key or JWT subject
+	Client RateLimitRule
+
+	// IP limits the requests from one IP address
+	IP RateLimitRule
+
+	// Survey limits the requests submitting to one survey of a tenant
+	Survey RateLimitRule
+
+	// Clients overrides the client limit of individual callers, keyed like
+	// RateLimitedSubmission.Client
+	Clients map[string]RateLimitRule
+
+	// Surveys overrides the survey limit of individual surveys, keyed by survey ID
+	Surveys map[string]RateLimitRule
+}
+
+// override returns the rule in force for key: its override in overrides, if
+// any, else rule. An override with a zero rate keeps rule and one with a
+// negative rate lifts the limit
+func override(rule RateLimitRule, overrides map[string]RateLimitRule, key string) RateLimitRule {
+	if o, ok := overrides[key]; ok && o.Rate != 0 {
+		return o
+	}
+	return rule
+}
+
+// RateLimitRule is one rate limit
+type RateLimitRule struct {
+	// Rate is the number of requests accepted per second; zero disables the limit
+	Rate float64
+
+	// Burst is the number of requests accepted at once above Rate; zero allows
+	// one second's worth
+	Burst int
+}
+
+// burst returns the number of requests accepted at once
+func (r RateLimitRule) burst() int {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+	return int(math.Max(1, math.Ceil(r.Rate)))
+}
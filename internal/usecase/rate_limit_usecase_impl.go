@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+const (
+	// rateLimitKeyPrefix is the prefix of every rate limit key
+	rateLimitKeyPrefix = "ratelimit:"
+
+	// rateLimitFallbackPeriod is how long limits are kept in process after the
+	// shared store failed, before it is tried again
+	rateLimitFallbackPeriod = 5 * time.Second
+)
+
+// rateLimitUseCase implements the RateLimitUseCase interface
+type rateLimitUseCase struct {
+	rateLimitRepo repository.RateLimitRepository
+	localRepo     repository.RateLimitRepository
+	settings      atomic.Pointer[RateLimitSettings]
+
+	// fallbackUntil is when the shared store is tried again, in Unix nanoseconds
+	fallbackUntil atomic.Int64
+}
+
+// NewRateLimitUseCase creates a new rate limit use case
+// The limit of the instance is kept in localRepo, and so are the shared
+// limits while rateLimitRepo fails. A nil localRepo keeps every limit in
+// rateLimitRepo and fails submissions while it fails
+func NewRateLimitUseCase(rateLimitRepo, localRepo repository.RateLimitRepository, settings RateLimitSettings) RateLimitUseCase {
+	uc := &rateLimitUseCase{
+		rateLimitRepo: rateLimitRepo,
+		localRepo:     localRepo,
+	}
+	uc.settings.Store(&settings)
+	return uc
+}
+
+// UpdateSettings replaces the limits from the next submission on
+func (uc *rateLimitUseCase) UpdateSettings(settings RateLimitSettings) {
+	uc.settings.Store(&settings)
+}
+
+// rateLimitCheck is one limit a submission is counted against
+type rateLimitCheck struct {
+	rule        RateLimitRule
+	key         string
+	description string
+	local       bool
+	// cost is the number of requests the submission counts as
+	cost int
+}
+
+// taken is a limit a submission was counted against, to return it to when a later limit refuses it
+type taken struct {
+	repo  repository.RateLimitRepository
+	check rateLimitCheck
+}
+
+// AllowSubmission counts the submission against the instance, client, IP and
+// survey limits in turn, giving back what it took when one of them refuses it
+func (uc *rateLimitUseCase) AllowSubmission(ctx context.Context, submission RateLimitedSubmission) (entity.RateLimitResult, error) {
+	settings := uc.settings.Load()
+
+	checks := []rateLimitCheck{{settings.Instance, "instance", "this instance", true, 1}}
+	if submission.Client != "" {
+		rule := override(settings.Client, settings.Clients, submission.Client)
+		checks = append(checks, rateLimitCheck{rule, "client:" + submission.Client, "caller " + submission.Client, false, 1})
+	}
+	if submission.IP != "" {
+		checks = append(checks, rateLimitCheck{settings.IP, "ip:" + submission.IP, "IP address " + submission.IP, false, 1})
+	}
+	for _, surveyID := range submission.SurveyIDs {
+		rule := override(settings.Survey, settings.Surveys, surveyID)
+		cost := max(submission.SurveyResponses[surveyID], 1)
+		checks = append(checks, rateLimitCheck{rule, "survey:" + entity.SurveyKey(ctx, surveyID), "survey " + surveyID, false, cost})
+	}
+
+	var tightest entity.RateLimitResult
+	var counted []taken
+	for _, c := range checks {
+		if c.rule.Rate <= 0 {
+			continue
+		}
+		// More requests than the burst would never fit, however long the caller waits
+		if c.cost > c.rule.burst() {
+			uc.giveBack(ctx, counted)
+			return entity.RateLimitResult{Limit: c.rule.burst()}, fmt.Errorf("%w: %s accepts at most %d responses at once, got %d",
+				ErrRateLimited, c.description, c.rule.burst(), c.cost)
+		}
+
+		repo, result, err := uc.take(ctx, c)
+		if err != nil {
+			uc.giveBack(ctx, counted)
+			return entity.RateLimitResult{}, fmt.Errorf("failed to check rate limit of %s: %w", c.description, err)
+		}
+		if !result.Allowed {
+			uc.giveBack(ctx, counted)
+			return result, fmt.Errorf("%w: %s may submit %g requests per second, retry in %s",
+				ErrRateLimited, c.description, c.rule.Rate, result.RetryAfter.Round(time.Millisecond))
+		}
+		counted = append(counted, taken{repo, c})
+		if tightest.Limit == 0 || result.Remaining < tightest.Remaining ||
+			result.Remaining == tightest.Remaining && result.ResetAfter > tightest.ResetAfter {
+			tightest = result
+		}
+	}
+
+	return tightest, nil
+}
+
+// take counts the cost of c against its limit, in process for the
+// instance's limit and while the shared store is failing, and in the shared
+// store otherwise. Returns the repository the cost was counted in
+func (uc *rateLimitUseCase) take(ctx context.Context, c rateLimitCheck) (repository.RateLimitRepository, entity.RateLimitResult, error) {
+	key := rateLimitKeyPrefix + c.key
+	if uc.localRepo != nil && (c.local || time.Now().UnixNano() < uc.fallbackUntil.Load()) {
+		result, err := uc.localRepo.TakeRateLimit(ctx, key, c.rule.Rate, c.rule.burst(), c.cost)
+		return uc.localRepo, result, err
+	}
+
+	result, err := uc.rateLimitRepo.TakeRateLimit(ctx, key, c.rule.Rate, c.rule.burst(), c.cost)
+	if err == nil || uc.localRepo == nil || ctx.Err() != nil {
+		if err == nil && uc.fallbackUntil.Swap(0) != 0 {
+			fmt.Println("Rate limits are shared between instances again")
+		}
+		return uc.rateLimitRepo, result, err
+	}
+
+	if uc.fallbackUntil.Swap(time.Now().Add(rateLimitFallbackPeriod).UnixNano()) == 0 {
+		fmt.Printf("Error taking rate limit, limiting every instance on its own: %v\n", err)
+	}
+	result, err = uc.localRepo.TakeRateLimit(ctx, key, c.rule.Rate, c.rule.burst(), c.cost)
+	return uc.localRepo, result, err
+}
+
+// giveBack returns the requests of a refused submission to every limit they were counted against
+func (uc *rateLimitUseCase) giveBack(ctx context.Context, counted []taken) {
+	for _, t := range counted {
+		err := t.repo.ReturnRateLimit(ctx, rateLimitKeyPrefix+t.check.key, t.check.rule.Rate, t.check.rule.burst(), t.check.cost)
+		if err != nil {
+			fmt.Printf("Error returning rate limit of %s: %v\n", t.check.description, err)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	memoryRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/memory"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// failingRateLimitRepository is a RateLimitRepository whose store is unreachable
+type failingRateLimitRepository struct {
+	calls int
+}
+
+func (f *failingRateLimitRepository) TakeRateLimit(ctx context.Context, key string, rate float64, burst, cost int) (entity.RateLimitResult, error) {
+	f.calls++
+	return entity.RateLimitResult{}, errors.New("connection refused")
+}
+
+func (f *failingRateLimitRepository) ReturnRateLimit(ctx context.Context, key string, rate float64, burst, cost int) error {
+	return errors.New("connection refused")
+}
+
+func TestRateLimit_LimitsEachClientIPAndSurvey(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	repo := memoryRepo.NewRateLimitRepository(func() time.Time { return now })
+	uc := usecase.NewRateLimitUseCase(repo, nil, usecase.RateLimitSettings{
+		Client: usecase.RateLimitRule{Rate: 1, Burst: 3},
+		IP:     usecase.RateLimitRule{Rate: 1, Burst: 2},
+	})
+	ctx := context.Background()
+	submission := usecase.RateLimitedSubmission{Client: "api_key:1", IP: "10.0.0.1", SurveyIDs: []string{"survey-1"}}
+
+	result, err := uc.AllowSubmission(ctx, submission)
+	if err != nil || !result.Allowed || result.Limit != 2 || result.Remaining != 1 {
+		t.Fatalf("Expected the tighter IP limit to be reported with 1 remaining, got %+v, %v", result, err)
+	}
+	uc.AllowSubmission(ctx, submission)
+	result, err = uc.AllowSubmission(ctx, submission)
+	if !errors.Is(err, usecase.ErrRateLimited) || result.Allowed || result.RetryAfter != time.Second {
+		t.Fatalf("Expected the third request from the IP to be refused for a second, got %+v, %v", result, err)
+	}
+
+	// The same caller from another address is held to its own limit only,
+	// which the request refused by the IP limit was not counted against
+	submission.IP = "10.0.0.2"
+	if _, err := uc.AllowSubmission(ctx, submission); err != nil {
+		t.Errorf("Expected the caller's third counted request to be allowed, got %v", err)
+	}
+	if _, err := uc.AllowSubmission(ctx, submission); !errors.Is(err, usecase.ErrRateLimited) {
+		t.Errorf("Expected the caller's fourth counted request to be refused, got %v", err)
+	}
+	if _, err := uc.AllowSubmission(ctx, usecase.RateLimitedSubmission{IP: "10.0.0.2"}); err != nil {
+		t.Errorf("Expected an anonymous request from the other address to be allowed, got %v", err)
+	}
+
+	// Surveys are limited per tenant once a survey limit is set
+	uc.UpdateSettings(usecase.RateLimitSettings{Survey: usecase.RateLimitRule{Rate: 1}})
+	survey := usecase.RateLimitedSubmission{SurveyIDs: []string{"survey-1"}}
+	if _, err := uc.AllowSubmission(ctx, survey); err != nil {
+		t.Errorf("Expected the first request to the survey to be allowed, got %v", err)
+	}
+	if _, err := uc.AllowSubmission(ctx, survey); !errors.Is(err, usecase.ErrRateLimited) {
+		t.Errorf("Expected the second request to the survey to be refused, got %v", err)
+	}
+	if _, err := uc.AllowSubmission(entity.WithTenant(ctx, "acme"), survey); err != nil {
+		t.Errorf("Expected acme's survey-1 to have its own limit, got %v", err)
+	}
+}
+
+func TestRateLimit_CountsEveryResponseToASurvey(t *testing.T) {
+	uc := usecase.NewRateLimitUseCase(memoryRepo.NewRateLimitRepository(time.Now), nil, usecase.RateLimitSettings{
+		Client: usecase.RateLimitRule{Rate: 0.01, Burst: 1},
+		Survey: usecase.RateLimitRule{Rate: 0.01, Burst: 3},
+	})
+	ctx := context.Background()
+	batch := func(client string, responses int) usecase.RateLimitedSubmission {
+		return usecase.RateLimitedSubmission{Client: client, SurveyIDs: []string{"survey-1"},
+			SurveyResponses: map[string]int{"survey-1": responses}}
+	}
+
+	// A batch larger than the burst is refused outright, without counting against the caller
+	if _, err := uc.AllowSubmission(ctx, batch("api_key:1", 4)); !errors.Is(err, usecase.ErrRateLimited) {
+		t.Errorf("Expected a batch larger than the burst to be refused, got %v", err)
+	}
+	result, err := uc.AllowSubmission(ctx, batch("api_key:1", 3))
+	if err != nil {
+		t.Fatalf("Expected a batch of the burst to be allowed, got %v", err)
+	}
+	if result.Remaining != 0 {
+		t.Errorf("Expected the batch to use up the survey's burst, got %d remaining", result.Remaining)
+	}
+	if _, err := uc.AllowSubmission(ctx, batch("api_key:2", 1)); !errors.Is(err, usecase.ErrRateLimited) {
+		t.Errorf("Expected the survey's limit to be used up, got %v", err)
+	}
+}
+
+func TestRateLimit_FallsBackToLocalLimits(t *testing.T) {
+	shared := &failingRateLimitRepository{}
+	local := memoryRepo.NewRateLimitRepository(time.Now)
+	uc := usecase.NewRateLimitUseCase(shared, local, usecase.RateLimitSettings{
+		IP: usecase.RateLimitRule{Rate: 0.01, Burst: 2},
+	})
+	ctx := context.Background()
+	submission := usecase.RateLimitedSubmission{IP: "10.0.0.1"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := uc.AllowSubmission(ctx, submission); err != nil {
+			t.Fatalf("Expected the local limit to allow request %d, got %v", i+1, err)
+		}
+	}
+	if _, err := uc.AllowSubmission(ctx, submission); !errors.Is(err, usecase.ErrRateLimited) {
+		t.Errorf("Expected the local limit to be enforced, got %v", err)
+	}
+	if shared.calls != 1 {
+		t.Errorf("Expected the failing store to be left alone after it failed, got %d calls", shared.calls)
+	}
+
+	// Without a fallback the failure is returned
+	uc = usecase.NewRateLimitUseCase(shared, nil, usecase.RateLimitSettings{IP: usecase.RateLimitRule{Rate: 1}})
+	if _, err := uc.AllowSubmission(ctx, submission); err == nil || errors.Is(err, usecase.ErrRateLimited) {
+		t.Errorf("Expected the store's failure to be returned, got %v", err)
+	}
+}
+
+func TestRateLimit_RefusedSubmissionIsNotCounted(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	repo := memoryRepo.NewRateLimitRepository(func() time.Time { return now })
+	uc := usecase.NewRateLimitUseCase(repo, nil, usecase.RateLimitSettings{
+		Client: usecase.RateLimitRule{Rate: 1, Burst: 1},
+		Survey: usecase.RateLimitRule{Rate: 1, Burst: 1},
+	})
+	ctx := context.Background()
+
+	// Another caller uses up survey-1, so every request of the caller to it is refused
+	if _, err := uc.AllowSubmission(ctx, usecase.RateLimitedSubmission{Client: "api_key:2", SurveyIDs: []string{"survey-1"}}); err != nil {
+		t.Fatalf("Expected the first request to survey-1 to be allowed, got %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		_, err := uc.AllowSubmission(ctx, usecase.RateLimitedSubmission{Client: "api_key:1", SurveyIDs: []string{"survey-1"}})
+		if !errors.Is(err, usecase.ErrRateLimited) {
+			t.Fatalf("Expected request %d to survey-1 to be refused, got %v", i+1, err)
+		}
+	}
+
+	// The refused requests left the caller's limit untouched
+	if _, err := uc.AllowSubmission(ctx, usecase.RateLimitedSubmission{Client: "api_key:1", SurveyIDs: []string{"survey-2"}}); err != nil {
+		t.Errorf("Expected the caller's first counted request to be allowed, got %v", err)
+	}
+}
+
+func TestRateLimit_OverridesClientsAndSurveys(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	repo := memoryRepo.NewRateLimitRepository(func() time.Time { return now })
+	uc := usecase.NewRateLimitUseCase(repo, nil, usecase.RateLimitSettings{
+		Client: usecase.RateLimitRule{Rate: 1, Burst: 1},
+		Survey: usecase.RateLimitRule{Rate: 1, Burst: 1},
+		Clients: map[string]usecase.RateLimitRule{
+			"api_key:bulk":   {Rate: 10, Burst: 3},
+			"api_key:exempt": {Rate: -1},
+		},
+		Surveys: map[string]usecase.RateLimitRule{
+			"busy": {Rate: 10, Burst: 3},
+		},
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		client  string
+		survey  string
+		allowed int
+	}{
+		{"default limits", "api_key:1", "quiet", 1},
+		{"client override", "api_key:bulk", "", 3},
+		{"survey override", "", "busy", 3},
+		{"lifted client limit", "api_key:exempt", "", 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			submission := usecase.RateLimitedSubmission{Client: tt.client}
+			if tt.survey != "" {
+				submission.SurveyIDs = []string{tt.survey}
+			}
+			for i := 0; i < tt.allowed; i++ {
+				if _, err := uc.AllowSubmission(ctx, submission); err != nil {
+					t.Fatalf("Expected request %d to be allowed, got %v", i+1, err)
+				}
+			}
+			if tt.client == "api_key:exempt" {
+				return
+			}
+			if _, err := uc.AllowSubmission(ctx, submission); !errors.Is(err, usecase.ErrRateLimited) {
+				t.Errorf("Expected request %d to be refused, got %v", tt.allowed+1, err)
+			}
+		})
+	}
+}
+
+func TestRateLimit_KeepsInstanceLimitInProcess(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	shared := &failingRateLimitRepository{}
+	local := memoryRepo.NewRateLimitRepository(func() time.Time { return now })
+	uc := usecase.NewRateLimitUseCase(shared, local, usecase.RateLimitSettings{
+		Instance: usecase.RateLimitRule{Rate: 1, Burst: 2},
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := uc.AllowSubmission(ctx, usecase.RateLimitedSubmission{IP: "10.0.0.1"}); err != nil {
+			t.Fatalf("Expected request %d to be allowed, got %v", i+1, err)
+		}
+	}
+	if _, err := uc.AllowSubmission(ctx, usecase.RateLimitedSubmission{IP: "10.0.0.2"}); !errors.Is(err, usecase.ErrRateLimited) {
+		t.Errorf("Expected the instance to refuse a third request from anyone, got %v", err)
+	}
+	if shared.calls != 0 {
+		t.Errorf("Expected the instance limit to stay out of the shared store, got %d calls", shared.calls)
+	}
+}
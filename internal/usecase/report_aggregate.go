@@ -0,0 +1,252 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/axiomhq/hyperloglog"
+	"github.com/caio/go-tdigest/v4"
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// digestCompression trades t-digest size for quantile accuracy
+const digestCompression = 100
+
+// aggregator folds survey responses into a survey's mergeable partial aggregates
+// Sketches are decoded when a question is first touched and encoded again by finish
+type aggregator struct {
+	aggregate *entity.ReportAggregate
+	digests   map[string]*tdigest.TDigest
+	sketches  map[string]*hyperloglog.Sketch
+}
+
+// newReportAggregate returns empty aggregates for the survey
+func newReportAggregate(surveyID string) *entity.ReportAggregate {
+	return &entity.ReportAggregate{
+		SurveyID:  surveyID,
+		Questions: make(map[string]*entity.QuestionAggregate),
+	}
+}
+
+// newAggregator returns an aggregator folding into the given aggregates in place
+func newAggregator(aggregate *entity.ReportAggregate) *aggregator {
+	if aggregate.Questions == nil {
+		aggregate.Questions = make(map[string]*entity.QuestionAggregate)
+	}
+
+	return &aggregator{
+		aggregate: aggregate,
+		digests:   make(map[string]*tdigest.TDigest),
+		sketches:  make(map[string]*hyperloglog.Sketch),
+	}
+}
+
+// add folds a single response into the aggregates
+func (a *aggregator) add(response entity.SurveyResponse) error {
+	for question, answer := range FlattenAnswers(response.Answers) {
+		summary, ok := a.aggregate.Questions[question]
+		if !ok {
+			summary = &entity.QuestionAggregate{}
+			a.aggregate.Questions[question] = summary
+		}
+		if err := a.addAnswer(question, summary, answer); err != nil {
+			return err
+		}
+	}
+
+	a.aggregate.Offset++
+	a.aggregate.LastResponseID = response.ID
+	return nil
+}
+
+// addAnswer folds a single answer into a question's aggregates
+// Numbers feed the numeric statistics, everything else is counted by value,
+// each element of a multiple-choice array is counted separately, and every
+// value is added to the distinct-count sketch
+func (a *aggregator) addAnswer(question string, summary *entity.QuestionAggregate, answer interface{}) error {
+	summary.Answered++
+
+	sketch, err := a.sketch(question, summary)
+	if err != nil {
+		return err
+	}
+
+	values, ok := answer.([]interface{})
+	if !ok {
+		values = []interface{}{answer}
+	}
+
+	for _, value := range values {
+		key := fmt.Sprint(value)
+		sketch.Insert([]byte(key))
+
+		if number, ok := value.(float64); ok {
+			if err := a.addNumber(question, summary, number); err != nil {
+				return err
+			}
+			continue
+		}
+		if summary.Counts == nil {
+			summary.Counts = make(map[string]int)
+		}
+		summary.Counts[key]++
+	}
+
+	return nil
+}
+
+// addNumber folds a numeric answer into a question's aggregates
+func (a *aggregator) addNumber(question string, summary *entity.QuestionAggregate, number float64) error {
+	numeric := summary.Numeric
+	if numeric == nil {
+		numeric = &entity.NumericAggregate{Min: number, Max: number}
+		summary.Numeric = numeric
+	}
+
+	numeric.Count++
+	numeric.Sum += number
+	numeric.SumSquares += number * number
+	numeric.Min = math.Min(numeric.Min, number)
+	numeric.Max = math.Max(numeric.Max, number)
+
+	digest, err := a.digest(question, numeric)
+	if err != nil {
+		return err
+	}
+	return digest.Add(number)
+}
+
+// digest returns the decoded t-digest of a question's numeric answers
+func (a *aggregator) digest(question string, numeric *entity.NumericAggregate) (*tdigest.TDigest, error) {
+	if digest, ok := a.digests[question]; ok {
+		return digest, nil
+	}
+
+	digest, err := tdigest.New(tdigest.Compression(digestCompression))
+	if err != nil {
+		return nil, err
+	}
+	if len(numeric.Digest) > 0 {
+		if err := digest.FromBytes(numeric.Digest); err != nil {
+			return nil, fmt.Errorf("failed to decode digest of %q: %w", question, err)
+		}
+	}
+
+	a.digests[question] = digest
+	return digest, nil
+}
+
+// sketch returns the decoded HyperLogLog sketch of a question's answer values
+func (a *aggregator) sketch(question string, summary *entity.QuestionAggregate) (*hyperloglog.Sketch, error) {
+	if sketch, ok := a.sketches[question]; ok {
+		return sketch, nil
+	}
+
+	sketch := hyperloglog.New14()
+	if len(summary.Distinct) > 0 {
+		if err := sketch.UnmarshalBinary(summary.Distinct); err != nil {
+			return nil, fmt.Errorf("failed to decode distinct sketch of %q: %w", question, err)
+		}
+	}
+
+	a.sketches[question] = sketch
+	return sketch, nil
+}
+
+// finish encodes the sketches touched by add back into the aggregates
+func (a *aggregator) finish() error {
+	for question, digest := range a.digests {
+		data, err := digest.AsBytes()
+		if err != nil {
+			return fmt.Errorf("failed to encode digest of %q: %w", question, err)
+		}
+		a.aggregate.Questions[question].Numeric.Digest = data
+	}
+
+	for question, sketch := range a.sketches {
+		data, err := sketch.MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("failed to encode distinct sketch of %q: %w", question, err)
+		}
+		a.aggregate.Questions[question].Distinct = data
+	}
+
+	return nil
+}
+
+// report computes the survey's report from the aggregates
+func (a *aggregator) report() (entity.Report, error) {
+	report := entity.Report{
+		SurveyID:      a.aggregate.SurveyID,
+		ResponseCount: int(a.aggregate.Offset),
+		Questions:     make(map[string]*entity.QuestionSummary, len(a.aggregate.Questions)),
+	}
+
+	for question, aggregate := range a.aggregate.Questions {
+		summary := &entity.QuestionSummary{
+			Answered: aggregate.Answered,
+			Counts:   aggregate.Counts,
+		}
+
+		sketch, err := a.sketch(question, aggregate)
+		if err != nil {
+			return report, err
+		}
+		summary.Distinct = int(sketch.Estimate())
+
+		if numeric := aggregate.Numeric; numeric != nil {
+			digest, err := a.digest(question, numeric)
+			if err != nil {
+				return report, err
+			}
+			summary.Numeric = numericSummary(numeric, digest)
+		}
+
+		report.Questions[question] = summary
+	}
+
+	return report, nil
+}
+
+// numericSummary computes the statistics of a question's numeric answers
+// StdDev is the population standard deviation
+func numericSummary(numeric *entity.NumericAggregate, digest *tdigest.TDigest) *entity.NumericSummary {
+	n := float64(numeric.Count)
+	mean := numeric.Sum / n
+
+	// Rounding can push the variance of near-constant answers slightly below zero
+	variance := math.Max(0, numeric.SumSquares/n-mean*mean)
+
+	return &entity.NumericSummary{
+		Count:  numeric.Count,
+		Sum:    numeric.Sum,
+		Min:    numeric.Min,
+		Max:    numeric.Max,
+		Mean:   mean,
+		StdDev: math.Sqrt(variance),
+		Median: digest.Quantile(0.5),
+		P90:    digest.Quantile(0.9),
+		P99:    digest.Quantile(0.99),
+	}
+}
+
+// FlattenAnswers flattens nested answer maps into dot-separated question keys
+func FlattenAnswers(answers map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{}, len(answers))
+	flattenInto(flat, "", answers)
+	return flat
+}
+
+// flattenInto writes the entries of answers into flat using prefix for nested keys
+func flattenInto(flat map[string]interface{}, prefix string, answers map[string]interface{}) {
+	for key, value := range answers {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenInto(flat, key, nested)
+			continue
+		}
+		flat[key] = value
+	}
+}
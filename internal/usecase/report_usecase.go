@@ -11,9 +11,37 @@ type ReportUseCase interface {
 	// It checks for a lock and publishes a report job if needed
 	SubmitResponse(ctx context.Context, response entity.SurveyResponse) error
 
+	// SubmitResponses handles a batch of survey response submissions
+	// Each response is validated on its own, valid responses are stored in bulk,
+	// and at most one report job is scheduled per distinct survey ID.
+	// The returned slice holds each response's validation error, nil if it was accepted
+	SubmitResponses(ctx context.Context, responses []entity.SurveyResponse) ([]error, error)
+
+	// RequestReport publishes a report job for the given survey ID right away,
+	// bypassing the submission debounce window, and returns the queued job
+	RequestReport(ctx context.Context, surveyID string) (*entity.ReportJob, error)
+
 	// GenerateReport generates a report for the given survey ID
 	// This is the actual report generation logic that will be executed by the worker
 	GenerateReport(ctx context.Context, surveyID string) error
+
+	// GetReport returns the latest report for the given survey ID
+	// Returns repository.ErrNotFound if no report has been generated yet
+	GetReport(ctx context.Context, surveyID string) (*entity.Report, error)
+
+	// ScanResponses calls fn for every stored response for the given survey ID in submission order
+	// Responses are read in pages so exports of large surveys are not held in memory
+	ScanResponses(ctx context.Context, surveyID string, fn func(entity.SurveyResponse) error) error
+
+	// CountResponses returns the number of responses stored for the given survey ID
+	CountResponses(ctx context.Context, surveyID string) (int64, error)
+
+	// ListJobs returns the report jobs recorded for the given survey ID, oldest first
+	ListJobs(ctx context.Context, surveyID string) ([]entity.JobRecord, error)
+
+	// UpdateSettings replaces the report settings while the use case runs
+	// Debounce windows that are already open are unaffected
+	UpdateSettings(settings ReportSettings)
 }
 
 // ReportWorkerUseCase defines the interface for the report worker
@@ -23,4 +51,71 @@ type ReportWorkerUseCase interface {
 
 	// StopWorker stops the worker
 	StopWorker() error
+
+	// Stats returns counters of the jobs handled since the worker was created
+	Stats() WorkerStats
+
+	// UpdateSettings replaces the worker settings while the worker runs
+	// Jobs already being processed finish undisturbed
+	UpdateSettings(settings WorkerSettings) error
+}
+
+// ReportNotifier defines the interface the report worker announces report outcomes through
+type ReportNotifier interface {
+	// NotifyReportCompleted announces the latest report for the given survey ID
+	NotifyReportCompleted(ctx context.Context, surveyID string) error
+
+	// NotifyReportFailed announces that generating the report for the given survey ID failed
+	NotifyReportFailed(ctx context.Context, surveyID string, cause error) error
+}
+
+// WorkerSettings holds the tunables of the report worker
+type WorkerSettings struct {
+	// Concurrency is the number of jobs processed at the same time
+	Concurrency int
+
+	// Prefetch is the number of jobs the queue delivers ahead of being
+	// processed; zero delivers as many as Concurrency
+	Prefetch int
+
+	// Scheduling controls how free slots are shared between tenants
+	Scheduling Scheduling
+
+	// TenantConcurrency is the number of jobs of one tenant processed at the
+	// same time; zero allows as many as Concurrency
+	TenantConcurrency int
+}
+
+// Scheduling controls which delivered job takes a slot once one is free
+type Scheduling string
+
+const (
+	// SchedulingFIFO gives a free slot to any waiting job regardless of its
+	// tenant, roughly in the order the jobs were delivered
+	SchedulingFIFO Scheduling = "fifo"
+
+	// SchedulingFair gives a free slot to a job of the waiting tenant with the
+	// fewest jobs running, so one tenant's backlog cannot hold up another's reports
+	SchedulingFair Scheduling = "fair"
+)
+
+// DefaultWorkerSettings returns the settings used when none are configured
+func DefaultWorkerSettings() WorkerSettings {
+	return WorkerSettings{
+		Concurrency: 1,
+		Scheduling:  SchedulingFIFO,
+	}
+}
+
+// WorkerStats holds counters of the jobs handled by a report worker
+type WorkerStats struct {
+	// Generated is the number of jobs that produced a new report
+	Generated int64 `json:"generated"`
+
+	// Skipped is the number of jobs acknowledged without work because a newer
+	// completed report already covered them
+	Skipped int64 `json:"skipped"`
+
+	// Failed is the number of jobs whose report generation returned an error
+	Failed int64 `json:"failed"`
 }
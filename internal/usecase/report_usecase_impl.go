@@ -2,7 +2,9 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
@@ -13,34 +15,185 @@ const (
 	// LockTTL is the time-to-live for the Redis lock
 	LockTTL = 30 * time.Second
 
-	// LockKeyPrefix is the prefix for the Redis lock key
+	// LockKeyPrefix is the default prefix for the Redis lock key
 	LockKeyPrefix = "report:lock:"
 )
 
+// errAggregateDrift is returned when a survey's aggregates no longer line up with its stored responses
+var errAggregateDrift = errors.New("aggregate does not match stored responses")
+
+// DebounceMode controls when the single report job of a debounce window runs
+type DebounceMode string
+
+const (
+	// DebounceLeading runs the job as soon as the first response of a window arrives.
+	// Responses that arrive later in the same window wait for the next window's report
+	DebounceLeading DebounceMode = "leading"
+
+	// DebounceTrailing delays the job until the window closes, so its report
+	// covers every response received during the window
+	DebounceTrailing DebounceMode = "trailing"
+)
+
+// ReportSettings holds the tunables of the report use case
+type ReportSettings struct {
+	// LockTTL is the length of the debounce window
+	LockTTL time.Duration
+
+	// LockKeyPrefix is prepended to the tenant and survey ID to form the
+	// survey's debounce lock key, {prefix}{tenant}:{survey}
+	LockKeyPrefix string
+
+	// DebounceMode controls when the window's report job runs
+	DebounceMode DebounceMode
+
+	// RecomputeEvery is the number of incremental report runs after which the
+	// aggregates are rebuilt from every response; zero disables the limit
+	RecomputeEvery int
+
+	// RecomputeInterval is the age after which the aggregates are rebuilt from
+	// every response; zero disables the limit
+	RecomputeInterval time.Duration
+
+	// Surveys overrides the debounce settings of individual surveys, keyed by survey ID
+	Surveys map[string]SurveySettings
+}
+
+// SurveySettings holds the debounce settings overridden for one survey
+// Zero values keep the setting of ReportSettings
+type SurveySettings struct {
+	// LockTTL is the length of the survey's debounce window
+	LockTTL time.Duration
+
+	// DebounceMode controls when the survey's window report job runs
+	DebounceMode DebounceMode
+}
+
+// debounce returns the debounce window length and mode in force for the survey
+func (s ReportSettings) debounce(surveyID string) (time.Duration, DebounceMode) {
+	ttl, mode := s.LockTTL, s.DebounceMode
+	if override, ok := s.Surveys[surveyID]; ok {
+		if override.LockTTL > 0 {
+			ttl = override.LockTTL
+		}
+		if override.DebounceMode != "" {
+			mode = override.DebounceMode
+		}
+	}
+	return ttl, mode
+}
+
+// DefaultReportSettings returns the settings used when none are configured
+func DefaultReportSettings() ReportSettings {
+	return ReportSettings{
+		LockTTL:           LockTTL,
+		LockKeyPrefix:     LockKeyPrefix,
+		DebounceMode:      DebounceLeading,
+		RecomputeEvery:    100,
+		RecomputeInterval: time.Hour,
+	}
+}
+
 // reportUseCase implements the ReportUseCase interface
 type reportUseCase struct {
-	lockRepo  repository.LockRepository
-	queueRepo repository.QueueRepository
+	lockRepo     repository.LockRepository
+	queueRepo    repository.QueueRepository
+	responseRepo repository.ResponseRepository
+	reportRepo   repository.ReportRepository
+	jobRepo      repository.JobRepository
+	settings     atomic.Pointer[ReportSettings]
 }
 
 // NewReportUseCase creates a new report use case
 func NewReportUseCase(
 	lockRepo repository.LockRepository,
 	queueRepo repository.QueueRepository,
+	responseRepo repository.ResponseRepository,
+	reportRepo repository.ReportRepository,
+	jobRepo repository.JobRepository,
+	settings ReportSettings,
 ) ReportUseCase {
-	return &reportUseCase{
-		lockRepo:  lockRepo,
-		queueRepo: queueRepo,
+	uc := &reportUseCase{
+		lockRepo:     lockRepo,
+		queueRepo:    queueRepo,
+		responseRepo: responseRepo,
+		reportRepo:   reportRepo,
+		jobRepo:      jobRepo,
 	}
+	uc.settings.Store(&settings)
+	return uc
+}
+
+// UpdateSettings replaces the settings used from the next submission on
+// Debounce windows that are already open keep the length they were opened with
+func (uc *reportUseCase) UpdateSettings(settings ReportSettings) {
+	uc.settings.Store(&settings)
 }
 
 // SubmitResponse handles a new survey response submission
 func (uc *reportUseCase) SubmitResponse(ctx context.Context, response entity.SurveyResponse) error {
-	// Create lock key using survey ID
-	lockKey := fmt.Sprintf("%s%s", LockKeyPrefix, response.SurveyID)
+	if err := ValidateResponse(response); err != nil {
+		return err
+	}
+	response.TenantID = tenantOf(ctx)
+
+	// Store the response before scheduling a report so the report can include it
+	if err := uc.responseRepo.SaveResponse(ctx, response); err != nil {
+		return fmt.Errorf("failed to save response: %w", err)
+	}
+
+	return uc.scheduleReport(ctx, response.SurveyID)
+}
+
+// SubmitResponses handles a batch of survey response submissions
+func (uc *reportUseCase) SubmitResponses(ctx context.Context, responses []entity.SurveyResponse) ([]error, error) {
+	results := make([]error, len(responses))
+	valid := make([]entity.SurveyResponse, 0, len(responses))
+	var surveyIDs []string
+	seen := make(map[string]bool)
+
+	for i, response := range responses {
+		if err := ValidateResponse(response); err != nil {
+			results[i] = err
+			continue
+		}
+		response.TenantID = tenantOf(ctx)
+		valid = append(valid, response)
+		if !seen[response.SurveyID] {
+			seen[response.SurveyID] = true
+			surveyIDs = append(surveyIDs, response.SurveyID)
+		}
+	}
+
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	if err := uc.responseRepo.SaveResponses(ctx, valid); err != nil {
+		return nil, fmt.Errorf("failed to save responses: %w", err)
+	}
+
+	// The responses are stored, so a scheduling failure only delays their report
+	// until the next submission and must not reject them
+	for _, surveyID := range surveyIDs {
+		if err := uc.scheduleReport(ctx, surveyID); err != nil {
+			fmt.Printf("Error scheduling report for survey ID %s: %v\n", surveyID, err)
+		}
+	}
+
+	return results, nil
+}
+
+// scheduleReport publishes a report job for the survey unless one is already scheduled
+func (uc *reportUseCase) scheduleReport(ctx context.Context, surveyID string) error {
+	settings := uc.settings.Load()
+	ttl, mode := settings.debounce(surveyID)
+
+	// Create lock key using tenant and survey ID, so tenants sharing a survey ID debounce apart
+	lockKey := fmt.Sprintf("%s%s:%s", settings.LockKeyPrefix, entity.TenantFromContext(ctx), surveyID)
 
 	// Try to acquire lock
-	locked, err := uc.lockRepo.SetLock(ctx, lockKey, LockTTL)
+	locked, err := uc.lockRepo.SetLock(ctx, lockKey, ttl)
 	if err != nil {
 		return fmt.Errorf("failed to set lock: %w", err)
 	}
@@ -51,31 +204,222 @@ func (uc *reportUseCase) SubmitResponse(ctx context.Context, response entity.Sur
 		return nil
 	}
 
-	// Create and publish report job
-	job := entity.ReportJob{
-		SurveyID: response.SurveyID,
+	var notBefore int64
+	if mode == DebounceTrailing {
+		// Run once the window closes so the report covers the whole window
+		notBefore = time.Now().Add(ttl).UnixNano()
 	}
 
-	if err := uc.queueRepo.PublishReportJob(ctx, job); err != nil {
+	if _, err := uc.publishJob(ctx, surveyID, notBefore); err != nil {
 		// If publishing fails, release the lock
 		uc.lockRepo.ReleaseLock(ctx, lockKey)
-		return fmt.Errorf("failed to publish report job: %w", err)
+		return err
 	}
 
 	return nil
 }
 
+// RequestReport publishes a report job for the survey without taking the debounce lock
+func (uc *reportUseCase) RequestReport(ctx context.Context, surveyID string) (*entity.ReportJob, error) {
+	if err := ValidateSurveyID(surveyID); err != nil {
+		return nil, err
+	}
+
+	job, err := uc.publishJob(ctx, surveyID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// publishJob publishes a report job for the survey and records it as queued
+func (uc *reportUseCase) publishJob(ctx context.Context, surveyID string, notBefore int64) (entity.ReportJob, error) {
+	// Count the stored responses first so the job's report covers every
+	// response stored before it was published
+	count, err := uc.responseRepo.CountResponses(ctx, surveyID)
+	if err != nil {
+		fmt.Printf("Error counting responses for survey ID %s: %v\n", surveyID, err)
+	}
+
+	requestedAt := time.Now().UnixNano()
+	job := entity.ReportJob{
+		ID:            fmt.Sprintf("%s-%d", surveyID, requestedAt),
+		SurveyID:      surveyID,
+		TenantID:      tenantOf(ctx),
+		RequestedAt:   requestedAt,
+		NotBefore:     notBefore,
+		ResponseCount: count,
+	}
+
+	if err := uc.queueRepo.PublishReportJob(ctx, job); err != nil {
+		return entity.ReportJob{}, fmt.Errorf("failed to publish report job: %w", err)
+	}
+
+	// The job is already queued, so a failure to record it only affects status reporting
+	err = uc.jobRepo.SaveJob(ctx, entity.JobRecord{
+		ID:          job.ID,
+		SurveyID:    job.SurveyID,
+		Status:      entity.JobStatusQueued,
+		RequestedAt: job.RequestedAt,
+		NotBefore:   job.NotBefore,
+	})
+	if err != nil {
+		fmt.Printf("Error recording report job %s: %v\n", job.ID, err)
+	}
+
+	return job, nil
+}
+
 // GenerateReport generates a report for the given survey ID
+// Only responses stored since the previous run are read and folded into the
+// survey's partial aggregates, which are rebuilt from every response when
+// they no longer line up with the stored responses or a recompute is due
 func (uc *reportUseCase) GenerateReport(ctx context.Context, surveyID string) error {
-	// This is where the actual report generation logic would go
-	// For this example, we'll just simulate some work
-
 	fmt.Printf("Generating report for survey ID: %s\n", surveyID)
 
-	// Simulate some work
-	time.Sleep(2 * time.Second)
+	aggregate, err := uc.reportRepo.GetAggregate(ctx, surveyID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("failed to get aggregate: %w", err)
+	}
+	// The version keeps counting across rebuilds so it only ever moves forward
+	var version int64
+	if aggregate != nil {
+		version = aggregate.Version
+	}
+	full := aggregate == nil || uc.recomputeDue(aggregate, time.Now())
+	if full {
+		aggregate = newReportAggregate(surveyID)
+	}
+
+	agg, err := uc.foldResponses(ctx, aggregate)
+	if errors.Is(err, errAggregateDrift) {
+		fmt.Printf("Aggregates for survey ID %s no longer match the stored responses, recomputing\n", surveyID)
+		full, aggregate = true, newReportAggregate(surveyID)
+		agg, err = uc.foldResponses(ctx, aggregate)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list responses: %w", err)
+	}
+	if err := agg.finish(); err != nil {
+		return err
+	}
+
+	if full {
+		aggregate.IncrementalRuns = 0
+		aggregate.RecomputedAt = time.Now().Unix()
+	} else {
+		aggregate.IncrementalRuns++
+	}
+	aggregate.Version = version + 1
+
+	report, err := agg.report()
+	if err != nil {
+		return err
+	}
+	report.Version = aggregate.Version
+	report.GeneratedAt = time.Now().Unix()
 
-	fmt.Printf("Report generation completed for survey ID: %s\n", surveyID)
+	// Save the aggregates first: if saving the report fails, the retry folds
+	// nothing new and rebuilds the same report from them
+	if err := uc.reportRepo.SaveAggregate(ctx, *aggregate); err != nil {
+		return fmt.Errorf("failed to save aggregate: %w", err)
+	}
+	if err := uc.reportRepo.SaveReport(ctx, report); err != nil {
+		return fmt.Errorf("failed to save report: %w", err)
+	}
+
+	// Record how many stored responses the report covers so jobs published
+	// for no more of them can be coalesced
+	watermark := entity.ReportWatermark{
+		SurveyID:      surveyID,
+		Offset:        aggregate.Offset,
+		ResponseCount: report.ResponseCount,
+		GeneratedAt:   report.GeneratedAt,
+	}
+	if err := uc.reportRepo.SaveWatermark(ctx, watermark); err != nil {
+		return fmt.Errorf("failed to save watermark: %w", err)
+	}
+
+	mode := "incremental"
+	if full {
+		mode = "full"
+	}
+	fmt.Printf("Report generation completed for survey ID: %s (%d responses, %s)\n", surveyID, report.ResponseCount, mode)
 
 	return nil
 }
+
+// foldResponses folds the responses stored after the aggregate's offset into it
+// The last response already folded in is read again and must still be the one
+// recorded in the aggregate, otherwise errAggregateDrift is returned
+func (uc *reportUseCase) foldResponses(ctx context.Context, aggregate *entity.ReportAggregate) (*aggregator, error) {
+	agg := newAggregator(aggregate)
+
+	offset, verify := aggregate.Offset, aggregate.Offset > 0
+	if verify {
+		offset--
+	}
+
+	err := uc.responseRepo.ScanResponses(ctx, aggregate.SurveyID, offset, func(response entity.SurveyResponse) error {
+		if verify {
+			verify = false
+			if response.ID != aggregate.LastResponseID {
+				return errAggregateDrift
+			}
+			return nil
+		}
+		return agg.add(response)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if verify {
+		// Fewer responses are stored than the aggregate has folded in
+		return nil, errAggregateDrift
+	}
+
+	return agg, nil
+}
+
+// recomputeDue reports whether the aggregates should be rebuilt from every
+// response to correct any drift accumulated by incremental runs
+func (uc *reportUseCase) recomputeDue(aggregate *entity.ReportAggregate, now time.Time) bool {
+	settings := uc.settings.Load()
+	if every := settings.RecomputeEvery; every > 0 && aggregate.IncrementalRuns >= every {
+		return true
+	}
+	if interval := settings.RecomputeInterval; interval > 0 && now.Sub(time.Unix(aggregate.RecomputedAt, 0)) >= interval {
+		return true
+	}
+	return false
+}
+
+// GetReport returns the latest report for the given survey ID
+func (uc *reportUseCase) GetReport(ctx context.Context, surveyID string) (*entity.Report, error) {
+	return uc.reportRepo.GetReport(ctx, surveyID)
+}
+
+// ScanResponses calls fn for every stored response for the given survey ID
+func (uc *reportUseCase) ScanResponses(ctx context.Context, surveyID string, fn func(entity.SurveyResponse) error) error {
+	return uc.responseRepo.ScanResponses(ctx, surveyID, 0, fn)
+}
+
+// CountResponses returns the number of responses stored for the given survey ID
+func (uc *reportUseCase) CountResponses(ctx context.Context, surveyID string) (int64, error) {
+	return uc.responseRepo.CountResponses(ctx, surveyID)
+}
+
+// ListJobs returns the report jobs recorded for the given survey ID
+func (uc *reportUseCase) ListJobs(ctx context.Context, surveyID string) ([]entity.JobRecord, error) {
+	return uc.jobRepo.ListJobs(ctx, surveyID)
+}
+
+// tenantOf returns the tenant ctx acts for as recorded on stored entities,
+// empty for the default tenant so entities stored without tenants look alike
+func tenantOf(ctx context.Context) string {
+	if tenantID := entity.TenantFromContext(ctx); tenantID != entity.DefaultTenant {
+		return tenantID
+	}
+	return ""
+}
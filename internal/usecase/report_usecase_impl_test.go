@@ -3,12 +3,17 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+	"math"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
 )
 
 // MockLockRepository is a manual mock for the LockRepository interface
@@ -29,6 +34,7 @@ func (m *MockLockRepository) ReleaseLock(ctx context.Context, key string) (bool,
 type MockQueueRepository struct {
 	publishReportJobFunc  func(ctx context.Context, job entity.ReportJob) error
 	consumeReportJobsFunc func(ctx context.Context, callback func(entity.ReportJob) error) error
+	setPrefetchFunc       func(prefetch int) error
 	closeFunc             func() error
 }
 
@@ -40,17 +46,234 @@ func (m *MockQueueRepository) ConsumeReportJobs(ctx context.Context, callback fu
 	return m.consumeReportJobsFunc(ctx, callback)
 }
 
+func (m *MockQueueRepository) SetPrefetch(prefetch int) error {
+	if m.setPrefetchFunc == nil {
+		return nil
+	}
+	return m.setPrefetchFunc(prefetch)
+}
+
+func (m *MockQueueRepository) Ping(ctx context.Context) error {
+	return nil
+}
+
 func (m *MockQueueRepository) Close() error {
 	return m.closeFunc()
 }
 
+// MockResponseRepository is a manual mock for the ResponseRepository interface
+type MockResponseRepository struct {
+	saveResponseFunc  func(ctx context.Context, response entity.SurveyResponse) error
+	saveResponsesFunc func(ctx context.Context, responses []entity.SurveyResponse) error
+	listResponsesFunc func(ctx context.Context, surveyID string) ([]entity.SurveyResponse, error)
+	// countResponsesFunc is optional; without it no responses are counted
+	countResponsesFunc func(ctx context.Context, surveyID string) (int64, error)
+}
+
+func (m *MockResponseRepository) SaveResponse(ctx context.Context, response entity.SurveyResponse) error {
+	return m.saveResponseFunc(ctx, response)
+}
+
+func (m *MockResponseRepository) SaveResponses(ctx context.Context, responses []entity.SurveyResponse) error {
+	return m.saveResponsesFunc(ctx, responses)
+}
+
+// ScanResponses scans the responses returned by listResponsesFunc after offset
+func (m *MockResponseRepository) ScanResponses(ctx context.Context, surveyID string, offset int64, fn func(entity.SurveyResponse) error) error {
+	responses, err := m.ListResponses(ctx, surveyID)
+	if err != nil {
+		return err
+	}
+	for _, response := range responses[min(int(offset), len(responses)):] {
+		if err := fn(response); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockResponseRepository) ListResponses(ctx context.Context, surveyID string) ([]entity.SurveyResponse, error) {
+	return m.listResponsesFunc(ctx, surveyID)
+}
+
+func (m *MockResponseRepository) CountResponses(ctx context.Context, surveyID string) (int64, error) {
+	if m.countResponsesFunc == nil {
+		return 0, nil
+	}
+	return m.countResponsesFunc(ctx, surveyID)
+}
+
+// MockReportRepository is a manual mock for the ReportRepository interface
+type MockReportRepository struct {
+	saveReportFunc    func(ctx context.Context, report entity.Report) error
+	getReportFunc     func(ctx context.Context, surveyID string) (*entity.Report, error)
+	saveWatermarkFunc func(ctx context.Context, watermark entity.ReportWatermark) error
+	getWatermarkFunc  func(ctx context.Context, surveyID string) (*entity.ReportWatermark, error)
+	saveAggregateFunc func(ctx context.Context, aggregate entity.ReportAggregate) error
+	getAggregateFunc  func(ctx context.Context, surveyID string) (*entity.ReportAggregate, error)
+}
+
+func (m *MockReportRepository) SaveReport(ctx context.Context, report entity.Report) error {
+	return m.saveReportFunc(ctx, report)
+}
+
+func (m *MockReportRepository) GetReport(ctx context.Context, surveyID string) (*entity.Report, error) {
+	return m.getReportFunc(ctx, surveyID)
+}
+
+func (m *MockReportRepository) SaveAggregate(ctx context.Context, aggregate entity.ReportAggregate) error {
+	return m.saveAggregateFunc(ctx, aggregate)
+}
+
+func (m *MockReportRepository) GetAggregate(ctx context.Context, surveyID string) (*entity.ReportAggregate, error) {
+	return m.getAggregateFunc(ctx, surveyID)
+}
+
+func (m *MockReportRepository) SaveWatermark(ctx context.Context, watermark entity.ReportWatermark) error {
+	return m.saveWatermarkFunc(ctx, watermark)
+}
+
+func (m *MockReportRepository) GetWatermark(ctx context.Context, surveyID string) (*entity.ReportWatermark, error) {
+	return m.getWatermarkFunc(ctx, surveyID)
+}
+
+// memoryJobRepository is an in-memory JobRepository for tests
+type memoryJobRepository struct {
+	mu   sync.Mutex
+	jobs map[string]entity.JobRecord
+}
+
+func newMemoryJobRepository() *memoryJobRepository {
+	return &memoryJobRepository{jobs: make(map[string]entity.JobRecord)}
+}
+
+func (m *memoryJobRepository) SaveJob(ctx context.Context, job entity.JobRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[job.ID] = job
+	return nil
+}
+
+func (m *memoryJobRepository) GetJob(ctx context.Context, surveyID, jobID string) (*entity.JobRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[jobID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &job, nil
+}
+
+func (m *memoryJobRepository) ListJobs(ctx context.Context, surveyID string) ([]entity.JobRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var jobs []entity.JobRecord
+	for _, job := range m.jobs {
+		if job.SurveyID == surveyID {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].RequestedAt < jobs[j].RequestedAt })
+	return jobs, nil
+}
+
+// newStoringResponseRepository returns a response repository mock that accepts every response
+func newStoringResponseRepository(t *testing.T) *MockResponseRepository {
+	return &MockResponseRepository{
+		saveResponseFunc: func(ctx context.Context, response entity.SurveyResponse) error {
+			return nil
+		},
+		saveResponsesFunc: func(ctx context.Context, responses []entity.SurveyResponse) error {
+			return nil
+		},
+		listResponsesFunc: func(ctx context.Context, surveyID string) ([]entity.SurveyResponse, error) {
+			t.Errorf("ListResponses should not be called")
+			return nil, nil
+		},
+	}
+}
+
+// newUnusedReportRepository returns a report repository mock that fails the test when used
+func newUnusedReportRepository(t *testing.T) *MockReportRepository {
+	return &MockReportRepository{
+		saveReportFunc: func(ctx context.Context, report entity.Report) error {
+			t.Errorf("SaveReport should not be called")
+			return nil
+		},
+		getReportFunc: func(ctx context.Context, surveyID string) (*entity.Report, error) {
+			t.Errorf("GetReport should not be called")
+			return nil, nil
+		},
+		saveWatermarkFunc: func(ctx context.Context, watermark entity.ReportWatermark) error {
+			t.Errorf("SaveWatermark should not be called")
+			return nil
+		},
+		getWatermarkFunc: func(ctx context.Context, surveyID string) (*entity.ReportWatermark, error) {
+			t.Errorf("GetWatermark should not be called")
+			return nil, nil
+		},
+		saveAggregateFunc: func(ctx context.Context, aggregate entity.ReportAggregate) error {
+			t.Errorf("SaveAggregate should not be called")
+			return nil
+		},
+		getAggregateFunc: func(ctx context.Context, surveyID string) (*entity.ReportAggregate, error) {
+			t.Errorf("GetAggregate should not be called")
+			return nil, nil
+		},
+	}
+}
+
+// newStoringReportRepository returns a report repository mock that keeps the
+// latest report, watermark and aggregate of every survey in memory
+func newStoringReportRepository() *MockReportRepository {
+	reports := make(map[string]entity.Report)
+	watermarks := make(map[string]entity.ReportWatermark)
+	aggregates := make(map[string]entity.ReportAggregate)
+
+	return &MockReportRepository{
+		saveReportFunc: func(ctx context.Context, report entity.Report) error {
+			reports[report.SurveyID] = report
+			return nil
+		},
+		getReportFunc: func(ctx context.Context, surveyID string) (*entity.Report, error) {
+			report, ok := reports[surveyID]
+			if !ok {
+				return nil, repository.ErrNotFound
+			}
+			return &report, nil
+		},
+		saveWatermarkFunc: func(ctx context.Context, watermark entity.ReportWatermark) error {
+			watermarks[watermark.SurveyID] = watermark
+			return nil
+		},
+		getWatermarkFunc: func(ctx context.Context, surveyID string) (*entity.ReportWatermark, error) {
+			watermark, ok := watermarks[surveyID]
+			if !ok {
+				return nil, repository.ErrNotFound
+			}
+			return &watermark, nil
+		},
+		saveAggregateFunc: func(ctx context.Context, aggregate entity.ReportAggregate) error {
+			aggregates[aggregate.SurveyID] = aggregate
+			return nil
+		},
+		getAggregateFunc: func(ctx context.Context, surveyID string) (*entity.ReportAggregate, error) {
+			aggregate, ok := aggregates[surveyID]
+			if !ok {
+				return nil, repository.ErrNotFound
+			}
+			return &aggregate, nil
+		},
+	}
+}
+
 func TestSubmitResponse_Success(t *testing.T) {
 	// Setup mocks
 	mockLockRepo := &MockLockRepository{
 		setLockFunc: func(ctx context.Context, key string, ttl time.Duration) (bool, error) {
 			// Verify the key and ttl
-			if key != "report:lock:survey-123" || ttl != usecase.LockTTL {
-				t.Errorf("Expected key=%s, ttl=%v, got key=%s, ttl=%v", "report:lock:survey-123", usecase.LockTTL, key, ttl)
+			if key != "report:lock:default:survey-123" || ttl != usecase.LockTTL {
+				t.Errorf("Expected key=%s, ttl=%v, got key=%s, ttl=%v", "report:lock:default:survey-123", usecase.LockTTL, key, ttl)
 			}
 			return true, nil
 		},
@@ -66,6 +289,9 @@ func TestSubmitResponse_Success(t *testing.T) {
 			if job.SurveyID != "survey-123" {
 				t.Errorf("Expected SurveyID=%s, got %s", "survey-123", job.SurveyID)
 			}
+			if job.RequestedAt == 0 {
+				t.Errorf("Expected RequestedAt to be set")
+			}
 			return nil
 		},
 		consumeReportJobsFunc: func(ctx context.Context, callback func(entity.ReportJob) error) error {
@@ -88,7 +314,7 @@ func TestSubmitResponse_Success(t *testing.T) {
 	}
 
 	// Create use case and call method
-	uc := usecase.NewReportUseCase(mockLockRepo, mockQueueRepo)
+	uc := usecase.NewReportUseCase(mockLockRepo, mockQueueRepo, newStoringResponseRepository(t), newUnusedReportRepository(t), newMemoryJobRepository(), usecase.DefaultReportSettings())
 	err := uc.SubmitResponse(ctx, response)
 
 	// Assert results
@@ -102,8 +328,8 @@ func TestSubmitResponse_LockAlreadyAcquired(t *testing.T) {
 	mockLockRepo := &MockLockRepository{
 		setLockFunc: func(ctx context.Context, key string, ttl time.Duration) (bool, error) {
 			// Verify the key and ttl
-			if key != "report:lock:survey-123" || ttl != usecase.LockTTL {
-				t.Errorf("Expected key=%s, ttl=%v, got key=%s, ttl=%v", "report:lock:survey-123", usecase.LockTTL, key, ttl)
+			if key != "report:lock:default:survey-123" || ttl != usecase.LockTTL {
+				t.Errorf("Expected key=%s, ttl=%v, got key=%s, ttl=%v", "report:lock:default:survey-123", usecase.LockTTL, key, ttl)
 			}
 			// Return false to simulate lock already acquired
 			return false, nil
@@ -139,7 +365,7 @@ func TestSubmitResponse_LockAlreadyAcquired(t *testing.T) {
 	}
 
 	// Create use case and call method
-	uc := usecase.NewReportUseCase(mockLockRepo, mockQueueRepo)
+	uc := usecase.NewReportUseCase(mockLockRepo, mockQueueRepo, newStoringResponseRepository(t), newUnusedReportRepository(t), newMemoryJobRepository(), usecase.DefaultReportSettings())
 	err := uc.SubmitResponse(ctx, response)
 
 	// Assert results
@@ -154,8 +380,8 @@ func TestSubmitResponse_SetLockError(t *testing.T) {
 	mockLockRepo := &MockLockRepository{
 		setLockFunc: func(ctx context.Context, key string, ttl time.Duration) (bool, error) {
 			// Verify the key and ttl
-			if key != "report:lock:survey-123" || ttl != usecase.LockTTL {
-				t.Errorf("Expected key=%s, ttl=%v, got key=%s, ttl=%v", "report:lock:survey-123", usecase.LockTTL, key, ttl)
+			if key != "report:lock:default:survey-123" || ttl != usecase.LockTTL {
+				t.Errorf("Expected key=%s, ttl=%v, got key=%s, ttl=%v", "report:lock:default:survey-123", usecase.LockTTL, key, ttl)
 			}
 			// Return error to simulate lock error
 			return false, expectedErr
@@ -191,7 +417,7 @@ func TestSubmitResponse_SetLockError(t *testing.T) {
 	}
 
 	// Create use case and call method
-	uc := usecase.NewReportUseCase(mockLockRepo, mockQueueRepo)
+	uc := usecase.NewReportUseCase(mockLockRepo, mockQueueRepo, newStoringResponseRepository(t), newUnusedReportRepository(t), newMemoryJobRepository(), usecase.DefaultReportSettings())
 	err := uc.SubmitResponse(ctx, response)
 
 	// Assert results
@@ -210,14 +436,14 @@ func TestSubmitResponse_PublishJobError(t *testing.T) {
 	mockLockRepo := &MockLockRepository{
 		setLockFunc: func(ctx context.Context, key string, ttl time.Duration) (bool, error) {
 			// Verify the key and ttl
-			if key != "report:lock:survey-123" || ttl != usecase.LockTTL {
-				t.Errorf("Expected key=%s, ttl=%v, got key=%s, ttl=%v", "report:lock:survey-123", usecase.LockTTL, key, ttl)
+			if key != "report:lock:default:survey-123" || ttl != usecase.LockTTL {
+				t.Errorf("Expected key=%s, ttl=%v, got key=%s, ttl=%v", "report:lock:default:survey-123", usecase.LockTTL, key, ttl)
 			}
 			return true, nil
 		},
 		releaseLockFunc: func(ctx context.Context, key string) (bool, error) {
-			if key != "report:lock:survey-123" {
-				t.Errorf("Expected key=%s, got %s", "report:lock:survey-123", key)
+			if key != "report:lock:default:survey-123" {
+				t.Errorf("Expected key=%s, got %s", "report:lock:default:survey-123", key)
 			}
 			lockReleased = true
 			return true, nil
@@ -254,7 +480,7 @@ func TestSubmitResponse_PublishJobError(t *testing.T) {
 	}
 
 	// Create use case and call method
-	uc := usecase.NewReportUseCase(mockLockRepo, mockQueueRepo)
+	uc := usecase.NewReportUseCase(mockLockRepo, mockQueueRepo, newStoringResponseRepository(t), newUnusedReportRepository(t), newMemoryJobRepository(), usecase.DefaultReportSettings())
 	err := uc.SubmitResponse(ctx, response)
 
 	// Assert results
@@ -271,6 +497,63 @@ func TestSubmitResponse_PublishJobError(t *testing.T) {
 	}
 }
 
+func TestSubmitResponse_SaveResponseError(t *testing.T) {
+	// Setup mocks
+	mockLockRepo := &MockLockRepository{
+		setLockFunc: func(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+			t.Errorf("SetLock should not be called when SaveResponse returns an error")
+			return false, nil
+		},
+		releaseLockFunc: func(ctx context.Context, key string) (bool, error) {
+			t.Errorf("ReleaseLock should not be called")
+			return false, nil
+		},
+	}
+
+	mockQueueRepo := &MockQueueRepository{
+		publishReportJobFunc: func(ctx context.Context, job entity.ReportJob) error {
+			t.Errorf("PublishReportJob should not be called")
+			return nil
+		},
+		consumeReportJobsFunc: func(ctx context.Context, callback func(entity.ReportJob) error) error {
+			t.Errorf("ConsumeReportJobs should not be called")
+			return nil
+		},
+		closeFunc: func() error {
+			t.Errorf("Close should not be called")
+			return nil
+		},
+	}
+
+	mockResponseRepo := &MockResponseRepository{
+		saveResponseFunc: func(ctx context.Context, response entity.SurveyResponse) error {
+			return errors.New("storage unavailable")
+		},
+		listResponsesFunc: func(ctx context.Context, surveyID string) ([]entity.SurveyResponse, error) {
+			t.Errorf("ListResponses should not be called")
+			return nil, nil
+		},
+	}
+
+	// Create test data
+	ctx := context.Background()
+	response := entity.SurveyResponse{
+		ID:        "resp-123",
+		SurveyID:  "survey-123",
+		Answers:   map[string]interface{}{"q1": "answer1"},
+		CreatedAt: time.Now().Unix(),
+	}
+
+	// Create use case and call method
+	uc := usecase.NewReportUseCase(mockLockRepo, mockQueueRepo, mockResponseRepo, newUnusedReportRepository(t), newMemoryJobRepository(), usecase.DefaultReportSettings())
+	err := uc.SubmitResponse(ctx, response)
+
+	// Assert results
+	if err == nil || !strings.Contains(err.Error(), "failed to save response") {
+		t.Errorf("Expected error to contain 'failed to save response', got %v", err)
+	}
+}
+
 func TestGenerateReport(t *testing.T) {
 	// Setup mocks
 	mockLockRepo := &MockLockRepository{
@@ -299,16 +582,401 @@ func TestGenerateReport(t *testing.T) {
 		},
 	}
 
+	mockResponseRepo := &MockResponseRepository{
+		saveResponseFunc: func(ctx context.Context, response entity.SurveyResponse) error {
+			t.Errorf("SaveResponse should not be called")
+			return nil
+		},
+		listResponsesFunc: func(ctx context.Context, surveyID string) ([]entity.SurveyResponse, error) {
+			return []entity.SurveyResponse{
+				{ID: "resp-1", SurveyID: surveyID, Answers: map[string]interface{}{
+					"color":  "red",
+					"rating": float64(4),
+					"tags":   []interface{}{"a", "b"},
+				}},
+				{ID: "resp-2", SurveyID: surveyID, Answers: map[string]interface{}{
+					"color":   "blue",
+					"rating":  float64(2),
+					"address": map[string]interface{}{"city": "Jakarta"},
+				}},
+			}, nil
+		},
+	}
+
+	var saved *entity.Report
+	var watermark *entity.ReportWatermark
+	mockReportRepo := newUnusedReportRepository(t)
+	mockReportRepo.saveReportFunc = func(ctx context.Context, report entity.Report) error {
+		saved = &report
+		return nil
+	}
+	mockReportRepo.saveWatermarkFunc = func(ctx context.Context, w entity.ReportWatermark) error {
+		watermark = &w
+		return nil
+	}
+	mockReportRepo.getAggregateFunc = func(ctx context.Context, surveyID string) (*entity.ReportAggregate, error) {
+		return nil, repository.ErrNotFound
+	}
+	mockReportRepo.saveAggregateFunc = func(ctx context.Context, aggregate entity.ReportAggregate) error {
+		return nil
+	}
+
 	// Create test data
 	ctx := context.Background()
 	surveyID := "survey-123"
 
 	// Create use case and call method
-	uc := usecase.NewReportUseCase(mockLockRepo, mockQueueRepo)
+	uc := usecase.NewReportUseCase(mockLockRepo, mockQueueRepo, mockResponseRepo, mockReportRepo, newMemoryJobRepository(), usecase.DefaultReportSettings())
 	err := uc.GenerateReport(ctx, surveyID)
 
 	// Assert results
 	if err != nil {
-		t.Errorf("Expected no error, got %v", err)
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved == nil {
+		t.Fatalf("Expected report to be saved")
+	}
+	if saved.SurveyID != surveyID || saved.ResponseCount != 2 {
+		t.Errorf("Expected survey %s with 2 responses, got %s with %d", surveyID, saved.SurveyID, saved.ResponseCount)
+	}
+	if got := saved.Questions["color"].Counts["red"]; got != 1 {
+		t.Errorf("Expected 1 'red' answer, got %d", got)
+	}
+	if got := saved.Questions["tags"].Counts["b"]; got != 1 {
+		t.Errorf("Expected 1 'b' tag, got %d", got)
+	}
+	if got := saved.Questions["address.city"].Answered; got != 1 {
+		t.Errorf("Expected nested answer to be flattened, got %d answers", got)
+	}
+	if numeric := saved.Questions["rating"].Numeric; numeric == nil || numeric.Mean != 3 || numeric.Min != 2 || numeric.Max != 4 {
+		t.Errorf("Expected rating mean=3 min=2 max=4, got %+v", numeric)
+	}
+	if watermark == nil || watermark.Offset != 2 || watermark.ResponseCount != 2 {
+		t.Fatalf("Expected watermark up to the second response with 2 responses, got %+v", watermark)
+	}
+	if !watermark.Covers(2) || watermark.Covers(3) {
+		t.Errorf("Expected watermark to cover only jobs published for up to 2 responses")
+	}
+}
+
+func TestGenerateReport_ListResponsesError(t *testing.T) {
+	mockResponseRepo := &MockResponseRepository{
+		saveResponseFunc: func(ctx context.Context, response entity.SurveyResponse) error {
+			t.Errorf("SaveResponse should not be called")
+			return nil
+		},
+		listResponsesFunc: func(ctx context.Context, surveyID string) ([]entity.SurveyResponse, error) {
+			return nil, errors.New("storage unavailable")
+		},
+	}
+
+	mockReportRepo := newUnusedReportRepository(t)
+	mockReportRepo.getAggregateFunc = func(ctx context.Context, surveyID string) (*entity.ReportAggregate, error) {
+		return nil, repository.ErrNotFound
+	}
+
+	uc := usecase.NewReportUseCase(&MockLockRepository{}, &MockQueueRepository{}, mockResponseRepo, mockReportRepo, newMemoryJobRepository(), usecase.DefaultReportSettings())
+	err := uc.GenerateReport(context.Background(), "survey-123")
+
+	if err == nil || !strings.Contains(err.Error(), "failed to list responses") {
+		t.Errorf("Expected error to contain 'failed to list responses', got %v", err)
+	}
+}
+
+func TestSubmitResponse_InvalidResponse(t *testing.T) {
+	uc := usecase.NewReportUseCase(&MockLockRepository{}, &MockQueueRepository{}, &MockResponseRepository{}, newUnusedReportRepository(t), newMemoryJobRepository(), usecase.DefaultReportSettings())
+	err := uc.SubmitResponse(context.Background(), entity.SurveyResponse{ID: "resp-123"})
+
+	if !errors.Is(err, usecase.ErrSurveyIDRequired) {
+		t.Errorf("Expected ErrSurveyIDRequired, got %v", err)
+	}
+}
+
+func TestSubmitResponse_RejectsTenantSeparatorInSurveyID(t *testing.T) {
+	uc := usecase.NewReportUseCase(&MockLockRepository{}, &MockQueueRepository{}, &MockResponseRepository{}, newUnusedReportRepository(t), newMemoryJobRepository(), usecase.DefaultReportSettings())
+	err := uc.SubmitResponse(context.Background(), entity.SurveyResponse{ID: "resp-123", SurveyID: "acme:survey-123"})
+
+	if !errors.Is(err, usecase.ErrInvalidSurveyID) {
+		t.Errorf("Expected ErrInvalidSurveyID, got %v", err)
+	}
+}
+
+func TestSubmitResponses_SchedulesOneJobPerSurvey(t *testing.T) {
+	// Setup mocks
+	var lockKeys []string
+	mockLockRepo := &MockLockRepository{
+		setLockFunc: func(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+			lockKeys = append(lockKeys, key)
+			return true, nil
+		},
+	}
+
+	var published []string
+	mockQueueRepo := &MockQueueRepository{
+		publishReportJobFunc: func(ctx context.Context, job entity.ReportJob) error {
+			published = append(published, job.SurveyID)
+			return nil
+		},
+	}
+
+	var stored []entity.SurveyResponse
+	mockResponseRepo := &MockResponseRepository{
+		saveResponsesFunc: func(ctx context.Context, responses []entity.SurveyResponse) error {
+			stored = responses
+			return nil
+		},
+	}
+
+	// Create test data
+	responses := []entity.SurveyResponse{
+		{ID: "resp-1", SurveyID: "survey-a", Answers: map[string]interface{}{"q1": "x"}},
+		{ID: "resp-2", SurveyID: "", Answers: map[string]interface{}{"q1": "x"}},
+		{ID: "resp-3", SurveyID: "survey-b", Answers: map[string]interface{}{"q1": "y"}},
+		{ID: "resp-4", SurveyID: "survey-a", Answers: map[string]interface{}{"q1": "z"}},
+	}
+
+	// Create use case and call method
+	uc := usecase.NewReportUseCase(mockLockRepo, mockQueueRepo, mockResponseRepo, newUnusedReportRepository(t), newMemoryJobRepository(), usecase.DefaultReportSettings())
+	results, err := uc.SubmitResponses(context.Background(), responses)
+
+	// Assert results
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if results[0] != nil || results[2] != nil || results[3] != nil {
+		t.Errorf("Expected valid responses to be accepted, got %v", results)
+	}
+	if !errors.Is(results[1], usecase.ErrSurveyIDRequired) {
+		t.Errorf("Expected ErrSurveyIDRequired for the second response, got %v", results[1])
+	}
+	if len(stored) != 3 {
+		t.Errorf("Expected 3 responses stored in bulk, got %d", len(stored))
+	}
+	if strings.Join(lockKeys, ",") != "report:lock:default:survey-a,report:lock:default:survey-b" {
+		t.Errorf("Expected one lock attempt per distinct survey, got %v", lockKeys)
+	}
+	if len(published) != 2 {
+		t.Errorf("Expected one job per distinct survey, got %v", published)
+	}
+}
+
+// ratingResponses returns responses to survey-123 rating 1 to n with IDs starting at first
+func ratingResponses(first, n int) []entity.SurveyResponse {
+	responses := make([]entity.SurveyResponse, n)
+	for i := range responses {
+		responses[i] = entity.SurveyResponse{
+			ID:       fmt.Sprintf("resp-%d", first+i),
+			SurveyID: "survey-123",
+			Answers:  map[string]interface{}{"rating": float64(i%5 + 1), "color": fmt.Sprintf("c%d", i%3)},
+		}
+	}
+	return responses
+}
+
+func TestGenerateReport_FoldsOnlyNewResponses(t *testing.T) {
+	stored := ratingResponses(0, 10)
+	scanned := 0
+	mockResponseRepo := &MockResponseRepository{
+		listResponsesFunc: func(ctx context.Context, surveyID string) ([]entity.SurveyResponse, error) {
+			return stored, nil
+		},
+	}
+	mockReportRepo := newStoringReportRepository()
+
+	uc := usecase.NewReportUseCase(&MockLockRepository{}, &MockQueueRepository{}, &countingResponseRepository{mockResponseRepo, &scanned}, mockReportRepo, newMemoryJobRepository(), usecase.DefaultReportSettings())
+	ctx := context.Background()
+	if err := uc.GenerateReport(ctx, "survey-123"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The second run reads the last folded response again to verify it, then only the new ones
+	stored = append(stored, ratingResponses(10, 5)...)
+	scanned = 0
+	if err := uc.GenerateReport(ctx, "survey-123"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if scanned != 6 {
+		t.Errorf("Expected 6 responses read by the incremental run, got %d", scanned)
+	}
+
+	report, _ := mockReportRepo.getReportFunc(ctx, "survey-123")
+	if report.ResponseCount != 15 {
+		t.Errorf("Expected 15 responses, got %d", report.ResponseCount)
+	}
+	if got := report.Questions["color"].Counts["c0"]; got != 6 {
+		t.Errorf("Expected 6 'c0' answers, got %d", got)
+	}
+	if got := report.Questions["color"].Distinct; got != 3 {
+		t.Errorf("Expected 3 distinct colors, got %d", got)
+	}
+	numeric := report.Questions["rating"].Numeric
+	if numeric == nil || numeric.Count != 15 || numeric.Mean != 3 || numeric.Median != 3 {
+		t.Fatalf("Expected 15 ratings with mean and median 3, got %+v", numeric)
+	}
+	if math.Abs(numeric.StdDev-math.Sqrt(2)) > 1e-9 {
+		t.Errorf("Expected standard deviation sqrt(2), got %v", numeric.StdDev)
+	}
+
+	aggregate, _ := mockReportRepo.getAggregateFunc(ctx, "survey-123")
+	if aggregate.Offset != 15 || aggregate.LastResponseID != "resp-14" || aggregate.IncrementalRuns != 1 {
+		t.Errorf("Expected aggregate at offset 15 after 1 incremental run, got %+v", aggregate)
+	}
+}
+
+func TestGenerateReport_RecomputesWhenResponsesDrift(t *testing.T) {
+	stored := ratingResponses(0, 10)
+	mockResponseRepo := &MockResponseRepository{
+		listResponsesFunc: func(ctx context.Context, surveyID string) ([]entity.SurveyResponse, error) {
+			return stored, nil
+		},
+	}
+	mockReportRepo := newStoringReportRepository()
+
+	uc := usecase.NewReportUseCase(&MockLockRepository{}, &MockQueueRepository{}, mockResponseRepo, mockReportRepo, newMemoryJobRepository(), usecase.DefaultReportSettings())
+	ctx := context.Background()
+	if err := uc.GenerateReport(ctx, "survey-123"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The stored responses were replaced, so the folded ones no longer line up
+	stored = ratingResponses(100, 4)
+	if err := uc.GenerateReport(ctx, "survey-123"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	report, _ := mockReportRepo.getReportFunc(ctx, "survey-123")
+	if report.ResponseCount != 4 {
+		t.Errorf("Expected the report to be rebuilt from 4 responses, got %d", report.ResponseCount)
+	}
+	if report.Version != 2 {
+		t.Errorf("Expected the rebuilt report to keep counting versions, got version %d", report.Version)
+	}
+	aggregate, _ := mockReportRepo.getAggregateFunc(ctx, "survey-123")
+	if aggregate.IncrementalRuns != 0 || aggregate.LastResponseID != "resp-103" {
+		t.Errorf("Expected a full recompute up to resp-103, got %+v", aggregate)
+	}
+}
+
+func TestGenerateReport_RecomputesPeriodically(t *testing.T) {
+	mockResponseRepo := &MockResponseRepository{
+		listResponsesFunc: func(ctx context.Context, surveyID string) ([]entity.SurveyResponse, error) {
+			return ratingResponses(0, 3), nil
+		},
+	}
+	mockReportRepo := newStoringReportRepository()
+
+	settings := usecase.DefaultReportSettings()
+	settings.RecomputeEvery = 2
+	uc := usecase.NewReportUseCase(&MockLockRepository{}, &MockQueueRepository{}, mockResponseRepo, mockReportRepo, newMemoryJobRepository(), settings)
+
+	// A full run, two incremental runs, then a full run again
+	ctx := context.Background()
+	for i, want := range []int{0, 1, 2, 0} {
+		if err := uc.GenerateReport(ctx, "survey-123"); err != nil {
+			t.Fatalf("Run %d: expected no error, got %v", i, err)
+		}
+		aggregate, _ := mockReportRepo.getAggregateFunc(ctx, "survey-123")
+		if aggregate.IncrementalRuns != want || aggregate.Offset != 3 {
+			t.Errorf("Run %d: expected %d incremental runs at offset 3, got %d at %d", i, want, aggregate.IncrementalRuns, aggregate.Offset)
+		}
+	}
+}
+
+// countingResponseRepository counts the responses passed to ScanResponses callbacks
+type countingResponseRepository struct {
+	*MockResponseRepository
+	scanned *int
+}
+
+func (c *countingResponseRepository) ScanResponses(ctx context.Context, surveyID string, offset int64, fn func(entity.SurveyResponse) error) error {
+	return c.MockResponseRepository.ScanResponses(ctx, surveyID, offset, func(response entity.SurveyResponse) error {
+		*c.scanned++
+		return fn(response)
+	})
+}
+
+func TestSubmitResponse_AppliesUpdatedSurveySettings(t *testing.T) {
+	var ttls []time.Duration
+	mockLockRepo := &MockLockRepository{
+		setLockFunc: func(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+			ttls = append(ttls, ttl)
+			return true, nil
+		},
+	}
+
+	var delayed []bool
+	mockQueueRepo := &MockQueueRepository{
+		publishReportJobFunc: func(ctx context.Context, job entity.ReportJob) error {
+			delayed = append(delayed, job.NotBefore != 0)
+			return nil
+		},
+	}
+
+	uc := usecase.NewReportUseCase(mockLockRepo, mockQueueRepo, newStoringResponseRepository(t), newUnusedReportRepository(t), newMemoryJobRepository(), usecase.DefaultReportSettings())
+	submit := func(surveyID string) {
+		t.Helper()
+		response := entity.SurveyResponse{ID: "resp-" + surveyID, SurveyID: surveyID, Answers: map[string]interface{}{"q1": "answer1"}}
+		if err := uc.SubmitResponse(context.Background(), response); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	submit("survey-123")
+
+	settings := usecase.DefaultReportSettings()
+	settings.LockTTL = time.Minute
+	settings.Surveys = map[string]usecase.SurveySettings{
+		"survey-456": {LockTTL: 5 * time.Second, DebounceMode: usecase.DebounceTrailing},
+	}
+	uc.UpdateSettings(settings)
+
+	submit("survey-123")
+	submit("survey-456")
+
+	if fmt.Sprint(ttls) != "[30s 1m0s 5s]" {
+		t.Errorf("Expected the default, updated and per-survey windows, got %v", ttls)
+	}
+	if fmt.Sprint(delayed) != "[false false true]" {
+		t.Errorf("Expected only the trailing survey's job to be delayed, got %v", delayed)
+	}
+}
+
+func TestSubmitResponse_TrailingDebounceDelaysJobUntilWindowCloses(t *testing.T) {
+	settings := usecase.DefaultReportSettings()
+	settings.LockTTL = 10 * time.Second
+	settings.DebounceMode = usecase.DebounceTrailing
+
+	var published []entity.ReportJob
+	mockLockRepo := &MockLockRepository{
+		setLockFunc: func(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+			if ttl != settings.LockTTL {
+				t.Errorf("Expected the configured window %v, got %v", settings.LockTTL, ttl)
+			}
+			return true, nil
+		},
+	}
+	mockQueueRepo := &MockQueueRepository{
+		publishReportJobFunc: func(ctx context.Context, job entity.ReportJob) error {
+			published = append(published, job)
+			return nil
+		},
+	}
+	jobRepo := newMemoryJobRepository()
+
+	uc := usecase.NewReportUseCase(mockLockRepo, mockQueueRepo, newStoringResponseRepository(t), newUnusedReportRepository(t), jobRepo, settings)
+	err := uc.SubmitResponse(context.Background(), entity.SurveyResponse{ID: "resp-123", SurveyID: "survey-123"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(published) != 1 {
+		t.Fatalf("Expected one job, got %d", len(published))
+	}
+	job := published[0]
+	if delay := time.Duration(job.NotBefore - job.RequestedAt); delay < settings.LockTTL-time.Second || delay > settings.LockTTL {
+		t.Errorf("Expected the job to wait about %v for the window to close, got %v", settings.LockTTL, delay)
+	}
+	if record, err := jobRepo.GetJob(context.Background(), "survey-123", job.ID); err != nil || record.NotBefore != job.NotBefore {
+		t.Errorf("Expected the job record to carry the delay, got %+v (%v)", record, err)
 	}
 }
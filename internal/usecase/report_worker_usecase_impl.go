@@ -2,7 +2,11 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
 	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
@@ -10,21 +14,45 @@ import (
 
 // reportWorkerUseCase implements the ReportWorkerUseCase interface
 type reportWorkerUseCase struct {
-	queueRepo     repository.QueueRepository
-	reportUseCase ReportUseCase
-	ctx           context.Context
-	cancelFunc    context.CancelFunc
+	queueRepo       repository.QueueRepository
+	reportRepo      repository.ReportRepository
+	jobRepo         repository.JobRepository
+	reportUseCase   ReportUseCase
+	documentUseCase DocumentUseCase
+	notifier        ReportNotifier
+	settings        atomic.Pointer[WorkerSettings]
+	slots           *jobSlots
+	ctx             context.Context
+	cancelFunc      context.CancelFunc
+
+	generated atomic.Int64
+	skipped   atomic.Int64
+	failed    atomic.Int64
 }
 
 // NewReportWorkerUseCase creates a new report worker use case
+// documentUseCase may be nil when no report documents are rendered and
+// notifier may be nil when report outcomes are not announced
 func NewReportWorkerUseCase(
 	queueRepo repository.QueueRepository,
+	reportRepo repository.ReportRepository,
+	jobRepo repository.JobRepository,
 	reportUseCase ReportUseCase,
+	documentUseCase DocumentUseCase,
+	notifier ReportNotifier,
+	settings WorkerSettings,
 ) ReportWorkerUseCase {
-	return &reportWorkerUseCase{
-		queueRepo:     queueRepo,
-		reportUseCase: reportUseCase,
+	uc := &reportWorkerUseCase{
+		queueRepo:       queueRepo,
+		reportRepo:      reportRepo,
+		jobRepo:         jobRepo,
+		reportUseCase:   reportUseCase,
+		documentUseCase: documentUseCase,
+		notifier:        notifier,
+		slots:           newJobSlots(settings),
 	}
+	uc.settings.Store(&settings)
+	return uc
 }
 
 // StartWorker starts the worker that consumes report jobs
@@ -32,10 +60,25 @@ func (uc *reportWorkerUseCase) StartWorker(ctx context.Context) error {
 	// Create a new context with cancel function
 	uc.ctx, uc.cancelFunc = context.WithCancel(ctx)
 
+	// Ask for enough jobs to keep every slot busy
+	if err := uc.queueRepo.SetPrefetch(uc.settings.Load().prefetch()); err != nil {
+		return fmt.Errorf("failed to start worker: %w", err)
+	}
+
 	// Start consuming jobs
 	err := uc.queueRepo.ConsumeReportJobs(uc.ctx, func(job entity.ReportJob) error {
+		// Every read and write of the job goes to its tenant's keys
+		ctx := entity.WithTenant(uc.ctx, job.TenantID)
+		tenantID := entity.TenantFromContext(ctx)
+
+		// Wait for a free slot so at most Concurrency jobs are processed at once
+		if err := uc.slots.acquire(ctx, tenantID); err != nil {
+			return err
+		}
+		defer uc.slots.release(tenantID)
+
 		// Process the job by calling the report use case
-		return uc.processJob(uc.ctx, job)
+		return uc.processJob(ctx, job)
 	})
 
 	if err != nil {
@@ -56,15 +99,302 @@ func (uc *reportWorkerUseCase) StopWorker() error {
 	return nil
 }
 
+// UpdateSettings resizes the worker while it runs
+// Lowering the concurrency lets the jobs being processed finish and holds back
+// new ones until enough of them have
+func (uc *reportWorkerUseCase) UpdateSettings(settings WorkerSettings) error {
+	uc.settings.Store(&settings)
+	uc.slots.resize(settings)
+
+	if err := uc.queueRepo.SetPrefetch(settings.prefetch()); err != nil {
+		return fmt.Errorf("failed to update worker settings: %w", err)
+	}
+	return nil
+}
+
+// Stats returns counters of the jobs handled since the worker was created
+func (uc *reportWorkerUseCase) Stats() WorkerStats {
+	return WorkerStats{
+		Generated: uc.generated.Load(),
+		Skipped:   uc.skipped.Load(),
+		Failed:    uc.failed.Load(),
+	}
+}
+
 // processJob processes a report job
 func (uc *reportWorkerUseCase) processJob(ctx context.Context, job entity.ReportJob) error {
 	fmt.Printf("Processing report job for survey ID: %s\n", job.SurveyID)
 
+	// Skip jobs already covered by a report generated after they were requested.
+	// Returning nil acknowledges the job so duplicates are collapsed, not retried
+	covered, err := uc.isCovered(ctx, job)
+	if err != nil {
+		// A missing watermark must never block a report, so fall through and generate
+		fmt.Printf("Error reading watermark for survey ID %s: %v\n", job.SurveyID, err)
+	}
+	if covered {
+		uc.skipped.Add(1)
+		fmt.Printf("Skipping report job for survey ID: %s, already covered by a newer report\n", job.SurveyID)
+		uc.recordJob(ctx, job, func(record *entity.JobRecord) {
+			record.Status = entity.JobStatusSkipped
+			record.FinishedAt = time.Now().UnixNano()
+		})
+		return nil
+	}
+
+	attempts := 0
+	uc.recordJob(ctx, job, func(record *entity.JobRecord) {
+		record.Status = entity.JobStatusRunning
+		record.StartedAt = time.Now().UnixNano()
+		record.Attempts++
+		record.Error = ""
+		attempts = record.Attempts
+	})
+
 	// Call the report use case to generate the report
-	err := uc.reportUseCase.GenerateReport(ctx, job.SurveyID)
+	err = uc.reportUseCase.GenerateReport(ctx, job.SurveyID)
 	if err != nil {
+		uc.failed.Add(1)
+		uc.recordJob(ctx, job, func(record *entity.JobRecord) {
+			record.Status = entity.JobStatusFailed
+			record.FinishedAt = time.Now().UnixNano()
+			record.Error = err.Error()
+		})
+		// The queue retries failed jobs, so only the first failure of a job is announced
+		if attempts <= 1 {
+			uc.notify(job.SurveyID, func(notifier ReportNotifier) error {
+				return notifier.NotifyReportFailed(ctx, job.SurveyID, err)
+			})
+		}
 		return fmt.Errorf("failed to generate report: %w", err)
 	}
 
+	// Render the shareable documents from the new report. A failure keeps the
+	// previous documents and does not fail the job: the report itself is saved,
+	// and retrying would be skipped as covered by its watermark anyway
+	if uc.documentUseCase != nil {
+		if err := uc.documentUseCase.RenderDocuments(ctx, job.SurveyID); err != nil {
+			fmt.Printf("Error rendering report documents for survey ID %s: %v\n", job.SurveyID, err)
+		}
+	}
+
+	uc.generated.Add(1)
+	uc.recordJob(ctx, job, func(record *entity.JobRecord) {
+		record.Status = entity.JobStatusCompleted
+		record.FinishedAt = time.Now().UnixNano()
+	})
+	uc.notify(job.SurveyID, func(notifier ReportNotifier) error {
+		return notifier.NotifyReportCompleted(ctx, job.SurveyID)
+	})
 	return nil
 }
+
+// notify announces a report outcome through the notifier, if one is configured
+// Failures are logged because notifications must never fail a report job
+func (uc *reportWorkerUseCase) notify(surveyID string, announce func(ReportNotifier) error) {
+	if uc.notifier == nil {
+		return
+	}
+	if err := announce(uc.notifier); err != nil {
+		fmt.Printf("Error notifying report outcome for survey ID %s: %v\n", surveyID, err)
+	}
+}
+
+// recordJob applies an update to the job's status record
+// Failures are logged because job status must never block report generation
+func (uc *reportWorkerUseCase) recordJob(ctx context.Context, job entity.ReportJob, update func(*entity.JobRecord)) {
+	if job.ID == "" {
+		// Jobs published before job tracking existed have no record
+		return
+	}
+
+	record, err := uc.jobRepo.GetJob(ctx, job.SurveyID, job.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		record = &entity.JobRecord{
+			ID:          job.ID,
+			SurveyID:    job.SurveyID,
+			RequestedAt: job.RequestedAt,
+			NotBefore:   job.NotBefore,
+		}
+	} else if err != nil {
+		fmt.Printf("Error reading report job %s: %v\n", job.ID, err)
+		return
+	}
+
+	update(record)
+	if err := uc.jobRepo.SaveJob(ctx, *record); err != nil {
+		fmt.Printf("Error recording report job %s: %v\n", job.ID, err)
+	}
+}
+
+// isCovered reports whether the latest completed report already includes
+// every response the job was published for. A delayed job promises to
+// include the responses stored until it is due, so those are counted now
+func (uc *reportWorkerUseCase) isCovered(ctx context.Context, job entity.ReportJob) (bool, error) {
+	watermark, err := uc.reportRepo.GetWatermark(ctx, job.SurveyID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	count := job.ResponseCount
+	if job.NotBefore != 0 || count == 0 {
+		if count, err = uc.reportUseCase.CountResponses(ctx, job.SurveyID); err != nil {
+			return false, err
+		}
+	}
+	return watermark.Covers(count), nil
+}
+
+// prefetch returns the number of jobs to have delivered ahead of processing
+func (s WorkerSettings) prefetch() int {
+	return max(s.Prefetch, s.Concurrency, 1)
+}
+
+// jobSlots limits how many jobs are processed at once, in total and per tenant
+// The limits can change while slots are held: lowering them lets their jobs
+// finish and holds back new jobs until enough slots are released
+type jobSlots struct {
+	mu          sync.Mutex
+	limit       int
+	tenantLimit int
+	fair        bool
+	used        int
+	running     map[string]int
+	waiting     map[string]int
+	changed     chan struct{}
+
+	// grants numbers the slots handed out, and granted holds the number of
+	// the last slot each running or waiting tenant was given
+	grants  uint64
+	granted map[string]uint64
+}
+
+// newJobSlots creates slots for the worker's concurrency, at least one
+func newJobSlots(settings WorkerSettings) *jobSlots {
+	s := &jobSlots{
+		running: make(map[string]int),
+		waiting: make(map[string]int),
+		granted: make(map[string]uint64),
+		changed: make(chan struct{}),
+	}
+	s.resize(settings)
+	return s
+}
+
+// acquire waits for a free slot for a job of the tenant until ctx is canceled
+func (s *jobSlots) acquire(ctx context.Context, tenantID string) error {
+	s.mu.Lock()
+	s.waiting[tenantID]++
+	for {
+		if s.free(tenantID) {
+			s.done(s.waiting, tenantID)
+			s.running[tenantID]++
+			s.used++
+			s.grants++
+			s.granted[tenantID] = s.grants
+			s.mu.Unlock()
+			return nil
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			s.mu.Lock()
+			s.done(s.waiting, tenantID)
+			// Another tenant may have been held back for this one
+			s.wake()
+			s.mu.Unlock()
+			return ctx.Err()
+		}
+		s.mu.Lock()
+	}
+}
+
+// free reports whether a job of the tenant may take a slot now; callers hold mu
+// In fair mode a slot goes to the waiting tenants with the fewest running
+// jobs first and, among those, to the one served longest ago, so a tenant
+// with many delivered jobs takes turns with the others instead of every slot
+func (s *jobSlots) free(tenantID string) bool {
+	if s.used >= s.limit {
+		return false
+	}
+	if s.tenantLimit > 0 && s.running[tenantID] >= s.tenantLimit {
+		return false
+	}
+	if !s.fair {
+		return true
+	}
+
+	for other := range s.waiting {
+		if other == tenantID || (s.tenantLimit > 0 && s.running[other] >= s.tenantLimit) {
+			continue
+		}
+		if s.running[other] < s.running[tenantID] ||
+			(s.running[other] == s.running[tenantID] && s.granted[other] < s.granted[tenantID]) {
+			return false
+		}
+	}
+	return true
+}
+
+// release frees a slot taken by acquire for a job of the tenant
+func (s *jobSlots) release(tenantID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done(s.running, tenantID)
+	s.used--
+	s.wake()
+}
+
+// resize changes the limits and scheduling mode; the total limit is at least one
+func (s *jobSlots) resize(settings WorkerSettings) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit = max(settings.Concurrency, 1)
+	s.tenantLimit = max(settings.TenantConcurrency, 0)
+	s.fair = settings.Scheduling == SchedulingFair
+	s.wake()
+}
+
+// done decrements the tenant's count, forgetting tenants that reach zero and
+// have no other jobs, so they are first in line again when they return; callers hold mu
+func (s *jobSlots) done(counts map[string]int, tenantID string) {
+	if counts[tenantID]--; counts[tenantID] <= 0 {
+		delete(counts, tenantID)
+	}
+	if s.running[tenantID] == 0 && s.waiting[tenantID] == 0 {
+		delete(s.granted, tenantID)
+	}
+}
+
+// wake lets every waiting acquire check for a free slot again; callers hold mu
+func (s *jobSlots) wake() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// ReportNotifiers announces report outcomes through every notifier in turn
+type ReportNotifiers []ReportNotifier
+
+// NotifyReportCompleted announces the completed report through every notifier
+func (n ReportNotifiers) NotifyReportCompleted(ctx context.Context, surveyID string) error {
+	var errs []error
+	for _, notifier := range n {
+		errs = append(errs, notifier.NotifyReportCompleted(ctx, surveyID))
+	}
+	return errors.Join(errs...)
+}
+
+// NotifyReportFailed announces the failed report through every notifier
+func (n ReportNotifiers) NotifyReportFailed(ctx context.Context, surveyID string, cause error) error {
+	var errs []error
+	for _, notifier := range n {
+		errs = append(errs, notifier.NotifyReportFailed(ctx, surveyID, cause))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,456 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// MockReportUseCase is a manual mock for the ReportUseCase interface
+// Methods the tests do not call fall through to the nil embedded interface
+type MockReportUseCase struct {
+	usecase.ReportUseCase
+	generateReportFunc func(ctx context.Context, surveyID string) error
+	requestReportFunc  func(ctx context.Context, surveyID string) (*entity.ReportJob, error)
+	countResponsesFunc func(ctx context.Context, surveyID string) (int64, error)
+}
+
+func (m *MockReportUseCase) GenerateReport(ctx context.Context, surveyID string) error {
+	return m.generateReportFunc(ctx, surveyID)
+}
+
+func (m *MockReportUseCase) UpdateSettings(settings usecase.ReportSettings) {}
+
+func (m *MockReportUseCase) RequestReport(ctx context.Context, surveyID string) (*entity.ReportJob, error) {
+	return m.requestReportFunc(ctx, surveyID)
+}
+
+func (m *MockReportUseCase) CountResponses(ctx context.Context, surveyID string) (int64, error) {
+	return m.countResponsesFunc(ctx, surveyID)
+}
+
+// startWorkerWithJobs starts a worker whose queue delivers the given jobs synchronously
+// and returns the error the callback returned for each job
+func startWorkerWithJobs(t *testing.T, worker func(repository.QueueRepository) usecase.ReportWorkerUseCase, jobs ...entity.ReportJob) (usecase.ReportWorkerUseCase, []error) {
+	var results []error
+	queue := &MockQueueRepository{
+		consumeReportJobsFunc: func(ctx context.Context, callback func(entity.ReportJob) error) error {
+			for _, job := range jobs {
+				results = append(results, callback(job))
+			}
+			return nil
+		},
+	}
+
+	w := worker(queue)
+	if err := w.StartWorker(context.Background()); err != nil {
+		t.Fatalf("Expected worker to start, got %v", err)
+	}
+	t.Cleanup(func() { w.StopWorker() })
+
+	return w, results
+}
+
+func TestWorker_SkipsJobCoveredByNewerReport(t *testing.T) {
+	reportRepo := newUnusedReportRepository(t)
+	reportRepo.getWatermarkFunc = func(ctx context.Context, surveyID string) (*entity.ReportWatermark, error) {
+		return &entity.ReportWatermark{SurveyID: surveyID, Offset: 200}, nil
+	}
+
+	generated := 0
+	reportUseCase := &MockReportUseCase{
+		generateReportFunc: func(ctx context.Context, surveyID string) error {
+			generated++
+			return nil
+		},
+	}
+
+	// The worker's clock plays no part: only the number of responses each job must cover
+	worker, results := startWorkerWithJobs(t, func(queue repository.QueueRepository) usecase.ReportWorkerUseCase {
+		return usecase.NewReportWorkerUseCase(queue, reportRepo, newMemoryJobRepository(), reportUseCase, nil, nil, usecase.DefaultWorkerSettings())
+	},
+		entity.ReportJob{SurveyID: "survey-123", RequestedAt: 300, ResponseCount: 150},
+		entity.ReportJob{SurveyID: "survey-123", RequestedAt: 100, ResponseCount: 250},
+	)
+
+	for i, err := range results {
+		if err != nil {
+			t.Errorf("Expected job %d to be acknowledged, got %v", i, err)
+		}
+	}
+	if generated != 1 {
+		t.Errorf("Expected only the uncovered job to generate a report, got %d", generated)
+	}
+	if stats := worker.Stats(); stats.Skipped != 1 || stats.Generated != 1 {
+		t.Errorf("Expected 1 skipped and 1 generated, got %+v", stats)
+	}
+}
+
+func TestWorker_GeneratesDelayedJobWithResponsesInItsWindow(t *testing.T) {
+	reportRepo := newUnusedReportRepository(t)
+	reportRepo.getWatermarkFunc = func(ctx context.Context, surveyID string) (*entity.ReportWatermark, error) {
+		return &entity.ReportWatermark{SurveyID: surveyID, Offset: 200}, nil
+	}
+
+	generated := 0
+	reportUseCase := &MockReportUseCase{
+		generateReportFunc: func(ctx context.Context, surveyID string) error {
+			generated++
+			return nil
+		},
+		countResponsesFunc: func(ctx context.Context, surveyID string) (int64, error) {
+			return 250, nil
+		},
+	}
+
+	// Covered when published, but responses stored in its window since are missing
+	_, results := startWorkerWithJobs(t, func(queue repository.QueueRepository) usecase.ReportWorkerUseCase {
+		return usecase.NewReportWorkerUseCase(queue, reportRepo, newMemoryJobRepository(), reportUseCase, nil, nil, usecase.DefaultWorkerSettings())
+	}, entity.ReportJob{SurveyID: "survey-123", RequestedAt: 150, NotBefore: 250, ResponseCount: 150})
+
+	if len(results) != 1 || results[0] != nil {
+		t.Fatalf("Expected the job to be acknowledged, got %v", results)
+	}
+	if generated != 1 {
+		t.Errorf("Expected the delayed job to generate a report, got %d", generated)
+	}
+}
+
+func TestWorker_GeneratesWithoutWatermark(t *testing.T) {
+	reportRepo := newUnusedReportRepository(t)
+	reportRepo.getWatermarkFunc = func(ctx context.Context, surveyID string) (*entity.ReportWatermark, error) {
+		return nil, repository.ErrNotFound
+	}
+
+	reportUseCase := &MockReportUseCase{
+		generateReportFunc: func(ctx context.Context, surveyID string) error {
+			return errors.New("storage unavailable")
+		},
+	}
+
+	worker, results := startWorkerWithJobs(t, func(queue repository.QueueRepository) usecase.ReportWorkerUseCase {
+		return usecase.NewReportWorkerUseCase(queue, reportRepo, newMemoryJobRepository(), reportUseCase, nil, nil, usecase.DefaultWorkerSettings())
+	}, entity.ReportJob{SurveyID: "survey-123", RequestedAt: 100})
+
+	if len(results) != 1 || results[0] == nil {
+		t.Fatalf("Expected the failed job to be returned for retry, got %v", results)
+	}
+	if stats := worker.Stats(); stats.Failed != 1 || stats.Skipped != 0 {
+		t.Errorf("Expected 1 failed and 0 skipped, got %+v", stats)
+	}
+}
+
+// recordingNotifier records the report outcomes announced by the worker
+type recordingNotifier struct {
+	completed []string
+	failed    []string
+}
+
+func (n *recordingNotifier) NotifyReportCompleted(ctx context.Context, surveyID string) error {
+	n.completed = append(n.completed, surveyID)
+	return nil
+}
+
+func (n *recordingNotifier) NotifyReportFailed(ctx context.Context, surveyID string, cause error) error {
+	n.failed = append(n.failed, surveyID)
+	return nil
+}
+
+func TestWorker_NotifiesFirstFailureOnly(t *testing.T) {
+	reportRepo := newUnusedReportRepository(t)
+	reportRepo.getWatermarkFunc = func(ctx context.Context, surveyID string) (*entity.ReportWatermark, error) {
+		return nil, repository.ErrNotFound
+	}
+
+	attempts := 0
+	reportUseCase := &MockReportUseCase{
+		generateReportFunc: func(ctx context.Context, surveyID string) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("storage unavailable")
+			}
+			return nil
+		},
+	}
+
+	// The same job delivered three times, as the queue does when it retries a failed job
+	notifier := &recordingNotifier{}
+	job := entity.ReportJob{ID: "job-1", SurveyID: "survey-123", RequestedAt: 100}
+	startWorkerWithJobs(t, func(queue repository.QueueRepository) usecase.ReportWorkerUseCase {
+		return usecase.NewReportWorkerUseCase(queue, reportRepo, newMemoryJobRepository(), reportUseCase, nil, notifier, usecase.DefaultWorkerSettings())
+	}, job, job, job)
+
+	if len(notifier.failed) != 1 {
+		t.Errorf("Expected the failure to be announced once, got %d", len(notifier.failed))
+	}
+	if len(notifier.completed) != 1 || notifier.completed[0] != "survey-123" {
+		t.Errorf("Expected the completed report to be announced, got %v", notifier.completed)
+	}
+}
+
+func TestWorker_LimitsConcurrency(t *testing.T) {
+	reportRepo := newUnusedReportRepository(t)
+	reportRepo.getWatermarkFunc = func(ctx context.Context, surveyID string) (*entity.ReportWatermark, error) {
+		return nil, repository.ErrNotFound
+	}
+
+	var mu sync.Mutex
+	running, peak := 0, 0
+	reportUseCase := &MockReportUseCase{
+		generateReportFunc: func(ctx context.Context, surveyID string) error {
+			mu.Lock()
+			running++
+			peak = max(peak, running)
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return errors.New("storage unavailable")
+		},
+	}
+
+	// The queue hands every job to the worker at once, like a RabbitMQ consumer with a large prefetch
+	var wg sync.WaitGroup
+	queue := &MockQueueRepository{
+		consumeReportJobsFunc: func(ctx context.Context, callback func(entity.ReportJob) error) error {
+			for i := 0; i < 6; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					callback(entity.ReportJob{ID: fmt.Sprintf("job-%d", i), SurveyID: fmt.Sprintf("survey-%d", i)})
+				}()
+			}
+			return nil
+		},
+	}
+
+	worker := usecase.NewReportWorkerUseCase(queue, reportRepo, newMemoryJobRepository(), reportUseCase, nil, nil, usecase.WorkerSettings{Concurrency: 2})
+	if err := worker.StartWorker(context.Background()); err != nil {
+		t.Fatalf("Expected worker to start, got %v", err)
+	}
+	defer worker.StopWorker()
+	wg.Wait()
+
+	if peak != 2 {
+		t.Errorf("Expected 2 jobs to be generated at once, got %d", peak)
+	}
+	if stats := worker.Stats(); stats.Failed != 6 {
+		t.Errorf("Expected 6 failed jobs, got %+v", stats)
+	}
+}
+
+func TestWorker_ResizesWithoutDroppingJobs(t *testing.T) {
+	reportRepo := newUnusedReportRepository(t)
+	reportRepo.getWatermarkFunc = func(ctx context.Context, surveyID string) (*entity.ReportWatermark, error) {
+		return nil, repository.ErrNotFound
+	}
+
+	started := make(chan string, 4)
+	release := make(chan struct{})
+	reportUseCase := &MockReportUseCase{
+		generateReportFunc: func(ctx context.Context, surveyID string) error {
+			started <- surveyID
+			<-release
+			return nil
+		},
+	}
+
+	var mu sync.Mutex
+	var prefetches []int
+	var wg sync.WaitGroup
+	queue := &MockQueueRepository{
+		consumeReportJobsFunc: func(ctx context.Context, callback func(entity.ReportJob) error) error {
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					callback(entity.ReportJob{ID: fmt.Sprintf("job-%d", i), SurveyID: fmt.Sprintf("survey-%d", i)})
+				}()
+			}
+			return nil
+		},
+		setPrefetchFunc: func(prefetch int) error {
+			mu.Lock()
+			defer mu.Unlock()
+			prefetches = append(prefetches, prefetch)
+			return nil
+		},
+	}
+
+	worker := usecase.NewReportWorkerUseCase(queue, reportRepo, newMemoryJobRepository(), reportUseCase, nil, nil, usecase.WorkerSettings{Concurrency: 1})
+	if err := worker.StartWorker(context.Background()); err != nil {
+		t.Fatalf("Expected worker to start, got %v", err)
+	}
+	defer worker.StopWorker()
+
+	waitStarted := func(n int) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			select {
+			case <-started:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("Expected %d more jobs to start", n-i)
+			}
+		}
+	}
+	waitStarted(1)
+
+	// Raising the concurrency lets two more of the waiting jobs start
+	if err := worker.UpdateSettings(usecase.WorkerSettings{Concurrency: 3}); err != nil {
+		t.Fatalf("Expected settings to update, got %v", err)
+	}
+	waitStarted(2)
+
+	// Lowering it again keeps the three running jobs going and holds back the last one
+	if err := worker.UpdateSettings(usecase.WorkerSettings{Concurrency: 1, Prefetch: 2}); err != nil {
+		t.Fatalf("Expected settings to update, got %v", err)
+	}
+	select {
+	case surveyID := <-started:
+		t.Fatalf("Expected %s to wait for a free slot", surveyID)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	waitStarted(1)
+	wg.Wait()
+
+	if stats := worker.Stats(); stats.Generated != 4 {
+		t.Errorf("Expected every job to be generated, got %+v", stats)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if fmt.Sprint(prefetches) != "[1 3 2]" {
+		t.Errorf("Expected the prefetch to follow the settings, got %v", prefetches)
+	}
+}
+
+func TestWorker_SharesSlotsFairlyBetweenTenants(t *testing.T) {
+	reportRepo := newUnusedReportRepository(t)
+	reportRepo.getWatermarkFunc = func(ctx context.Context, surveyID string) (*entity.ReportWatermark, error) {
+		return nil, repository.ErrNotFound
+	}
+
+	started := make(chan string, 5)
+	release := make(chan struct{})
+	reportUseCase := &MockReportUseCase{
+		generateReportFunc: func(ctx context.Context, surveyID string) error {
+			started <- entity.TenantFromContext(ctx) + "/" + surveyID
+			<-release
+			return nil
+		},
+	}
+
+	// One tenant's backlog is delivered ahead of another tenant's single job
+	var wg sync.WaitGroup
+	deliver := func(callback func(entity.ReportJob) error, tenantID, surveyID string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			callback(entity.ReportJob{ID: tenantID + "-" + surveyID, SurveyID: surveyID, TenantID: tenantID})
+		}()
+	}
+	queue := &MockQueueRepository{
+		consumeReportJobsFunc: func(ctx context.Context, callback func(entity.ReportJob) error) error {
+			deliver(callback, "big", "survey-1")
+			time.Sleep(20 * time.Millisecond)
+			deliver(callback, "big", "survey-2")
+			deliver(callback, "big", "survey-3")
+			deliver(callback, "big", "survey-4")
+			time.Sleep(20 * time.Millisecond)
+			deliver(callback, "", "survey-1")
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		},
+	}
+
+	settings := usecase.WorkerSettings{Concurrency: 1, Scheduling: usecase.SchedulingFair}
+	worker := usecase.NewReportWorkerUseCase(queue, reportRepo, newMemoryJobRepository(), reportUseCase, nil, nil, settings)
+	if err := worker.StartWorker(context.Background()); err != nil {
+		t.Fatalf("Expected worker to start, got %v", err)
+	}
+	defer worker.StopWorker()
+
+	var order []string
+	for i := 0; i < 5; i++ {
+		select {
+		case job := <-started:
+			order = append(order, job)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected 5 jobs to start, got %v", order)
+		}
+		release <- struct{}{}
+	}
+	wg.Wait()
+
+	// The default tenant's job runs as soon as the first slot frees up, not after the backlog
+	if order[0] != "big/survey-1" || order[1] != "default/survey-1" {
+		t.Errorf("Expected the default tenant's job to run second, got %v", order)
+	}
+}
+
+func TestWorker_LimitsTenantConcurrency(t *testing.T) {
+	reportRepo := newUnusedReportRepository(t)
+	reportRepo.getWatermarkFunc = func(ctx context.Context, surveyID string) (*entity.ReportWatermark, error) {
+		return nil, repository.ErrNotFound
+	}
+
+	var mu sync.Mutex
+	running, peak := map[string]int{}, map[string]int{}
+	reportUseCase := &MockReportUseCase{
+		generateReportFunc: func(ctx context.Context, surveyID string) error {
+			tenantID := entity.TenantFromContext(ctx)
+			mu.Lock()
+			running[tenantID]++
+			peak[tenantID] = max(peak[tenantID], running[tenantID])
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			running[tenantID]--
+			mu.Unlock()
+			return nil
+		},
+	}
+
+	var wg sync.WaitGroup
+	queue := &MockQueueRepository{
+		consumeReportJobsFunc: func(ctx context.Context, callback func(entity.ReportJob) error) error {
+			for i := 0; i < 6; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					tenantID := []string{"acme", "globex"}[i%2]
+					callback(entity.ReportJob{ID: fmt.Sprintf("job-%d", i), SurveyID: fmt.Sprintf("survey-%d", i), TenantID: tenantID})
+				}()
+			}
+			return nil
+		},
+	}
+
+	settings := usecase.WorkerSettings{Concurrency: 4, TenantConcurrency: 2}
+	worker := usecase.NewReportWorkerUseCase(queue, reportRepo, newMemoryJobRepository(), reportUseCase, nil, nil, settings)
+	if err := worker.StartWorker(context.Background()); err != nil {
+		t.Fatalf("Expected worker to start, got %v", err)
+	}
+	defer worker.StopWorker()
+	wg.Wait()
+
+	if peak["acme"] != 2 || peak["globex"] != 2 {
+		t.Errorf("Expected each tenant to run 2 jobs at once, got %v", peak)
+	}
+	if stats := worker.Stats(); stats.Generated != 6 {
+		t.Errorf("Expected 6 generated jobs, got %+v", stats)
+	}
+}
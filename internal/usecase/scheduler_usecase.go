@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// SchedulerLeaseKey is the key of the leader lease held by the instance that runs schedules
+const SchedulerLeaseKey = "scheduler:leader"
+
+// ErrInvalidSchedule is returned when a schedule is rejected
+var ErrInvalidSchedule = entity.NewError(entity.ErrorInvalid, "invalid_schedule", "invalid schedule")
+
+// SchedulerSettings holds the tunables of the report scheduler
+type SchedulerSettings struct {
+	// TickInterval is how often the leader looks up due schedules
+	TickInterval time.Duration
+}
+
+// DefaultSchedulerSettings returns the settings used when none are configured
+func DefaultSchedulerSettings() SchedulerSettings {
+	return SchedulerSettings{
+		TickInterval: time.Second,
+	}
+}
+
+// SchedulerUseCase defines the interface for scheduled report generation
+// Every instance runs the scheduler, but only the elected leader publishes
+// the report jobs of due schedules
+type SchedulerUseCase interface {
+	// CreateSchedule schedules report generation for a survey, either
+	// periodically from a cron expression or once at a Unix time.
+	// Returns ErrInvalidSchedule unless exactly one valid cron or future at is given
+	CreateSchedule(ctx context.Context, surveyID, cron string, at int64) (*entity.Schedule, error)
+
+	// ListSchedules returns the schedules of the given survey ID, oldest first
+	ListSchedules(ctx context.Context, surveyID string) ([]entity.Schedule, error)
+
+	// DeleteSchedule removes a schedule
+	// Returns repository.ErrNotFound if there is no such schedule
+	DeleteSchedule(ctx context.Context, surveyID, scheduleID string) error
+
+	// StartScheduler starts campaigning for leadership and running due schedules
+	StartScheduler(ctx context.Context) error
+
+	// StopScheduler stops the scheduler and resigns leadership
+	StopScheduler() error
+}
@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	"github.com/robfig/cron/v3"
+)
+
+// schedulerUseCase implements the SchedulerUseCase interface
+type schedulerUseCase struct {
+	scheduleRepo  repository.ScheduleRepository
+	elector       repository.LeaderElector
+	reportUseCase ReportUseCase
+	idGenerator   IDGenerator
+	settings      SchedulerSettings
+	cancelFunc    context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+// NewSchedulerUseCase creates a new scheduler use case
+// elector must campaign for SchedulerLeaseKey, shared by every instance
+func NewSchedulerUseCase(
+	scheduleRepo repository.ScheduleRepository,
+	elector repository.LeaderElector,
+	reportUseCase ReportUseCase,
+	idGenerator IDGenerator,
+	settings SchedulerSettings,
+) SchedulerUseCase {
+	return &schedulerUseCase{
+		scheduleRepo:  scheduleRepo,
+		elector:       elector,
+		reportUseCase: reportUseCase,
+		idGenerator:   idGenerator,
+		settings:      settings,
+	}
+}
+
+// CreateSchedule schedules report generation for a survey
+func (uc *schedulerUseCase) CreateSchedule(ctx context.Context, surveyID, expr string, at int64) (*entity.Schedule, error) {
+	if surveyID == "" {
+		return nil, fmt.Errorf("%w: survey ID is required", ErrInvalidSchedule)
+	}
+	if (expr == "") == (at == 0) {
+		return nil, fmt.Errorf("%w: exactly one of cron and at is required", ErrInvalidSchedule)
+	}
+
+	now := time.Now()
+	schedule := entity.Schedule{
+		ID:        uc.idGenerator.NewID(),
+		SurveyID:  surveyID,
+		TenantID:  tenantOf(ctx),
+		Cron:      expr,
+		At:        at,
+		CreatedAt: now.Unix(),
+	}
+
+	if expr != "" {
+		next, err := nextCronRun(expr, now)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+		}
+		schedule.NextRunAt = next
+	} else {
+		if at <= now.Unix() {
+			return nil, fmt.Errorf("%w: at must be in the future", ErrInvalidSchedule)
+		}
+		schedule.NextRunAt = at
+	}
+
+	if err := uc.scheduleRepo.SaveSchedule(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to save schedule: %w", err)
+	}
+
+	return &schedule, nil
+}
+
+// ListSchedules returns the schedules of the given survey ID
+func (uc *schedulerUseCase) ListSchedules(ctx context.Context, surveyID string) ([]entity.Schedule, error) {
+	return uc.scheduleRepo.ListSchedules(ctx, surveyID)
+}
+
+// DeleteSchedule removes a schedule
+func (uc *schedulerUseCase) DeleteSchedule(ctx context.Context, surveyID, scheduleID string) error {
+	return uc.scheduleRepo.DeleteSchedule(ctx, surveyID, scheduleID)
+}
+
+// StartScheduler starts campaigning for leadership and the loop that runs due schedules
+func (uc *schedulerUseCase) StartScheduler(ctx context.Context) error {
+	ctx, uc.cancelFunc = context.WithCancel(ctx)
+
+	uc.wg.Add(2)
+	go func() {
+		defer uc.wg.Done()
+		uc.lead(ctx)
+	}()
+	go func() {
+		defer uc.wg.Done()
+
+		ticker := time.NewTicker(uc.settings.TickInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if uc.elector.IsLeader() {
+				uc.runDue(ctx, time.Now())
+			}
+		}
+	}()
+
+	fmt.Println("Report scheduler started successfully")
+	return nil
+}
+
+// StopScheduler stops both loops and resigns so another instance can take
+// over without waiting for the leader lease to expire
+func (uc *schedulerUseCase) StopScheduler() error {
+	if uc.cancelFunc != nil {
+		uc.cancelFunc()
+	}
+	uc.wg.Wait()
+
+	if err := uc.elector.Resign(context.Background()); err != nil {
+		return fmt.Errorf("failed to resign scheduler leadership: %w", err)
+	}
+
+	fmt.Println("Report scheduler stopped")
+	return nil
+}
+
+// lead campaigns for leadership and campaigns again whenever it is lost
+func (uc *schedulerUseCase) lead(ctx context.Context) {
+	for {
+		if err := uc.elector.Campaign(ctx); err != nil {
+			return
+		}
+
+		for leading := true; leading; {
+			select {
+			case <-ctx.Done():
+				return
+			case leading = <-uc.elector.Leadership():
+			}
+		}
+	}
+}
+
+// runDue publishes a report job for every schedule due at now and advances it
+// A schedule is advanced only after its job was published, so a leader that
+// dies in between makes the next leader publish it again rather than miss it
+func (uc *schedulerUseCase) runDue(ctx context.Context, now time.Time) {
+	schedules, err := uc.scheduleRepo.ListDueSchedules(ctx, now.Unix())
+	if err != nil {
+		fmt.Printf("Error listing due schedules: %v\n", err)
+		return
+	}
+
+	for _, schedule := range schedules {
+		// Due schedules of every tenant are listed together, so each runs as its own tenant
+		ctx := entity.WithTenant(ctx, schedule.TenantID)
+		job, err := uc.reportUseCase.RequestReport(ctx, schedule.SurveyID)
+		if err != nil {
+			// Left due, so the next tick tries again
+			fmt.Printf("Error running schedule %s for survey ID %s: %v\n", schedule.ID, schedule.SurveyID, err)
+			continue
+		}
+
+		schedule.LastRunAt = now.Unix()
+		schedule.LastJobID = job.ID
+		schedule.NextRunAt = 0
+		if schedule.Cron != "" {
+			// Runs missed while no instance was leading are skipped, not caught up
+			if schedule.NextRunAt, err = nextCronRun(schedule.Cron, now); err != nil {
+				fmt.Printf("Error parsing cron of schedule %s: %v\n", schedule.ID, err)
+			}
+		}
+
+		// The schedule may have been deleted while its report was requested
+		err = uc.scheduleRepo.UpdateSchedule(ctx, schedule)
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			fmt.Printf("Schedule %s was deleted while it ran, not saving it\n", schedule.ID)
+		case err != nil:
+			fmt.Printf("Error saving schedule %s: %v\n", schedule.ID, err)
+		}
+	}
+}
+
+// nextCronRun returns the first time after the given time that the cron expression
+// fires, in Unix seconds. Expressions without a CRON_TZ prefix are read in UTC
+func nextCronRun(expr string, after time.Time) (int64, error) {
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return 0, err
+	}
+
+	next := schedule.Next(after.UTC())
+	if next.IsZero() {
+		return 0, fmt.Errorf("cron expression %q never fires", expr)
+	}
+
+	return next.Unix(), nil
+}
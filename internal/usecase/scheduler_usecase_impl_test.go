@@ -0,0 +1,343 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	"github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/election"
+	memoryRepo "github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/memory"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// memoryScheduleRepository is an in-memory ScheduleRepository
+type memoryScheduleRepository struct {
+	mu        sync.Mutex
+	schedules map[string]entity.Schedule
+}
+
+func newMemoryScheduleRepository() *memoryScheduleRepository {
+	return &memoryScheduleRepository{schedules: make(map[string]entity.Schedule)}
+}
+
+func (r *memoryScheduleRepository) SaveSchedule(ctx context.Context, schedule entity.Schedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schedules[schedule.ID] = schedule
+	return nil
+}
+
+func (r *memoryScheduleRepository) UpdateSchedule(ctx context.Context, schedule entity.Schedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.schedules[schedule.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	r.schedules[schedule.ID] = schedule
+	return nil
+}
+
+func (r *memoryScheduleRepository) GetSchedule(ctx context.Context, surveyID, scheduleID string) (*entity.Schedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	schedule, ok := r.schedules[scheduleID]
+	if !ok || schedule.SurveyID != surveyID {
+		return nil, repository.ErrNotFound
+	}
+	return &schedule, nil
+}
+
+func (r *memoryScheduleRepository) ListSchedules(ctx context.Context, surveyID string) ([]entity.Schedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var schedules []entity.Schedule
+	for _, schedule := range r.schedules {
+		if schedule.SurveyID == surveyID {
+			schedules = append(schedules, schedule)
+		}
+	}
+	return schedules, nil
+}
+
+func (r *memoryScheduleRepository) ListDueSchedules(ctx context.Context, now int64) ([]entity.Schedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var schedules []entity.Schedule
+	for _, schedule := range r.schedules {
+		if schedule.NextRunAt > 0 && schedule.NextRunAt <= now {
+			schedules = append(schedules, schedule)
+		}
+	}
+	return schedules, nil
+}
+
+func (r *memoryScheduleRepository) DeleteSchedule(ctx context.Context, surveyID, scheduleID string) error {
+	if _, err := r.GetSchedule(ctx, surveyID, scheduleID); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.schedules, scheduleID)
+	return nil
+}
+
+// requestRecorder is a ReportUseCase that records report requests on a channel
+func requestRecorder(requests chan string) *MockReportUseCase {
+	var mu sync.Mutex
+	published := 0
+	return &MockReportUseCase{
+		requestReportFunc: func(ctx context.Context, surveyID string) (*entity.ReportJob, error) {
+			mu.Lock()
+			published++
+			id := fmt.Sprintf("job-%d", published)
+			mu.Unlock()
+			requests <- surveyID
+			return &entity.ReportJob{ID: id, SurveyID: surveyID}, nil
+		},
+	}
+}
+
+var testSchedulerSettings = usecase.SchedulerSettings{TickInterval: 10 * time.Millisecond}
+
+// schedulerIDs names the lease holders of every scheduler started by the tests
+var schedulerIDs = &sequenceIDGenerator{}
+
+// newSchedulerElector returns an elector campaigning on leaseRepo under a new holder name
+func newSchedulerElector(leaseRepo repository.LeaseRepository) repository.LeaderElector {
+	settings := election.Settings{LeaseTTL: time.Minute, RenewInterval: 10 * time.Millisecond, RetryInterval: 10 * time.Millisecond}
+	return election.NewLeaderElector(leaseRepo, usecase.SchedulerLeaseKey, schedulerIDs.NewID(), settings, nil)
+}
+
+func newMemoryLeaseRepository() repository.LeaseRepository {
+	return memoryRepo.NewLeaseRepository(time.Now)
+}
+
+func startScheduler(t *testing.T, scheduleRepo repository.ScheduleRepository, leaseRepo repository.LeaseRepository, reportUseCase usecase.ReportUseCase) usecase.SchedulerUseCase {
+	uc := usecase.NewSchedulerUseCase(scheduleRepo, newSchedulerElector(leaseRepo), reportUseCase, &sequenceIDGenerator{}, testSchedulerSettings)
+	if err := uc.StartScheduler(context.Background()); err != nil {
+		t.Fatalf("Expected scheduler to start, got %v", err)
+	}
+	t.Cleanup(func() { uc.StopScheduler() })
+	return uc
+}
+
+func expectRequest(t *testing.T, requests chan string, surveyID string) {
+	t.Helper()
+	select {
+	case got := <-requests:
+		if got != surveyID {
+			t.Fatalf("Expected a report request for %s, got %s", surveyID, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected a report request for %s", surveyID)
+	}
+}
+
+func expectNoRequest(t *testing.T, requests chan string) {
+	t.Helper()
+	select {
+	case got := <-requests:
+		t.Fatalf("Expected no report request, got one for %s", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestCreateSchedule_Validation(t *testing.T) {
+	uc := usecase.NewSchedulerUseCase(newMemoryScheduleRepository(), newSchedulerElector(newMemoryLeaseRepository()), &MockReportUseCase{}, &sequenceIDGenerator{}, testSchedulerSettings)
+	future := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name string
+		cron string
+		at   int64
+	}{
+		{"neither", "", 0},
+		{"both", "@daily", future},
+		{"bad cron", "61 * * * *", 0},
+		{"past at", "", time.Now().Add(-time.Minute).Unix()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.CreateSchedule(context.Background(), "survey-123", tt.cron, tt.at)
+			if !errors.Is(err, usecase.ErrInvalidSchedule) {
+				t.Errorf("Expected ErrInvalidSchedule, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateSchedule_ComputesNextRunInUTC(t *testing.T) {
+	uc := usecase.NewSchedulerUseCase(newMemoryScheduleRepository(), newSchedulerElector(newMemoryLeaseRepository()), &MockReportUseCase{}, &sequenceIDGenerator{}, testSchedulerSettings)
+
+	utc, err := uc.CreateSchedule(context.Background(), "survey-123", "30 9 * * *", 0)
+	if err != nil {
+		t.Fatalf("Expected schedule to be created, got %v", err)
+	}
+	next := time.Unix(utc.NextRunAt, 0).UTC()
+	if next.Hour() != 9 || next.Minute() != 30 || time.Until(next) > 24*time.Hour || time.Until(next) <= 0 {
+		t.Errorf("Expected next run at the coming 09:30 UTC, got %v", next)
+	}
+
+	zoned, err := uc.CreateSchedule(context.Background(), "survey-123", "CRON_TZ=Asia/Jakarta 30 9 * * *", 0)
+	if err != nil {
+		t.Fatalf("Expected schedule to be created, got %v", err)
+	}
+	// Asia/Jakarta is UTC+7 without daylight saving
+	if next := time.Unix(zoned.NextRunAt, 0).UTC(); next.Hour() != 2 || next.Minute() != 30 {
+		t.Errorf("Expected next run at 02:30 UTC, got %v", next)
+	}
+}
+
+func TestScheduler_OnlyLeaderRunsDueSchedules(t *testing.T) {
+	scheduleRepo := newMemoryScheduleRepository()
+	leaseRepo := newMemoryLeaseRepository()
+	requests := make(chan string, 10)
+
+	now := time.Now()
+	scheduleRepo.SaveSchedule(context.Background(), entity.Schedule{
+		ID:        "schedule-1",
+		SurveyID:  "survey-123",
+		Cron:      "@hourly",
+		NextRunAt: now.Add(-time.Second).Unix(),
+	})
+
+	startScheduler(t, scheduleRepo, leaseRepo, requestRecorder(requests))
+	startScheduler(t, scheduleRepo, leaseRepo, requestRecorder(requests))
+
+	expectRequest(t, requests, "survey-123")
+	expectNoRequest(t, requests)
+
+	schedule, err := scheduleRepo.GetSchedule(context.Background(), "survey-123", "schedule-1")
+	if err != nil {
+		t.Fatalf("Expected schedule to exist, got %v", err)
+	}
+	if schedule.LastJobID != "job-1" || schedule.LastRunAt < now.Unix() {
+		t.Errorf("Expected the run to be recorded, got %+v", schedule)
+	}
+	if next := time.Unix(schedule.NextRunAt, 0); !next.After(now) || next.Minute() != 0 {
+		t.Errorf("Expected next run at the coming full hour, got %v", next)
+	}
+}
+
+func TestScheduler_OneOffFiresOnce(t *testing.T) {
+	scheduleRepo := newMemoryScheduleRepository()
+	requests := make(chan string, 10)
+
+	scheduleRepo.SaveSchedule(context.Background(), entity.Schedule{
+		ID:        "schedule-1",
+		SurveyID:  "survey-123",
+		At:        time.Now().Add(-time.Second).Unix(),
+		NextRunAt: time.Now().Add(-time.Second).Unix(),
+	})
+
+	startScheduler(t, scheduleRepo, newMemoryLeaseRepository(), requestRecorder(requests))
+
+	expectRequest(t, requests, "survey-123")
+	expectNoRequest(t, requests)
+
+	schedule, _ := scheduleRepo.GetSchedule(context.Background(), "survey-123", "schedule-1")
+	if schedule.NextRunAt != 0 {
+		t.Errorf("Expected a fired one-off schedule to have no next run, got %d", schedule.NextRunAt)
+	}
+}
+
+func TestScheduler_FailedRequestStaysDue(t *testing.T) {
+	scheduleRepo := newMemoryScheduleRepository()
+	due := time.Now().Add(-time.Second).Unix()
+	scheduleRepo.SaveSchedule(context.Background(), entity.Schedule{
+		ID:        "schedule-1",
+		SurveyID:  "survey-123",
+		Cron:      "@hourly",
+		NextRunAt: due,
+	})
+
+	attempts := make(chan struct{}, 10)
+	reportUseCase := &MockReportUseCase{
+		requestReportFunc: func(ctx context.Context, surveyID string) (*entity.ReportJob, error) {
+			attempts <- struct{}{}
+			return nil, errors.New("queue unavailable")
+		},
+	}
+
+	startScheduler(t, scheduleRepo, newMemoryLeaseRepository(), reportUseCase)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-attempts:
+		case <-time.After(time.Second):
+			t.Fatalf("Expected the due schedule to be retried on every tick")
+		}
+	}
+
+	schedule, _ := scheduleRepo.GetSchedule(context.Background(), "survey-123", "schedule-1")
+	if schedule.NextRunAt != due || schedule.LastJobID != "" {
+		t.Errorf("Expected the schedule to stay due, got %+v", schedule)
+	}
+}
+
+func TestScheduler_DeletedWhileRunningStaysDeleted(t *testing.T) {
+	scheduleRepo := newMemoryScheduleRepository()
+	scheduleRepo.SaveSchedule(context.Background(), entity.Schedule{
+		ID:        "schedule-1",
+		SurveyID:  "survey-123",
+		Cron:      "@hourly",
+		NextRunAt: time.Now().Add(-time.Second).Unix(),
+	})
+
+	// The schedule is deleted while its report is being requested
+	requests := make(chan string, 10)
+	reportUseCase := &MockReportUseCase{
+		requestReportFunc: func(ctx context.Context, surveyID string) (*entity.ReportJob, error) {
+			scheduleRepo.DeleteSchedule(ctx, surveyID, "schedule-1")
+			requests <- surveyID
+			return &entity.ReportJob{ID: "job-1", SurveyID: surveyID}, nil
+		},
+	}
+
+	uc := startScheduler(t, scheduleRepo, newMemoryLeaseRepository(), reportUseCase)
+	expectRequest(t, requests, "survey-123")
+	uc.StopScheduler()
+
+	if _, err := scheduleRepo.GetSchedule(context.Background(), "survey-123", "schedule-1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected the deleted schedule not to be saved again, got %v", err)
+	}
+}
+
+func TestScheduler_StandbyTakesOverAfterLeaderStops(t *testing.T) {
+	scheduleRepo := newMemoryScheduleRepository()
+	leaseRepo := newMemoryLeaseRepository()
+	leaderRequests := make(chan string, 10)
+	standbyRequests := make(chan string, 10)
+
+	leader := startScheduler(t, scheduleRepo, leaseRepo, requestRecorder(leaderRequests))
+	// Give the first scheduler time to be elected
+	time.Sleep(30 * time.Millisecond)
+	startScheduler(t, scheduleRepo, leaseRepo, requestRecorder(standbyRequests))
+
+	scheduleRepo.SaveSchedule(context.Background(), entity.Schedule{
+		ID:        "schedule-1",
+		SurveyID:  "survey-123",
+		Cron:      "@hourly",
+		NextRunAt: time.Now().Add(-time.Second).Unix(),
+	})
+	expectRequest(t, leaderRequests, "survey-123")
+
+	if err := leader.StopScheduler(); err != nil {
+		t.Fatalf("Expected scheduler to stop, got %v", err)
+	}
+
+	// The stopped leader released its lease, so the standby runs the next due schedule
+	scheduleRepo.SaveSchedule(context.Background(), entity.Schedule{
+		ID:        "schedule-2",
+		SurveyID:  "survey-456",
+		At:        time.Now().Add(-time.Second).Unix(),
+		NextRunAt: time.Now().Add(-time.Second).Unix(),
+	})
+	expectRequest(t, standbyRequests, "survey-456")
+	expectNoRequest(t, leaderRequests)
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+var (
+	// ErrInvalidResponse is wrapped by every response validation error
+	ErrInvalidResponse = entity.NewError(entity.ErrorInvalid, "invalid_response", "invalid response")
+
+	// ErrSurveyIDRequired is returned when a response has no survey ID
+	ErrSurveyIDRequired = fmt.Errorf("%w: survey ID is required", ErrInvalidResponse)
+
+	// ErrInvalidSurveyID is returned when a survey ID contains the ":"
+	// separating tenants from surveys in storage keys
+	ErrInvalidSurveyID = entity.NewError(entity.ErrorInvalid, "invalid_survey_id", `survey IDs must not contain ":"`)
+
+	// ErrEmptyQuestion is returned when a response has an answer without a question key
+	ErrEmptyQuestion = fmt.Errorf("%w: answer keys must not be empty", ErrInvalidResponse)
+)
+
+// ValidateSurveyID checks that a survey ID can name a survey
+func ValidateSurveyID(surveyID string) error {
+	if surveyID == "" {
+		return ErrSurveyIDRequired
+	}
+	if !entity.ValidSurveyID(surveyID) {
+		return fmt.Errorf("%w: %q", ErrInvalidSurveyID, surveyID)
+	}
+	return nil
+}
+
+// ValidateResponse checks that a survey response can be stored
+func ValidateResponse(response entity.SurveyResponse) error {
+	if err := ValidateSurveyID(response.SurveyID); err != nil {
+		return err
+	}
+
+	for question := range response.Answers {
+		if question == "" {
+			return ErrEmptyQuestion
+		}
+	}
+
+	return nil
+}
+
+// CheckAnswerLimits checks that a response has at most maxAnswers answers and
+// that arrays and objects nest at most maxDepth deep in every answer
+func CheckAnswerLimits(answers map[string]interface{}, maxAnswers, maxDepth int) error {
+	if len(answers) > maxAnswers {
+		return fmt.Errorf("%w: %d answers exceed the limit of %d", ErrInvalidResponse, len(answers), maxAnswers)
+	}
+	for question, answer := range answers {
+		if answerDepth(answer) > maxDepth {
+			return fmt.Errorf("%w: answer to %q exceeds the nesting depth of %d", ErrInvalidResponse, question, maxDepth)
+		}
+	}
+	return nil
+}
+
+// answerDepth returns how deeply arrays and objects nest in a decoded JSON
+// value, zero for a plain value
+func answerDepth(value interface{}) int {
+	depth := 0
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			depth = max(depth, answerDepth(item))
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			depth = max(depth, answerDepth(item))
+		}
+	default:
+		return 0
+	}
+	return depth + 1
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	// WebhookSignatureHeader carries the payload signature, "sha256=" followed by the hex HMAC
+	WebhookSignatureHeader = "X-Webhook-Signature"
+
+	// WebhookTimestampHeader carries the Unix time the payload was signed at
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+
+	// WebhookEventHeader carries the event name of the payload
+	WebhookEventHeader = "X-Webhook-Event"
+
+	// WebhookDeliveryHeader carries the delivery ID, the same for every attempt of a delivery
+	WebhookDeliveryHeader = "X-Webhook-Delivery"
+
+	// signaturePrefix names the signature algorithm
+	signaturePrefix = "sha256="
+)
+
+// SignWebhook returns the signature of a payload signed at the given Unix time
+// It is the HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook's secret,
+// so a receiver can reject payloads replayed long after they were signed
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature reports whether signature is the valid signature of
+// a payload signed at the given Unix time, comparing in constant time
+func VerifyWebhookSignature(secret, signature string, timestamp int64, body []byte) bool {
+	return hmac.Equal([]byte(signature), []byte(SignWebhook(secret, timestamp, body)))
+}
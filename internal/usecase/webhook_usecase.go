@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"net/netip"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+)
+
+// ErrInvalidWebhook is returned when a webhook registration is rejected
+var ErrInvalidWebhook = entity.NewError(entity.ErrorInvalid, "invalid_webhook", "invalid webhook")
+
+// WebhookSettings holds the tunables of webhook delivery
+type WebhookSettings struct {
+	// MaxAttempts is the number of attempts made before a delivery is given up
+	MaxAttempts int
+
+	// BaseBackoff is the delay before the first retry; each further retry doubles it
+	BaseBackoff time.Duration
+
+	// MaxBackoff caps the delay between two attempts
+	MaxBackoff time.Duration
+
+	// Timeout bounds how long a receiver may take to answer one attempt
+	Timeout time.Duration
+
+	// AllowedNetworks are the loopback, private or link-local networks webhooks
+	// may still be delivered to, such as local receivers in tests; every other
+	// address that is not public is refused
+	AllowedNetworks []netip.Prefix
+}
+
+// DefaultWebhookSettings returns the settings used when none are configured
+func DefaultWebhookSettings() WebhookSettings {
+	return WebhookSettings{
+		MaxAttempts: 8,
+		BaseBackoff: 5 * time.Second,
+		MaxBackoff:  30 * time.Minute,
+		Timeout:     10 * time.Second,
+	}
+}
+
+// WebhookSender defines the interface for posting a webhook payload to a receiver
+type WebhookSender interface {
+	// Send posts body with the given headers to url and returns the receiver's
+	// HTTP status code. An error means no response was received
+	Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, error)
+}
+
+// WebhookUseCase defines the interface for webhook registration and delivery
+type WebhookUseCase interface {
+	ReportNotifier
+
+	// RegisterWebhook registers a URL to be notified about the survey's reports
+	// A random signing secret is generated when secret is empty.
+	// Returns ErrInvalidWebhook if the URL is not an absolute http or https URL,
+	// or names a host that is not public and not in the allowed networks
+	RegisterWebhook(ctx context.Context, surveyID, url, secret string) (*entity.Webhook, error)
+
+	// ListWebhooks returns the webhooks registered for the given survey ID
+	ListWebhooks(ctx context.Context, surveyID string) ([]entity.Webhook, error)
+
+	// DeleteWebhook removes a webhook; deliveries still queued for it are dropped
+	// Returns repository.ErrNotFound if there is no such webhook
+	DeleteWebhook(ctx context.Context, surveyID, webhookID string) error
+
+	// ListDeliveries returns the delivery log for the given survey ID, oldest first
+	ListDeliveries(ctx context.Context, surveyID string) ([]entity.WebhookDeliveryAttempt, error)
+
+	// StartDispatcher starts consuming the delivery queue and posting payloads
+	StartDispatcher(ctx context.Context) error
+
+	// StopDispatcher stops the dispatcher
+	StopDispatcher() error
+}
@@ -0,0 +1,332 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+)
+
+// webhookUseCase implements the WebhookUseCase interface
+type webhookUseCase struct {
+	webhookRepo repository.WebhookRepository
+	queueRepo   repository.WebhookQueueRepository
+	reportRepo  repository.ReportRepository
+	sender      WebhookSender
+	idGenerator IDGenerator
+	settings    WebhookSettings
+	ctx         context.Context
+	cancelFunc  context.CancelFunc
+}
+
+// NewWebhookUseCase creates a new webhook use case
+func NewWebhookUseCase(
+	webhookRepo repository.WebhookRepository,
+	queueRepo repository.WebhookQueueRepository,
+	reportRepo repository.ReportRepository,
+	sender WebhookSender,
+	idGenerator IDGenerator,
+	settings WebhookSettings,
+) WebhookUseCase {
+	return &webhookUseCase{
+		webhookRepo: webhookRepo,
+		queueRepo:   queueRepo,
+		reportRepo:  reportRepo,
+		sender:      sender,
+		idGenerator: idGenerator,
+		settings:    settings,
+	}
+}
+
+// RegisterWebhook registers a URL to be notified about the survey's reports
+func (uc *webhookUseCase) RegisterWebhook(ctx context.Context, surveyID, rawURL, secret string) (*entity.Webhook, error) {
+	if surveyID == "" {
+		return nil, fmt.Errorf("%w: survey ID is required", ErrInvalidWebhook)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+	}
+	if !webhookHostAllowed(u.Hostname(), uc.settings.AllowedNetworks) {
+		return nil, fmt.Errorf("%w: url must not point to a loopback, private or link-local address", ErrInvalidWebhook)
+	}
+
+	if secret == "" {
+		if secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	webhook := entity.Webhook{
+		ID:        uc.idGenerator.NewID(),
+		SurveyID:  surveyID,
+		URL:       u.String(),
+		Secret:    secret,
+		CreatedAt: time.Now().Unix(),
+	}
+	if err := uc.webhookRepo.SaveWebhook(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to save webhook: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+// webhookHostAllowed reports whether a webhook URL may name host: host names
+// other than localhost are only checked once they are resolved, when the
+// receiver is dialed
+func webhookHostAllowed(host string, allowed []netip.Prefix) bool {
+	host = strings.ToLower(host)
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return WebhookAddressAllowed(netip.AddrFrom4([4]byte{127, 0, 0, 1}), allowed)
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return true
+	}
+	return WebhookAddressAllowed(addr, allowed)
+}
+
+// WebhookAddressAllowed reports whether webhooks may be delivered to addr:
+// public addresses always may, loopback, private, link-local, multicast and
+// unspecified ones, cloud metadata endpoints included, only when they are in
+// one of the allowed networks
+func WebhookAddressAllowed(addr netip.Addr, allowed []netip.Prefix) bool {
+	addr = addr.Unmap().WithZone("")
+	for _, network := range allowed {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+// ListWebhooks returns the webhooks registered for the given survey ID
+func (uc *webhookUseCase) ListWebhooks(ctx context.Context, surveyID string) ([]entity.Webhook, error) {
+	return uc.webhookRepo.ListWebhooks(ctx, surveyID)
+}
+
+// DeleteWebhook removes a webhook
+func (uc *webhookUseCase) DeleteWebhook(ctx context.Context, surveyID, webhookID string) error {
+	return uc.webhookRepo.DeleteWebhook(ctx, surveyID, webhookID)
+}
+
+// ListDeliveries returns the delivery log for the given survey ID
+func (uc *webhookUseCase) ListDeliveries(ctx context.Context, surveyID string) ([]entity.WebhookDeliveryAttempt, error) {
+	return uc.webhookRepo.ListDeliveryAttempts(ctx, surveyID)
+}
+
+// NotifyReportCompleted queues a report.completed delivery to every webhook of the survey
+func (uc *webhookUseCase) NotifyReportCompleted(ctx context.Context, surveyID string) error {
+	report, err := uc.reportRepo.GetReport(ctx, surveyID)
+	if err != nil {
+		return fmt.Errorf("failed to get report: %w", err)
+	}
+
+	return uc.notify(ctx, entity.WebhookPayload{
+		Event:         entity.WebhookEventReportCompleted,
+		SurveyID:      surveyID,
+		ReportVersion: report.Version,
+		Summary:       report,
+		OccurredAt:    time.Now().Unix(),
+	})
+}
+
+// NotifyReportFailed queues a report.failed delivery to every webhook of the survey
+// The payload carries the version of the last report that did complete, if any,
+// and a stable error code; the cause is logged rather than sent to the receiver
+func (uc *webhookUseCase) NotifyReportFailed(ctx context.Context, surveyID string, cause error) error {
+	fmt.Printf("Notifying webhooks of failed report for survey ID %s: %v\n", surveyID, cause)
+
+	code := entity.WebhookErrorReportFailed
+	if domainErr := entity.ErrorOf(cause); domainErr != nil {
+		code = domainErr.Code
+	}
+	payload := entity.WebhookPayload{
+		Event:      entity.WebhookEventReportFailed,
+		SurveyID:   surveyID,
+		Error:      code,
+		OccurredAt: time.Now().Unix(),
+	}
+
+	report, err := uc.reportRepo.GetReport(ctx, surveyID)
+	switch {
+	case err == nil:
+		payload.ReportVersion = report.Version
+	case !errors.Is(err, repository.ErrNotFound):
+		return fmt.Errorf("failed to get report: %w", err)
+	}
+
+	return uc.notify(ctx, payload)
+}
+
+// notify queues one delivery of the payload per webhook registered for its survey
+func (uc *webhookUseCase) notify(ctx context.Context, payload entity.WebhookPayload) error {
+	webhooks, err := uc.webhookRepo.ListWebhooks(ctx, payload.SurveyID)
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	var errs []error
+	for _, webhook := range webhooks {
+		delivery := entity.WebhookDelivery{
+			ID:        uc.idGenerator.NewID(),
+			WebhookID: webhook.ID,
+			SurveyID:  webhook.SurveyID,
+			TenantID:  tenantOf(ctx),
+			Event:     payload.Event,
+			Payload:   body,
+			Attempt:   1,
+			CreatedAt: time.Now().Unix(),
+		}
+		if err := uc.queueRepo.PublishDelivery(ctx, delivery); err != nil {
+			errs = append(errs, fmt.Errorf("failed to queue delivery to webhook %s: %w", webhook.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// StartDispatcher starts consuming the delivery queue and posting payloads
+func (uc *webhookUseCase) StartDispatcher(ctx context.Context) error {
+	uc.ctx, uc.cancelFunc = context.WithCancel(ctx)
+
+	err := uc.queueRepo.ConsumeDeliveries(uc.ctx, func(delivery entity.WebhookDelivery) error {
+		return uc.deliver(entity.WithTenant(uc.ctx, delivery.TenantID), delivery)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start webhook dispatcher: %w", err)
+	}
+
+	fmt.Println("Webhook dispatcher started successfully")
+	return nil
+}
+
+// StopDispatcher stops the dispatcher
+func (uc *webhookUseCase) StopDispatcher() error {
+	if uc.cancelFunc != nil {
+		uc.cancelFunc()
+	}
+
+	fmt.Println("Webhook dispatcher stopped")
+	return nil
+}
+
+// deliver makes one attempt to post a delivery and records it in the delivery log
+// A failed attempt is published again with a backoff delay until MaxAttempts is
+// reached; an error is only returned when the retry itself could not be queued,
+// so the queue redelivers the attempt instead of losing it
+func (uc *webhookUseCase) deliver(ctx context.Context, delivery entity.WebhookDelivery) error {
+	webhook, err := uc.webhookRepo.GetWebhook(ctx, delivery.SurveyID, delivery.WebhookID)
+	if errors.Is(err, repository.ErrNotFound) {
+		fmt.Printf("Dropping delivery %s: webhook %s was deleted\n", delivery.ID, delivery.WebhookID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	attempt := entity.WebhookDeliveryAttempt{
+		DeliveryID: delivery.ID,
+		WebhookID:  webhook.ID,
+		SurveyID:   webhook.SurveyID,
+		Event:      delivery.Event,
+		URL:        webhook.URL,
+		Attempt:    delivery.Attempt,
+	}
+
+	start := time.Now()
+	attempt.StatusCode, err = uc.send(ctx, webhook, delivery)
+	attempt.AttemptedAt = start.UnixNano()
+	attempt.DurationMs = time.Since(start).Milliseconds()
+
+	switch {
+	case err == nil && attempt.StatusCode >= 200 && attempt.StatusCode < 300:
+		attempt.Status = entity.DeliveryStatusDelivered
+	case err == nil:
+		attempt.Error = fmt.Sprintf("receiver answered with HTTP %d", attempt.StatusCode)
+	default:
+		attempt.Error = err.Error()
+	}
+
+	if attempt.Status == "" {
+		if delivery.Attempt < uc.settings.MaxAttempts {
+			retry := delivery
+			retry.Attempt++
+			retry.NotBefore = time.Now().Add(uc.backoff(delivery.Attempt)).UnixNano()
+			if err := uc.queueRepo.PublishDelivery(ctx, retry); err != nil {
+				return fmt.Errorf("failed to queue retry of delivery %s: %w", delivery.ID, err)
+			}
+			attempt.Status = entity.DeliveryStatusRetrying
+		} else {
+			attempt.Status = entity.DeliveryStatusFailed
+			fmt.Printf("Giving up delivery %s to webhook %s after %d attempts\n", delivery.ID, webhook.ID, delivery.Attempt)
+		}
+	}
+
+	// The attempt was made either way, so a failure to log it must not repeat it
+	if err := uc.webhookRepo.SaveDeliveryAttempt(ctx, attempt); err != nil {
+		fmt.Printf("Error recording delivery %s: %v\n", delivery.ID, err)
+	}
+
+	return nil
+}
+
+// send signs the delivery's payload with the webhook's secret and posts it
+func (uc *webhookUseCase) send(ctx context.Context, webhook *entity.Webhook, delivery entity.WebhookDelivery) (int, error) {
+	if uc.settings.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, uc.settings.Timeout)
+		defer cancel()
+	}
+
+	timestamp := time.Now().Unix()
+	headers := map[string]string{
+		"Content-Type":         "application/json",
+		WebhookEventHeader:     delivery.Event,
+		WebhookDeliveryHeader:  delivery.ID,
+		WebhookTimestampHeader: strconv.FormatInt(timestamp, 10),
+		WebhookSignatureHeader: SignWebhook(webhook.Secret, timestamp, delivery.Payload),
+	}
+
+	return uc.sender.Send(ctx, webhook.URL, headers, delivery.Payload)
+}
+
+// backoff returns the delay after the given failed attempt, doubling from
+// BaseBackoff and capped at MaxBackoff
+func (uc *webhookUseCase) backoff(attempt int) time.Duration {
+	delay := uc.settings.BaseBackoff
+	for i := 1; i < attempt && delay < uc.settings.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if uc.settings.MaxBackoff > 0 && delay > uc.settings.MaxBackoff {
+		delay = uc.settings.MaxBackoff
+	}
+	return delay
+}
+
+// newWebhookSecret returns a random signing secret
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,351 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rfanazhari/distributed-queue-processor/domain/entity"
+	"github.com/rfanazhari/distributed-queue-processor/domain/repository"
+	"github.com/rfanazhari/distributed-queue-processor/internal/infrastructure/webhook"
+	"github.com/rfanazhari/distributed-queue-processor/internal/usecase"
+)
+
+// memoryWebhookRepository is an in-memory WebhookRepository for tests
+type memoryWebhookRepository struct {
+	mu       sync.Mutex
+	webhooks map[string]entity.Webhook
+	attempts []entity.WebhookDeliveryAttempt
+}
+
+func newMemoryWebhookRepository() *memoryWebhookRepository {
+	return &memoryWebhookRepository{webhooks: make(map[string]entity.Webhook)}
+}
+
+func (m *memoryWebhookRepository) SaveWebhook(ctx context.Context, webhook entity.Webhook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.webhooks[webhook.ID] = webhook
+	return nil
+}
+
+func (m *memoryWebhookRepository) GetWebhook(ctx context.Context, surveyID, webhookID string) (*entity.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	webhook, ok := m.webhooks[webhookID]
+	if !ok || webhook.SurveyID != surveyID {
+		return nil, repository.ErrNotFound
+	}
+	return &webhook, nil
+}
+
+func (m *memoryWebhookRepository) ListWebhooks(ctx context.Context, surveyID string) ([]entity.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var webhooks []entity.Webhook
+	for _, webhook := range m.webhooks {
+		if webhook.SurveyID == surveyID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (m *memoryWebhookRepository) DeleteWebhook(ctx context.Context, surveyID, webhookID string) error {
+	if _, err := m.GetWebhook(ctx, surveyID, webhookID); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.webhooks, webhookID)
+	return nil
+}
+
+func (m *memoryWebhookRepository) SaveDeliveryAttempt(ctx context.Context, attempt entity.WebhookDeliveryAttempt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.attempts = append(m.attempts, attempt)
+	return nil
+}
+
+func (m *memoryWebhookRepository) ListDeliveryAttempts(ctx context.Context, surveyID string) ([]entity.WebhookDeliveryAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]entity.WebhookDeliveryAttempt(nil), m.attempts...), nil
+}
+
+// memoryWebhookQueue holds published deliveries until the test pumps them
+// to the consumer, ignoring their delay so retries run immediately
+type memoryWebhookQueue struct {
+	pending  []entity.WebhookDelivery
+	callback func(entity.WebhookDelivery) error
+}
+
+func (q *memoryWebhookQueue) PublishDelivery(ctx context.Context, delivery entity.WebhookDelivery) error {
+	q.pending = append(q.pending, delivery)
+	return nil
+}
+
+func (q *memoryWebhookQueue) ConsumeDeliveries(ctx context.Context, callback func(entity.WebhookDelivery) error) error {
+	q.callback = callback
+	return nil
+}
+
+func (q *memoryWebhookQueue) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (q *memoryWebhookQueue) Close() error {
+	return nil
+}
+
+// pump delivers queued deliveries until the queue is empty and returns every
+// delivery it handed to the consumer
+func (q *memoryWebhookQueue) pump(t *testing.T) []entity.WebhookDelivery {
+	var handled []entity.WebhookDelivery
+	for len(q.pending) > 0 {
+		delivery := q.pending[0]
+		q.pending = q.pending[1:]
+		if err := q.callback(delivery); err != nil {
+			t.Fatalf("Expected delivery %s to be acknowledged, got %v", delivery.ID, err)
+		}
+		handled = append(handled, delivery)
+	}
+	return handled
+}
+
+// sequenceIDGenerator returns IDs numbered in order
+type sequenceIDGenerator struct {
+	next int
+}
+
+func (g *sequenceIDGenerator) NewID() string {
+	g.next++
+	return fmt.Sprintf("id-%d", g.next)
+}
+
+// webhookReceiver is a local webhook endpoint that verifies signatures and
+// answers with the next status from statuses, then 200 once they run out
+type webhookReceiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	payloads []entity.WebhookPayload
+}
+
+func newWebhookReceiver(t *testing.T, secret string, statuses ...int) *webhookReceiver {
+	receiver := &webhookReceiver{statuses: statuses}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(usecase.WebhookTimestampHeader), 10, 64)
+		if !usecase.VerifyWebhookSignature(secret, r.Header.Get(usecase.WebhookSignatureHeader), timestamp, body) {
+			t.Errorf("Expected a valid signature, got %q", r.Header.Get(usecase.WebhookSignatureHeader))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var payload entity.WebhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("Expected a JSON payload, got %v", err)
+		}
+		if event := r.Header.Get(usecase.WebhookEventHeader); event != payload.Event {
+			t.Errorf("Expected event header %q, got %q", payload.Event, event)
+		}
+
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		receiver.payloads = append(receiver.payloads, payload)
+		status := http.StatusOK
+		if len(receiver.statuses) > 0 {
+			status, receiver.statuses = receiver.statuses[0], receiver.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+// newWebhookTest returns a started webhook use case delivering to a registered receiver
+func newWebhookTest(t *testing.T, settings usecase.WebhookSettings, statuses ...int) (usecase.WebhookUseCase, *memoryWebhookRepository, *memoryWebhookQueue, *webhookReceiver) {
+	reportRepo := newStoringReportRepository()
+	reportRepo.SaveReport(context.Background(), entity.Report{SurveyID: "survey-123", ResponseCount: 2, Version: 3})
+
+	webhookRepo := newMemoryWebhookRepository()
+	queue := &memoryWebhookQueue{}
+	settings.AllowedNetworks = []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}
+	uc := usecase.NewWebhookUseCase(webhookRepo, queue, reportRepo, webhook.NewSender(settings.AllowedNetworks), &sequenceIDGenerator{}, settings)
+	if err := uc.StartDispatcher(context.Background()); err != nil {
+		t.Fatalf("Expected dispatcher to start, got %v", err)
+	}
+	t.Cleanup(func() { uc.StopDispatcher() })
+
+	receiver := newWebhookReceiver(t, "top-secret", statuses...)
+	if _, err := uc.RegisterWebhook(context.Background(), "survey-123", receiver.URL, "top-secret"); err != nil {
+		t.Fatalf("Expected webhook to be registered, got %v", err)
+	}
+
+	return uc, webhookRepo, queue, receiver
+}
+
+func TestWebhook_DeliversSignedPayload(t *testing.T) {
+	uc, webhookRepo, queue, receiver := newWebhookTest(t, usecase.DefaultWebhookSettings())
+
+	if err := uc.NotifyReportCompleted(context.Background(), "survey-123"); err != nil {
+		t.Fatalf("Expected notification to be queued, got %v", err)
+	}
+	queue.pump(t)
+
+	if len(receiver.payloads) != 1 {
+		t.Fatalf("Expected 1 payload, got %d", len(receiver.payloads))
+	}
+	payload := receiver.payloads[0]
+	if payload.Event != entity.WebhookEventReportCompleted || payload.SurveyID != "survey-123" || payload.ReportVersion != 3 {
+		t.Errorf("Unexpected payload: %+v", payload)
+	}
+	if payload.Summary == nil || payload.Summary.ResponseCount != 2 {
+		t.Errorf("Expected the report summary in the payload, got %+v", payload.Summary)
+	}
+
+	attempts, _ := webhookRepo.ListDeliveryAttempts(context.Background(), "survey-123")
+	if len(attempts) != 1 || attempts[0].Status != entity.DeliveryStatusDelivered || attempts[0].StatusCode != http.StatusOK {
+		t.Errorf("Expected one delivered attempt in the log, got %+v", attempts)
+	}
+}
+
+func TestWebhook_RetriesWithBackoff(t *testing.T) {
+	settings := usecase.WebhookSettings{MaxAttempts: 5, BaseBackoff: time.Second, MaxBackoff: 3 * time.Second, Timeout: time.Second}
+	uc, webhookRepo, queue, receiver := newWebhookTest(t, settings,
+		http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable)
+
+	if err := uc.NotifyReportFailed(context.Background(), "survey-123", errors.New("dial tcp 10.0.0.5:6379: connection refused")); err != nil {
+		t.Fatalf("Expected notification to be queued, got %v", err)
+	}
+	deliveries := queue.pump(t)
+
+	if len(receiver.payloads) != 4 {
+		t.Fatalf("Expected 4 attempts, got %d", len(receiver.payloads))
+	}
+	if payload := receiver.payloads[0]; payload.Event != entity.WebhookEventReportFailed || payload.Error != entity.WebhookErrorReportFailed || payload.ReportVersion != 3 {
+		t.Errorf("Unexpected payload: %+v", payload)
+	}
+
+	// Each retry waits twice as long as the previous one, capped at MaxBackoff
+	expected := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	for i, want := range expected {
+		retry := deliveries[i+1]
+		if retry.ID != deliveries[0].ID || retry.Attempt != i+2 {
+			t.Errorf("Expected retry %d to be attempt %d of %s, got attempt %d of %s", i, i+2, deliveries[0].ID, retry.Attempt, retry.ID)
+		}
+		delay := time.Until(time.Unix(0, retry.NotBefore))
+		if delay > want || delay < want-time.Second {
+			t.Errorf("Expected retry %d to be delayed by about %v, got %v", i, want, delay)
+		}
+	}
+
+	attempts, _ := webhookRepo.ListDeliveryAttempts(context.Background(), "survey-123")
+	statuses := []string{entity.DeliveryStatusRetrying, entity.DeliveryStatusRetrying, entity.DeliveryStatusRetrying, entity.DeliveryStatusDelivered}
+	if len(attempts) != len(statuses) {
+		t.Fatalf("Expected %d logged attempts, got %d", len(statuses), len(attempts))
+	}
+	for i, status := range statuses {
+		if attempts[i].Status != status || attempts[i].Attempt != i+1 {
+			t.Errorf("Expected attempt %d to be %s, got %+v", i+1, status, attempts[i])
+		}
+	}
+}
+
+func TestWebhook_GivesUpAfterMaxAttempts(t *testing.T) {
+	settings := usecase.WebhookSettings{MaxAttempts: 2, BaseBackoff: time.Second, MaxBackoff: time.Second, Timeout: time.Second}
+	uc, webhookRepo, queue, receiver := newWebhookTest(t, settings,
+		http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+
+	uc.NotifyReportCompleted(context.Background(), "survey-123")
+	queue.pump(t)
+
+	if len(receiver.payloads) != 2 {
+		t.Errorf("Expected 2 attempts, got %d", len(receiver.payloads))
+	}
+	attempts, _ := webhookRepo.ListDeliveryAttempts(context.Background(), "survey-123")
+	if last := attempts[len(attempts)-1]; last.Status != entity.DeliveryStatusFailed || last.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Expected the last attempt to be logged as failed, got %+v", last)
+	}
+}
+
+func TestWebhook_DropsDeliveriesOfDeletedWebhook(t *testing.T) {
+	uc, _, queue, receiver := newWebhookTest(t, usecase.DefaultWebhookSettings())
+
+	uc.NotifyReportCompleted(context.Background(), "survey-123")
+	webhooks, _ := uc.ListWebhooks(context.Background(), "survey-123")
+	if err := uc.DeleteWebhook(context.Background(), "survey-123", webhooks[0].ID); err != nil {
+		t.Fatalf("Expected webhook to be deleted, got %v", err)
+	}
+	queue.pump(t)
+
+	if len(receiver.payloads) != 0 {
+		t.Errorf("Expected no payload for a deleted webhook, got %d", len(receiver.payloads))
+	}
+}
+
+func TestRegisterWebhook_RejectsInvalidURL(t *testing.T) {
+	uc := usecase.NewWebhookUseCase(newMemoryWebhookRepository(), &memoryWebhookQueue{}, newStoringReportRepository(),
+		webhook.NewSender(nil), &sequenceIDGenerator{}, usecase.DefaultWebhookSettings())
+
+	for _, url := range []string{"", "example.com/hook", "ftp://example.com/hook", "http://",
+		"http://localhost:8080/hook", "http://127.0.0.1/hook", "http://[::1]/hook", "http://10.0.0.5/hook",
+		"http://192.168.1.10/hook", "http://169.254.169.254/latest/meta-data", "http://[::ffff:169.254.169.254]/", "http://0.0.0.0/hook"} {
+		if _, err := uc.RegisterWebhook(context.Background(), "survey-123", url, ""); !errors.Is(err, usecase.ErrInvalidWebhook) {
+			t.Errorf("Expected ErrInvalidWebhook for %q, got %v", url, err)
+		}
+	}
+
+	registered, err := uc.RegisterWebhook(context.Background(), "survey-123", "https://example.com/hook", "")
+	if err != nil || len(registered.Secret) != 64 {
+		t.Errorf("Expected a generated secret, got %+v, %v", registered, err)
+	}
+}
+
+func TestRegisterWebhook_AllowsConfiguredNetworks(t *testing.T) {
+	settings := usecase.DefaultWebhookSettings()
+	settings.AllowedNetworks = []netip.Prefix{netip.MustParsePrefix("10.1.0.0/16")}
+	uc := usecase.NewWebhookUseCase(newMemoryWebhookRepository(), &memoryWebhookQueue{}, newStoringReportRepository(),
+		webhook.NewSender(settings.AllowedNetworks), &sequenceIDGenerator{}, settings)
+
+	if _, err := uc.RegisterWebhook(context.Background(), "survey-123", "http://10.1.2.3/hook", ""); err != nil {
+		t.Errorf("Expected a receiver in an allowed network to be registered, got %v", err)
+	}
+	if _, err := uc.RegisterWebhook(context.Background(), "survey-123", "http://10.2.0.1/hook", ""); !errors.Is(err, usecase.ErrInvalidWebhook) {
+		t.Errorf("Expected ErrInvalidWebhook for a receiver outside the allowed networks, got %v", err)
+	}
+}
+
+func TestSender_RefusesPrivateAddresses(t *testing.T) {
+	var hits atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer receiver.Close()
+
+	// The receiver listens on loopback, which is only reachable once allowed
+	_, err := webhook.NewSender(nil).Send(context.Background(), receiver.URL, nil, []byte("{}"))
+	if !errors.Is(err, webhook.ErrAddressNotAllowed) {
+		t.Errorf("Expected ErrAddressNotAllowed, got %v", err)
+	}
+	if hits.Load() != 0 {
+		t.Errorf("Expected no request to reach the receiver, got %d", hits.Load())
+	}
+
+	allowed := []netip.Prefix{netip.MustParsePrefix("127.0.0.1/32")}
+	if _, err := webhook.NewSender(allowed).Send(context.Background(), receiver.URL, nil, []byte("{}")); err != nil {
+		t.Errorf("Expected the allowed receiver to be reached, got %v", err)
+	}
+}
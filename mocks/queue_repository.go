@@ -50,6 +50,24 @@ func (_m *QueueRepository) ConsumeReportJobs(ctx context.Context, callback func(
 	return r0
 }
 
+// Ping provides a mock function with given fields: ctx
+func (_m *QueueRepository) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // PublishReportJob provides a mock function with given fields: ctx, job
 func (_m *QueueRepository) PublishReportJob(ctx context.Context, job entity.ReportJob) error {
 	ret := _m.Called(ctx, job)
@@ -68,6 +86,24 @@ func (_m *QueueRepository) PublishReportJob(ctx context.Context, job entity.Repo
 	return r0
 }
 
+// SetPrefetch provides a mock function with given fields: prefetch
+func (_m *QueueRepository) SetPrefetch(prefetch int) error {
+	ret := _m.Called(prefetch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPrefetch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int) error); ok {
+		r0 = rf(prefetch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewQueueRepository creates a new instance of QueueRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewQueueRepository(t interface {
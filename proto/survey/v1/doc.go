@@ -0,0 +1,7 @@
+// Package surveyv1 holds the protobuf messages and gRPC stubs of the survey
+// service's gRPC API, generated from survey.proto. The API accepts survey
+// responses and serves the reports generated from them, like the HTTP API
+// under /api/v1
+package surveyv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative survey/v1/survey.proto
@@ -0,0 +1,309 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: survey/v1/survey.proto
+
+package surveyv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SurveyService_SubmitResponse_FullMethodName = "/survey.v1.SurveyService/SubmitResponse"
+	SurveyService_BatchSubmit_FullMethodName    = "/survey.v1.SurveyService/BatchSubmit"
+	SurveyService_GetReport_FullMethodName      = "/survey.v1.SurveyService/GetReport"
+	SurveyService_GetJobStatus_FullMethodName   = "/survey.v1.SurveyService/GetJobStatus"
+	SurveyService_WatchReport_FullMethodName    = "/survey.v1.SurveyService/WatchReport"
+)
+
+// SurveyServiceClient is the client API for SurveyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SurveyService submits survey responses and reads the reports generated from them.
+//
+// Callers authenticate with an API key in the x-api-key metadata, or with an
+// API key or JWT in the authorization metadata as a bearer token. The
+// x-tenant-id metadata names the tenant a call acts for.
+type SurveyServiceClient interface {
+	// SubmitResponse submits one survey response; a report job is scheduled
+	// for its survey. Requires the submit scope.
+	SubmitResponse(ctx context.Context, in *SubmitResponseRequest, opts ...grpc.CallOption) (*SubmitResponseResponse, error)
+	// BatchSubmit submits many survey responses at once. Each response is
+	// accepted or rejected on its own. Requires the submit scope.
+	BatchSubmit(ctx context.Context, in *BatchSubmitRequest, opts ...grpc.CallOption) (*BatchSubmitResponse, error)
+	// GetReport returns the latest report of a survey, NOT_FOUND until one
+	// has been generated. Requires the read:report scope.
+	GetReport(ctx context.Context, in *GetReportRequest, opts ...grpc.CallOption) (*Report, error)
+	// GetJobStatus returns a report job of a survey. Requires the read:report scope.
+	GetJobStatus(ctx context.Context, in *GetJobStatusRequest, opts ...grpc.CallOption) (*Job, error)
+	// WatchReport streams the latest report of a survey, if any, followed by
+	// every new version as it is generated, until the caller cancels.
+	// Requires the read:report scope.
+	WatchReport(ctx context.Context, in *WatchReportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Report], error)
+}
+
+type surveyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSurveyServiceClient(cc grpc.ClientConnInterface) SurveyServiceClient {
+	return &surveyServiceClient{cc}
+}
+
+func (c *surveyServiceClient) SubmitResponse(ctx context.Context, in *SubmitResponseRequest, opts ...grpc.CallOption) (*SubmitResponseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitResponseResponse)
+	err := c.cc.Invoke(ctx, SurveyService_SubmitResponse_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *surveyServiceClient) BatchSubmit(ctx context.Context, in *BatchSubmitRequest, opts ...grpc.CallOption) (*BatchSubmitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchSubmitResponse)
+	err := c.cc.Invoke(ctx, SurveyService_BatchSubmit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *surveyServiceClient) GetReport(ctx context.Context, in *GetReportRequest, opts ...grpc.CallOption) (*Report, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Report)
+	err := c.cc.Invoke(ctx, SurveyService_GetReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *surveyServiceClient) GetJobStatus(ctx context.Context, in *GetJobStatusRequest, opts ...grpc.CallOption) (*Job, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Job)
+	err := c.cc.Invoke(ctx, SurveyService_GetJobStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *surveyServiceClient) WatchReport(ctx context.Context, in *WatchReportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Report], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SurveyService_ServiceDesc.Streams[0], SurveyService_WatchReport_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchReportRequest, Report]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SurveyService_WatchReportClient = grpc.ServerStreamingClient[Report]
+
+// SurveyServiceServer is the server API for SurveyService service.
+// All implementations must embed UnimplementedSurveyServiceServer
+// for forward compatibility.
+//
+// SurveyService submits survey responses and reads the reports generated from them.
+//
+// Callers authenticate with an API key in the x-api-key metadata, or with an
+// API key or JWT in the authorization metadata as a bearer token. The
+// x-tenant-id metadata names the tenant a call acts for.
+type SurveyServiceServer interface {
+	// SubmitResponse submits one survey response; a report job is scheduled
+	// for its survey. Requires the submit scope.
+	SubmitResponse(context.Context, *SubmitResponseRequest) (*SubmitResponseResponse, error)
+	// BatchSubmit submits many survey responses at once. Each response is
+	// accepted or rejected on its own. Requires the submit scope.
+	BatchSubmit(context.Context, *BatchSubmitRequest) (*BatchSubmitResponse, error)
+	// GetReport returns the latest report of a survey, NOT_FOUND until one
+	// has been generated. Requires the read:report scope.
+	GetReport(context.Context, *GetReportRequest) (*Report, error)
+	// GetJobStatus returns a report job of a survey. Requires the read:report scope.
+	GetJobStatus(context.Context, *GetJobStatusRequest) (*Job, error)
+	// WatchReport streams the latest report of a survey, if any, followed by
+	// every new version as it is generated, until the caller cancels.
+	// Requires the read:report scope.
+	WatchReport(*WatchReportRequest, grpc.ServerStreamingServer[Report]) error
+	mustEmbedUnimplementedSurveyServiceServer()
+}
+
+// UnimplementedSurveyServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSurveyServiceServer struct{}
+
+func (UnimplementedSurveyServiceServer) SubmitResponse(context.Context, *SubmitResponseRequest) (*SubmitResponseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitResponse not implemented")
+}
+func (UnimplementedSurveyServiceServer) BatchSubmit(context.Context, *BatchSubmitRequest) (*BatchSubmitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchSubmit not implemented")
+}
+func (UnimplementedSurveyServiceServer) GetReport(context.Context, *GetReportRequest) (*Report, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReport not implemented")
+}
+func (UnimplementedSurveyServiceServer) GetJobStatus(context.Context, *GetJobStatusRequest) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobStatus not implemented")
+}
+func (UnimplementedSurveyServiceServer) WatchReport(*WatchReportRequest, grpc.ServerStreamingServer[Report]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchReport not implemented")
+}
+func (UnimplementedSurveyServiceServer) mustEmbedUnimplementedSurveyServiceServer() {}
+func (UnimplementedSurveyServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeSurveyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SurveyServiceServer will
+// result in compilation errors.
+type UnsafeSurveyServiceServer interface {
+	mustEmbedUnimplementedSurveyServiceServer()
+}
+
+func RegisterSurveyServiceServer(s grpc.ServiceRegistrar, srv SurveyServiceServer) {
+	// If the following call pancis, it indicates UnimplementedSurveyServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SurveyService_ServiceDesc, srv)
+}
+
+func _SurveyService_SubmitResponse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitResponseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SurveyServiceServer).SubmitResponse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SurveyService_SubmitResponse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SurveyServiceServer).SubmitResponse(ctx, req.(*SubmitResponseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SurveyService_BatchSubmit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchSubmitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SurveyServiceServer).BatchSubmit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SurveyService_BatchSubmit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SurveyServiceServer).BatchSubmit(ctx, req.(*BatchSubmitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SurveyService_GetReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SurveyServiceServer).GetReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SurveyService_GetReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SurveyServiceServer).GetReport(ctx, req.(*GetReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SurveyService_GetJobStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SurveyServiceServer).GetJobStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SurveyService_GetJobStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SurveyServiceServer).GetJobStatus(ctx, req.(*GetJobStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SurveyService_WatchReport_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchReportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SurveyServiceServer).WatchReport(m, &grpc.GenericServerStream[WatchReportRequest, Report]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SurveyService_WatchReportServer = grpc.ServerStreamingServer[Report]
+
+// SurveyService_ServiceDesc is the grpc.ServiceDesc for SurveyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SurveyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "survey.v1.SurveyService",
+	HandlerType: (*SurveyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitResponse",
+			Handler:    _SurveyService_SubmitResponse_Handler,
+		},
+		{
+			MethodName: "BatchSubmit",
+			Handler:    _SurveyService_BatchSubmit_Handler,
+		},
+		{
+			MethodName: "GetReport",
+			Handler:    _SurveyService_GetReport_Handler,
+		},
+		{
+			MethodName: "GetJobStatus",
+			Handler:    _SurveyService_GetJobStatus_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchReport",
+			Handler:       _SurveyService_WatchReport_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "survey/v1/survey.proto",
+}
@@ -6,7 +6,7 @@ Start-Sleep -Seconds 5
 
 # Submit a survey response
 Write-Host "Submitting survey response..."
-$response1 = Invoke-RestMethod -Uri "http://localhost:8080/api/survey/submit" `
+$response1 = Invoke-RestMethod -Uri "http://localhost:8080/api/v1/survey/submit" `
     -Method Post `
     -ContentType "application/json" `
     -Body '{
@@ -21,7 +21,7 @@ $response1 = Invoke-RestMethod -Uri "http://localhost:8080/api/survey/submit" `
 Write-Host "Response: $($response1 | ConvertTo-Json)"
 
 Write-Host "`nSubmitting another response for the same survey (should be debounced)..."
-$response2 = Invoke-RestMethod -Uri "http://localhost:8080/api/survey/submit" `
+$response2 = Invoke-RestMethod -Uri "http://localhost:8080/api/v1/survey/submit" `
     -Method Post `
     -ContentType "application/json" `
     -Body '{
@@ -36,7 +36,7 @@ $response2 = Invoke-RestMethod -Uri "http://localhost:8080/api/survey/submit" `
 Write-Host "Response: $($response2 | ConvertTo-Json)"
 
 Write-Host "`nSubmitting response for a different survey..."
-$response3 = Invoke-RestMethod -Uri "http://localhost:8080/api/survey/submit" `
+$response3 = Invoke-RestMethod -Uri "http://localhost:8080/api/v1/survey/submit" `
     -Method Post `
     -ContentType "application/json" `
     -Body '{
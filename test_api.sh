@@ -8,7 +8,7 @@ sleep 5
 
 # Submit a survey response
 echo "Submitting survey response..."
-curl -X POST http://localhost:8080/api/survey/submit \
+curl -X POST http://localhost:8080/api/v1/survey/submit \
   -H "Content-Type: application/json" \
   -d '{
     "survey_id": "survey123",
@@ -20,7 +20,7 @@ curl -X POST http://localhost:8080/api/survey/submit \
   }'
 
 echo -e "\n\nSubmitting another response for the same survey (should be debounced)..."
-curl -X POST http://localhost:8080/api/survey/submit \
+curl -X POST http://localhost:8080/api/v1/survey/submit \
   -H "Content-Type: application/json" \
   -d '{
     "survey_id": "survey123",
@@ -32,7 +32,7 @@ curl -X POST http://localhost:8080/api/survey/submit \
   }'
 
 echo -e "\n\nSubmitting response for a different survey..."
-curl -X POST http://localhost:8080/api/survey/submit \
+curl -X POST http://localhost:8080/api/v1/survey/submit \
   -H "Content-Type: application/json" \
   -d '{
     "survey_id": "survey456",
@@ -30,7 +30,7 @@ function Submit-SurveyResponse {
     } | ConvertTo-Json
     
     try {
-        $response = Invoke-RestMethod -Uri "http://localhost:$Port/api/survey/submit" `
+        $response = Invoke-RestMethod -Uri "http://localhost:$Port/api/v1/survey/submit" `
             -Method Post `
             -ContentType "application/json" `
             -Body $body